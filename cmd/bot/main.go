@@ -3,37 +3,86 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"os"
 
 	"github.com/callegarimattia/battleship/internal/bot"
 	"github.com/callegarimattia/battleship/internal/controller"
 	"github.com/callegarimattia/battleship/internal/env"
+	"github.com/callegarimattia/battleship/internal/logging"
+	"github.com/callegarimattia/battleship/internal/remote"
 	"github.com/callegarimattia/battleship/internal/service"
 )
 
 func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
 	// Load configuration
 	cfg, err := env.LoadBotConfig()
 	if err != nil {
-		log.Fatalf("Failed to load bot config: %v", err)
+		return fmt.Errorf("failed to load bot config: %w", err)
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		return err
 	}
 
 	// Initialize services
 	notifier := service.NewNotificationService()
-	identityService := service.NewIdentityService(cfg.JWTSecret)
-	memoryService := service.NewMemoryService(notifier)
 
-	// Create controller
-	ctrl := controller.NewAppController(identityService, memoryService, memoryService, notifier)
+	var ctrl *controller.AppController
+	if cfg.ServerURL != "" {
+		// Play on the server, so that Discord players share matches with web and TUI players
+		backend := remote.New(cfg.ServerURL, cfg.BotAPIKey, notifier, remote.WithLogger(logger))
+		defer backend.Close()
+
+		ctrl = controller.NewAppController(backend, backend, backend, notifier)
+	} else {
+		identityService := service.NewIdentityService(
+			cfg.JWTSecret,
+			service.WithTokenIssuer(cfg.JWTIssuer),
+			service.WithTokenAudience(cfg.JWTAudience),
+		)
+		memoryService := service.NewMemoryService(
+			notifier,
+			service.WithAutoReady(cfg.AutoReady),
+			service.WithMaxActiveGames(cfg.MaxActiveGames),
+			service.WithMaxTotalGames(cfg.MaxTotalGames),
+			service.WithLogger(logger),
+			service.WithGC(service.GCConfig{
+				Interval:    cfg.GCInterval,
+				FinishedTTL: cfg.FinishedTTL,
+				StaleTTL:    cfg.StaleTTL,
+			}),
+		)
+		defer memoryService.Close()
+
+		ctrl = controller.NewAppController(identityService, memoryService, memoryService, notifier)
+	}
 
 	// Create and start bot
-	discordBot, err := bot.NewDiscordBot(cfg.DiscordToken, cfg.DiscordAppID, ctrl, notifier)
+	discordBot, err := bot.NewDiscordBot(
+		cfg.DiscordToken,
+		cfg.DiscordAppID,
+		ctrl,
+		notifier,
+		bot.WithGuildID(cfg.DiscordGuildID),
+		bot.WithNotifyCooldown(cfg.DiscordNotifyCooldown),
+		bot.WithLogger(logger),
+	)
 	if err != nil {
-		log.Fatalf("Failed to create Discord bot: %v", err)
+		return fmt.Errorf("failed to create Discord bot: %w", err)
 	}
 
-	log.Println("Starting Discord bot...")
+	logger.Info("Starting Discord bot")
 	if err := discordBot.Start(context.Background()); err != nil {
-		log.Fatalf("Bot error: %v", err)
+		return fmt.Errorf("bot error: %w", err)
 	}
+	return nil
 }
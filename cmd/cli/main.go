@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -9,7 +10,16 @@ import (
 )
 
 func main() {
-	p := tea.NewProgram(tui.New(), tea.WithAltScreen())
+	confirmFire := flag.Bool("confirm-fire", false, "require a second Enter to confirm each shot and ship placement")
+	flag.Parse()
+
+	m, err := tui.New(tui.WithConfirmFire(*confirmFire))
+	if err != nil {
+		fmt.Printf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
@@ -7,23 +7,32 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
 
 	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/env"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 )
 
-func TestE2E_FullGameScenario(t *testing.T) {
-	// Disable rate limiting for E2E tests
-	os.Setenv("RATE_LIMIT", "1000")
-	defer os.Unsetenv("RATE_LIMIT")
+// testConfig returns the server configuration from the environment, without rate limiting.
+func testConfig(t *testing.T) *env.Config {
+	t.Helper()
+
+	cfg, err := env.LoadServerConfig()
+	require.NoError(t, err)
+	cfg.RateLimit = 1000
+	return cfg
+}
 
+func TestE2E_FullGameScenario(t *testing.T) {
 	t.Parallel()
 
-	app := &Application{}
-	app.Setup()
+	app := &Application{Config: testConfig(t)}
+	require.NoError(t, app.Setup())
+	defer app.Close()
 
 	// Use a real HTTP server
 	ts := httptest.NewServer(app.E)
@@ -91,6 +100,111 @@ func TestE2E_FullGameScenario(t *testing.T) {
 	require.Equal(t, alice.ID, finalState.Winner)
 }
 
+func TestE2E_FreeForAllTarget(t *testing.T) {
+	t.Parallel()
+
+	app := &Application{Config: testConfig(t)}
+	require.NoError(t, app.Setup())
+	defer app.Close()
+
+	ts := httptest.NewServer(app.E)
+	defer ts.Close()
+
+	players := make([]*testClient, 3)
+	ids := make([]string, 3)
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		players[i] = &testClient{t: t, baseURL: ts.URL, client: ts.Client()}
+		ids[i] = players[i].login(name).ID
+	}
+	alice, bob, carol := players[0], players[1], players[2]
+
+	rec := alice.do(http.MethodPost, "/matches",
+		map[string]any{"free_for_all": 3, "board_size": 6, "fleet_preset": "small"}, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var created map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	matchID := created["match_id"]
+
+	bob.joinMatch(matchID)
+	carol.joinMatch(matchID)
+	for _, c := range players {
+		c.placeShip(matchID, 3, 0, 0, false)
+		c.placeShip(matchID, 2, 0, 1, false)
+		c.placeShip(matchID, 2, 0, 2, false)
+	}
+	require.Equal(t, dto.StatePlaying, alice.getMatchState(matchID).State)
+
+	attack := func(c *testClient, body map[string]any) *testResponse {
+		return c.do(http.MethodPost, "/matches/"+matchID+"/attack", body, nil)
+	}
+
+	rec = attack(alice, map[string]any{"x": 0, "y": 0})
+	require.Equal(t, http.StatusBadRequest, rec.Code, "a free-for-all shot names its target")
+	rec = attack(alice, map[string]any{"target": ids[0], "x": 0, "y": 0})
+	require.Equal(t, http.StatusBadRequest, rec.Code, "nobody fires at their own board")
+
+	rec = attack(alice, map[string]any{"target": ids[2], "x": 0, "y": 0})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, dto.CellHit, carol.getMatchState(matchID).Me.Board.Grid[0][0], "Carol's board is fired at")
+	require.Equal(t, dto.CellShip, bob.getMatchState(matchID).Me.Board.Grid[0][0], "Bob's board is untouched")
+
+	rec = attack(bob, map[string]any{"target": ids[0], "x": 5, "y": 5})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, dto.CellMiss, alice.getMatchState(matchID).Me.Board.Grid[5][5])
+}
+
+func TestE2E_WebSocketAuth(t *testing.T) {
+	t.Parallel()
+
+	app := &Application{Config: testConfig(t)}
+	require.NoError(t, app.Setup())
+	t.Cleanup(app.Close)
+
+	// The subtests run in parallel and outlive this function, so the server
+	// has to be torn down by Cleanup rather than defer.
+	ts := httptest.NewServer(app.E)
+	t.Cleanup(ts.Close)
+
+	aliceClient := &testClient{t: t, baseURL: ts.URL, client: ts.Client()}
+	_ = aliceClient.login("Alice")
+	matchID := aliceClient.createMatch()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/matches/" + matchID + "/ws"
+
+	t.Run("Missing Token", func(t *testing.T) {
+		t.Parallel()
+		_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.Error(t, err)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Query Token", func(t *testing.T) {
+		t.Parallel()
+		ws, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+aliceClient.token, nil)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		var evt dto.WSEvent
+		require.NoError(t, ws.ReadJSON(&evt))
+		require.Equal(t, "game_update", evt.Type)
+	})
+
+	t.Run("Subprotocol Token", func(t *testing.T) {
+		t.Parallel()
+		dialer := websocket.Dialer{Subprotocols: []string{"bearer", aliceClient.token}}
+		ws, _, err := dialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		require.Equal(t, "bearer", ws.Subprotocol())
+
+		var evt dto.WSEvent
+		require.NoError(t, ws.ReadJSON(&evt))
+		require.Equal(t, "game_update", evt.Type)
+	})
+}
+
 // --- Test Helper ---
 
 type testClient struct {
@@ -228,3 +342,120 @@ func (c *testClient) attack(matchID string, x, y int) dto.GameView {
 	require.NoError(c.t, err)
 	return state
 }
+
+func TestE2E_BodyLimits(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	cfg.GameBodyLimit = 4 << 10
+	cfg.AuthBodyLimit = 16 << 10
+	app := &Application{Config: cfg}
+	require.NoError(t, app.Setup())
+	defer app.Close()
+
+	ts := httptest.NewServer(app.E)
+	defer ts.Close()
+
+	c := &testClient{t: t, baseURL: ts.URL, client: ts.Client()}
+	padded := func(size int) map[string]string {
+		return map[string]string{"username": "Alice", "padding": strings.Repeat("x", size)}
+	}
+
+	rec := c.do(http.MethodPost, "/login", padded(20<<10), nil)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = c.do(http.MethodPost, "/login", padded(8<<10), nil)
+	require.Equal(t, http.StatusOK, rec.Code, "logins have more room than game actions")
+	var auth dto.AuthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &auth))
+	c.token = auth.Token
+
+	matchID := c.createMatch()
+
+	rec = c.do(http.MethodPost, "/matches/"+matchID+"/attack", padded(8<<10), nil)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = c.do(http.MethodPost, "/matches", padded(8<<10), nil)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestE2E_Metrics(t *testing.T) {
+	t.Parallel()
+
+	app := &Application{Config: testConfig(t)}
+	require.NoError(t, app.Setup())
+	defer app.Close()
+	ts := httptest.NewServer(app.E)
+	defer ts.Close()
+
+	c := &testClient{t: t, baseURL: ts.URL, client: ts.Client()}
+	rec := c.do(http.MethodGet, "/metrics", nil, nil)
+	require.Equal(t, http.StatusNotFound, rec.Code, "metrics are off by default")
+
+	cfg := testConfig(t)
+	cfg.MetricsEnabled = true
+	app = &Application{Config: cfg}
+	require.NoError(t, app.Setup())
+	defer app.Close()
+	ts = httptest.NewServer(app.E)
+	defer ts.Close()
+
+	c = &testClient{t: t, baseURL: ts.URL, client: ts.Client()}
+	_ = c.login("Alice")
+	matchID := c.createMatch()
+	_ = c.getMatchState(matchID)
+
+	rec = c.do(http.MethodGet, "/metrics", nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	require.Contains(t, body, "# TYPE battleship_service_call_duration_seconds histogram")
+	require.Contains(t, body, `battleship_service_call_duration_seconds_count{operation="create_match"} 1`)
+	require.Contains(t, body, `battleship_service_call_duration_seconds_count{operation="get_state"} 1`)
+}
+
+func TestSetup_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"Unknown log level", map[string]string{"LOG_LEVEL": "chatty"}, "log level"},
+		{"Unknown log format", map[string]string{"LOG_FORMAT": "xml"}, "log format"},
+		{"RS256 without key", map[string]string{"JWT_SIGNING_METHOD": "RS256"}, "JWT_PRIVATE_KEY_FILE"},
+		{"Unsupported signing method", map[string]string{"JWT_SIGNING_METHOD": "none"}, "JWT_SIGNING_METHOD"},
+		{"Default secret in production", map[string]string{"ENV": "production", "PORT": "8080"}, "JWT_SECRET"},
+		{
+			"Every problem listed in production",
+			map[string]string{"ENV": "production", "JWT_SECRET": "s3cr3t", "RATE_LIMIT": "0", "PORT": ""},
+			"RATE_LIMIT must be positive, got 0; PORT is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			app := &Application{}
+			err := app.Setup()
+			defer app.Close()
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSetup_InjectedConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	app := &Application{Config: cfg}
+	require.NoError(t, app.Setup())
+	defer app.Close()
+
+	require.Same(t, cfg, app.Config, "a given configuration is not reloaded")
+	require.NotNil(t, app.Notifier)
+	require.NotNil(t, app.Memory)
+	require.NotNil(t, app.Identity)
+}
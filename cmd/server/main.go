@@ -2,12 +2,23 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/callegarimattia/battleship/internal/buildinfo"
 	"github.com/callegarimattia/battleship/internal/controller"
 	"github.com/callegarimattia/battleship/internal/env"
+	"github.com/callegarimattia/battleship/internal/logging"
+	"github.com/callegarimattia/battleship/internal/metrics"
 	"github.com/callegarimattia/battleship/internal/server"
 	"github.com/callegarimattia/battleship/internal/service"
 	echojwt "github.com/labstack/echo-jwt/v4"
@@ -23,71 +34,260 @@ func main() {
 	}
 }
 
+// Application is the HTTP server with the services behind it.
+// Setup fills in everything but Config, which is loaded from the environment unless set beforehand.
 type Application struct {
 	E      *echo.Echo
 	Config *env.Config
+
+	Notifier    *service.NotificationService // Event bus
+	Memory      *service.MemoryService       // Lobby and game service
+	Identity    *service.MemoryIdentityService
+	Tournaments *service.MemoryTournamentService
 }
 
-// Setup initializes the Echo instance and routes.
+// Setup initializes the services, the Echo instance and routes.
 // It is separate from Run so that tests can initialize without starting the listener.
-func (a *Application) Setup() {
-	var err error
-	a.Config, err = env.LoadServerConfig()
-	if err != nil {
-		log.Fatalf("Failed to load server config: %v", err)
+func (a *Application) Setup() error {
+	if a.Config == nil {
+		cfg, err := env.LoadServerConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load server config: %w", err)
+		}
+		a.Config = cfg
 	}
 
 	cfg := a.Config
 
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		return err
+	}
+	// Components not handed the logger explicitly fall back to the default one
+	slog.SetDefault(logger)
+
+	for _, warning := range cfg.Warnings {
+		logger.Warn("Unsafe configuration, refused in production", "problem", warning)
+	}
+
 	// Initialize event bus
-	// Initialize services
 	notifier := service.NewNotificationService()
-	memEngine := service.NewMemoryService(notifier)
-	authService := service.NewIdentityService(cfg.JWTSecret)
-	appCtrl := controller.NewAppController(authService, memEngine, memEngine, notifier)
+
+	// Initialize services
+	memEngine := service.NewMemoryService(
+		notifier,
+		service.WithAutoReady(cfg.AutoReady),
+		service.WithMaxActiveGames(cfg.MaxActiveGames),
+		service.WithMaxTotalGames(cfg.MaxTotalGames),
+		service.WithAbandonGracePeriod(cfg.AbandonGracePeriod),
+		service.WithLogger(logger),
+		service.WithGC(service.GCConfig{
+			Interval:    cfg.GCInterval,
+			FinishedTTL: cfg.FinishedTTL,
+			StaleTTL:    cfg.StaleTTL,
+		}),
+	)
+	signingKey, verifyKey, err := tokenKeys(cfg)
+	if err != nil {
+		memEngine.Close()
+		return fmt.Errorf("failed to load JWT keys: %w", err)
+	}
+	identityOpts := []service.IdentityOption{
+		service.WithTokenIssuer(cfg.JWTIssuer),
+		service.WithTokenAudience(cfg.JWTAudience),
+	}
+	if signingKey != nil {
+		identityOpts = append(identityOpts, service.WithRS256(signingKey))
+	}
+	authService := service.NewIdentityService(cfg.JWTSecret, identityOpts...)
+	tournaments := service.NewMemoryTournamentService(memEngine, notifier, service.WithTournamentLogger(logger))
+
+	// Cross-cutting concerns are layered around the memory service: Metrics(Logging(MemoryService))
+	var (
+		lobby controller.LobbyService = service.NewLoggingLobbyService(memEngine, logger)
+		game  controller.GameService  = service.NewLoggingGameService(memEngine, logger)
+	)
+	// Timed only when metrics are enabled, so that they cost nothing otherwise
+	var latency *metrics.Histogram
+	if cfg.MetricsEnabled {
+		latency = metrics.NewHistogram(
+			"battleship_service_call_duration_seconds",
+			"Time taken by lobby and game service calls.",
+			"operation",
+			nil,
+		)
+		lobby = service.NewMetricsLobbyService(lobby, latency)
+		game = service.NewMetricsGameService(game, latency)
+	}
+
+	appCtrl := controller.NewAppController(
+		authService,
+		lobby,
+		game,
+		notifier,
+		controller.WithTournaments(tournaments),
+		controller.WithAdmin(memEngine),
+	)
+	a.Notifier, a.Memory, a.Identity, a.Tournaments = notifier, memEngine, authService, tournaments
 
 	a.E = echo.New()
 
 	// Middleware
-	a.E.Use(middleware.RequestLogger())
-	a.E.Use(middleware.Recover())
+	a.E.Use(server.RequestLogger(logger))
+	a.E.Use(server.Recover(logger))
 	a.E.Use(middleware.Secure())
-	a.E.Use(middleware.CORS())
-	a.E.Use(middleware.BodyLimit("1M"))
-	a.E.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(rate.Limit(cfg.RateLimit))))
+	// Without allowed origins the middleware would fall back to "*", so cross-origin access stays off
+	if len(cfg.CORSAllowOrigins) > 0 {
+		a.E.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins: cfg.CORSAllowOrigins,
+			AllowMethods: cfg.CORSAllowMethods,
+			AllowHeaders: cfg.CORSAllowHeaders,
+		}))
+	}
+	a.E.Use(bodyLimit(cfg.BodyLimit))
+	a.E.Use(server.RateLimit(rate.Limit(cfg.RateLimit)))
 
-	h := server.NewEchoHandler(appCtrl)
+	h := server.NewEchoHandler(
+		appCtrl,
+		server.WithAllowedOrigins(cfg.CORSAllowOrigins),
+		server.WithWriteTimeout(cfg.WSWriteTimeout),
+		server.WithWebSocketBuffers(cfg.WSReadBufferSize, cfg.WSWriteBufferSize),
+		server.WithPollTimeout(cfg.PollTimeout),
+		server.WithCoalesceWindow(cfg.WSCoalesceWindow),
+	)
 
 	a.E.GET("/health", func(c echo.Context) error {
 		return c.String(http.StatusOK, "OK")
 	})
+	a.E.GET("/version", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, buildinfo.Get())
+	})
+	if latency != nil {
+		a.E.GET("/metrics", func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+			_, err := latency.WriteTo(c.Response())
+			return err
+		})
+	}
 
 	a.E.Static("/docs", "docs")
 	a.E.Static("/", "public")
 
-	a.E.POST("/login", h.Login)
+	authLimit := bodyLimit(cfg.AuthBodyLimit)
+	a.E.POST("/login", h.Login, authLimit)
+	if cfg.BotAPIKey != "" {
+		a.E.POST("/login/platform", h.PlatformLogin, authLimit, server.RequireAPIKey(cfg.BotAPIKey))
+	}
+
+	requireJWT := echojwt.WithConfig(echojwt.Config{
+		ParseTokenFunc: server.ParseToken(verifyKey, cfg.JWTIssuer, cfg.JWTAudience),
+	})
+
+	a.E.GET("/me", h.Me, requireJWT, server.RequirePlayerID)
+	a.E.GET("/me/match", h.ActiveMatch, requireJWT, server.RequirePlayerID)
+
+	gameLimit := bodyLimit(cfg.GameBodyLimit)
 
-	g := a.E.Group("/matches")
+	a.E.GET("/practice-board", h.PracticeBoard)
+	a.E.GET("/scoreboard", h.Scoreboard)
+
+	g := a.E.Group("/matches", gameLimit, server.ValidMatchID)
 	g.GET("", h.ListMatches)
+	g.GET("/:id/reveal", h.Reveal)
+	g.GET("/:id/rules", h.Rules)
 
 	// Protected routes
 	protected := g.Group("")
-	protected.Use(echojwt.WithConfig(echojwt.Config{
-		SigningKey: []byte(cfg.JWTSecret),
-	}))
+	protected.Use(requireJWT)
 	protected.Use(server.RequirePlayerID)
 
 	protected.POST("", h.HostMatch)
 	protected.POST("/:id/join", h.JoinMatch)
+	protected.POST("/:id/resume", h.ResumeMatch)
 	protected.GET("/:id", h.GetState)
+	protected.GET("/:id/events", h.Events)
+	protected.GET("/:id/poll", h.Poll)
+	protected.GET("/:id/export", h.Export)
+	protected.DELETE("/:id", h.CancelMatch)
 	protected.POST("/:id/place", h.PlaceShip)
+	protected.POST("/:id/validate-placement", h.ValidatePlacement)
+	protected.POST("/:id/ready", h.Ready)
 	protected.POST("/:id/attack", h.Attack)
-	protected.GET("/:id/ws", h.StreamMatchEvents)
+	protected.POST("/:id/validate-attack", h.ValidateAttack)
+	protected.GET("/:id/legal-moves", h.LegalMoves)
+
+	// Browsers cannot set headers on the WebSocket handshake, so the token may also come
+	// from the query string or the subprotocol list
+	g.GET("/:id/ws", h.StreamMatchEvents, server.WebSocketToken, requireJWT, server.RequirePlayerID)
+
+	// JSON-RPC alternative to the routes above, for integrators who prefer it
+	a.E.POST("/rpc", h.RPC, gameLimit, requireJWT, server.RequirePlayerID)
+
+	if cfg.AdminAPIKey != "" {
+		admin := a.E.Group("/admin", gameLimit, server.RequireAPIKey(cfg.AdminAPIKey))
+		admin.POST("/matches/:id/terminate", h.TerminateMatch, server.ValidMatchID)
+	}
+
+	t := a.E.Group("/tournaments", gameLimit)
+	t.GET("/:id", h.GetTournament)
+	t.POST("", h.CreateTournament, requireJWT, server.RequirePlayerID)
+	t.POST("/:id/join", h.JoinTournament, requireJWT, server.RequirePlayerID)
+	t.POST("/:id/start", h.StartTournament, requireJWT, server.RequirePlayerID)
+
+	return nil
 }
 
-// Run calls Setup and then starts the server.
+// tokenKeys returns the keys signing and verifying tokens for JWT_SIGNING_METHOD.
+// The signing key is nil for HS256, where the shared secret is used both ways.
+func tokenKeys(cfg *env.Config) (*rsa.PrivateKey, any, error) {
+	switch cfg.JWTSigningMethod {
+	case "HS256":
+		return nil, []byte(cfg.JWTSecret), nil
+	case "RS256":
+		if cfg.JWTPrivateKeyFile == "" {
+			return nil, nil, errors.New("RS256 requires JWT_PRIVATE_KEY_FILE")
+		}
+		privateKey, err := service.LoadRSAPrivateKey(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		publicKey := &privateKey.PublicKey
+		if cfg.JWTPublicKeyFile != "" {
+			if publicKey, err = server.LoadRSAPublicKey(cfg.JWTPublicKeyFile); err != nil {
+				return nil, nil, err
+			}
+		}
+		return privateKey, publicKey, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported JWT_SIGNING_METHOD %q (want HS256 or RS256)", cfg.JWTSigningMethod)
+	}
+}
+
+// bodyLimit rejects request bodies larger than limit bytes with a 413. Zero or less sets no limit.
+func bodyLimit(limit int64) echo.MiddlewareFunc {
+	if limit <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	return middleware.BodyLimit(strconv.FormatInt(limit, 10))
+}
+
+// Close releases the resources created by Setup, such as the service background goroutines.
+func (a *Application) Close() {
+	if a.Tournaments != nil {
+		a.Tournaments.Close()
+	}
+	if a.Memory != nil {
+		a.Memory.Close()
+	}
+}
+
+// Run calls Setup and then starts the server until an interrupt or termination signal is received.
 func (a *Application) Run() error {
-	a.Setup()
+	if err := a.Setup(); err != nil {
+		return err
+	}
+	defer a.Close()
 
 	s := &http.Server{
 		Addr:              ":" + a.Config.Port,
@@ -98,5 +298,23 @@ func (a *Application) Run() error {
 		ReadHeaderTimeout: 2 * time.Second,
 	}
 
-	return s.ListenAndServe()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.ListenAndServe() }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
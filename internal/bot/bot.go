@@ -4,11 +4,12 @@ package bot
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/callegarimattia/battleship/internal/controller"
@@ -18,6 +19,7 @@ import (
 type DiscordBot struct {
 	session         *discordgo.Session
 	appID           string
+	guildID         string // Empty for global commands
 	ctrl            *controller.AppController
 	notifier        controller.NotificationService
 	activeMatches   map[string]string // userID -> matchID
@@ -26,6 +28,30 @@ type DiscordBot struct {
 	discordMu       sync.RWMutex
 	matchToChannel  map[string]string // matchID -> channelID
 	channelMu       sync.RWMutex
+	notifyCooldown  time.Duration
+	outbox          *outbox
+	logger          *slog.Logger
+}
+
+// Option configures optional DiscordBot behavior.
+type Option func(*DiscordBot)
+
+// WithGuildID registers the slash commands in a single guild instead of globally.
+// Guild commands are available immediately, while global ones can take up to an hour to propagate.
+func WithGuildID(guildID string) Option {
+	return func(b *DiscordBot) { b.guildID = guildID }
+}
+
+// WithNotifyCooldown sets the minimum time between two notification posts in a channel.
+// Events arriving in the meantime are coalesced into the next post.
+func WithNotifyCooldown(cooldown time.Duration) Option {
+	return func(b *DiscordBot) { b.notifyCooldown = cooldown }
+}
+
+// WithLogger sets the logger the bot reports its activity and failures to.
+// The default logger is used otherwise.
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *DiscordBot) { b.logger = logger }
 }
 
 // NewDiscordBot creates a new Discord bot instance.
@@ -33,6 +59,7 @@ func NewDiscordBot(
 	token, appID string,
 	ctrl *controller.AppController,
 	notifier controller.NotificationService,
+	opts ...Option,
 ) (*DiscordBot, error) {
 	if appID == "" {
 		return nil, fmt.Errorf("app ID is required")
@@ -51,8 +78,21 @@ func NewDiscordBot(
 		activeMatches:   make(map[string]string),
 		playerToDiscord: make(map[string]string),
 		matchToChannel:  make(map[string]string),
+		notifyCooldown:  defaultNotifyCooldown,
+		logger:          slog.Default(),
 	}
 
+	for _, opt := range opts {
+		opt(bot)
+	}
+
+	bot.outbox = newOutbox(bot.notifyCooldown, sendWithRetry(
+		func(channelID string, msg *discordgo.MessageSend) (*discordgo.Message, error) {
+			return session.ChannelMessageSendComplex(channelID, msg)
+		},
+		bot.logger,
+	), bot.logger)
+
 	// Register interaction handler
 	session.AddHandler(bot.handleInteraction)
 
@@ -66,18 +106,18 @@ func (b *DiscordBot) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to open Discord connection: %w", err)
 	}
 
-	log.Println("Discord bot connected successfully")
+	b.logger.Info("Discord bot connected")
 
 	// Subscribe to game events
 	b.subscribeToEvents()
-	log.Println("Subscribed to game events")
+	b.logger.Debug("Subscribed to game events")
 
 	// Register slash commands
 	if err := b.registerCommands(); err != nil {
 		return fmt.Errorf("failed to register commands: %w", err)
 	}
 
-	log.Println("Slash commands registered successfully")
+	b.logger.Info("Slash commands registered")
 
 	// Wait for interrupt signal
 	stop := make(chan os.Signal, 1)
@@ -85,9 +125,9 @@ func (b *DiscordBot) Start(ctx context.Context) error {
 
 	select {
 	case <-stop:
-		log.Println("Received shutdown signal")
+		b.logger.Info("Received shutdown signal")
 	case <-ctx.Done():
-		log.Println("Context cancelled")
+		b.logger.Info("Context cancelled")
 	}
 
 	return b.Shutdown()
@@ -95,6 +135,6 @@ func (b *DiscordBot) Start(ctx context.Context) error {
 
 // Shutdown gracefully closes the Discord connection.
 func (b *DiscordBot) Shutdown() error {
-	log.Println("Shutting down Discord bot...")
+	b.logger.Info("Shutting down Discord bot")
 	return b.session.Close()
 }
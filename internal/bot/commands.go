@@ -1,9 +1,11 @@
 package bot
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
 )
 
 var commands = []*discordgo.ApplicationCommand{
@@ -15,6 +17,51 @@ var commands = []*discordgo.ApplicationCommand{
 				Name:        "host",
 				Description: "Create a new game",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "size",
+						Description: fmt.Sprintf("Board size (%d-%d, default %d)", model.MinGridSize, model.MaxGridSize, model.GridSize),
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						MinValue:    floatPtr(model.MinGridSize),
+						MaxValue:    model.MaxGridSize,
+					},
+					{
+						Name:        "preset",
+						Description: "Fleet preset (default standard)",
+						Type:        discordgo.ApplicationCommandOptionString,
+						Choices:     presetChoices(),
+					},
+				},
+			},
+			{
+				Name:        "solo",
+				Description: "Play against the built-in AI",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "difficulty",
+						Description: "How well the AI plays (default medium)",
+						Type:        discordgo.ApplicationCommandOptionString,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: dto.DifficultyEasy, Value: dto.DifficultyEasy},
+							{Name: dto.DifficultyMedium, Value: dto.DifficultyMedium},
+							{Name: dto.DifficultyHard, Value: dto.DifficultyHard},
+						},
+					},
+					{
+						Name:        "size",
+						Description: fmt.Sprintf("Board size (%d-%d, default %d)", model.MinGridSize, model.MaxGridSize, model.GridSize),
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						MinValue:    floatPtr(model.MinGridSize),
+						MaxValue:    model.MaxGridSize,
+					},
+					{
+						Name:        "preset",
+						Description: "Fleet preset (default standard)",
+						Type:        discordgo.ApplicationCommandOptionString,
+						Choices:     presetChoices(),
+					},
+				},
 			},
 			{
 				Name:        "join",
@@ -33,6 +80,13 @@ var commands = []*discordgo.ApplicationCommand{
 				Name:        "list",
 				Description: "List available matches",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "live",
+						Description: "Also show games already in progress",
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+					},
+				},
 			},
 			{
 				Name:        "place",
@@ -49,19 +103,19 @@ var commands = []*discordgo.ApplicationCommand{
 					},
 					{
 						Name:        "x",
-						Description: "X coordinate (0-9)",
+						Description: "X coordinate (0-14, within your board)",
 						Type:        discordgo.ApplicationCommandOptionInteger,
 						Required:    true,
 						MinValue:    floatPtr(0),
-						MaxValue:    9,
+						MaxValue:    model.MaxGridSize - 1,
 					},
 					{
 						Name:        "y",
-						Description: "Y coordinate (0-9)",
+						Description: "Y coordinate (0-14, within your board)",
 						Type:        discordgo.ApplicationCommandOptionInteger,
 						Required:    true,
 						MinValue:    floatPtr(0),
-						MaxValue:    9,
+						MaxValue:    model.MaxGridSize - 1,
 					},
 					{
 						Name:        "vertical",
@@ -78,19 +132,19 @@ var commands = []*discordgo.ApplicationCommand{
 				Options: []*discordgo.ApplicationCommandOption{
 					{
 						Name:        "x",
-						Description: "X coordinate (0-9)",
+						Description: "X coordinate (0-14, within your board)",
 						Type:        discordgo.ApplicationCommandOptionInteger,
 						Required:    true,
 						MinValue:    floatPtr(0),
-						MaxValue:    9,
+						MaxValue:    model.MaxGridSize - 1,
 					},
 					{
 						Name:        "y",
-						Description: "Y coordinate (0-9)",
+						Description: "Y coordinate (0-14, within your board)",
 						Type:        discordgo.ApplicationCommandOptionInteger,
 						Required:    true,
 						MinValue:    floatPtr(0),
-						MaxValue:    9,
+						MaxValue:    model.MaxGridSize - 1,
 					},
 				},
 			},
@@ -107,16 +161,32 @@ func floatPtr(f float64) *float64 {
 	return &f
 }
 
-// registerCommands registers all slash commands with Discord.
+// presetChoices offers every known fleet preset as a fixed choice.
+func presetChoices() []*discordgo.ApplicationCommandOptionChoice {
+	names := model.FleetPresetNames()
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(names))
+	for _, name := range names {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+	return choices
+}
+
+// registerCommands registers all slash commands with Discord, in the configured guild or globally.
+// The whole set is overwritten, so commands that no longer exist are removed on startup.
 func (b *DiscordBot) registerCommands() error {
-	log.Println("Registering slash commands...")
+	scope := "globally"
+	if b.guildID != "" {
+		scope = "in guild " + b.guildID
+	}
+	b.logger.Info("Registering slash commands", "scope", scope)
+
+	registered, err := b.session.ApplicationCommandBulkOverwrite(b.appID, b.guildID, commands)
+	if err != nil {
+		return err
+	}
 
-	for _, cmd := range commands {
-		_, err := b.session.ApplicationCommandCreate(b.appID, "", cmd)
-		if err != nil {
-			return err
-		}
-		log.Printf("Registered command: %s", cmd.Name)
+	for _, cmd := range registered {
+		b.logger.Debug("Registered command", "command", cmd.Name)
 	}
 
 	return nil
@@ -1,8 +1,9 @@
 package bot
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/callegarimattia/battleship/internal/dto"
@@ -20,8 +21,8 @@ func (b *DiscordBot) subscribeToEvents() {
 
 // handleGameEvent processes game events and sends notifications.
 func (b *DiscordBot) handleGameEvent(event *dto.GameEvent) {
-	// Don't notify the player who triggered the event
-	if event.TargetID == event.PlayerID {
+	// Don't notify the player who triggered the event, nor the built-in AI
+	if event.TargetID == event.PlayerID || strings.HasPrefix(event.TargetID, dto.AIPlayerPrefix) {
 		return
 	}
 
@@ -45,15 +46,8 @@ func (b *DiscordBot) handleGameEvent(event *dto.GameEvent) {
 	discordUserID := b.playerToDiscord[event.TargetID]
 	b.discordMu.RUnlock()
 
-	// Send message to channel with mention
-	content := ""
-	if discordUserID != "" {
-		content = fmt.Sprintf("<@%s>", discordUserID)
-	}
-
-	if err := b.sendChannelMessage(channelID, content, embed); err != nil {
-		log.Printf("Failed to send message to channel %s: %v", channelID, err)
-	}
+	// Queue the message; bursts of events are coalesced to stay within Discord's rate limits
+	b.outbox.enqueue(channelID, discordUserID, embed)
 }
 
 // formatEventEmbed creates an embed for the given event.
@@ -82,6 +76,14 @@ func (b *DiscordBot) formatEventEmbed(event *dto.GameEvent) *discordgo.MessageEm
 			return nil
 		}
 		coord := CoordinateToChess(data.X, data.Y)
+		// Every other player hears of the shot, but only one of them fires next
+		if data.NextTurn != event.TargetID {
+			return &discordgo.MessageEmbed{
+				Title:       "💥 Attack!",
+				Description: fmt.Sprintf("%s attacked %s. Result: %s", data.Attacker, coord, data.Result),
+				Color:       0xff9900,
+			}
+		}
 		return &discordgo.MessageEmbed{
 			Title: "💥 Your Turn!",
 			Description: fmt.Sprintf(
@@ -104,28 +106,42 @@ func (b *DiscordBot) formatEventEmbed(event *dto.GameEvent) *discordgo.MessageEm
 		if !ok {
 			return nil
 		}
-		return &discordgo.MessageEmbed{
+		embed := &discordgo.MessageEmbed{
 			Title:       "🏆 Game Over!",
 			Description: fmt.Sprintf("Winner: %s", data.Winner),
 			Color:       0xffd700,
 		}
+		if data.Winner == "" {
+			embed.Title = "🤝 Game Over: Draw!"
+			embed.Description = "Neither fleet was sunk."
+		}
+		// The fleets are no longer secret, so show where everything was
+		if reveal, err := b.ctrl.RevealAction(context.Background(), event.MatchID); err == nil {
+			embed.Fields = formatRevealFields(reveal)
+		}
+		return embed
+
+	case dto.EventMatchCancelled:
+		return &discordgo.MessageEmbed{
+			Title:       "🚫 Match Cancelled",
+			Description: "The host cancelled the match.",
+			Color:       0x808080,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("Match ID: %s", event.MatchID),
+			},
+		}
+
+	case dto.EventMatchTerminated:
+		return &discordgo.MessageEmbed{
+			Title:       "🛑 Match Terminated",
+			Description: "The server's operators ended the match.",
+			Color:       0x808080,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("Match ID: %s", event.MatchID),
+			},
+		}
 
 	default:
 		return nil
 	}
 }
-
-// sendChannelMessage sends a message to a Discord channel.
-func (b *DiscordBot) sendChannelMessage(
-	channelID, content string,
-	embed *discordgo.MessageEmbed,
-) error {
-	_, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-		Content: content,
-		Embeds:  []*discordgo.MessageEmbed{embed},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to send channel message: %w", err)
-	}
-	return nil
-}
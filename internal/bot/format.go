@@ -2,58 +2,66 @@ package bot
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
 )
 
 // CoordinateToChess converts numeric coordinates to chess-style (A-J, 1-10).
 func CoordinateToChess(x, y int) string {
-	if x < 0 || x > 9 || y < 0 || y > 9 {
-		return fmt.Sprintf("(%d,%d)", x, y)
-	}
-	col := string(rune('A' + x))
-	row := y + 1
-	return fmt.Sprintf("%s%d", col, row)
+	return model.Coordinate{X: x, Y: y}.String()
 }
 
-// ChessToCoordinate converts chess-style coordinates to numeric (0-9, 0-9).
-func ChessToCoordinate(chess string) (x, y int, err error) {
-	chess = strings.ToUpper(strings.TrimSpace(chess))
-	if len(chess) < 2 {
-		return 0, 0, fmt.Errorf("invalid coordinate format")
+// ChessToCoordinate converts chess-style coordinates to numeric ones on a board of the given size,
+// e.g. A-J and 1-10 to 0-9 on the standard board.
+func ChessToCoordinate(chess string, size int) (x, y int, err error) {
+	c, err := model.ParseCoordinateFor(chess, size)
+	if err != nil {
+		return 0, 0, err
 	}
+	return c.X, c.Y, nil
+}
 
-	col := chess[0]
-	if col < 'A' || col > 'J' {
-		return 0, 0, fmt.Errorf("column must be A-J")
+// GetShipName returns the ship name for a given size, as the model names ship types.
+func GetShipName(size int) string {
+	if t, ok := model.ShipTypeOfSize(size); ok {
+		return t.Name()
 	}
-	x = int(col - 'A')
+	return fmt.Sprintf("Ship (size %d)", size)
+}
 
-	var row int
-	_, err = fmt.Sscanf(chess[1:], "%d", &row)
-	if err != nil || row < 1 || row > 10 {
-		return 0, 0, fmt.Errorf("row must be 1-10")
+// FormatMatchStatus describes a lobby entry, e.g. "🟡 Waiting 1/2" or "🔵 Playing · 👀 3".
+func FormatMatchStatus(match dto.MatchSummary) string {
+	if match.State == dto.StateWaiting {
+		return fmt.Sprintf("🟡 Waiting %d/2", match.PlayerCount)
 	}
-	y = row - 1
 
-	return x, y, nil
+	status := "🔵 Playing"
+	if match.Watchers > 0 {
+		status += fmt.Sprintf(" · 👀 %d", match.Watchers)
+	}
+	return status
 }
 
-// GetShipName returns the ship name for a given size.
-func GetShipName(size int) string {
-	switch size {
-	case 5:
-		return "Carrier"
-	case 4:
-		return "Battleship"
-	case 3:
-		return "Cruiser"
-	case 2:
-		return "Destroyer"
-	default:
-		return fmt.Sprintf("Ship (size %d)", size)
+// formatMatchSettings renders the rules of a match so that joiners know what they sign up for.
+func formatMatchSettings(settings dto.MatchSettings) []*discordgo.MessageEmbedField {
+	fleet, _ := model.FleetPreset(settings.FleetPreset) // Already accepted by the server
+
+	return []*discordgo.MessageEmbedField{
+		{
+			Name:   "Board",
+			Value:  fmt.Sprintf("%d×%d", settings.BoardSize, settings.BoardSize),
+			Inline: true,
+		},
+		{
+			Name:   fmt.Sprintf("Fleet (%s)", settings.FleetPreset),
+			Value:  formatFleetWithNames(fleet, nil),
+			Inline: true,
+		},
 	}
 }
 
@@ -72,10 +80,10 @@ func FormatGameState(view *dto.GameView) *discordgo.MessageEmbed { //nolint:funl
 	}
 
 	// Add turn information with player ID (we don't have usernames in GameView)
-	if view.Turn != "" {
-		turnPlayer := "You"
-		if view.Enemy.ID == view.Turn {
-			turnPlayer = "Opponent"
+	if view.State == dto.StatePlaying {
+		turnPlayer := "Opponent"
+		if view.YourTurn {
+			turnPlayer = "You"
 		}
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Current Turn",
@@ -84,6 +92,14 @@ func FormatGameState(view *dto.GameView) *discordgo.MessageEmbed { //nolint:funl
 		})
 	}
 
+	if view.State == dto.StateDraw {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "🤝 Draw",
+			Value:  "Neither fleet was sunk",
+			Inline: false,
+		})
+	}
+
 	// Add winner if game is over
 	if view.Winner != "" {
 		winnerText := "You won! 🎉"
@@ -116,8 +132,8 @@ func FormatGameState(view *dto.GameView) *discordgo.MessageEmbed { //nolint:funl
 	}
 
 	// Add fleet status with ship names
-	myFleet := formatFleetWithNames(view.Me.Fleet)
-	enemyFleet := formatFleetWithNames(view.Enemy.Fleet)
+	myFleet := formatFleetWithNames(view.Me.Fleet, view.Me.Ships)
+	enemyFleet := formatFleetWithNames(view.Enemy.Fleet, view.Enemy.Ships)
 	embed.Fields = append(embed.Fields,
 		&discordgo.MessageEmbedField{
 			Name:   "🚢 Your Fleet",
@@ -134,6 +150,40 @@ func FormatGameState(view *dto.GameView) *discordgo.MessageEmbed { //nolint:funl
 	return embed
 }
 
+// formatRevealFields shows both boards of a decided match with every ship, the winner's first.
+// After a draw the host's board comes first.
+func formatRevealFields(reveal dto.RevealView) []*discordgo.MessageEmbedField {
+	rank := func(p dto.PlayerView) int {
+		if p.ID == reveal.Winner {
+			return 0
+		}
+		return 1
+	}
+	players := slices.Clone(reveal.Players)
+	slices.SortStableFunc(players, func(a, b dto.PlayerView) int { return rank(a) - rank(b) })
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(players))
+	for i, p := range players {
+		var name string
+		switch {
+		case reveal.Winner == "" && i == 0:
+			name = "⚓ Host's Board"
+		case reveal.Winner == "":
+			name = "⚓ Guest's Board"
+		case p.ID == reveal.Winner:
+			name = "🏆 Winner's Board"
+		default:
+			name = "⚓ Runner-up's Board"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   name,
+			Value:  formatBoardWithChessCoords(p.Board),
+			Inline: false,
+		})
+	}
+	return fields
+}
+
 func formatBoardWithChessCoords(board dto.BoardView) string {
 	var sb strings.Builder
 
@@ -171,16 +221,31 @@ func cellToEmoji(cell dto.CellState) string {
 	}
 }
 
-func formatFleetWithNames(fleet map[int]int) string {
+// formatFleetWithNames lists the remaining ships of a fleet by type.
+// ships holds the remaining ships by type name; without it the fleet is split into types as the model does.
+func formatFleetWithNames(fleet map[int]int, ships map[string]int) string {
 	if len(fleet) == 0 {
 		return "All ships sunk!"
 	}
 
+	if ships == nil {
+		ships = make(map[string]int)
+		for t, count := range model.FleetShipTypes(fleet) {
+			ships[t.Name()] = count
+		}
+	}
+
 	var sb strings.Builder
-	for size := 5; size >= 2; size-- {
-		if count, ok := fleet[size]; ok && count > 0 {
-			shipName := GetShipName(size)
-			fmt.Fprintf(&sb, "%s (size %d): %d\n", shipName, size, count)
+	for _, size := range slices.Backward(slices.Sorted(maps.Keys(fleet))) {
+		named := 0
+		for _, t := range model.ShipTypesOfSize(size) {
+			if count := ships[t.Name()]; count > 0 {
+				fmt.Fprintf(&sb, "%s (size %d): %d\n", t.Name(), size, count)
+				named += count
+			}
+		}
+		if rest := fleet[size] - named; rest > 0 {
+			fmt.Fprintf(&sb, "Ship (size %d): %d\n", size, rest)
 		}
 	}
 	return sb.String()
@@ -194,6 +259,8 @@ func getColorForState(state dto.GameState) int {
 		return 0x0099ff // Blue
 	case dto.StateFinished:
 		return 0x00ff00 // Green
+	case dto.StateDraw:
+		return 0xffd700 // Gold
 	default:
 		return 0x808080 // Gray
 	}
@@ -3,10 +3,11 @@ package bot
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
 )
 
 // handleInteraction is the main handler for all Discord interactions.
@@ -22,7 +23,7 @@ func (b *DiscordBot) handleInteraction(s *discordgo.Session, i *discordgo.Intera
 
 	// Get subcommand
 	if len(data.Options) == 0 {
-		respondError(s, i, "No subcommand provided")
+		b.respondError(s, i, "No subcommand provided")
 		return
 	}
 
@@ -35,7 +36,7 @@ func (b *DiscordBot) handleInteraction(s *discordgo.Session, i *discordgo.Intera
 
 	authResp, err := b.ctrl.Login(ctx, username, "discord", userID)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to authenticate: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to authenticate: %v", err))
 		return
 	}
 
@@ -44,11 +45,13 @@ func (b *DiscordBot) handleInteraction(s *discordgo.Session, i *discordgo.Intera
 	// Route to appropriate handler
 	switch subcommand.Name {
 	case "host":
-		b.handleHost(ctx, s, i, playerID)
+		b.handleHost(ctx, s, i, playerID, subcommand.Options)
+	case "solo":
+		b.handleSolo(ctx, s, i, playerID, subcommand.Options)
 	case "join":
 		b.handleJoin(ctx, s, i, playerID, subcommand.Options)
 	case "list":
-		b.handleList(ctx, s, i)
+		b.handleList(ctx, s, i, subcommand.Options)
 	case "place":
 		b.handlePlace(ctx, s, i, playerID, subcommand.Options)
 	case "attack":
@@ -56,7 +59,7 @@ func (b *DiscordBot) handleInteraction(s *discordgo.Session, i *discordgo.Intera
 	case "status":
 		b.handleStatus(ctx, s, i, playerID)
 	default:
-		respondError(s, i, "Unknown subcommand")
+		b.respondError(s, i, "Unknown subcommand")
 	}
 }
 
@@ -65,10 +68,13 @@ func (b *DiscordBot) handleHost(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	playerID string,
+	options []*discordgo.ApplicationCommandInteractionDataOption,
 ) {
-	matchID, err := b.ctrl.HostGameAction(ctx, playerID)
+	settings := matchSettingsFromOptions(options)
+
+	matchID, err := b.ctrl.HostGameAction(ctx, playerID, settings)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to create match: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to create match: %v", err))
 		return
 	}
 
@@ -82,13 +88,71 @@ func (b *DiscordBot) handleHost(
 			"Match ID: `%s`\n\nShare this ID with your opponent so they can join!",
 			matchID,
 		),
-		Color: 0x00ff00,
+		Color:  0x00ff00,
+		Fields: formatMatchSettings(settings),
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: "Use /battleship place to set up your ships",
 		},
 	}
 
-	respondEmbed(s, i, embed, false) // Public announcement
+	b.respondEmbed(s, i, embed, false) // Public announcement
+}
+
+func (b *DiscordBot) handleSolo(
+	ctx context.Context,
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	playerID string,
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+) {
+	settings := matchSettingsFromOptions(options)
+	settings.VsBot = true
+
+	matchID, err := b.ctrl.HostGameAction(ctx, playerID, settings)
+	if err != nil {
+		b.respondError(s, i, fmt.Sprintf("Failed to create match: %v", err))
+		return
+	}
+
+	discordUserID := i.Member.User.ID
+	b.registerMatch(playerID, discordUserID, matchID, i.ChannelID)
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🤖 Solo Match vs AI (%s)", settings.Difficulty),
+		Description: fmt.Sprintf(
+			"Match ID: `%s`\n\nThe AI has placed its fleet and is waiting for you.",
+			matchID,
+		),
+		Color:  0x00ff00,
+		Fields: formatMatchSettings(settings),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Use /battleship place to set up your ships; the AI fires back after each of your shots",
+		},
+	}
+
+	b.respondEmbed(s, i, embed, false) // Public announcement
+}
+
+// matchSettingsFromOptions reads the host and solo options, filling in the defaults so they can be shown back.
+func matchSettingsFromOptions(
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+) dto.MatchSettings {
+	settings := dto.MatchSettings{
+		BoardSize:   model.GridSize,
+		FleetPreset: model.PresetStandard,
+		Difficulty:  dto.DifficultyMedium,
+	}
+	for _, opt := range options {
+		switch opt.Name {
+		case "size":
+			settings.BoardSize = int(opt.IntValue())
+		case "preset":
+			settings.FleetPreset = opt.StringValue()
+		case "difficulty":
+			settings.Difficulty = opt.StringValue()
+		}
+	}
+	return settings
 }
 
 func (b *DiscordBot) handleJoin(
@@ -102,7 +166,7 @@ func (b *DiscordBot) handleJoin(
 
 	view, err := b.ctrl.JoinGameAction(ctx, matchID, playerID)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to join match: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to join match: %v", err))
 		return
 	}
 
@@ -120,17 +184,25 @@ func (b *DiscordBot) handleJoin(
 		},
 	}
 
-	respondEmbed(s, i, embed, true) // Ephemeral
+	b.respondEmbed(s, i, embed, true) // Ephemeral
 }
 
 func (b *DiscordBot) handleList(
 	ctx context.Context,
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
+	options []*discordgo.ApplicationCommandInteractionDataOption,
 ) {
-	matches, err := b.ctrl.ListGamesAction(ctx)
+	var filter dto.MatchFilter
+	for _, opt := range options {
+		if opt.Name == "live" {
+			filter.IncludeLive = opt.BoolValue()
+		}
+	}
+
+	matches, err := b.ctrl.ListGamesAction(ctx, filter)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to list matches: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to list matches: %v", err))
 		return
 	}
 
@@ -140,16 +212,16 @@ func (b *DiscordBot) handleList(
 			Description: "No matches available. Use `/battleship host` to create one!",
 			Color:       0xffaa00,
 		}
-		respondEmbed(s, i, embed, true) // Ephemeral
+		b.respondEmbed(s, i, embed, true) // Ephemeral
 		return
 	}
 
 	var description strings.Builder
 	for _, match := range matches {
-		fmt.Fprintf(&description, "**%s** - Host: %s (%d/2 players)\n",
+		fmt.Fprintf(&description, "**%s** - Host: %s · %s\n",
 			match.ID,
 			match.HostName,
-			match.PlayerCount)
+			FormatMatchStatus(match))
 	}
 
 	embed := &discordgo.MessageEmbed{
@@ -161,7 +233,7 @@ func (b *DiscordBot) handleList(
 		},
 	}
 
-	respondEmbed(s, i, embed, true) // Ephemeral
+	b.respondEmbed(s, i, embed, true) // Ephemeral
 }
 
 func (b *DiscordBot) handlePlace(
@@ -173,9 +245,9 @@ func (b *DiscordBot) handlePlace(
 ) {
 	// Get active match
 	discordUserID := i.Member.User.ID
-	matchID, ok := b.getActiveMatch(discordUserID)
+	matchID, ok := b.findActiveMatch(ctx, playerID, discordUserID, i.ChannelID)
 	if !ok {
-		respondError(
+		b.respondError(
 			s,
 			i,
 			"You are not in an active match. Use `/battleship host` or `/battleship join` first.",
@@ -196,13 +268,13 @@ func (b *DiscordBot) handlePlace(
 
 	view, err := b.ctrl.PlaceShipAction(ctx, matchID, playerID, size, x, y, vertical)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to place ship: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to place ship: %v", err))
 		return
 	}
 
 	embed := FormatGameState(&view)
 	embed.Title = "🚢 Ship Placed!"
-	respondEmbed(s, i, embed, true) // Ephemeral
+	b.respondEmbed(s, i, embed, true) // Ephemeral
 }
 
 func (b *DiscordBot) handleAttack(
@@ -214,9 +286,9 @@ func (b *DiscordBot) handleAttack(
 ) {
 	// Get active match
 	discordUserID := i.Member.User.ID
-	matchID, ok := b.getActiveMatch(discordUserID)
+	matchID, ok := b.findActiveMatch(ctx, playerID, discordUserID, i.ChannelID)
 	if !ok {
-		respondError(
+		b.respondError(
 			s,
 			i,
 			"You are not in an active match. Use `/battleship host` or `/battleship join` first.",
@@ -234,13 +306,16 @@ func (b *DiscordBot) handleAttack(
 
 	view, err := b.ctrl.AttackAction(ctx, matchID, playerID, x, y)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to attack: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to attack: %v", err))
 		return
 	}
 
 	embed := FormatGameState(&view)
 	embed.Title = fmt.Sprintf("💥 Attack at (%d, %d)!", x, y)
-	respondEmbed(s, i, embed, true) // Ephemeral
+	if view.NearbyShip {
+		embed.Description = "🌡️ Getting warmer: a ship is right next to that miss."
+	}
+	b.respondEmbed(s, i, embed, true) // Ephemeral
 }
 
 func (b *DiscordBot) handleStatus(
@@ -251,9 +326,9 @@ func (b *DiscordBot) handleStatus(
 ) {
 	// Get active match
 	discordUserID := i.Member.User.ID
-	matchID, ok := b.getActiveMatch(discordUserID)
+	matchID, ok := b.findActiveMatch(ctx, playerID, discordUserID, i.ChannelID)
 	if !ok {
-		respondError(
+		b.respondError(
 			s,
 			i,
 			"You are not in an active match. Use `/battleship host` or `/battleship join` first.",
@@ -263,17 +338,17 @@ func (b *DiscordBot) handleStatus(
 
 	view, err := b.ctrl.GetGameStateAction(ctx, matchID, playerID)
 	if err != nil {
-		respondError(s, i, fmt.Sprintf("Failed to get game state: %v", err))
+		b.respondError(s, i, fmt.Sprintf("Failed to get game state: %v", err))
 		return
 	}
 
 	embed := FormatGameState(&view)
-	respondEmbed(s, i, embed, true) // Ephemeral
+	b.respondEmbed(s, i, embed, true) // Ephemeral
 }
 
 // Helper functions for responding
 
-func respondEmbed(
+func (b *DiscordBot) respondEmbed(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	embed *discordgo.MessageEmbed,
@@ -292,15 +367,15 @@ func respondEmbed(
 		},
 	})
 	if err != nil {
-		log.Printf("Failed to respond to interaction: %v", err)
+		b.logger.Error("Failed to respond to interaction", "error", err)
 	}
 }
 
-func respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+func (b *DiscordBot) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
 	embed := &discordgo.MessageEmbed{
 		Title:       "❌ Error",
 		Description: message,
 		Color:       0xff0000,
 	}
-	respondEmbed(s, i, embed, true) // Errors are always ephemeral
+	b.respondEmbed(s, i, embed, true) // Errors are always ephemeral
 }
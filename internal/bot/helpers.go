@@ -1,5 +1,7 @@
 package bot
 
+import "context"
+
 // Helper functions for tracking players, matches, and channels
 
 // trackPlayer associates a player ID with their Discord user ID.
@@ -31,6 +33,34 @@ func (b *DiscordBot) getActiveMatch(discordUserID string) (string, bool) {
 	return matchID, ok
 }
 
+// findActiveMatch returns the active match for a Discord user.
+// When the bot has lost track of it (e.g. after a restart), it asks the game service
+// and tracks the match again, using the current channel if none is known for it.
+func (b *DiscordBot) findActiveMatch(
+	ctx context.Context,
+	playerID, discordUserID, channelID string,
+) (string, bool) {
+	if matchID, ok := b.getActiveMatch(discordUserID); ok {
+		return matchID, true
+	}
+
+	matchID, err := b.ctrl.ActiveMatchAction(ctx, playerID)
+	if err != nil {
+		return "", false
+	}
+
+	b.trackPlayer(playerID, discordUserID)
+	b.trackMatch(discordUserID, matchID)
+
+	b.channelMu.Lock()
+	if _, ok := b.matchToChannel[matchID]; !ok {
+		b.matchToChannel[matchID] = channelID
+	}
+	b.channelMu.Unlock()
+
+	return matchID, true
+}
+
 // registerMatch is a convenience function that tracks player, match, and channel.
 func (b *DiscordBot) registerMatch(playerID, discordUserID, matchID, channelID string) {
 	b.trackPlayer(playerID, discordUserID)
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscordBot_ConcurrentTracking mimics Discord delivering many interactions at once.
+// Run with -race to catch unguarded accesses to the tracking maps.
+func TestDiscordBot_ConcurrentTracking(t *testing.T) {
+	t.Parallel()
+
+	notifier := service.NewNotificationService()
+	memory := service.NewMemoryService(notifier)
+	t.Cleanup(memory.Close)
+	ctrl := controller.NewAppController(service.NewIdentityService("secret"), memory, memory, notifier)
+
+	b, err := NewDiscordBot("token", "app-id", ctrl, notifier)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	const players = 64
+
+	var wg sync.WaitGroup
+	for n := range players {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			playerID := fmt.Sprintf("player-%d", n)
+			discordUserID := fmt.Sprintf("discord-%d", n)
+			channelID := fmt.Sprintf("channel-%d", n)
+
+			matchID, err := ctrl.HostGameAction(ctx, playerID, dto.MatchSettings{})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// Half of the players go through /battleship host, the other half are recovered
+			// from the game service as if the bot had been restarted.
+			if n%2 == 0 {
+				b.registerMatch(playerID, discordUserID, matchID, channelID)
+			}
+
+			got, ok := b.findActiveMatch(ctx, playerID, discordUserID, channelID)
+			assert.True(t, ok)
+			assert.Equal(t, matchID, got)
+
+			// Events for untracked matches read the maps without sending anything.
+			b.handleGameEvent(&dto.GameEvent{
+				Type:      dto.EventShipPlaced,
+				MatchID:   "untracked",
+				PlayerID:  playerID,
+				TargetID:  "someone-else",
+				Timestamp: time.Now(),
+			})
+		}()
+	}
+	wg.Wait()
+
+	b.matchMu.RLock()
+	assert.Len(t, b.activeMatches, players)
+	b.matchMu.RUnlock()
+
+	b.discordMu.RLock()
+	assert.Len(t, b.playerToDiscord, players)
+	b.discordMu.RUnlock()
+
+	b.channelMu.RLock()
+	assert.Len(t, b.matchToChannel, players)
+	b.channelMu.RUnlock()
+}
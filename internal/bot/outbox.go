@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// defaultNotifyCooldown is the minimum time between two notification posts in the same channel.
+	defaultNotifyCooldown = time.Second
+	// maxEmbedsPerMessage is the most embeds Discord accepts in a single message.
+	maxEmbedsPerMessage = 10
+	// maxSendAttempts bounds the tries for a rate-limited message.
+	maxSendAttempts = 4
+	// initialSendBackoff is the wait before the first retry when Discord gives no Retry-After.
+	initialSendBackoff = 500 * time.Millisecond
+)
+
+// channelOutbox collects the notifications waiting to be posted in one channel.
+type channelOutbox struct {
+	embeds    []*discordgo.MessageEmbed
+	mentions  []string // Discord user IDs, without duplicates
+	lastSent  time.Time
+	scheduled bool // A flush is already pending
+}
+
+// outbox throttles notification posts per channel. The first notification in a quiet channel
+// goes out right away; the ones arriving during the cooldown are coalesced into a single message.
+type outbox struct {
+	cooldown time.Duration
+	send     func(channelID string, msg *discordgo.MessageSend) error
+	channels map[string]*channelOutbox
+	mu       sync.Mutex
+	logger   *slog.Logger
+}
+
+func newOutbox(
+	cooldown time.Duration,
+	send func(string, *discordgo.MessageSend) error,
+	logger *slog.Logger,
+) *outbox {
+	return &outbox{
+		cooldown: cooldown,
+		send:     send,
+		channels: make(map[string]*channelOutbox),
+		logger:   logger,
+	}
+}
+
+// enqueue schedules an embed for the channel, mentioning the Discord user if one is given.
+func (o *outbox) enqueue(channelID, discordUserID string, embed *discordgo.MessageEmbed) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ch, ok := o.channels[channelID]
+	if !ok {
+		ch = &channelOutbox{}
+		o.channels[channelID] = ch
+	}
+
+	ch.embeds = append(ch.embeds, embed)
+	if discordUserID != "" && !slices.Contains(ch.mentions, discordUserID) {
+		ch.mentions = append(ch.mentions, discordUserID)
+	}
+
+	if ch.scheduled {
+		return
+	}
+	ch.scheduled = true
+	time.AfterFunc(max(o.cooldown-time.Since(ch.lastSent), 0), func() { o.flush(channelID) })
+}
+
+// flush posts everything queued for the channel, splitting it into as few messages as Discord allows.
+func (o *outbox) flush(channelID string) {
+	o.mu.Lock()
+	ch := o.channels[channelID]
+	embeds, mentions := ch.embeds, ch.mentions
+	ch.embeds, ch.mentions = nil, nil
+	ch.scheduled = false
+	ch.lastSent = time.Now()
+	o.mu.Unlock()
+
+	content := make([]string, 0, len(mentions))
+	for _, id := range mentions {
+		content = append(content, fmt.Sprintf("<@%s>", id))
+	}
+
+	for batch := range slices.Chunk(embeds, maxEmbedsPerMessage) {
+		msg := &discordgo.MessageSend{
+			Content: strings.Join(content, " "),
+			Embeds:  batch,
+		}
+		if err := o.send(channelID, msg); err != nil {
+			o.logger.Error("Failed to send message", "channel_id", channelID, "error", err)
+		}
+		content = nil // Mention the players once
+	}
+}
+
+// sendWithRetry posts a message, waiting and retrying while Discord rate-limits the bot.
+func sendWithRetry(
+	send func(channelID string, msg *discordgo.MessageSend) (*discordgo.Message, error),
+	logger *slog.Logger,
+) func(string, *discordgo.MessageSend) error {
+	return func(channelID string, msg *discordgo.MessageSend) error {
+		backoff := initialSendBackoff
+		for attempt := 1; ; attempt++ {
+			_, err := send(channelID, msg)
+			if err == nil {
+				return nil
+			}
+
+			wait, limited := retryAfter(err, backoff)
+			if !limited || attempt == maxSendAttempts {
+				return fmt.Errorf("failed to send channel message: %w", err)
+			}
+
+			logger.Warn("Rate limited, retrying", "channel_id", channelID, "wait", wait)
+			time.Sleep(wait)
+			backoff *= 2
+		}
+	}
+}
+
+// retryAfter reports whether err is a Discord rate limit and how long to wait before retrying.
+// The wait Discord asks for is used when available, the fallback otherwise.
+func retryAfter(err error, fallback time.Duration) (time.Duration, bool) {
+	var rateLimit *discordgo.RateLimitError
+	if errors.As(err, &rateLimit) {
+		if rateLimit.RateLimit != nil && rateLimit.TooManyRequests != nil && rateLimit.RetryAfter > 0 {
+			return rateLimit.RetryAfter, true
+		}
+		return fallback, true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil &&
+		restErr.Response.StatusCode == http.StatusTooManyRequests {
+		return fallback, true
+	}
+
+	return 0, false
+}
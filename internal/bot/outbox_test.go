@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSender collects the messages an outbox sends.
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []*discordgo.MessageSend
+	at   []time.Time
+}
+
+func (r *recordingSender) send(_ string, msg *discordgo.MessageSend) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, msg)
+	r.at = append(r.at, time.Now())
+	return nil
+}
+
+func (r *recordingSender) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sent)
+}
+
+func TestOutbox_CoalescesBursts(t *testing.T) {
+	t.Parallel()
+
+	const cooldown = 100 * time.Millisecond
+	rec := &recordingSender{}
+	o := newOutbox(cooldown, rec.send, slog.New(slog.DiscardHandler))
+
+	o.enqueue("chan", "alice", &discordgo.MessageEmbed{Title: "first"})
+	assert.Eventually(t, func() bool { return rec.count() == 1 }, time.Second, 5*time.Millisecond,
+		"the first notification in a quiet channel goes out right away")
+
+	for range 12 {
+		o.enqueue("chan", "alice", &discordgo.MessageEmbed{Title: "burst"})
+	}
+	o.enqueue("chan", "bob", &discordgo.MessageEmbed{Title: "burst"})
+
+	assert.Eventually(t, func() bool { return rec.count() == 3 }, time.Second, 5*time.Millisecond)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	// Allow for the time between the first flush starting and the send being recorded
+	assert.GreaterOrEqual(t, rec.at[1].Sub(rec.at[0]), cooldown*9/10, "the burst waits for the cooldown")
+	assert.Len(t, rec.sent[1].Embeds, maxEmbedsPerMessage)
+	assert.Len(t, rec.sent[2].Embeds, 3)
+	assert.Equal(t, "<@alice> <@bob>", rec.sent[1].Content)
+	assert.Empty(t, rec.sent[2].Content, "players are mentioned once per flush")
+}
+
+func TestSendWithRetry(t *testing.T) {
+	t.Parallel()
+
+	rateLimited := &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{
+		TooManyRequests: &discordgo.TooManyRequests{RetryAfter: time.Millisecond},
+	}}
+
+	t.Run("Retries rate limits", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		send := sendWithRetry(func(string, *discordgo.MessageSend) (*discordgo.Message, error) {
+			calls++
+			if calls < 3 {
+				return nil, rateLimited
+			}
+			return &discordgo.Message{}, nil
+		}, slog.New(slog.DiscardHandler))
+
+		require.NoError(t, send("chan", &discordgo.MessageSend{}))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Gives up after the last attempt", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		send := sendWithRetry(func(string, *discordgo.MessageSend) (*discordgo.Message, error) {
+			calls++
+			return nil, rateLimited
+		}, slog.New(slog.DiscardHandler))
+
+		assert.Error(t, send("chan", &discordgo.MessageSend{}))
+		assert.Equal(t, maxSendAttempts, calls)
+	})
+
+	t.Run("Does not retry other errors", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		send := sendWithRetry(func(string, *discordgo.MessageSend) (*discordgo.Message, error) {
+			calls++
+			return nil, errors.New("missing permissions")
+		}, slog.New(slog.DiscardHandler))
+
+		assert.Error(t, send("chan", &discordgo.MessageSend{}))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tooMany := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}
+	wait, limited := retryAfter(tooMany, time.Second)
+	assert.True(t, limited)
+	assert.Equal(t, time.Second, wait, "bare 429s fall back to the backoff")
+
+	forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	_, limited = retryAfter(forbidden, time.Second)
+	assert.False(t, limited)
+}
@@ -0,0 +1,49 @@
+// Package buildinfo reports which build of the server is running.
+// Version, Commit and BuildTime are set at build time with -ldflags, for example:
+//
+//	go build -ldflags "-X github.com/callegarimattia/battleship/internal/buildinfo.Version=v1.2.0" ./cmd/server
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time with -ldflags "-X". Builds that leave them unset, such as go run, keep these defaults.
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the running build. A commit or build time left unset falls back to what the
+// Go toolchain stamped from version control, if anything.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "unknown":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "unknown":
+				info.BuildTime = s.Value
+			}
+		}
+	}
+
+	return info
+}
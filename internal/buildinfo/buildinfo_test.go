@@ -0,0 +1,29 @@
+package buildinfo_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/buildinfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	info := buildinfo.Get()
+	assert.Equal(t, "dev", info.Version, "an unstamped build reports the default version")
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.NotEmpty(t, info.Commit)
+	assert.NotEmpty(t, info.BuildTime)
+
+	buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = "v1.2.0", "abc123", "2026-01-02T03:04:05Z"
+	t.Cleanup(func() {
+		buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = "dev", "unknown", "unknown"
+	})
+
+	assert.Equal(t, buildinfo.Info{
+		Version:   "v1.2.0",
+		Commit:    "abc123",
+		BuildTime: "2026-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+	}, buildinfo.Get())
+}
@@ -3,21 +3,45 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/callegarimattia/battleship/internal/dto"
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// How long the WebSocket may stay silent, pings included, before the server is considered gone
+	wsSilenceTimeout = 90 * time.Second
+	// Time allowed to answer a ping
+	wsWriteWait = 10 * time.Second
+)
+
+// ErrUnauthorized is returned when the server rejects the token, e.g. because it expired.
+var ErrUnauthorized = errors.New("not logged in")
+
 type Client struct {
 	BaseURL string
 	Token   string
+	APIKey  string // Sent by trusted integrations for platform logins
 	HTTP    *http.Client
+
+	// Last game state seen per match, revalidated with If-None-Match
+	states   map[string]cachedState
+	statesMu sync.Mutex
+}
+
+type cachedState struct {
+	etag string
+	view dto.GameView
 }
 
 func New(baseURL string) *Client {
@@ -27,8 +51,8 @@ func New(baseURL string) *Client {
 	}
 }
 
-// Helper for authorized requests
-func (c *Client) do(method, path string, body, dest any) error {
+// Helper for building authorized requests
+func (c *Client) newRequest(method, path string, body any) (*http.Request, error) {
 	var bodyReader *bytes.Buffer
 	if body != nil {
 		jsonBody, _ := json.Marshal(body)
@@ -39,21 +63,37 @@ func (c *Client) do(method, path string, body, dest any) error {
 
 	req, err := http.NewRequest(method, c.BaseURL+path, bodyReader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	if c.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 	}
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+
+	return req, nil
+}
+
+// Helper for authorized requests
+func (c *Client) do(method, path string, body, dest any) error {
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return err
+	}
 
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
 		return err
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("API Error: %d", resp.StatusCode)
+		return apiError(resp)
 	}
 
 	if dest != nil {
@@ -63,6 +103,33 @@ func (c *Client) do(method, path string, body, dest any) error {
 	return resp.Body.Close()
 }
 
+// APIError is an error response from the server.
+type APIError struct {
+	StatusCode int
+	Message    string        // The server's explanation, if it gave one
+	Problems   []string      // Every problem found, when the request failed validation
+	Code       string        // The machine-readable code, e.g. dto.ErrorCodeRateLimited
+	RetryAfter time.Duration // How long to wait before trying again, when the server said
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("API Error: %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API Error: %d", e.StatusCode)
+}
+
+// apiError turns an error response into an *APIError, keeping the server's explanation when there is one.
+func apiError(resp *http.Response) error {
+	var body dto.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: body.Message, Problems: body.Problems, Code: body.Code}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		apiErr.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return apiErr
+}
+
 // --- Auth ---
 
 func (c *Client) Login(username string) (*dto.AuthResponse, error) {
@@ -75,19 +142,73 @@ func (c *Client) Login(username string) (*dto.AuthResponse, error) {
 	return &res, err
 }
 
+// PlatformLogin logs in a user of another platform on behalf of a trusted integration.
+// The client must carry the server's API key.
+func (c *Client) PlatformLogin(username, source, externalID string) (*dto.AuthResponse, error) {
+	req := map[string]string{
+		"username":    username,
+		"source":      source,
+		"external_id": externalID,
+	}
+	var res dto.AuthResponse
+	err := c.do("POST", "/login/platform", req, &res)
+	if err == nil {
+		c.Token = res.Token
+	}
+	return &res, err
+}
+
+// Me returns the user the current token belongs to, or ErrUnauthorized if the token is no longer valid.
+func (c *Client) Me() (*dto.User, error) {
+	var user dto.User
+	err := c.do("GET", "/me", nil, &user)
+	return &user, err
+}
+
 // --- Lobby ---
 
-func (c *Client) ListMatches() ([]dto.MatchSummary, error) {
+// ListMatches lists the matches waiting for an opponent, and the ones in progress if the filter asks for them.
+func (c *Client) ListMatches(filter dto.MatchFilter) ([]dto.MatchSummary, error) {
+	path := "/matches"
+	if filter.IncludeLive {
+		path += "?live=true"
+	}
 	var matches []dto.MatchSummary
-	err := c.do("GET", "/matches", nil, &matches)
+	err := c.do("GET", path, nil, &matches)
 	return matches, err
 }
 
-func (c *Client) CreateMatch() (string, error) {
+// Scoreboard fetches the live overview of the matches in progress.
+func (c *Client) Scoreboard() ([]dto.ScoreboardEntry, error) {
+	var entries []dto.ScoreboardEntry
+	err := c.do("GET", "/scoreboard", nil, &entries)
+	return entries, err
+}
+
+// PracticeBoard fetches a sample placed board for the board size and fleet preset of the settings.
+func (c *Client) PracticeBoard(settings dto.MatchSettings) (*dto.BoardView, error) {
+	query := url.Values{}
+	if settings.BoardSize != 0 {
+		query.Set("board_size", strconv.Itoa(settings.BoardSize))
+	}
+	if settings.FleetPreset != "" {
+		query.Set("fleet_preset", settings.FleetPreset)
+	}
+	path := "/practice-board"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var board dto.BoardView
+	err := c.do("GET", path, nil, &board)
+	return &board, err
+}
+
+func (c *Client) CreateMatch(settings dto.MatchSettings) (string, error) {
 	var res struct {
 		MatchID string `json:"match_id"`
 	}
-	err := c.do("POST", "/matches", nil, &res)
+	err := c.do("POST", "/matches", settings, &res)
 	return res.MatchID, err
 }
 
@@ -97,12 +218,104 @@ func (c *Client) JoinMatch(matchID string) (*dto.GameView, error) {
 	return &game, err
 }
 
+// ResumeMatch takes back the user's seat in a match with the resume token of its previous holder.
+func (c *Client) ResumeMatch(matchID, token string) (*dto.GameView, error) {
+	var game dto.GameView
+	req := map[string]string{"resume_token": token}
+	err := c.do("POST", fmt.Sprintf("/matches/%s/resume", matchID), req, &game)
+	return &game, err
+}
+
+// ActiveMatch returns the ID of the unfinished match the current user is in.
+// It fails with a 404 *APIError when there is none.
+func (c *Client) ActiveMatch() (string, error) {
+	var res struct {
+		MatchID string `json:"match_id"`
+	}
+	err := c.do("GET", "/me/match", nil, &res)
+	return res.MatchID, err
+}
+
+// CancelMatch removes a match created by the current user.
+func (c *Client) CancelMatch(matchID string) error {
+	return c.do("DELETE", fmt.Sprintf("/matches/%s", matchID), nil, nil)
+}
+
 // --- Game ---
 
+// GetGameState fetches the game state, reusing the cached copy when the server reports it unchanged.
 func (c *Client) GetGameState(matchID string) (*dto.GameView, error) {
+	req, err := c.newRequest("GET", fmt.Sprintf("/matches/%s", matchID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.statesMu.Lock()
+	cached, hasCached := c.states[matchID]
+	c.statesMu.Unlock()
+
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		game := cached.view
+		return &game, nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, apiError(resp)
+	}
+
 	var game dto.GameView
-	err := c.do("GET", fmt.Sprintf("/matches/%s", matchID), nil, &game)
-	return &game, err
+	if err := json.NewDecoder(resp.Body).Decode(&game); err != nil {
+		return nil, err
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.statesMu.Lock()
+		if c.states == nil {
+			c.states = make(map[string]cachedState)
+		}
+		c.states[matchID] = cachedState{etag: etag, view: game}
+		c.statesMu.Unlock()
+	}
+
+	return &game, nil
+}
+
+// Events returns the events of the match visible to the current user since the given game version.
+func (c *Client) Events(matchID string, since int) ([]dto.GameEvent, error) {
+	var events []dto.GameEvent
+	err := c.do("GET", fmt.Sprintf("/matches/%s/events?since=%d", matchID, since), nil, &events)
+	return events, err
+}
+
+// MatchRecord downloads the move history of a match as JSON.
+func (c *Client) MatchRecord(matchID string) (*dto.MatchRecord, error) {
+	var record dto.MatchRecord
+	err := c.do("GET", fmt.Sprintf("/matches/%s/export?format=json", matchID), nil, &record)
+	return &record, err
+}
+
+// Reveal fetches both fleets of a won match.
+func (c *Client) Reveal(matchID string) (*dto.RevealView, error) {
+	var reveal dto.RevealView
+	err := c.do("GET", fmt.Sprintf("/matches/%s/reveal", matchID), nil, &reveal)
+	return &reveal, err
+}
+
+// GetRules fetches how a match is played: its board size, fleets and options.
+func (c *Client) GetRules(matchID string) (*dto.Rules, error) {
+	var rules dto.Rules
+	err := c.do("GET", fmt.Sprintf("/matches/%s/rules", matchID), nil, &rules)
+	return &rules, err
 }
 
 func (c *Client) PlaceShip(matchID string, size, x, y int, vertical bool) (*dto.GameView, error) {
@@ -117,6 +330,63 @@ func (c *Client) PlaceShip(matchID string, size, x, y int, vertical bool) (*dto.
 	return &game, err
 }
 
+// PlaceShipType places a ship chosen by its type name, e.g. "Submarine".
+func (c *Client) PlaceShipType(matchID, shipType string, x, y int, vertical bool) (*dto.GameView, error) {
+	var game dto.GameView
+	req := map[string]any{
+		"ship":     shipType,
+		"x":        x,
+		"y":        y,
+		"vertical": vertical,
+	}
+	err := c.do("POST", fmt.Sprintf("/matches/%s/place", matchID), req, &game)
+	return &game, err
+}
+
+// ValidatePlacement asks the server whether a ship placement would be accepted, without making it.
+func (c *Client) ValidatePlacement(matchID string, size, x, y int, vertical bool) (*dto.PlacementCheck, error) {
+	var check dto.PlacementCheck
+	req := map[string]any{
+		"size":     size,
+		"x":        x,
+		"y":        y,
+		"vertical": vertical,
+	}
+	err := c.do("POST", fmt.Sprintf("/matches/%s/validate-placement", matchID), req, &check)
+	return &check, err
+}
+
+// ValidateAttack asks the server whether a shot would be fired, without firing it.
+// An empty target stands for the sole opponent.
+func (c *Client) ValidateAttack(matchID, target string, x, y int) (*dto.AttackCheck, error) {
+	var check dto.AttackCheck
+	req := map[string]any{
+		"target": target,
+		"x":      x,
+		"y":      y,
+	}
+	err := c.do("POST", fmt.Sprintf("/matches/%s/validate-attack", matchID), req, &check)
+	return &check, err
+}
+
+// LegalMoves lists the cells the player may attack on their turn. The target names the board
+// in a free-for-all and may be left empty otherwise.
+func (c *Client) LegalMoves(matchID, targetID string) (*dto.LegalMoves, error) {
+	var moves dto.LegalMoves
+	path := fmt.Sprintf("/matches/%s/legal-moves", matchID)
+	if targetID != "" {
+		path += "?target=" + url.QueryEscape(targetID)
+	}
+	err := c.do("GET", path, nil, &moves)
+	return &moves, err
+}
+
+func (c *Client) Ready(matchID string) (*dto.GameView, error) {
+	var game dto.GameView
+	err := c.do("POST", fmt.Sprintf("/matches/%s/ready", matchID), nil, &game)
+	return &game, err
+}
+
 func (c *Client) Attack(matchID string, x, y int) (*dto.GameView, error) {
 	var game dto.GameView
 	req := map[string]any{
@@ -127,9 +397,196 @@ func (c *Client) Attack(matchID string, x, y int) (*dto.GameView, error) {
 	return &game, err
 }
 
-// SubscribeToMatch connects to the WebSocket endpoint and returns a channel that signals updates.
+// AttackAtVersion fires only if the game is still at the given version, so that retrying after
+// a timeout cannot fire a second shot. The server answers 409 Conflict once the game has moved on.
+func (c *Client) AttackAtVersion(matchID string, x, y, version int) (*dto.GameView, error) {
+	var game dto.GameView
+	req := map[string]any{
+		"x":       x,
+		"y":       y,
+		"version": version,
+	}
+	err := c.do("POST", fmt.Sprintf("/matches/%s/attack", matchID), req, &game)
+	return &game, err
+}
+
+// AttackTarget fires at the board of the named player, for matches with more than one opponent.
+// A nil version fires whatever the game version, as Attack does.
+func (c *Client) AttackTarget(matchID, targetID string, x, y int, version *int) (*dto.GameView, error) {
+	var game dto.GameView
+	req := map[string]any{
+		"target": targetID,
+		"x":      x,
+		"y":      y,
+	}
+	if version != nil {
+		req["version"] = *version
+	}
+	err := c.do("POST", fmt.Sprintf("/matches/%s/attack", matchID), req, &game)
+	return &game, err
+}
+
 // SubscribeToMatch connects to the WebSocket endpoint and returns a channel that receives game state updates.
 func (c *Client) SubscribeToMatch(matchID string) (<-chan *dto.WSEvent, error) {
+	return c.SubscribeToMatchContext(context.Background(), matchID)
+}
+
+// SubscribeToMatchContext is like SubscribeToMatch, but closes the connection once ctx is done.
+// Unlike Subscribe it does not reconnect: the channel is closed as soon as the connection is.
+func (c *Client) SubscribeToMatchContext(ctx context.Context, matchID string) (<-chan *dto.WSEvent, error) {
+	sub, err := c.subscribe(ctx, matchID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return sub.Events, nil
+}
+
+// SubscriptionState is where the connection of a Subscription stands.
+type SubscriptionState int
+
+const (
+	SubscriptionConnected    SubscriptionState = iota // Events are streaming
+	SubscriptionReconnecting                          // The connection dropped and is being dialed again
+	SubscriptionClosed                                // The subscription ended, see SubscriptionStatus.Close
+)
+
+// SubscriptionStatus reports a change in the connection of a Subscription.
+type SubscriptionStatus struct {
+	State   SubscriptionState
+	Attempt int         // The reconnection attempt, from 1, while reconnecting
+	Close   StreamClose // Why the stream ended, once closed
+}
+
+// StreamClose tells why a match stream ended.
+type StreamClose struct {
+	// Code is the WebSocket close code, or websocket.CloseAbnormalClosure when the connection dropped
+	// without a close frame and could not be dialed again.
+	Code int
+	// Reason is one of the dto.StreamClosed reasons the server gives, or empty when it gave none.
+	Reason string
+}
+
+// Subscription streams the events of a match over a WebSocket.
+// When the connection drops without the server closing it, it is dialed again, a few times,
+// asking the server to replay the events missed in between.
+type Subscription struct {
+	// Events delivers the server's messages in order, and is closed once the subscription ends.
+	Events <-chan *dto.WSEvent
+	// Status reports the connection coming and going. Its last status is SubscriptionClosed,
+	// sent before Events is closed, after which it is closed too.
+	// A reader that falls behind only misses the older statuses.
+	Status <-chan SubscriptionStatus
+
+	events chan *dto.WSEvent
+	status chan SubscriptionStatus
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close ends the subscription and waits until its connection is closed. It may be called more than once.
+func (s *Subscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// report sends a status without ever blocking, dropping the oldest one the reader has not taken yet.
+func (s *Subscription) report(status SubscriptionStatus) {
+	for {
+		select {
+		case s.status <- status:
+			return
+		default:
+		}
+		select {
+		case <-s.status:
+		default:
+		}
+	}
+}
+
+const (
+	// How many times a dropped subscription is dialed again before giving up
+	wsReconnectAttempts = 5
+	// Time between two reconnection attempts
+	wsReconnectDelay = 2 * time.Second
+)
+
+// Subscribe connects to the match's WebSocket endpoint. The subscription lasts until the server
+// closes the stream, the connection cannot be dialed again, ctx is done or it is closed.
+func (c *Client) Subscribe(ctx context.Context, matchID string) (*Subscription, error) {
+	return c.subscribe(ctx, matchID, wsReconnectAttempts)
+}
+
+// subscribe dials the match's stream and pumps it, dialing again up to reconnects times in a row
+// when the connection drops.
+func (c *Client) subscribe(ctx context.Context, matchID string, reconnects int) (*Subscription, error) {
+	conn, err := c.dialMatch(ctx, matchID, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &Subscription{
+		events: make(chan *dto.WSEvent, 16),
+		status: make(chan SubscriptionStatus, 4),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	sub.Events, sub.Status = sub.events, sub.status
+	sub.report(SubscriptionStatus{State: SubscriptionConnected})
+
+	go func() {
+		defer close(sub.done)
+		defer close(sub.status)
+		defer close(sub.events)
+		defer cancel()
+
+		version := -1 // Game version of the last message, to replay what a reconnection missed
+		for {
+			closed := pumpMatch(ctx, conn, sub.events, &version)
+			if closed.Code != websocket.CloseAbnormalClosure || ctx.Err() != nil {
+				sub.report(SubscriptionStatus{State: SubscriptionClosed, Close: closed})
+				return
+			}
+
+			if conn = c.redialMatch(ctx, sub, matchID, version, reconnects); conn == nil {
+				sub.report(SubscriptionStatus{State: SubscriptionClosed, Close: closed})
+				return
+			}
+			sub.report(SubscriptionStatus{State: SubscriptionConnected})
+		}
+	}()
+
+	return sub, nil
+}
+
+// redialMatch dials the match's stream again after the connection dropped, reporting each attempt.
+// It returns nil once the attempts are used up or ctx is done.
+func (c *Client) redialMatch(
+	ctx context.Context,
+	sub *Subscription,
+	matchID string,
+	since, attempts int,
+) *websocket.Conn {
+	for attempt := 1; attempt <= attempts; attempt++ {
+		sub.report(SubscriptionStatus{State: SubscriptionReconnecting, Attempt: attempt})
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wsReconnectDelay):
+		}
+
+		if conn, err := c.dialMatch(ctx, matchID, since); err == nil {
+			return conn
+		}
+	}
+	return nil
+}
+
+// dialMatch opens the match's WebSocket. A non-negative since asks the server to first replay
+// the events that happened after that game version.
+func (c *Client) dialMatch(ctx context.Context, matchID string, since int) (*websocket.Conn, error) {
 	// Determine WS scheme
 	scheme := "ws"
 	if strings.HasPrefix(c.BaseURL, "https") {
@@ -142,35 +599,67 @@ func (c *Client) SubscribeToMatch(matchID string) (<-chan *dto.WSEvent, error) {
 	}
 	u.Scheme = scheme
 	u.Path = fmt.Sprintf("/matches/%s/ws", matchID)
+	if since >= 0 {
+		u.RawQuery = url.Values{"since": {strconv.Itoa(since)}}.Encode()
+	}
 
 	header := http.Header{}
 	if c.Token != "" {
 		header.Set("Authorization", "Bearer "+c.Token)
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), header)
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
 	if err != nil {
 		return nil, err
 	}
+	return conn, nil
+}
 
-	updateChan := make(chan *dto.WSEvent, 1)
+// pumpMatch relays the connection's messages to events until it ends, keeping track of the game
+// version they reached, and returns why it ended. Once ctx is done the connection is closed
+// with a close frame.
+func pumpMatch(ctx context.Context, conn *websocket.Conn, events chan<- *dto.WSEvent, version *int) StreamClose {
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+		_ = conn.Close()
+	})
+	defer stop()
+	defer func() { _ = conn.Close() }()
 
-	// Pump
-	go func() {
-		defer func() { _ = conn.Close() }()
-		defer close(updateChan)
-		for {
-			var evt dto.WSEvent
-			if err := conn.ReadJSON(&evt); err != nil {
-				return
+	// The server pings periodically: answer and treat a silent server as gone
+	_ = conn.SetReadDeadline(time.Now().Add(wsSilenceTimeout))
+	conn.SetPingHandler(func(data string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(wsSilenceTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(wsWriteWait))
+	})
+
+	for {
+		var evt dto.WSEvent
+		if err := conn.ReadJSON(&evt); err != nil {
+			if ctx.Err() != nil {
+				return StreamClose{Code: websocket.CloseNormalClosure}
 			}
-			// Signal update
-			select {
-			case updateChan <- &evt:
-			default:
+			var closeErr *websocket.CloseError
+			// A dropped connection is reported as an abnormal closure too, with a text the server never sent
+			if errors.As(err, &closeErr) && closeErr.Code != websocket.CloseAbnormalClosure {
+				return StreamClose{Code: closeErr.Code, Reason: closeErr.Text}
 			}
+			return StreamClose{Code: websocket.CloseAbnormalClosure}
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsSilenceTimeout))
+
+		switch {
+		case evt.Payload != nil:
+			*version = max(*version, evt.Payload.Version)
+		case evt.Event != nil:
+			*version = max(*version, evt.Event.Version)
 		}
-	}()
 
-	return updateChan, nil
+		select {
+		case events <- &evt:
+		case <-ctx.Done():
+			return StreamClose{Code: websocket.CloseNormalClosure}
+		}
+	}
 }
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStreamServer serves match streams, handing each connection to the next handler in turn
+// along with the since query parameter it was dialed with. Connections beyond the handlers are refused.
+func newStreamServer(t *testing.T, handlers ...func(ws *websocket.Conn, since string)) *httptest.Server {
+	t.Helper()
+	conns := make(chan func(*websocket.Conn, string), len(handlers))
+	for _, h := range handlers {
+		conns <- h
+	}
+
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var handle func(*websocket.Conn, string)
+		select {
+		case handle = <-conns:
+		default:
+			http.Error(w, "no more connections", http.StatusServiceUnavailable)
+			return
+		}
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ws.Close()
+		handle(ws, r.URL.Query().Get("since"))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// collect drains the subscription, returning every event and every status it delivered.
+func collect(t *testing.T, sub *Subscription) ([]*dto.WSEvent, []SubscriptionStatus) {
+	t.Helper()
+	var events []*dto.WSEvent
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case evt, ok := <-sub.Events:
+			if !ok {
+				var statuses []SubscriptionStatus
+				for status := range sub.Status {
+					statuses = append(statuses, status)
+				}
+				return events, statuses
+			}
+			events = append(events, evt)
+		case <-timeout:
+			t.Fatal("the subscription did not end")
+		}
+	}
+}
+
+func update(version int) dto.WSEvent {
+	return dto.WSEvent{Type: "game_update", Payload: &dto.GameView{Version: version}}
+}
+
+func TestSubscribe_Reconnects(t *testing.T) {
+	t.Parallel()
+	dialed := make(chan string, 2)
+	ts := newStreamServer(t,
+		func(ws *websocket.Conn, since string) {
+			dialed <- since
+			assert.NoError(t, ws.WriteJSON(update(3)))
+			// Returning drops the connection without a close frame
+		},
+		func(ws *websocket.Conn, since string) {
+			dialed <- since
+			assert.NoError(t, ws.WriteJSON(update(4)))
+			assert.NoError(t, ws.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, dto.StreamClosedGameOver)))
+		},
+	)
+
+	sub, err := New(ts.URL).Subscribe(context.Background(), "m1")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	events, statuses := collect(t, sub)
+	require.Len(t, events, 2)
+	assert.Equal(t, 3, events[0].Payload.Version)
+	assert.Equal(t, 4, events[1].Payload.Version)
+
+	assert.Empty(t, <-dialed, "the first connection replays nothing")
+	assert.Equal(t, "3", <-dialed, "the second connection asks for what came after the last version seen")
+
+	assert.Equal(t, []SubscriptionStatus{
+		{State: SubscriptionConnected},
+		{State: SubscriptionReconnecting, Attempt: 1},
+		{State: SubscriptionConnected},
+		{State: SubscriptionClosed, Close: StreamClose{
+			Code:   websocket.CloseNormalClosure,
+			Reason: dto.StreamClosedGameOver,
+		}},
+	}, statuses, "the close reason comes last, once Events is closed")
+}
+
+func TestSubscribe_GivesUp(t *testing.T) {
+	t.Parallel()
+	ts := newStreamServer(t, func(ws *websocket.Conn, _ string) {
+		assert.NoError(t, ws.WriteJSON(update(1)))
+	})
+
+	sub, err := New(ts.URL).subscribe(context.Background(), "m1", 1)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	events, statuses := collect(t, sub)
+	assert.Len(t, events, 1)
+	require.NotEmpty(t, statuses)
+	assert.Equal(t, SubscriptionStatus{
+		State: SubscriptionClosed,
+		Close: StreamClose{Code: websocket.CloseAbnormalClosure},
+	}, statuses[len(statuses)-1])
+}
+
+func TestSubscription_Close(t *testing.T) {
+	t.Parallel()
+	closed := make(chan int, 1)
+	ts := newStreamServer(t, func(ws *websocket.Conn, _ string) {
+		_, _, err := ws.ReadMessage()
+		var closeErr *websocket.CloseError
+		if assert.ErrorAs(t, err, &closeErr) {
+			closed <- closeErr.Code
+		}
+	})
+
+	sub, err := New(ts.URL).Subscribe(context.Background(), "m1")
+	require.NoError(t, err)
+
+	sub.Close()
+	sub.Close() // Harmless the second time
+
+	select {
+	case _, ok := <-sub.Events:
+		assert.False(t, ok, "Close waits until the subscription has ended")
+	default:
+		t.Fatal("Events should be closed once Close returns")
+	}
+
+	var last SubscriptionStatus
+	for status := range sub.Status {
+		last = status
+	}
+	assert.Equal(t, SubscriptionStatus{
+		State: SubscriptionClosed,
+		Close: StreamClose{Code: websocket.CloseNormalClosure},
+	}, last)
+
+	select {
+	case code := <-closed:
+		assert.Equal(t, websocket.CloseNormalClosure, code, "the server is sent a close frame")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server was not told the stream closed")
+	}
+}
+
+func TestSubscription_Report(t *testing.T) {
+	t.Parallel()
+	sub := &Subscription{status: make(chan SubscriptionStatus, 2)}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		sub.report(SubscriptionStatus{State: SubscriptionReconnecting, Attempt: attempt})
+	}
+	sub.report(SubscriptionStatus{State: SubscriptionClosed})
+
+	assert.Equal(t, SubscriptionStatus{State: SubscriptionReconnecting, Attempt: 3}, <-sub.status,
+		"the oldest statuses are dropped for a reader that falls behind")
+	assert.Equal(t, SubscriptionStatus{State: SubscriptionClosed}, <-sub.status, "the last status is never dropped")
+}
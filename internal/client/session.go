@@ -0,0 +1,63 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Session is the login a client keeps between runs.
+type Session struct {
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// SessionPath returns where the session is stored: battleship/token in the user config directory,
+// which honours $XDG_CONFIG_HOME.
+func SessionPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no config directory: %w", err)
+	}
+	return filepath.Join(dir, "battleship", "token"), nil
+}
+
+// LoadSession reads a stored session. A missing, corrupt or empty file is reported as an error.
+func LoadSession(path string) (Session, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path is chosen by the user's environment
+	if err != nil {
+		return Session{}, err
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Session{}, fmt.Errorf("corrupt session file %s: %w", path, err)
+	}
+	if s.Token == "" {
+		return Session{}, fmt.Errorf("session file %s has no token", path)
+	}
+	return s, nil
+}
+
+// SaveSession stores the session, readable by the current user only.
+func SaveSession(path string, s Session) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ClearSession removes the stored session, if any.
+func ClearSession(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Package commitment lets players check that nobody moved their ships during a match.
+// When play starts, every fleet is hashed along with a secret salt and the hash is shown to the players;
+// once the match is over, the fleets and salts are revealed so that the hash can be recomputed.
+package commitment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/rules"
+)
+
+// SaltedBoard is a board as a placement commitment covers it, with the secret salt that keeps
+// the commitment from giving its ships away.
+type SaltedBoard struct {
+	Salt  string
+	Board rules.Board
+}
+
+// Placement hashes where the ships are on every board, in the order given.
+// Only whether a cell holds a ship counts, not whether it was hit, so the commitment made when
+// play starts can be recomputed from the fleets revealed once it is over: a board that changed
+// in between gives a different hash.
+func Placement(boards []SaltedBoard) string {
+	h := sha256.New()
+	for _, b := range boards {
+		fmt.Fprintf(h, "%s\n", b.Salt)
+		for y := range b.Board.Size() {
+			row := make([]byte, 0, b.Board.Size()+1)
+			for x := range b.Board.Size() {
+				switch b.Board.Cell(x, y) {
+				case dto.CellShip, dto.CellHit, dto.CellSunk:
+					row = append(row, '1')
+				default:
+					row = append(row, '0')
+				}
+			}
+			h.Write(append(row, '\n'))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Reveal recomputes the placement commitment from the fleets revealed at the end of
+// a match, to be compared with the one its players were shown when play started.
+func Reveal(reveal dto.RevealView) string {
+	boards := make([]SaltedBoard, 0, len(reveal.Players))
+	for _, p := range reveal.Players {
+		boards = append(boards, SaltedBoard{Salt: p.Salt, Board: rules.View(p.Board)})
+	}
+	return Placement(boards)
+}
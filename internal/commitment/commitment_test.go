@@ -0,0 +1,86 @@
+package commitment_test
+
+import (
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/commitment"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+// newView builds a square board view from rows of cells, '.' for empty, 'S' for a ship,
+// 'X' for a hit, 'O' for a miss and '#' for a sunk ship.
+func newView(rows ...string) rules.Board {
+	states := map[rune]dto.CellState{
+		'.': dto.CellEmpty,
+		'S': dto.CellShip,
+		'X': dto.CellHit,
+		'O': dto.CellMiss,
+		'#': dto.CellSunk,
+	}
+
+	grid := make([][]dto.CellState, len(rows))
+	for y, row := range rows {
+		for _, r := range row {
+			grid[y] = append(grid[y], states[r])
+		}
+	}
+	return rules.View(dto.BoardView{Grid: grid, Size: len(rows)})
+}
+
+func TestPlacement(t *testing.T) {
+	t.Parallel()
+
+	placed := []commitment.SaltedBoard{
+		{Salt: "a", Board: newView("SS.", "...", "..S")},
+		{Salt: "b", Board: newView("...", "S..", "S..")},
+	}
+	want := commitment.Placement(placed)
+
+	tests := []struct {
+		name   string
+		boards []commitment.SaltedBoard
+		same   bool
+	}{
+		{
+			name: "Fired Upon",
+			boards: []commitment.SaltedBoard{
+				{Salt: "a", Board: newView("#X.", ".O.", "..S")},
+				{Salt: "b", Board: newView("O..", "X..", "S..")},
+			},
+			same: true,
+		},
+		{
+			name: "Ship Moved",
+			boards: []commitment.SaltedBoard{
+				{Salt: "a", Board: newView("SS.", "...", "S..")},
+				{Salt: "b", Board: newView("...", "S..", "S..")},
+			},
+		},
+		{
+			name: "Other Salt",
+			boards: []commitment.SaltedBoard{
+				{Salt: "c", Board: newView("SS.", "...", "..S")},
+				{Salt: "b", Board: newView("...", "S..", "S..")},
+			},
+		},
+		{
+			name:   "Boards Swapped",
+			boards: []commitment.SaltedBoard{placed[1], placed[0]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := commitment.Placement(tt.boards)
+			if tt.same {
+				assert.Equal(t, want, got)
+			} else {
+				assert.NotEqual(t, want, got)
+			}
+		})
+	}
+}
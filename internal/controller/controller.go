@@ -3,19 +3,103 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/callegarimattia/battleship/internal/dto"
 )
 
+var (
+	// ErrMatchNotFound is returned when the requested match does not exist.
+	ErrMatchNotFound = errors.New("match not found")
+	// ErrNotHost is returned when a host-only action is requested by another player.
+	ErrNotHost = errors.New("only the host can do this")
+	// ErrInvalidSettings is returned when a match is created with a board size or fleet preset that is not supported.
+	ErrInvalidSettings = errors.New("invalid match settings")
+	// ErrUserNotFound is returned when a token refers to a user the identity service does not know.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrTooManyActiveGames is returned when a player who already takes part in as many unfinished
+	// matches as allowed tries to host or join another.
+	ErrTooManyActiveGames = errors.New("active game limit reached")
+	// ErrServerAtCapacity is returned when a match is created while the server already holds
+	// as many unfinished matches as it allows.
+	ErrServerAtCapacity = errors.New("server at capacity")
+	// ErrTournamentNotFound is returned when the requested tournament does not exist,
+	// or when the application runs no tournaments at all.
+	ErrTournamentNotFound = errors.New("tournament not found")
+	// ErrTournamentStarted is returned when signing up for, or starting, a tournament that already started.
+	ErrTournamentStarted = errors.New("tournament already started")
+	// ErrTournamentFull is returned when signing up for a tournament that has no room left.
+	ErrTournamentFull = errors.New("tournament is full")
+	// ErrNotEnoughPlayers is returned when starting a tournament with fewer than two players.
+	ErrNotEnoughPlayers = errors.New("a tournament needs at least two players")
+	// ErrInvalidResumeToken is returned when resuming a match with a token that belongs to none of its seats.
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+	// ErrVersionConflict is returned when a move expected a game version that is no longer current,
+	// because the game changed since the player last saw it.
+	ErrVersionConflict = errors.New("game changed since the expected version")
+)
+
+// ValidationError lists every problem found in a request, so that they can all be fixed at once.
+// errors.Is matches any of them.
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Messages(), "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// Messages returns the message of each problem.
+func (e *ValidationError) Messages() []string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.Error()
+	}
+	return messages
+}
+
+// expectedVersionKey is the context key of the game version a move expects.
+type expectedVersionKey struct{}
+
+// WithExpectedVersion returns a context that makes PlaceShip, PlaceShipType and Attack fail with
+// ErrVersionConflict unless the game is still at the given version. A client can then retry a move
+// that timed out without making it twice.
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersion returns the game version set by WithExpectedVersion, if any.
+func ExpectedVersion(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(int)
+	return version, ok
+}
+
 // NotificationService handles event publishing and subscription.
 type NotificationService interface {
-	Subscribe(matchID string) (Subscription, <-chan *dto.GameEvent)
+	// Subscribe delivers the events of the match. Given event types, only events of those types are delivered.
+	Subscribe(matchID string, types ...dto.EventType) (Subscription, <-chan *dto.GameEvent)
+	// SubscribeAll delivers the events of every match. Unlike Subscribe, events are never dropped
+	// for a slow reader, so it suits services that must see every event of the given types.
+	SubscribeAll(types ...dto.EventType) (Subscription, <-chan *dto.GameEvent)
 	Publish(event *dto.GameEvent)
+	// SubscriberCount returns how many clients are subscribed to a specific match (wildcard excluded).
+	SubscriberCount(matchID string) int
+	// Close ends every subscription to a specific match (wildcard excluded), closing their channels
+	// once the events already sent are read. The reason, one of the dto.StreamClosed reasons,
+	// tells the subscribers why, even those whose filter kept out the event that said so.
+	Close(matchID, reason string)
 }
 
 // Subscription represents a subscription to events.
 type Subscription interface {
 	Unsubscribe()
+	// Reason returns why Close ended the subscription, or an empty string if it did not.
+	Reason() string
 }
 
 // IdentityService handles user registration and login.
@@ -24,17 +108,32 @@ type IdentityService interface {
 	// source: "web", "discord", "cli"
 	// extID: The unique ID from the platform (e.g. Discord User ID, or just the username for Web)
 	LoginOrRegister(ctx context.Context, username, source, extID string) (dto.AuthResponse, error)
+	// GetUser returns a registered user by internal ID, or ErrUserNotFound.
+	GetUser(ctx context.Context, userID string) (dto.User, error)
 }
 
 // LobbyService handles finding and creating matches.
 type LobbyService interface {
-	// CreateMatch initializes a game in 'Waiting' state with the host joined.
-	CreateMatch(ctx context.Context, hostID string) (string, error)
-	// ListMatches returns all games currently in 'Waiting' state.
-	ListMatches(ctx context.Context) ([]dto.MatchSummary, error)
+	// CreateMatch initializes a game in 'Waiting' state with the host joined, using the given rules.
+	CreateMatch(ctx context.Context, hostID string, settings dto.MatchSettings) (string, error)
+	// ListMatches returns the games waiting for an opponent, plus the ones in progress if the filter asks for them.
+	ListMatches(ctx context.Context, filter dto.MatchFilter) ([]dto.MatchSummary, error)
 	// JoinMatch adds the player to the game.
 	// If successful, the game transitions to 'Setup'.
 	JoinMatch(ctx context.Context, matchID, playerID string) (dto.GameView, error)
+	// ResumeMatch hands the seat the resume token belongs to over to the player,
+	// who carries on where its previous holder left off.
+	ResumeMatch(ctx context.Context, matchID, playerID, token string) (dto.GameView, error)
+	// DeleteMatch cancels a match. Only its host may do so, or its owner if the settings name one,
+	// so that players cannot cancel their tournament matches; the opponent is notified.
+	DeleteMatch(ctx context.Context, matchID, playerID string) error
+	// ActiveMatch returns the ID of the unfinished match the player is in, or ErrMatchNotFound.
+	ActiveMatch(ctx context.Context, playerID string) (string, error)
+	// Scoreboard summarizes every match in setup or in play, with nothing a spectator may not see.
+	Scoreboard(ctx context.Context) ([]dto.ScoreboardEntry, error)
+	// PracticeBoard returns a sample layout of the fleet the board size and fleet preset pick.
+	// It is the same for the same settings, so that tutorials can show what a placed board looks like.
+	PracticeBoard(ctx context.Context, settings dto.MatchSettings) (dto.BoardView, error)
 }
 
 // GameService handles the actual gameplay (Setup -> Playing -> GameOver).
@@ -47,29 +146,105 @@ type GameService interface {
 		x, y int,
 		vertical bool,
 	) (dto.GameView, error)
+	// PlaceShipType places a ship chosen by its type name, which tells apart ships of the same size.
+	PlaceShipType(
+		ctx context.Context,
+		matchID, playerID string,
+		shipType string,
+		x, y int,
+		vertical bool,
+	) (dto.GameView, error)
+	// ValidatePlacement checks a placement without making it, reporting why PlaceShip would refuse it.
+	ValidatePlacement(
+		ctx context.Context,
+		matchID, playerID string,
+		size, x, y int,
+		vertical bool,
+	) (dto.PlacementCheck, error)
+	// Ready confirms a player's setup. The game starts once both players are ready.
+	Ready(ctx context.Context, matchID, playerID string) (dto.GameView, error)
+
 	// Attack handles the playing phase.
 	Attack(ctx context.Context, matchID, playerID string, x, y int) (dto.GameView, error)
+	// AttackTarget fires at the board of the named player, who must be a living opponent.
+	// An empty target stands for the sole opponent, as in Attack.
+	AttackTarget(ctx context.Context, matchID, playerID, targetID string, x, y int) (dto.GameView, error)
+	// ValidateAttack checks a shot without firing it, reporting why AttackTarget would refuse it.
+	ValidateAttack(ctx context.Context, matchID, playerID, targetID string, x, y int) (dto.AttackCheck, error)
+	// LegalMoves lists the cells of the target's board the player may attack on their turn.
+	// An empty target stands for the sole opponent, as in AttackTarget.
+	LegalMoves(ctx context.Context, matchID, playerID, targetID string) (dto.LegalMoves, error)
+	// IsPlayersTurn reports whether the player is the one expected to attack next.
+	IsPlayersTurn(ctx context.Context, matchID, playerID string) (bool, error)
+
 	// GetState is used for refreshing the UI.
 	GetState(ctx context.Context, matchID, playerID string) (dto.GameView, error)
+	// EventsSince returns the events visible to the player that happened after the given game version.
+	EventsSince(ctx context.Context, matchID, playerID string, since int) ([]dto.GameEvent, error)
+	// MatchRecord returns the shots fired in the match and its outcome, for the match's players.
+	MatchRecord(ctx context.Context, matchID, playerID string) (dto.MatchRecord, error)
+	// Reveal shows both fleets in full once the match has been won.
+	Reveal(ctx context.Context, matchID string) (dto.RevealView, error)
+	// Rules describes how the match is played: its board, fleets and options.
+	Rules(ctx context.Context, matchID string) (dto.Rules, error)
+}
+
+// TournamentService runs single-elimination tournaments made of ordinary matches.
+type TournamentService interface {
+	// CreateTournament opens a tournament for sign-ups, with the host as its first player.
+	// Every match of the tournament is played with the given settings.
+	CreateTournament(ctx context.Context, hostID string, settings dto.MatchSettings) (dto.TournamentView, error)
+	// JoinTournament signs the player up. Signing up twice is not an error.
+	JoinTournament(ctx context.Context, tournamentID, playerID string) (dto.TournamentView, error)
+	// StartTournament closes sign-ups and creates the matches of the first round. Only the host may do so.
+	StartTournament(ctx context.Context, tournamentID, playerID string) (dto.TournamentView, error)
+	// GetTournament returns the bracket and standings.
+	GetTournament(ctx context.Context, tournamentID string) (dto.TournamentView, error)
+}
+
+// AdminService holds the operations operators run on the server, outside the rules of any match.
+type AdminService interface {
+	// TerminateMatch removes a match whatever its state, for clearing one that got stuck.
+	// Its subscribers get a match.terminated event, then their streams are closed.
+	TerminateMatch(ctx context.Context, matchID string) error
 }
 
 // AppController is the main controller orchestrating the application flow.
 type AppController struct {
-	auth     IdentityService
-	lobby    LobbyService
-	game     GameService
-	notifier NotificationService
+	auth        IdentityService
+	lobby       LobbyService
+	game        GameService
+	notifier    NotificationService
+	tournaments TournamentService
+	admin       AdminService
+}
+
+// Option configures optional AppController dependencies.
+type Option func(*AppController)
+
+// WithTournaments lets players run tournaments. Without it, tournament actions return ErrTournamentNotFound.
+func WithTournaments(t TournamentService) Option {
+	return func(c *AppController) { c.tournaments = t }
+}
+
+// WithAdmin enables the operator actions. Without it, TerminateMatchAction returns ErrMatchNotFound.
+func WithAdmin(a AdminService) Option {
+	return func(c *AppController) { c.admin = a }
 }
 
-// NewAppController wires everything together.
 // NewAppController wires everything together.
 func NewAppController(
 	a IdentityService,
 	l LobbyService,
 	g GameService,
 	n NotificationService,
+	opts ...Option,
 ) *AppController {
-	return &AppController{auth: a, lobby: l, game: g, notifier: n}
+	c := &AppController{auth: a, lobby: l, game: g, notifier: n}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Login handles user authentication and registration.
@@ -80,14 +255,36 @@ func (c *AppController) Login(
 	return c.auth.LoginOrRegister(ctx, username, source, platformID)
 }
 
+// CurrentUser returns the user a validated token belongs to.
+func (c *AppController) CurrentUser(ctx context.Context, userID string) (dto.User, error) {
+	return c.auth.GetUser(ctx, userID)
+}
+
 // HostGameAction handles a player's request to host a new game.
-func (c *AppController) HostGameAction(ctx context.Context, playerID string) (string, error) {
-	return c.lobby.CreateMatch(ctx, playerID)
+func (c *AppController) HostGameAction(
+	ctx context.Context,
+	playerID string,
+	settings dto.MatchSettings,
+) (string, error) {
+	return c.lobby.CreateMatch(ctx, playerID, settings)
+}
+
+// PracticeBoardAction returns a sample placed board for the given board size and fleet preset.
+func (c *AppController) PracticeBoardAction(ctx context.Context, settings dto.MatchSettings) (dto.BoardView, error) {
+	return c.lobby.PracticeBoard(ctx, settings)
 }
 
 // ListGamesAction retrieves the list of current games in the lobby.
-func (c *AppController) ListGamesAction(ctx context.Context) ([]dto.MatchSummary, error) {
-	return c.lobby.ListMatches(ctx)
+func (c *AppController) ListGamesAction(
+	ctx context.Context,
+	filter dto.MatchFilter,
+) ([]dto.MatchSummary, error) {
+	return c.lobby.ListMatches(ctx, filter)
+}
+
+// ScoreboardAction returns the live overview of the matches in progress.
+func (c *AppController) ScoreboardAction(ctx context.Context) ([]dto.ScoreboardEntry, error) {
+	return c.lobby.Scoreboard(ctx)
 }
 
 // JoinGameAction handles a player's request to join an existing game.
@@ -98,6 +295,24 @@ func (c *AppController) JoinGameAction(
 	return c.lobby.JoinMatch(ctx, matchID, playerID)
 }
 
+// ResumeGameAction handles a player taking back their seat in a match with its resume token.
+func (c *AppController) ResumeGameAction(
+	ctx context.Context,
+	matchID, playerID, token string,
+) (dto.GameView, error) {
+	return c.lobby.ResumeMatch(ctx, matchID, playerID, token)
+}
+
+// CancelGameAction handles a host cancelling their match.
+func (c *AppController) CancelGameAction(ctx context.Context, matchID, playerID string) error {
+	return c.lobby.DeleteMatch(ctx, matchID, playerID)
+}
+
+// ActiveMatchAction looks up the unfinished match a player is taking part in.
+func (c *AppController) ActiveMatchAction(ctx context.Context, playerID string) (string, error) {
+	return c.lobby.ActiveMatch(ctx, playerID)
+}
+
 // PlaceShipAction handles a ship placement action from a player.
 func (c *AppController) PlaceShipAction(
 	ctx context.Context,
@@ -108,6 +323,35 @@ func (c *AppController) PlaceShipAction(
 	return c.game.PlaceShip(ctx, matchID, playerID, size, x, y, vertical)
 }
 
+// PlaceShipTypeAction handles a player placing a ship chosen by its type name.
+func (c *AppController) PlaceShipTypeAction(
+	ctx context.Context,
+	matchID, playerID string,
+	shipType string,
+	x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	return c.game.PlaceShipType(ctx, matchID, playerID, shipType, x, y, vertical)
+}
+
+// ValidatePlacementAction checks whether a player could place a ship, without placing it.
+func (c *AppController) ValidatePlacementAction(
+	ctx context.Context,
+	matchID, playerID string,
+	size, x, y int,
+	vertical bool,
+) (dto.PlacementCheck, error) {
+	return c.game.ValidatePlacement(ctx, matchID, playerID, size, x, y, vertical)
+}
+
+// ReadyAction handles a player confirming their ship placement.
+func (c *AppController) ReadyAction(
+	ctx context.Context,
+	matchID, playerID string,
+) (dto.GameView, error) {
+	return c.game.Ready(ctx, matchID, playerID)
+}
+
 // AttackAction handles an attack action from a player.
 func (c *AppController) AttackAction(
 	ctx context.Context,
@@ -117,6 +361,32 @@ func (c *AppController) AttackAction(
 	return c.game.Attack(ctx, matchID, playerID, x, y)
 }
 
+// AttackTargetAction handles an attack aimed at a named player.
+func (c *AppController) AttackTargetAction(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (dto.GameView, error) {
+	return c.game.AttackTarget(ctx, matchID, playerID, targetID, x, y)
+}
+
+// ValidateAttackAction checks whether a player could fire a shot, without firing it.
+func (c *AppController) ValidateAttackAction(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (dto.AttackCheck, error) {
+	return c.game.ValidateAttack(ctx, matchID, playerID, targetID, x, y)
+}
+
+// LegalMovesAction lists the cells of the target's board a player may attack on their turn.
+func (c *AppController) LegalMovesAction(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+) (dto.LegalMoves, error) {
+	return c.game.LegalMoves(ctx, matchID, playerID, targetID)
+}
+
 // GetGameStateAction retrieves the current state of the game for a player.
 func (c *AppController) GetGameStateAction(
 	ctx context.Context,
@@ -125,9 +395,98 @@ func (c *AppController) GetGameStateAction(
 	return c.game.GetState(ctx, matchID, playerID)
 }
 
-// SubscribeToMatch allows the handler to subscribe to match events.
+// IsPlayersTurnAction reports whether it is the player's turn to attack.
+func (c *AppController) IsPlayersTurnAction(
+	ctx context.Context,
+	matchID, playerID string,
+) (bool, error) {
+	return c.game.IsPlayersTurn(ctx, matchID, playerID)
+}
+
+// EventsSinceAction returns the events a reconnecting player missed since the given game version.
+func (c *AppController) EventsSinceAction(
+	ctx context.Context,
+	matchID, playerID string,
+	since int,
+) ([]dto.GameEvent, error) {
+	return c.game.EventsSince(ctx, matchID, playerID, since)
+}
+
+// MatchRecordAction returns the move history of a match for one of its players.
+func (c *AppController) MatchRecordAction(
+	ctx context.Context,
+	matchID, playerID string,
+) (dto.MatchRecord, error) {
+	return c.game.MatchRecord(ctx, matchID, playerID)
+}
+
+// RevealAction returns both fleets of a won match.
+func (c *AppController) RevealAction(ctx context.Context, matchID string) (dto.RevealView, error) {
+	return c.game.Reveal(ctx, matchID)
+}
+
+// RulesAction returns how a match is played.
+func (c *AppController) RulesAction(ctx context.Context, matchID string) (dto.Rules, error) {
+	return c.game.Rules(ctx, matchID)
+}
+
+// SubscribeToMatch allows the handler to subscribe to match events, optionally only to some event types.
 func (c *AppController) SubscribeToMatch(
 	matchID string,
+	types ...dto.EventType,
 ) (sub Subscription, eventChan <-chan *dto.GameEvent) {
-	return c.notifier.Subscribe(matchID)
+	return c.notifier.Subscribe(matchID, types...)
+}
+
+// TerminateMatchAction forcibly removes a match at an operator's request.
+func (c *AppController) TerminateMatchAction(ctx context.Context, matchID string) error {
+	if c.admin == nil {
+		return ErrMatchNotFound
+	}
+	return c.admin.TerminateMatch(ctx, matchID)
+}
+
+// CreateTournamentAction opens a tournament hosted by the player.
+func (c *AppController) CreateTournamentAction(
+	ctx context.Context,
+	hostID string,
+	settings dto.MatchSettings,
+) (dto.TournamentView, error) {
+	if c.tournaments == nil {
+		return dto.TournamentView{}, ErrTournamentNotFound
+	}
+	return c.tournaments.CreateTournament(ctx, hostID, settings)
+}
+
+// JoinTournamentAction signs a player up for a tournament.
+func (c *AppController) JoinTournamentAction(
+	ctx context.Context,
+	tournamentID, playerID string,
+) (dto.TournamentView, error) {
+	if c.tournaments == nil {
+		return dto.TournamentView{}, ErrTournamentNotFound
+	}
+	return c.tournaments.JoinTournament(ctx, tournamentID, playerID)
+}
+
+// StartTournamentAction starts a tournament on behalf of its host.
+func (c *AppController) StartTournamentAction(
+	ctx context.Context,
+	tournamentID, playerID string,
+) (dto.TournamentView, error) {
+	if c.tournaments == nil {
+		return dto.TournamentView{}, ErrTournamentNotFound
+	}
+	return c.tournaments.StartTournament(ctx, tournamentID, playerID)
+}
+
+// GetTournamentAction returns the bracket and standings of a tournament.
+func (c *AppController) GetTournamentAction(
+	ctx context.Context,
+	tournamentID string,
+) (dto.TournamentView, error) {
+	if c.tournaments == nil {
+		return dto.TournamentView{}, ErrTournamentNotFound
+	}
+	return c.tournaments.GetTournament(ctx, tournamentID)
 }
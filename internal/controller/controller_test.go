@@ -97,9 +97,10 @@ func TestLobbyActions(t *testing.T) {
 	t.Run("HostGameAction", func(t *testing.T) {
 		t.Parallel()
 		ctrl, _, mockLobby, _, _ := setupControllerTest(t)
-		mockLobby.EXPECT().CreateMatch(mock.Anything, "p1").Return("match-1", nil).Once()
+		settings := dto.MatchSettings{BoardSize: 8}
+		mockLobby.EXPECT().CreateMatch(mock.Anything, "p1", settings).Return("match-1", nil).Once()
 
-		id, err := ctrl.HostGameAction(context.Background(), "p1")
+		id, err := ctrl.HostGameAction(context.Background(), "p1", settings)
 		assert.NoError(t, err)
 		assert.Equal(t, "match-1", id)
 	})
@@ -107,9 +108,9 @@ func TestLobbyActions(t *testing.T) {
 	t.Run("HostGameAction Error", func(t *testing.T) {
 		t.Parallel()
 		ctrl, _, mockLobby, _, _ := setupControllerTest(t)
-		mockLobby.EXPECT().CreateMatch(mock.Anything, "p1").Return("", errors.New("fail")).Once()
+		mockLobby.EXPECT().CreateMatch(mock.Anything, "p1", mock.Anything).Return("", errors.New("fail")).Once()
 
-		_, err := ctrl.HostGameAction(context.Background(), "p1")
+		_, err := ctrl.HostGameAction(context.Background(), "p1", dto.MatchSettings{})
 		assert.Error(t, err)
 	})
 
@@ -117,9 +118,10 @@ func TestLobbyActions(t *testing.T) {
 		t.Parallel()
 		ctrl, _, mockLobby, _, _ := setupControllerTest(t)
 		expected := []dto.MatchSummary{{ID: "m1"}}
-		mockLobby.EXPECT().ListMatches(mock.Anything).Return(expected, nil).Once()
+		filter := dto.MatchFilter{IncludeLive: true}
+		mockLobby.EXPECT().ListMatches(mock.Anything, filter).Return(expected, nil).Once()
 
-		list, err := ctrl.ListGamesAction(context.Background())
+		list, err := ctrl.ListGamesAction(context.Background(), filter)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, list)
 	})
@@ -151,6 +153,18 @@ func TestGameActions(t *testing.T) {
 		assert.Equal(t, expected, view)
 	})
 
+	t.Run("ReadyAction", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, _, mockGame, _ := setupControllerTest(t)
+		expected := dto.GameView{State: "PLAYING"}
+		mockGame.EXPECT().Ready(mock.Anything, "m1", "p1").
+			Return(expected, nil).Once()
+
+		view, err := ctrl.ReadyAction(context.Background(), "m1", "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, view)
+	})
+
 	t.Run("AttackAction", func(t *testing.T) {
 		t.Parallel()
 		ctrl, _, _, mockGame, _ := setupControllerTest(t)
@@ -163,6 +177,50 @@ func TestGameActions(t *testing.T) {
 		assert.Equal(t, expected, view)
 	})
 
+	t.Run("IsPlayersTurnAction", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, _, mockGame, _ := setupControllerTest(t)
+		mockGame.EXPECT().IsPlayersTurn(mock.Anything, "m1", "p1").
+			Return(true, nil).Once()
+
+		yourTurn, err := ctrl.IsPlayersTurnAction(context.Background(), "m1", "p1")
+		assert.NoError(t, err)
+		assert.True(t, yourTurn)
+	})
+
+	t.Run("CancelGameAction", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, mockLobby, _, _ := setupControllerTest(t)
+		mockLobby.EXPECT().DeleteMatch(mock.Anything, "m1", "p1").
+			Return(nil).Once()
+
+		err := ctrl.CancelGameAction(context.Background(), "m1", "p1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("ActiveMatchAction", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, mockLobby, _, _ := setupControllerTest(t)
+		mockLobby.EXPECT().ActiveMatch(mock.Anything, "p1").
+			Return("m1", nil).Once()
+
+		matchID, err := ctrl.ActiveMatchAction(context.Background(), "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, "m1", matchID)
+	})
+
+	t.Run("EventsSinceAction", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, _, mockGame, _ := setupControllerTest(t)
+		expected := []dto.GameEvent{{Type: dto.EventAttackMade, Version: 4}}
+		mockGame.EXPECT().EventsSince(mock.Anything, "m1", "p1", 3).
+			Return(expected, nil).Once()
+
+		events, err := ctrl.EventsSinceAction(context.Background(), "m1", "p1", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, events)
+	})
+
 	t.Run("GetGameStateAction", func(t *testing.T) {
 		t.Parallel()
 		ctrl, _, _, mockGame, _ := setupControllerTest(t)
@@ -174,4 +232,78 @@ func TestGameActions(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expected, view)
 	})
+
+	t.Run("RevealAction", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, _, mockGame, _ := setupControllerTest(t)
+		expected := dto.RevealView{MatchID: "m1", Winner: "p1"}
+		mockGame.EXPECT().Reveal(mock.Anything, "m1").
+			Return(expected, nil).Once()
+
+		reveal, err := ctrl.RevealAction(context.Background(), "m1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, reveal)
+	})
+}
+
+func TestTournamentActions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("Not Configured", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, _, _, _ := setupControllerTest(t)
+
+		_, err := ctrl.CreateTournamentAction(ctx, "p1", dto.MatchSettings{})
+		assert.ErrorIs(t, err, controller.ErrTournamentNotFound)
+		_, err = ctrl.GetTournamentAction(ctx, "t1")
+		assert.ErrorIs(t, err, controller.ErrTournamentNotFound)
+	})
+
+	t.Run("Delegates", func(t *testing.T) {
+		t.Parallel()
+		tournaments := m.NewMockTournamentService(t)
+		ctrl := controller.NewAppController(
+			m.NewMockIdentityService(t),
+			m.NewMockLobbyService(t),
+			m.NewMockGameService(t),
+			m.NewMockNotificationService(t),
+			controller.WithTournaments(tournaments),
+		)
+
+		want := dto.TournamentView{ID: "t1", HostID: "p1"}
+		tournaments.EXPECT().StartTournament(mock.Anything, "t1", "p1").Return(want, nil).Once()
+
+		got, err := ctrl.StartTournamentAction(ctx, "t1", "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestTerminateMatchAction(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("Not Configured", func(t *testing.T) {
+		t.Parallel()
+		ctrl, _, _, _, _ := setupControllerTest(t)
+
+		assert.ErrorIs(t, ctrl.TerminateMatchAction(ctx, "m1"), controller.ErrMatchNotFound)
+	})
+
+	t.Run("Delegates", func(t *testing.T) {
+		t.Parallel()
+		admin := m.NewMockAdminService(t)
+		ctrl := controller.NewAppController(
+			m.NewMockIdentityService(t),
+			m.NewMockLobbyService(t),
+			m.NewMockGameService(t),
+			m.NewMockNotificationService(t),
+			controller.WithAdmin(admin),
+		)
+
+		admin.EXPECT().TerminateMatch(mock.Anything, "m1").Return(nil).Once()
+
+		assert.NoError(t, ctrl.TerminateMatchAction(ctx, "m1"))
+	})
 }
@@ -1,7 +1,10 @@
 // Package dto contains data transfer objects for representing game state.
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // CellState describes what a specific coordinate looks like.
 type CellState string
@@ -21,9 +24,12 @@ type GameState string
 
 // Possible GameState values.
 const (
-	StateSetup    GameState = "SETUP"
-	StatePlaying  GameState = "PLAYING"
-	StateFinished GameState = "FINISHED"
+	StateWaiting   GameState = "WAITING" // Host is alone in the match; only reported in match summaries
+	StateSetup     GameState = "SETUP"
+	StatePlaying   GameState = "PLAYING"
+	StateFinished  GameState = "FINISHED"
+	StateAbandoned GameState = "ABANDONED" // Ended without a winner after both players left
+	StateDraw      GameState = "DRAW"      // Decided without a winner, e.g. when the move limit ran out
 )
 
 // BoardView is a simplified, immutable snapshot of the board grid.
@@ -35,18 +41,149 @@ type BoardView struct {
 
 // PlayerView represents a player's public state.
 type PlayerView struct {
-	ID    string      `json:"id"`
-	Board BoardView   `json:"board"`
-	Fleet map[int]int `json:"fleet"` // Remaining ships by size
+	ID    string         `json:"id"`
+	Board BoardView      `json:"board"`
+	Fleet map[int]int    `json:"fleet"`           // Remaining ships by size
+	Ships map[string]int `json:"ships,omitempty"` // Remaining ships by type name, e.g. "Submarine"
+	Ready bool           `json:"ready"`           // Whether the player confirmed their setup
+	// ShipsAfloat counts the placed ships not sunk yet. It gives away no position, so the enemy's is shown too.
+	ShipsAfloat int `json:"ships_afloat"`
+	// StartingShips is the fleet the player began with by type name. It differs between the players
+	// when either has a handicap.
+	StartingShips map[string]int `json:"starting_ships,omitempty"`
+	// Salt kept the placement commitment from giving the fleet away. It is only told once the match is decided.
+	Salt string `json:"salt,omitempty"`
+}
+
+// PlacementCheck tells whether a ship placement would be accepted, and why not.
+type PlacementCheck struct {
+	Valid  bool   `json:"valid"`
+	Code   string `json:"code,omitempty"`   // Machine-readable reason, see the Placement constants
+	Reason string `json:"reason,omitempty"` // Human-readable reason
+}
+
+// Reasons a placement is refused.
+const (
+	PlacementOutOfBounds = "out_of_bounds"
+	PlacementOverlap     = "overlap"
+	PlacementDepleted    = "no_ships_remaining"
+	PlacementNotInSetup  = "not_in_setup"
+	PlacementInvalid     = "invalid"
+)
+
+// AttackCheck tells whether a shot would be fired, and why not.
+type AttackCheck struct {
+	Valid  bool   `json:"valid"`
+	Code   string `json:"code,omitempty"`   // Machine-readable reason, see the Attack constants
+	Reason string `json:"reason,omitempty"` // Human-readable reason
+}
+
+// Reasons a shot is refused.
+const (
+	AttackNotInPlay       = "not_in_play"
+	AttackNotYourTurn     = "not_your_turn"
+	AttackOutOfBounds     = "out_of_bounds"
+	AttackAlreadyAttacked = "already_attacked"
+	AttackTargetRequired  = "target_required"
+	AttackInvalidTarget   = "invalid_target"
+	AttackInvalid         = "invalid"
+)
+
+// Target is a cell a player may fire at.
+type Target struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Coord string `json:"coord"` // Chess-style notation, e.g. "B7"
+}
+
+// LegalMoves lists where a player may fire on their turn.
+type LegalMoves struct {
+	Targets []Target  `json:"targets"` // Cells not shot at yet
+	Board   BoardView `json:"board"`   // Hits, misses and sunk ships found so far
+}
+
+// MoveRecord is one shot in a match record.
+type MoveRecord struct {
+	Number   int    `json:"move"`
+	Attacker string `json:"attacker"`
+	Target   string `json:"target,omitempty"` // Player fired at, only in a free-for-all
+	Coord    string `json:"coord"`            // Chess-style notation, e.g. "B7"
+	Result   string `json:"result"`           // "hit", "miss", "sunk"
+	// NearbyShip marks a miss next to a ship, in assist mode.
+	NearbyShip bool `json:"nearby_ship,omitempty"`
+}
+
+// MatchRecord is the move history of a match and how it ended.
+type MatchRecord struct {
+	MatchID string       `json:"match_id"`
+	State   GameState    `json:"state"`
+	Winner  string       `json:"winner,omitempty"`
+	Moves   []MoveRecord `json:"moves"`
+
+	PlacementCommitment string `json:"placement_commitment,omitempty"` // Absent until play starts
+}
+
+// RevealView shows both fleets of a won match in full, as a spectator would see them.
+// Along with the fleets come their salts, so that anyone can recompute the placement commitment
+// (commitment.Reveal) and check it is the one the match started with.
+type RevealView struct {
+	MatchID string       `json:"match_id"`
+	Winner  string       `json:"winner"`
+	Players []PlayerView `json:"players"` // Host first
+
+	PlacementCommitment string `json:"placement_commitment"`
+}
+
+// Rules describe how a match is played, so that clients can set themselves up for it
+// instead of assuming the standard game.
+type Rules struct {
+	BoardSize   int        `json:"board_size"`
+	FleetPreset string     `json:"fleet_preset"`
+	HostFleet   []ShipRule `json:"host_fleet"`           // Ships the host starts with, largest first
+	GuestFleet  []ShipRule `json:"guest_fleet"`          // Ships the guest starts with; a handicap makes it differ
+	MoveLimit   int        `json:"move_limit,omitempty"` // Shots both players may fire in total; absent without a limit
+	Assist      bool       `json:"assist"`               // Misses tell whether a ship is right next to them
+	AutoPlace   bool       `json:"auto_place"`           // Fleets are placed at random
+	VsBot       bool       `json:"vs_bot"`               // The guest is the built-in opponent
+	Difficulty  string     `json:"difficulty,omitempty"` // Built-in opponent strength, only with VsBot
+	// ExtraTurnOnHit keeps the turn with a player who hits, until they miss
+	ExtraTurnOnHit bool `json:"extra_turn_on_hit"`
+	TeamSize       int  `json:"team_size"`              // Players on each side, sharing its board and fleet
+	FreeForAll     int  `json:"free_for_all,omitempty"` // Players in a free-for-all; absent otherwise
+}
+
+// ShipRule is one kind of ship in a fleet.
+type ShipRule struct {
+	Name  string `json:"name,omitempty"` // Type name, e.g. "Submarine"; absent for sizes no type has
+	Size  int    `json:"size"`
+	Count int    `json:"count"`
 }
 
 // GameView is the full packet sent to an observer (UI).
 type GameView struct {
-	State  GameState  `json:"state"`
-	Turn   string     `json:"turn"`
-	Winner string     `json:"winner,omitempty"`
-	Me     PlayerView `json:"me"`
-	Enemy  PlayerView `json:"enemy"`
+	State     GameState  `json:"state"`
+	Turn      string     `json:"turn"`
+	YourTurn  bool       `json:"your_turn"` // True when the observer is the player to move
+	Winner    string     `json:"winner,omitempty"`
+	Version   int        `json:"version"` // Increases on every state change
+	Me        PlayerView `json:"me"`
+	Enemy     PlayerView `json:"enemy"`
+	Seed      uint64     `json:"seed,omitempty"`       // Replays the match's random fleets; only told once it is over
+	MovesLeft int        `json:"moves_left,omitempty"` // Shots left before the move limit ends the match; absent without a limit
+	// NearbyShip is set in assist mode when the observer's last shot missed right next to a ship.
+	NearbyShip bool `json:"nearby_ship,omitempty"`
+	// ResumeToken lets the observer take their seat back from another device or login.
+	ResumeToken string `json:"resume_token,omitempty"`
+	// LivingPlayers and TargetIDs are only set in a free-for-all: the players still afloat,
+	// and those of them the observer may fire at.
+	LivingPlayers []string `json:"living_players,omitempty"`
+	TargetIDs     []string `json:"target_ids,omitempty"`
+	// Rules tell how the match is played, so that a client can size its grid and list the ships
+	// to place from any view, without assuming the standard game.
+	Rules Rules `json:"rules"`
+	// PlacementCommitment is a hash of every fleet as placed when play started, set from then on.
+	// The fleets and salts revealed at the end must hash to it, so no board can change mid-game unnoticed.
+	PlacementCommitment string `json:"placement_commitment,omitempty"`
 }
 
 // User represents a registered user.
@@ -55,25 +192,156 @@ type User struct {
 	Username string `json:"username"`
 }
 
+// ErrorResponse is the body of an error answer.
+// Requests that fail validation list every problem found, so that they can all be fixed at once.
+// Answers a client is expected to react to carry a Code, which unlike the message never changes.
+type ErrorResponse struct {
+	Message  string   `json:"message"`
+	Problems []string `json:"problems,omitempty"`
+	Code     string   `json:"code,omitempty"`
+}
+
+// ErrorCodeRateLimited marks a 429: the client should wait as long as the Retry-After header says.
+const ErrorCodeRateLimited = "RATE_LIMITED"
+
 // AuthResponse serves the JWT token along with user info.
 type AuthResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
 }
 
+// ScoreboardEntry is a match in progress as the live scoreboard shows it.
+// It holds nothing a spectator may not know, so no ship position is on it.
+type ScoreboardEntry struct {
+	MatchID   string             `json:"match_id"`
+	State     GameState          `json:"state"`
+	Players   []ScoreboardPlayer `json:"players"`        // Host first
+	Turn      string             `json:"turn,omitempty"` // Player to move; absent during setup
+	Shots     int                `json:"shots"`          // Shots fired so far by both players
+	Watchers  int                `json:"watchers"`       // Clients streaming the match, players included
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ScoreboardPlayer is a player of a match on the live scoreboard.
+type ScoreboardPlayer struct {
+	ID          string `json:"id"`
+	ShipsAfloat int    `json:"ships_afloat"`
+}
+
 // MatchSummary is used for the "Lobby List" screen.
 type MatchSummary struct {
-	ID          string    `json:"match_id"`
-	HostName    string    `json:"host_name"`
-	PlayerCount int       `json:"player_count"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string        `json:"match_id"`
+	HostName    string        `json:"host_name"`
+	PlayerCount int           `json:"player_count"`
+	State       GameState     `json:"state"`
+	Watchers    int           `json:"watchers"` // Clients streaming the match, players included
+	Settings    MatchSettings `json:"settings"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// MatchSettings are the rules a host picks when creating a match.
+// Zero values select the standard 10x10 board and fleet against a human opponent.
+type MatchSettings struct {
+	BoardSize   int    `json:"board_size,omitempty"`
+	FleetPreset string `json:"fleet_preset,omitempty"`
+	VsBot       bool   `json:"vs_bot,omitempty"`     // Play against the built-in opponent
+	Difficulty  string `json:"difficulty,omitempty"` // Built-in opponent strength, only used with VsBot
+	AutoPlace   bool   `json:"auto_place,omitempty"` // Place both fleets at random once the opponent joins
+	// Seed makes the random fleets, and the built-in opponent's shots, the same every time.
+	// It requires VsBot or AutoPlace; one is picked when they are set without a seed.
+	Seed uint64 `json:"seed,omitempty"`
+	// MoveLimit is how many shots both players may fire in total. The match then goes to whoever
+	// has more ship cells afloat, or is drawn if they are level. Zero means no limit.
+	MoveLimit int `json:"move_limit,omitempty"`
+	// Assist makes every miss tell whether a ship is on one of its four sides.
+	Assist bool `json:"assist,omitempty"`
+	// ExtraTurnOnHit lets a player who hits or sinks a ship fire again, until they miss.
+	ExtraTurnOnHit bool `json:"extra_turn_on_hit,omitempty"`
+	// HostHandicap and GuestHandicap give one player extra ships, or fewer with negative counts,
+	// by ship type name, e.g. {"Destroyer": 1}. Each fleet must still fit the board.
+	HostHandicap  map[string]int `json:"host_handicap,omitempty"`
+	GuestHandicap map[string]int `json:"guest_handicap,omitempty"`
+	// TeamSize is how many players each side has, up to two for a 2v2. Teammates share a board and fleet.
+	// Zero or one is a plain match between two players.
+	TeamSize int `json:"team_size,omitempty"`
+	// FreeForAll makes the match a free-for-all between that many players, 3-6, each on their own side.
+	// Every shot names its target, and the last fleet afloat wins. Zero is a match between two sides.
+	FreeForAll int `json:"free_for_all,omitempty"`
+	// Owner, when set, may cancel the match in place of its host, as a tournament does with its matches.
+	// Only services set it; clients can neither set nor see it.
+	Owner string `json:"-"`
+}
+
+// Built-in opponent difficulties.
+const (
+	DifficultyEasy   = "easy"   // Fires at random
+	DifficultyMedium = "medium" // Finishes off the ships it hits
+	DifficultyHard   = "hard"   // Also hunts in a checkerboard pattern
+)
+
+// AIPlayerPrefix starts the player ID of every built-in opponent.
+const AIPlayerPrefix = "ai:"
+
+// MatchFilter narrows down which matches a lobby listing returns.
+// The zero value lists only the matches that are still waiting for an opponent.
+type MatchFilter struct {
+	IncludeLive bool // Also list matches that are being set up or played
 }
 
+// TournamentState represents the phase of a tournament.
+type TournamentState string
+
+// Possible TournamentState values.
+const (
+	TournamentRegistering TournamentState = "REGISTERING" // Players may still sign up
+	TournamentRunning     TournamentState = "RUNNING"
+	TournamentFinished    TournamentState = "FINISHED"
+	TournamentFailed      TournamentState = "FAILED" // A round could not be started, see Error
+)
+
+// TournamentView is the bracket and standings of a single-elimination tournament.
+type TournamentView struct {
+	ID        string          `json:"tournament_id"`
+	HostID    string          `json:"host_id"`
+	State     TournamentState `json:"state"`
+	Settings  MatchSettings   `json:"settings"` // Rules of every match
+	Players   []string        `json:"players"`  // In registration order, which is also the seeding
+	Rounds    [][]Pairing     `json:"rounds"`
+	Standings []Standing      `json:"standings"`
+	Winner    string          `json:"winner,omitempty"`
+	Error     string          `json:"error,omitempty"` // Why the tournament failed
+}
+
+// Pairing is a match between two players in a tournament round.
+type Pairing struct {
+	PlayerA string `json:"player_a"`
+	PlayerB string `json:"player_b,omitempty"` // Empty when PlayerA has a bye
+	MatchID string `json:"match_id,omitempty"`
+	Winner  string `json:"winner,omitempty"`
+}
+
+// Standing is how far a player has got in a tournament.
+type Standing struct {
+	PlayerID   string `json:"player_id"`
+	Wins       int    `json:"wins"` // Byes included
+	Eliminated bool   `json:"eliminated"`
+}
+
+// Reasons the server gives in the close frame that ends a match stream, so clients can tell the player why.
+const (
+	StreamClosedGameOver        = "game_over"        // The match was won, drawn or abandoned
+	StreamClosedMatchCancelled  = "match_cancelled"  // The host cancelled the match
+	StreamClosedMatchTerminated = "match_terminated" // An operator removed the match
+	StreamClosedIdleTimeout     = "idle_timeout"     // The match was cleaned up after going idle
+)
+
 // WSEvent is a unified container for all WebSocket messages.
 type WSEvent struct {
-	Type    string    `json:"type"`              // e.g., "game_update", "error"
-	Payload *GameView `json:"payload,omitempty"` // The game state
-	Error   string    `json:"error,omitempty"`   // Error message if any
+	Type    string           `json:"type"`              // e.g., "game_update", "game_event", "error"
+	Payload *GameView        `json:"payload,omitempty"` // The game state
+	Attack  *AttackEventData `json:"attack,omitempty"`  // The shot behind a game_update, to animate its cell
+	Event   *GameEvent       `json:"event,omitempty"`   // A replayed event, when backfilling a reconnect
+	Error   string           `json:"error,omitempty"`   // Error message if any
 }
 
 // EventType represents the type of game event.
@@ -81,12 +349,17 @@ type EventType string
 
 // EventType possible values
 const (
-	EventPlayerJoined EventType = "player.joined"
-	EventShipPlaced   EventType = "ship.placed"
-	EventAttackMade   EventType = "attack.made"
-	EventGameStarted  EventType = "game.started"
-	EventGameOver     EventType = "game.over"
-	EventTurnChanged  EventType = "turn.changed"
+	EventPlayerJoined    EventType = "player.joined"
+	EventShipPlaced      EventType = "ship.placed"
+	EventPlayerReady     EventType = "player.ready"
+	EventAttackMade      EventType = "attack.made"
+	EventGameStarted     EventType = "game.started"
+	EventGameOver        EventType = "game.over"
+	EventTurnChanged     EventType = "turn.changed"
+	EventGameAbandoned   EventType = "game.abandoned"
+	EventMatchCancelled  EventType = "match.cancelled"
+	EventPlayerResumed   EventType = "player.resumed"
+	EventMatchTerminated EventType = "match.terminated" // An operator removed the match
 )
 
 // GameEvent represents a game event that can be published to subscribers.
@@ -96,25 +369,86 @@ type GameEvent struct {
 	PlayerID  string    `json:"player_id,omitempty"` // Player who triggered the event
 	TargetID  string    `json:"target_id,omitempty"` // Player who should be notified
 	Data      any       `json:"data,omitempty"`
+	Version   int       `json:"version,omitempty"` // Game version right after the event
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// UnmarshalJSON decodes the event's data into the type its event type publishes,
+// so that events read from the API carry the same data as in-process ones.
+func (e *GameEvent) UnmarshalJSON(b []byte) error {
+	type plain GameEvent
+	var raw struct {
+		plain
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = GameEvent(raw.plain)
+
+	if len(raw.Data) == 0 {
+		return nil
+	}
+
+	switch e.Type {
+	case EventAttackMade:
+		var data AttackEventData
+		if err := json.Unmarshal(raw.Data, &data); err != nil {
+			return err
+		}
+		e.Data = data
+	case EventShipPlaced:
+		var data ShipPlacedEventData
+		if err := json.Unmarshal(raw.Data, &data); err != nil {
+			return err
+		}
+		e.Data = data
+	case EventGameOver:
+		var data GameOverEventData
+		if err := json.Unmarshal(raw.Data, &data); err != nil {
+			return err
+		}
+		e.Data = data
+	default:
+		var data any
+		if err := json.Unmarshal(raw.Data, &data); err != nil {
+			return err
+		}
+		e.Data = data
+	}
+
+	return nil
+}
+
 // AttackEventData contains data for attack events.
 type AttackEventData struct {
-	X      int    `json:"x"`
-	Y      int    `json:"y"`
-	Result string `json:"result"` // "hit", "miss", "sunk"
+	Attacker string `json:"attacker,omitempty"`
+	Target   string `json:"target,omitempty"` // Player whose board was fired at, only in a free-for-all
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Coord    string `json:"coord"`  // Chess-style notation, e.g. "B7"
+	Result   string `json:"result"` // "hit", "miss", "sunk"
+	// NearbyShip marks a miss next to a ship, in assist mode.
+	NearbyShip bool `json:"nearby_ship,omitempty"`
+	// NextTurn is the player who fires next, who is not always the one notified. Empty once the game is over.
+	NextTurn string `json:"next_turn,omitempty"`
 }
 
 // ShipPlacedEventData contains data for ship placement events.
 type ShipPlacedEventData struct {
-	Size     int  `json:"size"`
-	X        int  `json:"x"`
-	Y        int  `json:"y"`
-	Vertical bool `json:"vertical"`
+	Size     int    `json:"size"`
+	Ship     string `json:"ship,omitempty"` // Ship type, when placed by type
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Vertical bool   `json:"vertical"`
 }
 
 // GameOverEventData contains data for game over events.
 type GameOverEventData struct {
 	Winner string `json:"winner"`
 }
+
+// PlayerResumedEventData contains data for events of a player taking their seat back under a new ID.
+type PlayerResumedEventData struct {
+	Previous string `json:"previous"` // The ID the seat had before
+}
@@ -0,0 +1,65 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// dotEnvFile is read from the working directory for local development.
+const dotEnvFile = ".env"
+
+// loadDotEnv sets the variables listed in the .env file, if there is one.
+// Variables already in the environment win over the file, and the file is
+// ignored entirely when ENV is production, where the environment is set directly.
+//
+// Lines are KEY=VALUE, optionally prefixed with "export". Blank lines and lines
+// starting with # are skipped, and values may be wrapped in single or double quotes.
+func loadDotEnv() error {
+	if os.Getenv("ENV") == "production" {
+		return nil
+	}
+
+	f, err := os.Open(dotEnvFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s line %d: expected KEY=VALUE", dotEnvFile, n)
+		}
+
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, unquote(strings.TrimSpace(value))); err != nil {
+			return fmt.Errorf("%s line %d: %w", dotEnvFile, n, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// unquote strips one pair of matching single or double quotes around the value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,120 @@
+package env
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests here change the working directory and the environment, so none of them runs in parallel.
+
+func TestLoadDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string            // Contents of the .env file, which is absent if empty
+		env     map[string]string // Set before loading
+		want    map[string]string // Set after loading
+		unset   []string          // Still unset after loading
+		wantErr string
+	}{
+		{
+			name:  "No File",
+			unset: []string{"DOTENV_TEST_A"},
+		},
+		{
+			name: "Values",
+			file: "DOTENV_TEST_A=plain\n" +
+				"DOTENV_TEST_B = \"double quoted\" \n" +
+				"DOTENV_TEST_C='single quoted'\n" +
+				"DOTENV_TEST_D=\"mismatched'\n" +
+				"export DOTENV_TEST_E=exported\n" +
+				"DOTENV_TEST_F=a=b\n" +
+				"DOTENV_TEST_G=\n",
+			want: map[string]string{
+				"DOTENV_TEST_A": "plain",
+				"DOTENV_TEST_B": "double quoted",
+				"DOTENV_TEST_C": "single quoted",
+				"DOTENV_TEST_D": "\"mismatched'",
+				"DOTENV_TEST_E": "exported",
+				"DOTENV_TEST_F": "a=b",
+				"DOTENV_TEST_G": "",
+			},
+		},
+		{
+			name:  "Comments And Blank Lines",
+			file:  "# A comment\n\n   \nDOTENV_TEST_A=1\n  # DOTENV_TEST_B=2\n",
+			want:  map[string]string{"DOTENV_TEST_A": "1"},
+			unset: []string{"DOTENV_TEST_B"},
+		},
+		{
+			name:    "Malformed Line",
+			file:    "DOTENV_TEST_A=1\n\nNOT A SETTING\nDOTENV_TEST_B=2\n",
+			want:    map[string]string{"DOTENV_TEST_A": "1"},
+			unset:   []string{"DOTENV_TEST_B"},
+			wantErr: ".env line 3: expected KEY=VALUE",
+		},
+		{
+			name:    "Missing Key",
+			file:    "=value\n",
+			wantErr: ".env line 1: expected KEY=VALUE",
+		},
+		{
+			name: "Environment Wins",
+			file: "DOTENV_TEST_A=from file\nDOTENV_TEST_B=from file\n",
+			env:  map[string]string{"DOTENV_TEST_A": "from environment", "DOTENV_TEST_B": ""},
+			want: map[string]string{"DOTENV_TEST_A": "from environment", "DOTENV_TEST_B": ""},
+		},
+		{
+			name:  "Ignored In Production",
+			file:  "DOTENV_TEST_A=1\nNOT A SETTING\n",
+			env:   map[string]string{"ENV": "production"},
+			unset: []string{"DOTENV_TEST_A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			if tt.file != "" {
+				require.NoError(t, os.WriteFile(dotEnvFile, []byte(tt.file), 0o600))
+			}
+
+			unsetEnv(t, "ENV")
+			for _, key := range tt.unset {
+				unsetEnv(t, key)
+			}
+			for key := range tt.want {
+				unsetEnv(t, key)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			err := loadDotEnv()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for key, want := range tt.want {
+				got, ok := os.LookupEnv(key)
+				assert.True(t, ok, "%s should be set", key)
+				assert.Equal(t, want, got, key)
+			}
+			for _, key := range tt.unset {
+				_, ok := os.LookupEnv(key)
+				assert.False(t, ok, "%s should not be set", key)
+			}
+		})
+	}
+}
+
+// unsetEnv removes the variable for the rest of the test, restoring it afterwards.
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	require.NoError(t, os.Unsetenv(key))
+}
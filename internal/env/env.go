@@ -5,14 +5,89 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// Default token claims, shared by the server and the bot.
+const (
+	defaultJWTIssuer   = "battleship"
+	defaultJWTAudience = "battleship-api"
+)
+
+// defaultJWTSecret signs tokens when JWT_SECRET is unset. It is public, so production refuses it.
+const defaultJWTSecret = "secret"
+
 // Config holds all application configuration from environment variables.
 type Config struct {
+	// Environment is the deployment environment, e.g. "development" or "production".
+	Environment string
+	// Warnings lists the unsafe settings tolerated outside production, for the caller to log.
+	Warnings []string
+
+	// LogLevel is the least severe level written: debug, info, warn or error.
+	// LogFormat is text or json, the latter for log aggregators.
+	LogLevel  string
+	LogFormat string
+
 	// Server configuration
 	Port      string
 	RateLimit int
 	JWTSecret string
+	// JWTIssuer and JWTAudience are put in issued tokens and required from incoming ones.
+	// Empty values disable the claim.
+	JWTIssuer   string
+	JWTAudience string
+	// JWTSigningMethod is HS256 (shared JWTSecret) or RS256 (key pair).
+	// RS256 needs JWTPrivateKeyFile; the public key is derived from it unless JWTPublicKeyFile is set.
+	JWTSigningMethod  string
+	JWTPrivateKeyFile string
+	JWTPublicKeyFile  string
+	AutoReady         bool // Start games as soon as both fleets are placed, without an explicit ready step
+	// MaxActiveGames is how many unfinished matches a player may take part in at once. Zero or less removes the limit.
+	MaxActiveGames int
+	// MaxTotalGames is how many matches the server holds at once. Finished matches are evicted to make room,
+	// so only unfinished ones can fill it up. Zero or less removes the limit.
+	MaxTotalGames int
+	// BotAPIKey lets trusted integrations such as the Discord bot log players in for them.
+	// Empty disables platform logins.
+	BotAPIKey string
+	// AdminAPIKey lets operators run admin actions, such as terminating a stuck match.
+	// It must differ from BotAPIKey. Empty disables the admin routes.
+	AdminAPIKey string
+	// AbandonGracePeriod is how long a started match may sit with no subscribers
+	// and no moves before it is marked abandoned. Zero disables it.
+	AbandonGracePeriod time.Duration
+
+	// Request body limits in bytes. BodyLimit caps every route; GameBodyLimit tightens it for
+	// match and tournament actions and AuthBodyLimit for logins. A larger route limit has no effect,
+	// and zero or less sets no limit.
+	BodyLimit     int64
+	GameBodyLimit int64
+	AuthBodyLimit int64
+
+	// Match garbage collection
+	GCInterval  time.Duration
+	FinishedTTL time.Duration // How long finished matches are kept
+	StaleTTL    time.Duration // How long unfinished matches without activity are kept
+
+	// CORS configuration. Empty methods or headers use the middleware defaults.
+	CORSAllowOrigins []string
+	CORSAllowMethods []string
+	CORSAllowHeaders []string
+
+	// WebSocket configuration. Zero buffer sizes use the library defaults.
+	WSWriteTimeout    time.Duration
+	WSReadBufferSize  int
+	WSWriteBufferSize int
+	// Events closer together than this are streamed as a single update. Zero streams one update per event.
+	WSCoalesceWindow time.Duration
+
+	// How long GET /matches/:id/poll waits for the game to change
+	PollTimeout time.Duration
+
+	// MetricsEnabled times the main service calls and serves the latencies at GET /metrics
+	MetricsEnabled bool
 
 	// Client configuration
 	BaseURL string
@@ -20,29 +95,129 @@ type Config struct {
 	// Discord bot configuration
 	DiscordToken string
 	DiscordAppID string
+	// DiscordGuildID limits slash commands to one server, where they update instantly.
+	// Empty registers them globally.
+	DiscordGuildID string
+	// DiscordNotifyCooldown is the minimum time between two notification posts in a channel.
+	DiscordNotifyCooldown time.Duration
+	// ServerURL is the Battleship server the bot logs players in with, using BotAPIKey.
+	// Empty keeps the bot self-contained.
+	ServerURL string
 }
 
 // LoadClientConfig loads configuration required for the client.
+// Like the other loaders, it first reads a .env file in the working directory, see loadDotEnv.
 func LoadClientConfig() (*Config, error) {
+	if err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		BaseURL:   getEnvOrDefault("BASE_URL", "http://localhost:8080"),
-		JWTSecret: getEnvOrDefault("JWT_SECRET", "secret"),
+		JWTSecret: getEnvOrDefault("JWT_SECRET", defaultJWTSecret),
 	}, nil
 }
 
 // LoadServerConfig loads configuration required for the HTTP server.
 func LoadServerConfig() (*Config, error) {
+	if err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+
+	environment := getEnvOrDefault("ENV", "development")
+
+	// Any origin is only allowed by default while developing
+	var defaultOrigins []string
+	if environment == "development" {
+		defaultOrigins = []string{"*"}
+	}
+
 	cfg := &Config{
+		Environment: environment,
+
+		LogLevel:  getEnvOrDefault("LOG_LEVEL", "info"),
+		LogFormat: getEnvOrDefault("LOG_FORMAT", "text"),
+
 		Port:      getEnvOrDefault("PORT", "8080"),
 		RateLimit: getEnvAsIntOrDefault("RATE_LIMIT", 20),
-		JWTSecret: getEnvOrDefault("JWT_SECRET", "secret"),
+		JWTSecret: getEnvOrDefault("JWT_SECRET", defaultJWTSecret),
+		AutoReady: getEnvAsBoolOrDefault("AUTO_READY", true),
+
+		MaxActiveGames: getEnvAsIntOrDefault("MAX_ACTIVE_GAMES_PER_USER", 1),
+		MaxTotalGames:  getEnvAsIntOrDefault("MAX_TOTAL_GAMES", 0),
+
+		JWTIssuer:   getEnvOrDefault("JWT_ISSUER", defaultJWTIssuer),
+		JWTAudience: getEnvOrDefault("JWT_AUDIENCE", defaultJWTAudience),
+
+		BotAPIKey:   os.Getenv("BOT_API_KEY"),
+		AdminAPIKey: os.Getenv("ADMIN_API_KEY"),
+
+		JWTSigningMethod:  strings.ToUpper(getEnvOrDefault("JWT_SIGNING_METHOD", "HS256")),
+		JWTPrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+		JWTPublicKeyFile:  os.Getenv("JWT_PUBLIC_KEY_FILE"),
+
+		AbandonGracePeriod: getEnvAsDurationOrDefault("ABANDON_GRACE_PERIOD", 10*time.Minute),
+
+		// No game request comes close to these; login is roomier in case auth payloads grow
+		BodyLimit:     getEnvAsByteSizeOrDefault("BODY_LIMIT", 64<<10),
+		GameBodyLimit: getEnvAsByteSizeOrDefault("GAME_BODY_LIMIT", 4<<10),
+		AuthBodyLimit: getEnvAsByteSizeOrDefault("AUTH_BODY_LIMIT", 16<<10),
+
+		GCInterval:  getEnvAsDurationOrDefault("GC_INTERVAL", time.Minute),
+		FinishedTTL: getEnvAsDurationOrDefault("FINISHED_TTL", 10*time.Minute),
+		StaleTTL:    getEnvAsDurationOrDefault("STALE_TTL", 24*time.Hour),
+
+		CORSAllowOrigins: getEnvAsSliceOrDefault("CORS_ALLOW_ORIGINS", defaultOrigins),
+		CORSAllowMethods: getEnvAsSliceOrDefault("CORS_ALLOW_METHODS", nil),
+		CORSAllowHeaders: getEnvAsSliceOrDefault("CORS_ALLOW_HEADERS", nil),
+
+		WSWriteTimeout:    getEnvAsDurationOrDefault("WS_WRITE_TIMEOUT", 10*time.Second),
+		WSReadBufferSize:  getEnvAsIntOrDefault("WS_READ_BUFFER_SIZE", 1024),
+		WSWriteBufferSize: getEnvAsIntOrDefault("WS_WRITE_BUFFER_SIZE", 1024),
+		WSCoalesceWindow:  getEnvAsDurationOrDefault("WS_COALESCE_WINDOW", 50*time.Millisecond),
+
+		PollTimeout: getEnvAsDurationOrDefault("POLL_TIMEOUT", 25*time.Second),
+
+		MetricsEnabled: getEnvAsBoolOrDefault("METRICS_ENABLED", false),
+	}
+
+	problems := unsafeServerSettings(cfg)
+	if environment == "production" && len(problems) > 0 {
+		return nil, fmt.Errorf("refusing to start in production: %s", strings.Join(problems, "; "))
 	}
+	cfg.Warnings = problems
 
 	return cfg, nil
 }
 
+// unsafeServerSettings lists the settings that are fine for development but not for production.
+func unsafeServerSettings(cfg *Config) []string {
+	var problems []string
+	if cfg.JWTSigningMethod == "HS256" && cfg.JWTSecret == defaultJWTSecret {
+		problems = append(problems, "JWT_SECRET is left at its public default, set it to a long random string")
+	}
+	if cfg.RateLimit <= 0 {
+		problems = append(problems, fmt.Sprintf("RATE_LIMIT must be positive, got %d", cfg.RateLimit))
+	}
+	if cfg.AdminAPIKey != "" && cfg.AdminAPIKey == cfg.BotAPIKey {
+		problems = append(problems, "ADMIN_API_KEY is the same as BOT_API_KEY, so the bot could run admin actions")
+	}
+	if os.Getenv("PORT") == "" {
+		problems = append(problems, "PORT is not set, defaulting to "+cfg.Port)
+	}
+	return problems
+}
+
 // LoadBotConfig loads configuration required for the Discord bot.
 func LoadBotConfig() (*Config, error) {
+	if err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+
+	if os.Getenv("SERVER_URL") != "" && os.Getenv("BOT_API_KEY") == "" {
+		return nil, fmt.Errorf("BOT_API_KEY environment variable is required with SERVER_URL")
+	}
+
 	token := os.Getenv("DISCORD_TOKEN")
 	if token == "" {
 		return nil, fmt.Errorf("DISCORD_TOKEN environment variable is required")
@@ -54,9 +229,29 @@ func LoadBotConfig() (*Config, error) {
 	}
 
 	cfg := &Config{
-		DiscordToken: token,
-		DiscordAppID: appID,
-		JWTSecret:    getEnvOrDefault("JWT_SECRET", "secret"),
+		LogLevel:  getEnvOrDefault("LOG_LEVEL", "info"),
+		LogFormat: getEnvOrDefault("LOG_FORMAT", "text"),
+
+		DiscordToken:          token,
+		DiscordAppID:          appID,
+		DiscordGuildID:        os.Getenv("DISCORD_GUILD_ID"),
+		DiscordNotifyCooldown: getEnvAsDurationOrDefault("DISCORD_NOTIFY_COOLDOWN", time.Second),
+
+		ServerURL: os.Getenv("SERVER_URL"),
+		BotAPIKey: os.Getenv("BOT_API_KEY"),
+
+		JWTSecret: getEnvOrDefault("JWT_SECRET", defaultJWTSecret),
+		AutoReady: getEnvAsBoolOrDefault("AUTO_READY", true),
+
+		MaxActiveGames: getEnvAsIntOrDefault("MAX_ACTIVE_GAMES_PER_USER", 1),
+		MaxTotalGames:  getEnvAsIntOrDefault("MAX_TOTAL_GAMES", 0),
+
+		JWTIssuer:   getEnvOrDefault("JWT_ISSUER", defaultJWTIssuer),
+		JWTAudience: getEnvOrDefault("JWT_AUDIENCE", defaultJWTAudience),
+
+		GCInterval:  getEnvAsDurationOrDefault("GC_INTERVAL", time.Minute),
+		FinishedTTL: getEnvAsDurationOrDefault("FINISHED_TTL", 10*time.Minute),
+		StaleTTL:    getEnvAsDurationOrDefault("STALE_TTL", 24*time.Hour),
 	}
 
 	return cfg, nil
@@ -79,3 +274,60 @@ func getEnvAsIntOrDefault(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBoolOrDefault(key string, defaultValue bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsByteSizeOrDefault reads a positive size in bytes, optionally followed by K or M
+// for kibibytes or mebibytes, e.g. "4K". An optional trailing B is ignored.
+func getEnvAsByteSizeOrDefault(key string, defaultValue int64) int64 {
+	val := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(os.Getenv(key))), "B")
+	if val == "" {
+		return defaultValue
+	}
+
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(val, "K"):
+		unit, val = 1<<10, strings.TrimSuffix(val, "K")
+	case strings.HasSuffix(val, "M"):
+		unit, val = 1<<20, strings.TrimSuffix(val, "M")
+	}
+
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || n <= 0 || n > (1<<40)/unit {
+		return defaultValue
+	}
+	return n * unit
+}
+
+// getEnvAsSliceOrDefault reads a comma-separated list, ignoring blank entries.
+func getEnvAsSliceOrDefault(key string, defaultValue []string) []string {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for item := range strings.SplitSeq(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
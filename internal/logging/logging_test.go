@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, "INFO", "JSON")
+		require.NoError(t, err)
+
+		logger.Debug("hidden")
+		logger.Info("match created", "match_id", "m1")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), "only the info entry is written")
+		assert.Equal(t, "match created", entry["msg"])
+		assert.Equal(t, "m1", entry["match_id"])
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, "warn", logging.FormatText)
+		require.NoError(t, err)
+
+		logger.Info("hidden")
+		logger.Warn("rate limited")
+		assert.Contains(t, buf.String(), "msg=\"rate limited\"")
+		assert.NotContains(t, buf.String(), "hidden")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := logging.New(&bytes.Buffer{}, "chatty", logging.FormatText)
+		assert.ErrorContains(t, err, "log level")
+
+		_, err = logging.New(&bytes.Buffer{}, "info", "xml")
+		assert.ErrorContains(t, err, "log format")
+	})
+}
@@ -0,0 +1,115 @@
+// Package metrics records latency histograms and writes them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets used unless others are given.
+// They span the sub-millisecond in-memory calls up to calls stuck behind a contended lock.
+var DefaultBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1}
+
+// Histogram counts observed durations in buckets, separately for each value of its label.
+type Histogram struct {
+	name    string
+	help    string
+	label   string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	counts []uint64 // Observations per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram returns a histogram with one series per value of label.
+// Nil buckets use DefaultBuckets.
+func NewHistogram(name, help, label string, buckets []float64) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &Histogram{
+		name:    name,
+		help:    help,
+		label:   label,
+		buckets: slices.Sorted(slices.Values(buckets)),
+		series:  make(map[string]*series),
+	}
+}
+
+// Observe records a duration for the given label value.
+func (h *Histogram) Observe(value string, d time.Duration) {
+	seconds := d.Seconds()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[value]
+	if !ok {
+		s = &series{counts: make([]uint64, len(h.buckets))}
+		h.series[value] = s
+	}
+	if i, _ := slices.BinarySearch(h.buckets, seconds); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.sum += seconds
+	s.count++
+}
+
+// Since records the time elapsed since start for the given label value.
+// It is meant to be deferred: defer h.Since("attack", time.Now()).
+func (h *Histogram) Since(value string, start time.Time) {
+	h.Observe(value, time.Since(start))
+}
+
+// WriteTo writes the histogram in the Prometheus text exposition format.
+func (h *Histogram) WriteTo(w io.Writer) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	fmt.Fprintf(cw, "# HELP %s %s\n", h.name, h.help)
+	fmt.Fprintf(cw, "# TYPE %s histogram\n", h.name)
+
+	for _, value := range slices.Sorted(maps.Keys(h.series)) {
+		s := h.series[value]
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(cw, "%s_bucket{%s=%q,le=%q} %d\n",
+				h.name, h.label, value, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(cw, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", h.name, h.label, value, s.count)
+		fmt.Fprintf(cw, "%s_sum{%s=%q} %s\n", h.name, h.label, value, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(cw, "%s_count{%s=%q} %d\n", h.name, h.label, value, s.count)
+	}
+
+	return cw.n, cw.err
+}
+
+// countingWriter keeps the byte count and the first error of a series of writes.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,41 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+
+	h := metrics.NewHistogram("call_seconds", "Call latency.", "operation", []float64{0.1, 0.01})
+	h.Observe("attack", 5*time.Millisecond)
+	h.Observe("attack", 10*time.Millisecond) // On a bound, which is inclusive
+	h.Observe("attack", 50*time.Millisecond)
+	h.Observe("attack", time.Second)
+	h.Observe("get_state", time.Millisecond)
+
+	var buf bytes.Buffer
+	n, err := h.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	assert.Equal(t, `# HELP call_seconds Call latency.
+# TYPE call_seconds histogram
+call_seconds_bucket{operation="attack",le="0.01"} 2
+call_seconds_bucket{operation="attack",le="0.1"} 3
+call_seconds_bucket{operation="attack",le="+Inf"} 4
+call_seconds_sum{operation="attack"} 1.065
+call_seconds_count{operation="attack"} 4
+call_seconds_bucket{operation="get_state",le="0.01"} 1
+call_seconds_bucket{operation="get_state",le="0.1"} 1
+call_seconds_bucket{operation="get_state",le="+Inf"} 1
+call_seconds_sum{operation="get_state"} 0.001
+call_seconds_count{operation="get_state"} 1
+`, buf.String())
+}
@@ -122,12 +122,668 @@ func (_c *MockGameService_Attack_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// AttackTarget provides a mock function for the type MockGameService
+func (_mock *MockGameService) AttackTarget(ctx context.Context, matchID string, playerID string, targetID string, x int, y int) (dto.GameView, error) {
+	ret := _mock.Called(ctx, matchID, playerID, targetID, x, y)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttackTarget")
+	}
+
+	var r0 dto.GameView
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) (dto.GameView, error)); ok {
+		return returnFunc(ctx, matchID, playerID, targetID, x, y)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) dto.GameView); ok {
+		r0 = returnFunc(ctx, matchID, playerID, targetID, x, y)
+	} else {
+		r0 = ret.Get(0).(dto.GameView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, int, int) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, targetID, x, y)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_AttackTarget_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttackTarget'
+type MockGameService_AttackTarget_Call struct {
+	*mock.Call
+}
+
+// AttackTarget is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - targetID string
+//   - x int
+//   - y int
+func (_e *MockGameService_Expecter) AttackTarget(ctx interface{}, matchID interface{}, playerID interface{}, targetID interface{}, x interface{}, y interface{}) *MockGameService_AttackTarget_Call {
+	return &MockGameService_AttackTarget_Call{Call: _e.mock.On("AttackTarget", ctx, matchID, playerID, targetID, x, y)}
+}
+
+func (_c *MockGameService_AttackTarget_Call) Run(run func(ctx context.Context, matchID string, playerID string, targetID string, x int, y int)) *MockGameService_AttackTarget_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		var arg5 int
+		if args[5] != nil {
+			arg5 = args[5].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_AttackTarget_Call) Return(gameView dto.GameView, err error) *MockGameService_AttackTarget_Call {
+	_c.Call.Return(gameView, err)
+	return _c
+}
+
+func (_c *MockGameService_AttackTarget_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, targetID string, x int, y int) (dto.GameView, error)) *MockGameService_AttackTarget_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EventsSince provides a mock function for the type MockGameService
+func (_mock *MockGameService) EventsSince(ctx context.Context, matchID string, playerID string, since int) ([]dto.GameEvent, error) {
+	ret := _mock.Called(ctx, matchID, playerID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EventsSince")
+	}
+
+	var r0 []dto.GameEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) ([]dto.GameEvent, error)); ok {
+		return returnFunc(ctx, matchID, playerID, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) []dto.GameEvent); ok {
+		r0 = returnFunc(ctx, matchID, playerID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.GameEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_EventsSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EventsSince'
+type MockGameService_EventsSince_Call struct {
+	*mock.Call
+}
+
+// EventsSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - since int
+func (_e *MockGameService_Expecter) EventsSince(ctx interface{}, matchID interface{}, playerID interface{}, since interface{}) *MockGameService_EventsSince_Call {
+	return &MockGameService_EventsSince_Call{Call: _e.mock.On("EventsSince", ctx, matchID, playerID, since)}
+}
+
+func (_c *MockGameService_EventsSince_Call) Run(run func(ctx context.Context, matchID string, playerID string, since int)) *MockGameService_EventsSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_EventsSince_Call) Return(gameEvents []dto.GameEvent, err error) *MockGameService_EventsSince_Call {
+	_c.Call.Return(gameEvents, err)
+	return _c
+}
+
+func (_c *MockGameService_EventsSince_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, since int) ([]dto.GameEvent, error)) *MockGameService_EventsSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetState provides a mock function for the type MockGameService
 func (_mock *MockGameService) GetState(ctx context.Context, matchID string, playerID string) (dto.GameView, error) {
 	ret := _mock.Called(ctx, matchID, playerID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetState")
+		panic("no return value specified for GetState")
+	}
+
+	var r0 dto.GameView
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (dto.GameView, error)); ok {
+		return returnFunc(ctx, matchID, playerID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) dto.GameView); ok {
+		r0 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r0 = ret.Get(0).(dto.GameView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_GetState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetState'
+type MockGameService_GetState_Call struct {
+	*mock.Call
+}
+
+// GetState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+func (_e *MockGameService_Expecter) GetState(ctx interface{}, matchID interface{}, playerID interface{}) *MockGameService_GetState_Call {
+	return &MockGameService_GetState_Call{Call: _e.mock.On("GetState", ctx, matchID, playerID)}
+}
+
+func (_c *MockGameService_GetState_Call) Run(run func(ctx context.Context, matchID string, playerID string)) *MockGameService_GetState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_GetState_Call) Return(gameView dto.GameView, err error) *MockGameService_GetState_Call {
+	_c.Call.Return(gameView, err)
+	return _c
+}
+
+func (_c *MockGameService_GetState_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string) (dto.GameView, error)) *MockGameService_GetState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsPlayersTurn provides a mock function for the type MockGameService
+func (_mock *MockGameService) IsPlayersTurn(ctx context.Context, matchID string, playerID string) (bool, error) {
+	ret := _mock.Called(ctx, matchID, playerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsPlayersTurn")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return returnFunc(ctx, matchID, playerID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_IsPlayersTurn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsPlayersTurn'
+type MockGameService_IsPlayersTurn_Call struct {
+	*mock.Call
+}
+
+// IsPlayersTurn is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+func (_e *MockGameService_Expecter) IsPlayersTurn(ctx interface{}, matchID interface{}, playerID interface{}) *MockGameService_IsPlayersTurn_Call {
+	return &MockGameService_IsPlayersTurn_Call{Call: _e.mock.On("IsPlayersTurn", ctx, matchID, playerID)}
+}
+
+func (_c *MockGameService_IsPlayersTurn_Call) Run(run func(ctx context.Context, matchID string, playerID string)) *MockGameService_IsPlayersTurn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_IsPlayersTurn_Call) Return(b bool, err error) *MockGameService_IsPlayersTurn_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockGameService_IsPlayersTurn_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string) (bool, error)) *MockGameService_IsPlayersTurn_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LegalMoves provides a mock function for the type MockGameService
+func (_mock *MockGameService) LegalMoves(ctx context.Context, matchID string, playerID string, targetID string) (dto.LegalMoves, error) {
+	ret := _mock.Called(ctx, matchID, playerID, targetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LegalMoves")
+	}
+
+	var r0 dto.LegalMoves
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (dto.LegalMoves, error)); ok {
+		return returnFunc(ctx, matchID, playerID, targetID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) dto.LegalMoves); ok {
+		r0 = returnFunc(ctx, matchID, playerID, targetID)
+	} else {
+		r0 = ret.Get(0).(dto.LegalMoves)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, targetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_LegalMoves_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LegalMoves'
+type MockGameService_LegalMoves_Call struct {
+	*mock.Call
+}
+
+// LegalMoves is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - targetID string
+func (_e *MockGameService_Expecter) LegalMoves(ctx interface{}, matchID interface{}, playerID interface{}, targetID interface{}) *MockGameService_LegalMoves_Call {
+	return &MockGameService_LegalMoves_Call{Call: _e.mock.On("LegalMoves", ctx, matchID, playerID, targetID)}
+}
+
+func (_c *MockGameService_LegalMoves_Call) Run(run func(ctx context.Context, matchID string, playerID string, targetID string)) *MockGameService_LegalMoves_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_LegalMoves_Call) Return(legalMoves dto.LegalMoves, err error) *MockGameService_LegalMoves_Call {
+	_c.Call.Return(legalMoves, err)
+	return _c
+}
+
+func (_c *MockGameService_LegalMoves_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, targetID string) (dto.LegalMoves, error)) *MockGameService_LegalMoves_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MatchRecord provides a mock function for the type MockGameService
+func (_mock *MockGameService) MatchRecord(ctx context.Context, matchID string, playerID string) (dto.MatchRecord, error) {
+	ret := _mock.Called(ctx, matchID, playerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MatchRecord")
+	}
+
+	var r0 dto.MatchRecord
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (dto.MatchRecord, error)); ok {
+		return returnFunc(ctx, matchID, playerID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) dto.MatchRecord); ok {
+		r0 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r0 = ret.Get(0).(dto.MatchRecord)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_MatchRecord_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MatchRecord'
+type MockGameService_MatchRecord_Call struct {
+	*mock.Call
+}
+
+// MatchRecord is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+func (_e *MockGameService_Expecter) MatchRecord(ctx interface{}, matchID interface{}, playerID interface{}) *MockGameService_MatchRecord_Call {
+	return &MockGameService_MatchRecord_Call{Call: _e.mock.On("MatchRecord", ctx, matchID, playerID)}
+}
+
+func (_c *MockGameService_MatchRecord_Call) Run(run func(ctx context.Context, matchID string, playerID string)) *MockGameService_MatchRecord_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_MatchRecord_Call) Return(matchRecord dto.MatchRecord, err error) *MockGameService_MatchRecord_Call {
+	_c.Call.Return(matchRecord, err)
+	return _c
+}
+
+func (_c *MockGameService_MatchRecord_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string) (dto.MatchRecord, error)) *MockGameService_MatchRecord_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PlaceShip provides a mock function for the type MockGameService
+func (_mock *MockGameService) PlaceShip(ctx context.Context, matchID string, playerID string, shipID int, x int, y int, vertical bool) (dto.GameView, error) {
+	ret := _mock.Called(ctx, matchID, playerID, shipID, x, y, vertical)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceShip")
+	}
+
+	var r0 dto.GameView
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, int, int, bool) (dto.GameView, error)); ok {
+		return returnFunc(ctx, matchID, playerID, shipID, x, y, vertical)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, int, int, bool) dto.GameView); ok {
+		r0 = returnFunc(ctx, matchID, playerID, shipID, x, y, vertical)
+	} else {
+		r0 = ret.Get(0).(dto.GameView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int, int, int, bool) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, shipID, x, y, vertical)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_PlaceShip_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PlaceShip'
+type MockGameService_PlaceShip_Call struct {
+	*mock.Call
+}
+
+// PlaceShip is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - shipID int
+//   - x int
+//   - y int
+//   - vertical bool
+func (_e *MockGameService_Expecter) PlaceShip(ctx interface{}, matchID interface{}, playerID interface{}, shipID interface{}, x interface{}, y interface{}, vertical interface{}) *MockGameService_PlaceShip_Call {
+	return &MockGameService_PlaceShip_Call{Call: _e.mock.On("PlaceShip", ctx, matchID, playerID, shipID, x, y, vertical)}
+}
+
+func (_c *MockGameService_PlaceShip_Call) Run(run func(ctx context.Context, matchID string, playerID string, shipID int, x int, y int, vertical bool)) *MockGameService_PlaceShip_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		var arg5 int
+		if args[5] != nil {
+			arg5 = args[5].(int)
+		}
+		var arg6 bool
+		if args[6] != nil {
+			arg6 = args[6].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+			arg6,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_PlaceShip_Call) Return(gameView dto.GameView, err error) *MockGameService_PlaceShip_Call {
+	_c.Call.Return(gameView, err)
+	return _c
+}
+
+func (_c *MockGameService_PlaceShip_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, shipID int, x int, y int, vertical bool) (dto.GameView, error)) *MockGameService_PlaceShip_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PlaceShipType provides a mock function for the type MockGameService
+func (_mock *MockGameService) PlaceShipType(ctx context.Context, matchID string, playerID string, shipType string, x int, y int, vertical bool) (dto.GameView, error) {
+	ret := _mock.Called(ctx, matchID, playerID, shipType, x, y, vertical)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceShipType")
+	}
+
+	var r0 dto.GameView
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int, int, bool) (dto.GameView, error)); ok {
+		return returnFunc(ctx, matchID, playerID, shipType, x, y, vertical)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int, int, bool) dto.GameView); ok {
+		r0 = returnFunc(ctx, matchID, playerID, shipType, x, y, vertical)
+	} else {
+		r0 = ret.Get(0).(dto.GameView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, int, int, bool) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, shipType, x, y, vertical)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_PlaceShipType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PlaceShipType'
+type MockGameService_PlaceShipType_Call struct {
+	*mock.Call
+}
+
+// PlaceShipType is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - shipType string
+//   - x int
+//   - y int
+//   - vertical bool
+func (_e *MockGameService_Expecter) PlaceShipType(ctx interface{}, matchID interface{}, playerID interface{}, shipType interface{}, x interface{}, y interface{}, vertical interface{}) *MockGameService_PlaceShipType_Call {
+	return &MockGameService_PlaceShipType_Call{Call: _e.mock.On("PlaceShipType", ctx, matchID, playerID, shipType, x, y, vertical)}
+}
+
+func (_c *MockGameService_PlaceShipType_Call) Run(run func(ctx context.Context, matchID string, playerID string, shipType string, x int, y int, vertical bool)) *MockGameService_PlaceShipType_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		var arg5 int
+		if args[5] != nil {
+			arg5 = args[5].(int)
+		}
+		var arg6 bool
+		if args[6] != nil {
+			arg6 = args[6].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+			arg6,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_PlaceShipType_Call) Return(gameView dto.GameView, err error) *MockGameService_PlaceShipType_Call {
+	_c.Call.Return(gameView, err)
+	return _c
+}
+
+func (_c *MockGameService_PlaceShipType_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, shipType string, x int, y int, vertical bool) (dto.GameView, error)) *MockGameService_PlaceShipType_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ready provides a mock function for the type MockGameService
+func (_mock *MockGameService) Ready(ctx context.Context, matchID string, playerID string) (dto.GameView, error) {
+	ret := _mock.Called(ctx, matchID, playerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ready")
 	}
 
 	var r0 dto.GameView
@@ -148,20 +804,20 @@ func (_mock *MockGameService) GetState(ctx context.Context, matchID string, play
 	return r0, r1
 }
 
-// MockGameService_GetState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetState'
-type MockGameService_GetState_Call struct {
+// MockGameService_Ready_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ready'
+type MockGameService_Ready_Call struct {
 	*mock.Call
 }
 
-// GetState is a helper method to define mock.On call
+// Ready is a helper method to define mock.On call
 //   - ctx context.Context
 //   - matchID string
 //   - playerID string
-func (_e *MockGameService_Expecter) GetState(ctx interface{}, matchID interface{}, playerID interface{}) *MockGameService_GetState_Call {
-	return &MockGameService_GetState_Call{Call: _e.mock.On("GetState", ctx, matchID, playerID)}
+func (_e *MockGameService_Expecter) Ready(ctx interface{}, matchID interface{}, playerID interface{}) *MockGameService_Ready_Call {
+	return &MockGameService_Ready_Call{Call: _e.mock.On("Ready", ctx, matchID, playerID)}
 }
 
-func (_c *MockGameService_GetState_Call) Run(run func(ctx context.Context, matchID string, playerID string)) *MockGameService_GetState_Call {
+func (_c *MockGameService_Ready_Call) Run(run func(ctx context.Context, matchID string, playerID string)) *MockGameService_Ready_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -184,60 +840,282 @@ func (_c *MockGameService_GetState_Call) Run(run func(ctx context.Context, match
 	return _c
 }
 
-func (_c *MockGameService_GetState_Call) Return(gameView dto.GameView, err error) *MockGameService_GetState_Call {
+func (_c *MockGameService_Ready_Call) Return(gameView dto.GameView, err error) *MockGameService_Ready_Call {
 	_c.Call.Return(gameView, err)
 	return _c
 }
 
-func (_c *MockGameService_GetState_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string) (dto.GameView, error)) *MockGameService_GetState_Call {
+func (_c *MockGameService_Ready_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string) (dto.GameView, error)) *MockGameService_Ready_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PlaceShip provides a mock function for the type MockGameService
-func (_mock *MockGameService) PlaceShip(ctx context.Context, matchID string, playerID string, shipID int, x int, y int, vertical bool) (dto.GameView, error) {
-	ret := _mock.Called(ctx, matchID, playerID, shipID, x, y, vertical)
+// Reveal provides a mock function for the type MockGameService
+func (_mock *MockGameService) Reveal(ctx context.Context, matchID string) (dto.RevealView, error) {
+	ret := _mock.Called(ctx, matchID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PlaceShip")
+		panic("no return value specified for Reveal")
 	}
 
-	var r0 dto.GameView
+	var r0 dto.RevealView
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, int, int, bool) (dto.GameView, error)); ok {
-		return returnFunc(ctx, matchID, playerID, shipID, x, y, vertical)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (dto.RevealView, error)); ok {
+		return returnFunc(ctx, matchID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, int, int, bool) dto.GameView); ok {
-		r0 = returnFunc(ctx, matchID, playerID, shipID, x, y, vertical)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) dto.RevealView); ok {
+		r0 = returnFunc(ctx, matchID)
 	} else {
-		r0 = ret.Get(0).(dto.GameView)
+		r0 = ret.Get(0).(dto.RevealView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, matchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_Reveal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reveal'
+type MockGameService_Reveal_Call struct {
+	*mock.Call
+}
+
+// Reveal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+func (_e *MockGameService_Expecter) Reveal(ctx interface{}, matchID interface{}) *MockGameService_Reveal_Call {
+	return &MockGameService_Reveal_Call{Call: _e.mock.On("Reveal", ctx, matchID)}
+}
+
+func (_c *MockGameService_Reveal_Call) Run(run func(ctx context.Context, matchID string)) *MockGameService_Reveal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_Reveal_Call) Return(revealView dto.RevealView, err error) *MockGameService_Reveal_Call {
+	_c.Call.Return(revealView, err)
+	return _c
+}
+
+func (_c *MockGameService_Reveal_Call) RunAndReturn(run func(ctx context.Context, matchID string) (dto.RevealView, error)) *MockGameService_Reveal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rules provides a mock function for the type MockGameService
+func (_mock *MockGameService) Rules(ctx context.Context, matchID string) (dto.Rules, error) {
+	ret := _mock.Called(ctx, matchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rules")
+	}
+
+	var r0 dto.Rules
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (dto.Rules, error)); ok {
+		return returnFunc(ctx, matchID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) dto.Rules); ok {
+		r0 = returnFunc(ctx, matchID)
+	} else {
+		r0 = ret.Get(0).(dto.Rules)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, matchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_Rules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rules'
+type MockGameService_Rules_Call struct {
+	*mock.Call
+}
+
+// Rules is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+func (_e *MockGameService_Expecter) Rules(ctx interface{}, matchID interface{}) *MockGameService_Rules_Call {
+	return &MockGameService_Rules_Call{Call: _e.mock.On("Rules", ctx, matchID)}
+}
+
+func (_c *MockGameService_Rules_Call) Run(run func(ctx context.Context, matchID string)) *MockGameService_Rules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_Rules_Call) Return(rules dto.Rules, err error) *MockGameService_Rules_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockGameService_Rules_Call) RunAndReturn(run func(ctx context.Context, matchID string) (dto.Rules, error)) *MockGameService_Rules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateAttack provides a mock function for the type MockGameService
+func (_mock *MockGameService) ValidateAttack(ctx context.Context, matchID string, playerID string, targetID string, x int, y int) (dto.AttackCheck, error) {
+	ret := _mock.Called(ctx, matchID, playerID, targetID, x, y)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAttack")
+	}
+
+	var r0 dto.AttackCheck
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) (dto.AttackCheck, error)); ok {
+		return returnFunc(ctx, matchID, playerID, targetID, x, y)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) dto.AttackCheck); ok {
+		r0 = returnFunc(ctx, matchID, playerID, targetID, x, y)
+	} else {
+		r0 = ret.Get(0).(dto.AttackCheck)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, int, int) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, targetID, x, y)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockGameService_ValidateAttack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateAttack'
+type MockGameService_ValidateAttack_Call struct {
+	*mock.Call
+}
+
+// ValidateAttack is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - targetID string
+//   - x int
+//   - y int
+func (_e *MockGameService_Expecter) ValidateAttack(ctx interface{}, matchID interface{}, playerID interface{}, targetID interface{}, x interface{}, y interface{}) *MockGameService_ValidateAttack_Call {
+	return &MockGameService_ValidateAttack_Call{Call: _e.mock.On("ValidateAttack", ctx, matchID, playerID, targetID, x, y)}
+}
+
+func (_c *MockGameService_ValidateAttack_Call) Run(run func(ctx context.Context, matchID string, playerID string, targetID string, x int, y int)) *MockGameService_ValidateAttack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		var arg5 int
+		if args[5] != nil {
+			arg5 = args[5].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MockGameService_ValidateAttack_Call) Return(attackCheck dto.AttackCheck, err error) *MockGameService_ValidateAttack_Call {
+	_c.Call.Return(attackCheck, err)
+	return _c
+}
+
+func (_c *MockGameService_ValidateAttack_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, targetID string, x int, y int) (dto.AttackCheck, error)) *MockGameService_ValidateAttack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidatePlacement provides a mock function for the type MockGameService
+func (_mock *MockGameService) ValidatePlacement(ctx context.Context, matchID string, playerID string, size int, x int, y int, vertical bool) (dto.PlacementCheck, error) {
+	ret := _mock.Called(ctx, matchID, playerID, size, x, y, vertical)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidatePlacement")
+	}
+
+	var r0 dto.PlacementCheck
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, int, int, bool) (dto.PlacementCheck, error)); ok {
+		return returnFunc(ctx, matchID, playerID, size, x, y, vertical)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, int, int, bool) dto.PlacementCheck); ok {
+		r0 = returnFunc(ctx, matchID, playerID, size, x, y, vertical)
+	} else {
+		r0 = ret.Get(0).(dto.PlacementCheck)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int, int, int, bool) error); ok {
-		r1 = returnFunc(ctx, matchID, playerID, shipID, x, y, vertical)
+		r1 = returnFunc(ctx, matchID, playerID, size, x, y, vertical)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockGameService_PlaceShip_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PlaceShip'
-type MockGameService_PlaceShip_Call struct {
+// MockGameService_ValidatePlacement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidatePlacement'
+type MockGameService_ValidatePlacement_Call struct {
 	*mock.Call
 }
 
-// PlaceShip is a helper method to define mock.On call
+// ValidatePlacement is a helper method to define mock.On call
 //   - ctx context.Context
 //   - matchID string
 //   - playerID string
-//   - shipID int
+//   - size int
 //   - x int
 //   - y int
 //   - vertical bool
-func (_e *MockGameService_Expecter) PlaceShip(ctx interface{}, matchID interface{}, playerID interface{}, shipID interface{}, x interface{}, y interface{}, vertical interface{}) *MockGameService_PlaceShip_Call {
-	return &MockGameService_PlaceShip_Call{Call: _e.mock.On("PlaceShip", ctx, matchID, playerID, shipID, x, y, vertical)}
+func (_e *MockGameService_Expecter) ValidatePlacement(ctx interface{}, matchID interface{}, playerID interface{}, size interface{}, x interface{}, y interface{}, vertical interface{}) *MockGameService_ValidatePlacement_Call {
+	return &MockGameService_ValidatePlacement_Call{Call: _e.mock.On("ValidatePlacement", ctx, matchID, playerID, size, x, y, vertical)}
 }
 
-func (_c *MockGameService_PlaceShip_Call) Run(run func(ctx context.Context, matchID string, playerID string, shipID int, x int, y int, vertical bool)) *MockGameService_PlaceShip_Call {
+func (_c *MockGameService_ValidatePlacement_Call) Run(run func(ctx context.Context, matchID string, playerID string, size int, x int, y int, vertical bool)) *MockGameService_ValidatePlacement_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -280,12 +1158,12 @@ func (_c *MockGameService_PlaceShip_Call) Run(run func(ctx context.Context, matc
 	return _c
 }
 
-func (_c *MockGameService_PlaceShip_Call) Return(gameView dto.GameView, err error) *MockGameService_PlaceShip_Call {
-	_c.Call.Return(gameView, err)
+func (_c *MockGameService_ValidatePlacement_Call) Return(placementCheck dto.PlacementCheck, err error) *MockGameService_ValidatePlacement_Call {
+	_c.Call.Return(placementCheck, err)
 	return _c
 }
 
-func (_c *MockGameService_PlaceShip_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, shipID int, x int, y int, vertical bool) (dto.GameView, error)) *MockGameService_PlaceShip_Call {
+func (_c *MockGameService_ValidatePlacement_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, size int, x int, y int, vertical bool) (dto.PlacementCheck, error)) *MockGameService_ValidatePlacement_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -38,6 +38,72 @@ func (_m *MockIdentityService) EXPECT() *MockIdentityService_Expecter {
 	return &MockIdentityService_Expecter{mock: &_m.Mock}
 }
 
+// GetUser provides a mock function for the type MockIdentityService
+func (_mock *MockIdentityService) GetUser(ctx context.Context, userID string) (dto.User, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 dto.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (dto.User, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) dto.User); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(dto.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIdentityService_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockIdentityService_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *MockIdentityService_Expecter) GetUser(ctx interface{}, userID interface{}) *MockIdentityService_GetUser_Call {
+	return &MockIdentityService_GetUser_Call{Call: _e.mock.On("GetUser", ctx, userID)}
+}
+
+func (_c *MockIdentityService_GetUser_Call) Run(run func(ctx context.Context, userID string)) *MockIdentityService_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdentityService_GetUser_Call) Return(user dto.User, err error) *MockIdentityService_GetUser_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIdentityService_GetUser_Call) RunAndReturn(run func(ctx context.Context, userID string) (dto.User, error)) *MockIdentityService_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoginOrRegister provides a mock function for the type MockIdentityService
 func (_mock *MockIdentityService) LoginOrRegister(ctx context.Context, username string, source string, extID string) (dto.AuthResponse, error) {
 	ret := _mock.Called(ctx, username, source, extID)
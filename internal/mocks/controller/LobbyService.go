@@ -38,26 +38,92 @@ func (_m *MockLobbyService) EXPECT() *MockLobbyService_Expecter {
 	return &MockLobbyService_Expecter{mock: &_m.Mock}
 }
 
-// CreateMatch provides a mock function for the type MockLobbyService
-func (_mock *MockLobbyService) CreateMatch(ctx context.Context, hostID string) (string, error) {
-	ret := _mock.Called(ctx, hostID)
+// ActiveMatch provides a mock function for the type MockLobbyService
+func (_mock *MockLobbyService) ActiveMatch(ctx context.Context, playerID string) (string, error) {
+	ret := _mock.Called(ctx, playerID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateMatch")
+		panic("no return value specified for ActiveMatch")
 	}
 
 	var r0 string
 	var r1 error
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return returnFunc(ctx, hostID)
+		return returnFunc(ctx, playerID)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = returnFunc(ctx, hostID)
+		r0 = returnFunc(ctx, playerID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, hostID)
+		r1 = returnFunc(ctx, playerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLobbyService_ActiveMatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ActiveMatch'
+type MockLobbyService_ActiveMatch_Call struct {
+	*mock.Call
+}
+
+// ActiveMatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - playerID string
+func (_e *MockLobbyService_Expecter) ActiveMatch(ctx interface{}, playerID interface{}) *MockLobbyService_ActiveMatch_Call {
+	return &MockLobbyService_ActiveMatch_Call{Call: _e.mock.On("ActiveMatch", ctx, playerID)}
+}
+
+func (_c *MockLobbyService_ActiveMatch_Call) Run(run func(ctx context.Context, playerID string)) *MockLobbyService_ActiveMatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLobbyService_ActiveMatch_Call) Return(s string, err error) *MockLobbyService_ActiveMatch_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockLobbyService_ActiveMatch_Call) RunAndReturn(run func(ctx context.Context, playerID string) (string, error)) *MockLobbyService_ActiveMatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateMatch provides a mock function for the type MockLobbyService
+func (_mock *MockLobbyService) CreateMatch(ctx context.Context, hostID string, settings dto.MatchSettings) (string, error) {
+	ret := _mock.Called(ctx, hostID, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMatch")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, dto.MatchSettings) (string, error)); ok {
+		return returnFunc(ctx, hostID, settings)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, dto.MatchSettings) string); ok {
+		r0 = returnFunc(ctx, hostID, settings)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, dto.MatchSettings) error); ok {
+		r1 = returnFunc(ctx, hostID, settings)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,11 +138,12 @@ type MockLobbyService_CreateMatch_Call struct {
 // CreateMatch is a helper method to define mock.On call
 //   - ctx context.Context
 //   - hostID string
-func (_e *MockLobbyService_Expecter) CreateMatch(ctx interface{}, hostID interface{}) *MockLobbyService_CreateMatch_Call {
-	return &MockLobbyService_CreateMatch_Call{Call: _e.mock.On("CreateMatch", ctx, hostID)}
+//   - settings dto.MatchSettings
+func (_e *MockLobbyService_Expecter) CreateMatch(ctx interface{}, hostID interface{}, settings interface{}) *MockLobbyService_CreateMatch_Call {
+	return &MockLobbyService_CreateMatch_Call{Call: _e.mock.On("CreateMatch", ctx, hostID, settings)}
 }
 
-func (_c *MockLobbyService_CreateMatch_Call) Run(run func(ctx context.Context, hostID string)) *MockLobbyService_CreateMatch_Call {
+func (_c *MockLobbyService_CreateMatch_Call) Run(run func(ctx context.Context, hostID string, settings dto.MatchSettings)) *MockLobbyService_CreateMatch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -86,9 +153,14 @@ func (_c *MockLobbyService_CreateMatch_Call) Run(run func(ctx context.Context, h
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 dto.MatchSettings
+		if args[2] != nil {
+			arg2 = args[2].(dto.MatchSettings)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -99,7 +171,70 @@ func (_c *MockLobbyService_CreateMatch_Call) Return(s string, err error) *MockLo
 	return _c
 }
 
-func (_c *MockLobbyService_CreateMatch_Call) RunAndReturn(run func(ctx context.Context, hostID string) (string, error)) *MockLobbyService_CreateMatch_Call {
+func (_c *MockLobbyService_CreateMatch_Call) RunAndReturn(run func(ctx context.Context, hostID string, settings dto.MatchSettings) (string, error)) *MockLobbyService_CreateMatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMatch provides a mock function for the type MockLobbyService
+func (_mock *MockLobbyService) DeleteMatch(ctx context.Context, matchID string, playerID string) error {
+	ret := _mock.Called(ctx, matchID, playerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMatch")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, matchID, playerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockLobbyService_DeleteMatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMatch'
+type MockLobbyService_DeleteMatch_Call struct {
+	*mock.Call
+}
+
+// DeleteMatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+func (_e *MockLobbyService_Expecter) DeleteMatch(ctx interface{}, matchID interface{}, playerID interface{}) *MockLobbyService_DeleteMatch_Call {
+	return &MockLobbyService_DeleteMatch_Call{Call: _e.mock.On("DeleteMatch", ctx, matchID, playerID)}
+}
+
+func (_c *MockLobbyService_DeleteMatch_Call) Run(run func(ctx context.Context, matchID string, playerID string)) *MockLobbyService_DeleteMatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLobbyService_DeleteMatch_Call) Return(err error) *MockLobbyService_DeleteMatch_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockLobbyService_DeleteMatch_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string) error) *MockLobbyService_DeleteMatch_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -177,8 +312,8 @@ func (_c *MockLobbyService_JoinMatch_Call) RunAndReturn(run func(ctx context.Con
 }
 
 // ListMatches provides a mock function for the type MockLobbyService
-func (_mock *MockLobbyService) ListMatches(ctx context.Context) ([]dto.MatchSummary, error) {
-	ret := _mock.Called(ctx)
+func (_mock *MockLobbyService) ListMatches(ctx context.Context, filter dto.MatchFilter) ([]dto.MatchSummary, error) {
+	ret := _mock.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListMatches")
@@ -186,18 +321,18 @@ func (_mock *MockLobbyService) ListMatches(ctx context.Context) ([]dto.MatchSumm
 
 	var r0 []dto.MatchSummary
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]dto.MatchSummary, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, dto.MatchFilter) ([]dto.MatchSummary, error)); ok {
+		return returnFunc(ctx, filter)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []dto.MatchSummary); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, dto.MatchFilter) []dto.MatchSummary); ok {
+		r0 = returnFunc(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]dto.MatchSummary)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, dto.MatchFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -211,18 +346,24 @@ type MockLobbyService_ListMatches_Call struct {
 
 // ListMatches is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockLobbyService_Expecter) ListMatches(ctx interface{}) *MockLobbyService_ListMatches_Call {
-	return &MockLobbyService_ListMatches_Call{Call: _e.mock.On("ListMatches", ctx)}
+//   - filter dto.MatchFilter
+func (_e *MockLobbyService_Expecter) ListMatches(ctx interface{}, filter interface{}) *MockLobbyService_ListMatches_Call {
+	return &MockLobbyService_ListMatches_Call{Call: _e.mock.On("ListMatches", ctx, filter)}
 }
 
-func (_c *MockLobbyService_ListMatches_Call) Run(run func(ctx context.Context)) *MockLobbyService_ListMatches_Call {
+func (_c *MockLobbyService_ListMatches_Call) Run(run func(ctx context.Context, filter dto.MatchFilter)) *MockLobbyService_ListMatches_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 dto.MatchFilter
+		if args[1] != nil {
+			arg1 = args[1].(dto.MatchFilter)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -233,7 +374,213 @@ func (_c *MockLobbyService_ListMatches_Call) Return(matchSummarys []dto.MatchSum
 	return _c
 }
 
-func (_c *MockLobbyService_ListMatches_Call) RunAndReturn(run func(ctx context.Context) ([]dto.MatchSummary, error)) *MockLobbyService_ListMatches_Call {
+func (_c *MockLobbyService_ListMatches_Call) RunAndReturn(run func(ctx context.Context, filter dto.MatchFilter) ([]dto.MatchSummary, error)) *MockLobbyService_ListMatches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PracticeBoard provides a mock function for the type MockLobbyService
+func (_mock *MockLobbyService) PracticeBoard(ctx context.Context, settings dto.MatchSettings) (dto.BoardView, error) {
+	ret := _mock.Called(ctx, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PracticeBoard")
+	}
+
+	var r0 dto.BoardView
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, dto.MatchSettings) (dto.BoardView, error)); ok {
+		return returnFunc(ctx, settings)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, dto.MatchSettings) dto.BoardView); ok {
+		r0 = returnFunc(ctx, settings)
+	} else {
+		r0 = ret.Get(0).(dto.BoardView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, dto.MatchSettings) error); ok {
+		r1 = returnFunc(ctx, settings)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLobbyService_PracticeBoard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PracticeBoard'
+type MockLobbyService_PracticeBoard_Call struct {
+	*mock.Call
+}
+
+// PracticeBoard is a helper method to define mock.On call
+//   - ctx context.Context
+//   - settings dto.MatchSettings
+func (_e *MockLobbyService_Expecter) PracticeBoard(ctx interface{}, settings interface{}) *MockLobbyService_PracticeBoard_Call {
+	return &MockLobbyService_PracticeBoard_Call{Call: _e.mock.On("PracticeBoard", ctx, settings)}
+}
+
+func (_c *MockLobbyService_PracticeBoard_Call) Run(run func(ctx context.Context, settings dto.MatchSettings)) *MockLobbyService_PracticeBoard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 dto.MatchSettings
+		if args[1] != nil {
+			arg1 = args[1].(dto.MatchSettings)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLobbyService_PracticeBoard_Call) Return(boardView dto.BoardView, err error) *MockLobbyService_PracticeBoard_Call {
+	_c.Call.Return(boardView, err)
+	return _c
+}
+
+func (_c *MockLobbyService_PracticeBoard_Call) RunAndReturn(run func(ctx context.Context, settings dto.MatchSettings) (dto.BoardView, error)) *MockLobbyService_PracticeBoard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResumeMatch provides a mock function for the type MockLobbyService
+func (_mock *MockLobbyService) ResumeMatch(ctx context.Context, matchID string, playerID string, token string) (dto.GameView, error) {
+	ret := _mock.Called(ctx, matchID, playerID, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumeMatch")
+	}
+
+	var r0 dto.GameView
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (dto.GameView, error)); ok {
+		return returnFunc(ctx, matchID, playerID, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) dto.GameView); ok {
+		r0 = returnFunc(ctx, matchID, playerID, token)
+	} else {
+		r0 = ret.Get(0).(dto.GameView)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, matchID, playerID, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLobbyService_ResumeMatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResumeMatch'
+type MockLobbyService_ResumeMatch_Call struct {
+	*mock.Call
+}
+
+// ResumeMatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - matchID string
+//   - playerID string
+//   - token string
+func (_e *MockLobbyService_Expecter) ResumeMatch(ctx interface{}, matchID interface{}, playerID interface{}, token interface{}) *MockLobbyService_ResumeMatch_Call {
+	return &MockLobbyService_ResumeMatch_Call{Call: _e.mock.On("ResumeMatch", ctx, matchID, playerID, token)}
+}
+
+func (_c *MockLobbyService_ResumeMatch_Call) Run(run func(ctx context.Context, matchID string, playerID string, token string)) *MockLobbyService_ResumeMatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLobbyService_ResumeMatch_Call) Return(gameView dto.GameView, err error) *MockLobbyService_ResumeMatch_Call {
+	_c.Call.Return(gameView, err)
+	return _c
+}
+
+func (_c *MockLobbyService_ResumeMatch_Call) RunAndReturn(run func(ctx context.Context, matchID string, playerID string, token string) (dto.GameView, error)) *MockLobbyService_ResumeMatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Scoreboard provides a mock function for the type MockLobbyService
+func (_mock *MockLobbyService) Scoreboard(ctx context.Context) ([]dto.ScoreboardEntry, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scoreboard")
+	}
+
+	var r0 []dto.ScoreboardEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]dto.ScoreboardEntry, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []dto.ScoreboardEntry); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.ScoreboardEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLobbyService_Scoreboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scoreboard'
+type MockLobbyService_Scoreboard_Call struct {
+	*mock.Call
+}
+
+// Scoreboard is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockLobbyService_Expecter) Scoreboard(ctx interface{}) *MockLobbyService_Scoreboard_Call {
+	return &MockLobbyService_Scoreboard_Call{Call: _e.mock.On("Scoreboard", ctx)}
+}
+
+func (_c *MockLobbyService_Scoreboard_Call) Run(run func(ctx context.Context)) *MockLobbyService_Scoreboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLobbyService_Scoreboard_Call) Return(scoreboardEntrys []dto.ScoreboardEntry, err error) *MockLobbyService_Scoreboard_Call {
+	_c.Call.Return(scoreboardEntrys, err)
+	return _c
+}
+
+func (_c *MockLobbyService_Scoreboard_Call) RunAndReturn(run func(ctx context.Context) ([]dto.ScoreboardEntry, error)) *MockLobbyService_Scoreboard_Call {
 	_c.Call.Return(run)
 	return _c
 }
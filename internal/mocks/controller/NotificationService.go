@@ -37,6 +37,52 @@ func (_m *MockNotificationService) EXPECT() *MockNotificationService_Expecter {
 	return &MockNotificationService_Expecter{mock: &_m.Mock}
 }
 
+// Close provides a mock function for the type MockNotificationService
+func (_mock *MockNotificationService) Close(matchID string, reason string) {
+	_mock.Called(matchID, reason)
+	return
+}
+
+// MockNotificationService_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockNotificationService_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+//   - matchID string
+//   - reason string
+func (_e *MockNotificationService_Expecter) Close(matchID interface{}, reason interface{}) *MockNotificationService_Close_Call {
+	return &MockNotificationService_Close_Call{Call: _e.mock.On("Close", matchID, reason)}
+}
+
+func (_c *MockNotificationService_Close_Call) Run(run func(matchID string, reason string)) *MockNotificationService_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationService_Close_Call) Return() *MockNotificationService_Close_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockNotificationService_Close_Call) RunAndReturn(run func(matchID string, reason string)) *MockNotificationService_Close_Call {
+	_c.Run(run)
+	return _c
+}
+
 // Publish provides a mock function for the type MockNotificationService
 func (_mock *MockNotificationService) Publish(event *dto.GameEvent) {
 	_mock.Called(event)
@@ -78,8 +124,15 @@ func (_c *MockNotificationService_Publish_Call) RunAndReturn(run func(event *dto
 }
 
 // Subscribe provides a mock function for the type MockNotificationService
-func (_mock *MockNotificationService) Subscribe(matchID string) (controller.Subscription, <-chan *dto.GameEvent) {
-	ret := _mock.Called(matchID)
+func (_mock *MockNotificationService) Subscribe(matchID string, types ...dto.EventType) (controller.Subscription, <-chan *dto.GameEvent) {
+	_va := make([]interface{}, len(types))
+	for _i := range types {
+		_va[_i] = types[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, matchID)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Subscribe")
@@ -87,18 +140,18 @@ func (_mock *MockNotificationService) Subscribe(matchID string) (controller.Subs
 
 	var r0 controller.Subscription
 	var r1 <-chan *dto.GameEvent
-	if returnFunc, ok := ret.Get(0).(func(string) (controller.Subscription, <-chan *dto.GameEvent)); ok {
-		return returnFunc(matchID)
+	if returnFunc, ok := ret.Get(0).(func(string, ...dto.EventType) (controller.Subscription, <-chan *dto.GameEvent)); ok {
+		return returnFunc(matchID, types...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) controller.Subscription); ok {
-		r0 = returnFunc(matchID)
+	if returnFunc, ok := ret.Get(0).(func(string, ...dto.EventType) controller.Subscription); ok {
+		r0 = returnFunc(matchID, types...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(controller.Subscription)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) <-chan *dto.GameEvent); ok {
-		r1 = returnFunc(matchID)
+	if returnFunc, ok := ret.Get(1).(func(string, ...dto.EventType) <-chan *dto.GameEvent); ok {
+		r1 = returnFunc(matchID, types...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(<-chan *dto.GameEvent)
@@ -114,18 +167,26 @@ type MockNotificationService_Subscribe_Call struct {
 
 // Subscribe is a helper method to define mock.On call
 //   - matchID string
-func (_e *MockNotificationService_Expecter) Subscribe(matchID interface{}) *MockNotificationService_Subscribe_Call {
-	return &MockNotificationService_Subscribe_Call{Call: _e.mock.On("Subscribe", matchID)}
+//   - types ...dto.EventType
+func (_e *MockNotificationService_Expecter) Subscribe(matchID interface{}, types ...interface{}) *MockNotificationService_Subscribe_Call {
+	return &MockNotificationService_Subscribe_Call{Call: _e.mock.On("Subscribe", append([]interface{}{matchID}, types...)...)}
 }
 
-func (_c *MockNotificationService_Subscribe_Call) Run(run func(matchID string)) *MockNotificationService_Subscribe_Call {
+func (_c *MockNotificationService_Subscribe_Call) Run(run func(matchID string, types ...dto.EventType)) *MockNotificationService_Subscribe_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
 			arg0 = args[0].(string)
 		}
+		variadicArgs := make([]dto.EventType, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(dto.EventType)
+			}
+		}
 		run(
 			arg0,
+			variadicArgs...,
 		)
 	})
 	return _c
@@ -136,7 +197,130 @@ func (_c *MockNotificationService_Subscribe_Call) Return(subscription controller
 	return _c
 }
 
-func (_c *MockNotificationService_Subscribe_Call) RunAndReturn(run func(matchID string) (controller.Subscription, <-chan *dto.GameEvent)) *MockNotificationService_Subscribe_Call {
+func (_c *MockNotificationService_Subscribe_Call) RunAndReturn(run func(matchID string, types ...dto.EventType) (controller.Subscription, <-chan *dto.GameEvent)) *MockNotificationService_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubscribeAll provides a mock function for the type MockNotificationService
+func (_mock *MockNotificationService) SubscribeAll(types ...dto.EventType) (controller.Subscription, <-chan *dto.GameEvent) {
+	_va := make([]interface{}, len(types))
+	for _i := range types {
+		_va[_i] = types[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeAll")
+	}
+
+	var r0 controller.Subscription
+	var r1 <-chan *dto.GameEvent
+	if returnFunc, ok := ret.Get(0).(func(...dto.EventType) (controller.Subscription, <-chan *dto.GameEvent)); ok {
+		return returnFunc(types...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(...dto.EventType) controller.Subscription); ok {
+		r0 = returnFunc(types...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(controller.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(...dto.EventType) <-chan *dto.GameEvent); ok {
+		r1 = returnFunc(types...)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan *dto.GameEvent)
+		}
+	}
+	return r0, r1
+}
+
+// MockNotificationService_SubscribeAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubscribeAll'
+type MockNotificationService_SubscribeAll_Call struct {
+	*mock.Call
+}
+
+// SubscribeAll is a helper method to define mock.On call
+//   - types ...dto.EventType
+func (_e *MockNotificationService_Expecter) SubscribeAll(types ...interface{}) *MockNotificationService_SubscribeAll_Call {
+	return &MockNotificationService_SubscribeAll_Call{Call: _e.mock.On("SubscribeAll", append([]interface{}{}, types...)...)}
+}
+
+func (_c *MockNotificationService_SubscribeAll_Call) Run(run func(types ...dto.EventType)) *MockNotificationService_SubscribeAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]dto.EventType, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(dto.EventType)
+			}
+		}
+		run(
+			variadicArgs...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationService_SubscribeAll_Call) Return(subscription controller.Subscription, gameEventCh <-chan *dto.GameEvent) *MockNotificationService_SubscribeAll_Call {
+	_c.Call.Return(subscription, gameEventCh)
+	return _c
+}
+
+func (_c *MockNotificationService_SubscribeAll_Call) RunAndReturn(run func(types ...dto.EventType) (controller.Subscription, <-chan *dto.GameEvent)) *MockNotificationService_SubscribeAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubscriberCount provides a mock function for the type MockNotificationService
+func (_mock *MockNotificationService) SubscriberCount(matchID string) int {
+	ret := _mock.Called(matchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscriberCount")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func(string) int); ok {
+		r0 = returnFunc(matchID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockNotificationService_SubscriberCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubscriberCount'
+type MockNotificationService_SubscriberCount_Call struct {
+	*mock.Call
+}
+
+// SubscriberCount is a helper method to define mock.On call
+//   - matchID string
+func (_e *MockNotificationService_Expecter) SubscriberCount(matchID interface{}) *MockNotificationService_SubscriberCount_Call {
+	return &MockNotificationService_SubscriberCount_Call{Call: _e.mock.On("SubscriberCount", matchID)}
+}
+
+func (_c *MockNotificationService_SubscriberCount_Call) Run(run func(matchID string)) *MockNotificationService_SubscriberCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationService_SubscriberCount_Call) Return(n int) *MockNotificationService_SubscriberCount_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockNotificationService_SubscriberCount_Call) RunAndReturn(run func(matchID string) int) *MockNotificationService_SubscriberCount_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -35,6 +35,50 @@ func (_m *MockSubscription) EXPECT() *MockSubscription_Expecter {
 	return &MockSubscription_Expecter{mock: &_m.Mock}
 }
 
+// Reason provides a mock function for the type MockSubscription
+func (_mock *MockSubscription) Reason() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reason")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockSubscription_Reason_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reason'
+type MockSubscription_Reason_Call struct {
+	*mock.Call
+}
+
+// Reason is a helper method to define mock.On call
+func (_e *MockSubscription_Expecter) Reason() *MockSubscription_Reason_Call {
+	return &MockSubscription_Reason_Call{Call: _e.mock.On("Reason")}
+}
+
+func (_c *MockSubscription_Reason_Call) Run(run func()) *MockSubscription_Reason_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSubscription_Reason_Call) Return(s string) *MockSubscription_Reason_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockSubscription_Reason_Call) RunAndReturn(run func() string) *MockSubscription_Reason_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Unsubscribe provides a mock function for the type MockSubscription
 func (_mock *MockSubscription) Unsubscribe() {
 	_mock.Called()
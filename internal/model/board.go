@@ -3,25 +3,34 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"iter"
+	"math/bits"
 	"slices"
 
 	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/rules"
 )
 
 var (
-	// ErrInvalidDimensions is returned when the board is created with non-positive dimensions.
+	// ErrInvalidDimensions is returned when the board is created with a size outside MinGridSize-MaxGridSize.
 	ErrInvalidDimensions = errors.New("invalid dimensions")
 	// ErrShipOutOfBounds is returned when a ship placement goes out of the board bounds.
-	ErrShipOutOfBounds = errors.New("ship placement out of bounds")
+	ErrShipOutOfBounds = rules.ErrOutOfBounds
 	// ErrShipOverlap is returned when a ship placement overlaps with another ship.
-	ErrShipOverlap = errors.New("ship placement overlaps with another ship")
+	ErrShipOverlap = rules.ErrOverlap
 	// ErrInvalidShipSize is returned when a ship tries to be created with a non-positive size.
-	ErrInvalidShipSize = errors.New("invalid ship size")
+	ErrInvalidShipSize = rules.ErrInvalidShipSize
+	// ErrShipNotFound is returned when removing a ship that is not on the board.
+	ErrShipNotFound = errors.New("no ship there")
 )
 
-// GridSize defines the size of the Battleship grid.
-const GridSize = 10
+// Board side lengths.
+const (
+	GridSize    = 10 // Size of the standard Battleship grid
+	MinGridSize = 5
+	MaxGridSize = 15 // Bitboard rows are uint16 masks
+)
 
 type tile struct {
 	isHit bool
@@ -29,9 +38,24 @@ type tile struct {
 }
 
 // Board represents the Battleship game board.
+// Alongside the tile grid it keeps bitboards of occupied and hit cells,
+// so that collision and sunk checks are plain bit operations.
 type Board struct {
-	tiles   [GridSize][GridSize]tile
-	history [GridSize][GridSize]ShotResult
+	size     int
+	tiles    [MaxGridSize][MaxGridSize]tile
+	history  [MaxGridSize][MaxGridSize]ShotResult
+	occupied bitboard
+	hits     bitboard
+	ships    []placedShip
+}
+
+// bitboard is a row-major bitmask of the grid: bit x of row y is cell (x, y).
+type bitboard [MaxGridSize]uint16
+
+// placedShip links a ship to the cells it occupies on the board.
+type placedShip struct {
+	ship *Ship
+	mask bitboard
 }
 
 // ShotResult represents the outcome of a shot fired at a coordinate.
@@ -54,9 +78,6 @@ const (
 	Vertical
 )
 
-// Coordinate represents a position on the Battleship grid.
-type Coordinate struct{ X, Y int }
-
 // Vector returns the row and column deltas for the given orientation.
 func (o Orientation) Vector() (dx, dy int) {
 	switch o {
@@ -69,7 +90,10 @@ func (o Orientation) Vector() (dx, dy int) {
 }
 
 // Ship represent a battleship ship.
-type Ship struct{ size int }
+type Ship struct {
+	size int
+	kind ShipType // Zero for ships of a size no type has
+}
 
 // NewShip creates a new Ship with the given size.
 func NewShip(s int) (*Ship, error) {
@@ -82,31 +106,92 @@ func NewShip(s int) (*Ship, error) {
 // Size returns the size of the ship.
 func (s *Ship) Size() int { return s.size }
 
-// NewBoard creates a new Board with the given number of rows and columns.
-// Negative or zero dimensions will return an error.
+// Type returns the ship's type and whether it has one.
+// Ships made with NewShip, or of a size no type has, are untyped.
+func (s *Ship) Type() (ShipType, bool) { return s.kind, s.kind != 0 }
+
+// NewBoard creates a new standard GridSize x GridSize board.
 func NewBoard() *Board {
-	return &Board{
-		tiles:   [GridSize][GridSize]tile{},
-		history: [GridSize][GridSize]ShotResult{},
+	return newBoard(GridSize)
+}
+
+// NewBoardOfSize creates a new square board with the given side length.
+// Sizes outside MinGridSize-MaxGridSize return ErrInvalidDimensions.
+func NewBoardOfSize(size int) (*Board, error) {
+	if err := validateGridSize(size); err != nil {
+		return nil, err
 	}
+	return newBoard(size), nil
 }
 
+func newBoard(size int) *Board {
+	return &Board{size: size}
+}
+
+// Size returns the side length of the board.
+func (b *Board) Size() int { return b.size }
+
 // PlaceShip places a ship on the board at the given coordinate with the specified orientation.
 // If the ship cannot be placed (e.g., out of bounds or overlapping another ship), an error is returned.
 func (b *Board) PlaceShip(c Coordinate, s *Ship, o Orientation) error {
-	segments := calculateSegments(c, s.Size(), o)
+	if err := b.CanPlaceShip(c, s.Size(), o); err != nil {
+		return err
+	}
 
-	if err := b.canPlaceShip(segments); err != nil {
+	b.placeShipAt(calculateSegments(c, s.Size(), o), s)
+
+	return nil
+}
+
+// CanPlaceShip reports why a ship of the given size could not be placed at the coordinate
+// with the orientation, or nil if it could. The board is left untouched.
+// Sizes and bounds are checked by the rules package; overlaps against the occupied bitboard.
+func (b *Board) CanPlaceShip(c Coordinate, size int, o Orientation) error {
+	if err := rules.CanFitShip(b, size, c.X, c.Y, o == Vertical); err != nil {
 		return err
 	}
+	if shipMask(c, size, o).intersects(&b.occupied) {
+		return ErrShipOverlap
+	}
+	return nil
+}
 
-	b.placeShipAt(segments, s)
+// RemoveShip takes a placed ship off the board, turning its cells back into water.
+// It returns ErrShipNotFound if the ship is not on the board.
+func (b *Board) RemoveShip(s *Ship) error {
+	i := slices.IndexFunc(b.ships, func(p placedShip) bool { return p.ship == s })
+	if i < 0 {
+		return ErrShipNotFound
+	}
+
+	b.removeShipAt(i)
 
 	return nil
 }
 
+// RemoveShipAt takes the ship covering the given coordinate off the board and returns it.
+// It returns ErrShipNotFound if the cell is water or outside the board.
+func (b *Board) RemoveShipAt(c Coordinate) (*Ship, error) {
+	if b.isOutOfBounds(c) || b.tiles[c.Y][c.X].ship == nil {
+		return nil, ErrShipNotFound
+	}
+
+	s := b.tiles[c.Y][c.X].ship
+	if err := b.RemoveShip(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// CanReceiveShot reports why a shot at the coordinate would be invalid, or nil if it would not.
+// It wraps ErrShotOutOfBounds off the board and ErrAlreadyAttacked on a cell already attacked.
+func (b *Board) CanReceiveShot(c Coordinate) error {
+	return rules.CanAttack(b, c.X, c.Y)
+}
+
 // ReceiveShot processes a shot fired at the given coordinate.
-// It returns the result of the shot (hit, miss, sunk, or invalid).
+// It returns the result of the shot (hit, miss, sunk, or invalid); CanReceiveShot tells why one is invalid.
 func (b *Board) ReceiveShot(c Coordinate) ShotResult {
 	if b.isOutOfBounds(c) {
 		return ShotResultInvalid
@@ -118,6 +203,7 @@ func (b *Board) ReceiveShot(c Coordinate) ShotResult {
 	}
 
 	t.isHit = true
+	b.hits.set(c)
 
 	switch {
 	case t.ship == nil: // Miss
@@ -134,21 +220,45 @@ func (b *Board) ReceiveShot(c Coordinate) ShotResult {
 
 // AllShipsSunk checks if every ship on the board has been destroyed.
 func (b *Board) AllShipsSunk() bool {
-	for _, t := range b.Cells() {
-		if t.ship != nil && !t.isHit {
-			return false
+	return b.occupied.coveredBy(&b.hits)
+}
+
+// CellsAfloat returns how many ship cells have not been hit yet.
+func (b *Board) CellsAfloat() int {
+	n := 0
+	for y := range b.size {
+		n += bits.OnesCount16(b.occupied[y] &^ b.hits[y])
+	}
+	return n
+}
+
+// ShipsAfloat returns how many of the ships placed on the board are not sunk yet.
+func (b *Board) ShipsAfloat() int {
+	n := 0
+	for i := range b.ships {
+		if !b.ships[i].mask.coveredBy(&b.hits) {
+			n++
 		}
 	}
+	return n
+}
 
-	return true
+// ShipNearby reports whether any of the four cells orthogonally adjacent to c holds a ship.
+func (b *Board) ShipNearby(c Coordinate) bool {
+	for _, n := range []Coordinate{{c.X, c.Y - 1}, {c.X + 1, c.Y}, {c.X, c.Y + 1}, {c.X - 1, c.Y}} {
+		if !b.isOutOfBounds(n) && b.tiles[n.Y][n.X].ship != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // Cells returns an iterator over the board.
 // It yields the coordinates and a POINTER to the tile.
 func (b *Board) Cells() iter.Seq2[Coordinate, *tile] {
 	return func(yield func(Coordinate, *tile) bool) {
-		for y := range b.tiles {
-			for x := range b.tiles[y] {
+		for y := range b.size {
+			for x := range b.size {
 				if !yield(Coordinate{X: x, Y: y}, &b.tiles[y][x]) {
 					return
 				}
@@ -157,71 +267,184 @@ func (b *Board) Cells() iter.Seq2[Coordinate, *tile] {
 	}
 }
 
+// shipCellsAt returns the cells of the ship covering c, or nil if there is none.
+func (b *Board) shipCellsAt(c Coordinate) []Coordinate {
+	if b.isOutOfBounds(c) || b.tiles[c.Y][c.X].ship == nil {
+		return nil
+	}
+
+	s := b.tiles[c.Y][c.X].ship
+	var cells []Coordinate
+	for coord, t := range b.Cells() {
+		if t.ship == s {
+			cells = append(cells, coord)
+		}
+	}
+	return cells
+}
+
+// Ships returns an iterator over the ships on the board in the order they were placed.
+// It yields each ship with the number of its cells that have been hit;
+// a ship is sunk once that number reaches its size.
+func (b *Board) Ships() iter.Seq2[*Ship, int] {
+	return func(yield func(*Ship, int) bool) {
+		for _, p := range b.ships {
+			if !yield(p.ship, p.mask.countAnd(&b.hits)) {
+				return
+			}
+		}
+	}
+}
+
 // GetSnapshot returns a snapshot view of the board.
 // If hideUnhitShips is true, unhit ships will be represented as unknown cells.
 func (b *Board) GetSnapshot(hideUnhitShips bool) dto.BoardView {
-	grid := make([][]dto.CellState, GridSize)
+	grid := make([][]dto.CellState, b.size)
 	for i := range grid {
-		grid[i] = make([]dto.CellState, GridSize)
+		grid[i] = make([]dto.CellState, b.size)
 	}
 
 	for coord, t := range b.Cells() {
-		var state dto.CellState
-
-		switch {
-		case t.isHit && t.ship != nil && b.isShipSunk(t.ship):
-			state = dto.CellSunk
-		case t.isHit && t.ship != nil:
-			state = dto.CellHit
-		case t.isHit:
-			state = dto.CellMiss
-		case hideUnhitShips:
-			state = dto.CellUnknown
-		case t.ship != nil:
-			state = dto.CellShip
-		default:
-			state = dto.CellEmpty
-		}
+		grid[coord.Y][coord.X] = b.cellState(t, hideUnhitShips)
+	}
 
-		grid[coord.Y][coord.X] = state
+	return dto.BoardView{Grid: grid, Size: b.size}
+}
+
+var _ rules.Board = (*Board)(nil)
+
+// Cell returns the state of the cell at x,y as its owner sees it, or CellUnknown off the board.
+// It lets the board be checked with the rules package.
+func (b *Board) Cell(x, y int) dto.CellState {
+	if b.isOutOfBounds(Coordinate{X: x, Y: y}) {
+		return dto.CellUnknown
 	}
+	return b.cellState(&b.tiles[y][x], false)
+}
 
-	return dto.BoardView{Grid: grid, Size: GridSize}
+func (b *Board) cellState(t *tile, hideUnhitShips bool) dto.CellState {
+	switch {
+	case t.isHit && t.ship != nil && b.isShipSunk(t.ship):
+		return dto.CellSunk
+	case t.isHit && t.ship != nil:
+		return dto.CellHit
+	case t.isHit:
+		return dto.CellMiss
+	case hideUnhitShips:
+		return dto.CellUnknown
+	case t.ship != nil:
+		return dto.CellShip
+	default:
+		return dto.CellEmpty
+	}
 }
 
 func (b *Board) isOutOfBounds(c Coordinate) bool {
-	return c.Y < 0 || c.Y >= len(b.tiles) || c.X < 0 || c.X >= len(b.tiles[0])
+	return c.Y < 0 || c.Y >= b.size || c.X < 0 || c.X >= b.size
 }
 
-func (b *Board) isOccupied(c Coordinate) bool {
-	return b.tiles[c.Y][c.X].ship != nil
+func validateGridSize(size int) error {
+	if size < MinGridSize || size > MaxGridSize {
+		return fmt.Errorf("%w: board size must be %d-%d", ErrInvalidDimensions, MinGridSize, MaxGridSize)
+	}
+	return nil
 }
 
 func (b *Board) isShipSunk(s *Ship) bool {
-	for _, t := range b.Cells() {
-		if t.ship == s && !t.isHit {
-			return false
+	for i := range b.ships {
+		if b.ships[i].ship == s {
+			return b.ships[i].mask.coveredBy(&b.hits)
 		}
 	}
 	return true
 }
 
-func (b *Board) canPlaceShip(s []Coordinate) error {
-	if slices.ContainsFunc(s, b.isOutOfBounds) {
-		return ErrShipOutOfBounds
+func (b *Board) placeShipAt(s []Coordinate, ship *Ship) {
+	mask := maskOf(s)
+	for _, c := range s {
+		b.tiles[c.Y][c.X].ship = ship
 	}
+	b.occupied.or(&mask)
+	b.ships = append(b.ships, placedShip{ship: ship, mask: mask})
+}
 
-	if slices.ContainsFunc(s, b.isOccupied) {
-		return ErrShipOverlap
+func (b *Board) removeShipAt(i int) {
+	mask := b.ships[i].mask
+	for y := range b.size {
+		for x := range b.size {
+			if mask[y]&(1<<x) != 0 {
+				b.tiles[y][x].ship = nil
+			}
+		}
 	}
+	b.occupied.andNot(&mask)
+	b.ships = slices.Delete(b.ships, i, i+1)
+}
 
-	return nil
+// maskOf builds a bitboard from in-bounds coordinates.
+func maskOf(cs []Coordinate) bitboard {
+	var m bitboard
+	for _, c := range cs {
+		m.set(c)
+	}
+	return m
 }
 
-func (b *Board) placeShipAt(s []Coordinate, ship *Ship) {
-	for _, c := range s {
-		b.tiles[c.Y][c.X].ship = ship
+// shipMask builds the bitboard of an in-bounds placement without listing its cells.
+func shipMask(c Coordinate, size int, o Orientation) *bitboard {
+	var m bitboard
+	if o == Vertical {
+		for y := c.Y; y < c.Y+size; y++ {
+			m[y] = 1 << c.X
+		}
+	} else {
+		m[c.Y] = (1<<size - 1) << c.X
 	}
+	return &m
+}
+
+func (m *bitboard) set(c Coordinate) {
+	m[c.Y] |= 1 << c.X
+}
+
+func (m *bitboard) or(o *bitboard) {
+	for y := range m {
+		m[y] |= o[y]
+	}
+}
+
+func (m *bitboard) andNot(o *bitboard) {
+	for y := range m {
+		m[y] &^= o[y]
+	}
+}
+
+func (m *bitboard) intersects(o *bitboard) bool {
+	for y := range m {
+		if m[y]&o[y] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// countAnd returns the number of bits set in both m and o.
+func (m *bitboard) countAnd(o *bitboard) int {
+	n := 0
+	for y := range m {
+		n += bits.OnesCount16(m[y] & o[y])
+	}
+	return n
+}
+
+// coveredBy reports whether every bit set in m is also set in o.
+func (m *bitboard) coveredBy(o *bitboard) bool {
+	for y := range m {
+		if m[y]&^o[y] != 0 {
+			return false
+		}
+	}
+	return true
 }
 
 func calculateSegments(start Coordinate, size int, o Orientation) []Coordinate {
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+// newBenchBoard returns a board with the standard fleet placed and every ship but the last one sunk,
+// which is the worst case for the full-grid scans.
+func newBenchBoard(b *testing.B) *Board {
+	b.Helper()
+
+	board := NewBoard()
+	sizes := []int{5, 4, 3, 3, 2}
+	for y, size := range sizes {
+		if err := board.PlaceShip(Coordinate{X: 0, Y: y}, &Ship{size: size}, Horizontal); err != nil {
+			b.Fatalf("failed to place ship: %v", err)
+		}
+	}
+
+	for y, size := range sizes[:len(sizes)-1] {
+		for x := range size {
+			board.ReceiveShot(Coordinate{X: x, Y: y})
+		}
+	}
+
+	return board
+}
+
+// allShipsSunkScan is the iterator-based implementation AllShipsSunk used before bitboards.
+func allShipsSunkScan(b *Board) bool {
+	for _, t := range b.Cells() {
+		if t.ship != nil && !t.isHit {
+			return false
+		}
+	}
+	return true
+}
+
+// isShipSunkScan is the iterator-based implementation isShipSunk used before bitboards.
+func isShipSunkScan(b *Board, s *Ship) bool {
+	for _, t := range b.Cells() {
+		if t.ship == s && !t.isHit {
+			return false
+		}
+	}
+	return true
+}
+
+// canPlaceShipScan is the tile-based implementation canPlaceShip used before bitboards.
+func canPlaceShipScan(b *Board, s []Coordinate) error {
+	if slices.ContainsFunc(s, b.isOutOfBounds) {
+		return ErrShipOutOfBounds
+	}
+
+	if slices.ContainsFunc(s, func(c Coordinate) bool { return b.tiles[c.Y][c.X].ship != nil }) {
+		return ErrShipOverlap
+	}
+
+	return nil
+}
+
+func BenchmarkAllShipsSunk(b *testing.B) {
+	board := newBenchBoard(b)
+
+	b.Run("Bitboard", func(b *testing.B) {
+		for b.Loop() {
+			board.AllShipsSunk()
+		}
+	})
+
+	b.Run("Iterator", func(b *testing.B) {
+		for b.Loop() {
+			allShipsSunkScan(board)
+		}
+	})
+}
+
+func BenchmarkIsShipSunk(b *testing.B) {
+	board := newBenchBoard(b)
+	last := board.ships[len(board.ships)-1].ship
+
+	b.Run("Bitboard", func(b *testing.B) {
+		for b.Loop() {
+			board.isShipSunk(last)
+		}
+	})
+
+	b.Run("Iterator", func(b *testing.B) {
+		for b.Loop() {
+			isShipSunkScan(board, last)
+		}
+	})
+}
+
+func BenchmarkCanPlaceShip(b *testing.B) {
+	board := newBenchBoard(b)
+	start := Coordinate{X: 4, Y: 0}
+	segments := calculateSegments(start, 5, Vertical)
+
+	b.Run("Bitboard", func(b *testing.B) {
+		for b.Loop() {
+			_ = board.CanPlaceShip(start, 5, Vertical)
+		}
+	})
+
+	b.Run("Tiles", func(b *testing.B) {
+		for b.Loop() {
+			_ = canPlaceShipScan(board, segments)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package model_test
 import (
 	"testing"
 
+	"github.com/callegarimattia/battleship/internal/dto"
 	m "github.com/callegarimattia/battleship/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -232,3 +233,195 @@ func TestAllShipsSunk(t *testing.T) {
 
 	assert.True(t, b.AllShipsSunk(), "All ships are destroyed, should return true")
 }
+
+func TestShipNearby(t *testing.T) {
+	t.Parallel()
+
+	b := m.NewBoard()
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: 4, Y: 4}, mustNewShip(t, 2), m.Horizontal)) // 4,4 -> 5,4
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: 0, Y: 9}, mustNewShip(t, 1), m.Horizontal))
+
+	tests := []struct {
+		name  string
+		coord m.Coordinate
+		want  bool
+	}{
+		{"Above", m.Coordinate{X: 4, Y: 3}, true},
+		{"Below", m.Coordinate{X: 5, Y: 5}, true},
+		{"Left", m.Coordinate{X: 3, Y: 4}, true},
+		{"Right", m.Coordinate{X: 6, Y: 4}, true},
+		{"Diagonal does not count", m.Coordinate{X: 3, Y: 3}, false},
+		{"Two cells away", m.Coordinate{X: 7, Y: 4}, false},
+		{"Corner next to a ship", m.Coordinate{X: 0, Y: 8}, true},
+		{"Edge with nothing around", m.Coordinate{X: 9, Y: 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, b.ShipNearby(tt.coord))
+		})
+	}
+}
+
+func TestCell(t *testing.T) {
+	t.Parallel()
+
+	b := m.NewBoard()
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: 0, Y: 0}, mustNewShip(t, 2), m.Horizontal))
+	b.ReceiveShot(m.Coordinate{X: 1, Y: 0})
+	b.ReceiveShot(m.Coordinate{X: 5, Y: 5})
+
+	tests := []struct {
+		name string
+		x, y int
+		want dto.CellState
+	}{
+		{"Ship", 0, 0, dto.CellShip},
+		{"Hit", 1, 0, dto.CellHit},
+		{"Miss", 5, 5, dto.CellMiss},
+		{"Water", 9, 9, dto.CellEmpty},
+		{"Negative", -1, 0, dto.CellUnknown},
+		{"Past the edge", 0, 10, dto.CellUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, b.Cell(tt.x, tt.y))
+		})
+	}
+}
+
+func TestNewBoardOfSize(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{m.MinGridSize - 1, m.MaxGridSize + 1} {
+		_, err := m.NewBoardOfSize(size)
+		assert.ErrorIs(t, err, m.ErrInvalidDimensions, "size %d", size)
+	}
+
+	b, err := m.NewBoardOfSize(m.MaxGridSize)
+	require.NoError(t, err)
+	assert.Equal(t, m.MaxGridSize, b.Size())
+
+	corner := m.Coordinate{X: m.MaxGridSize - 1, Y: m.MaxGridSize - 1}
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: corner.X, Y: 0}, mustNewShip(t, 2), m.Vertical))
+	assert.Equal(t, m.ShotResultMiss, b.ReceiveShot(corner))
+
+	small, err := m.NewBoardOfSize(m.MinGridSize)
+	require.NoError(t, err)
+	assert.ErrorIs(t, small.PlaceShip(m.Coordinate{X: 4, Y: 0}, mustNewShip(t, 2), m.Horizontal), m.ErrShipOutOfBounds)
+	assert.Equal(t, m.ShotResultInvalid, small.ReceiveShot(m.Coordinate{X: 5, Y: 0}))
+
+	view := small.GetSnapshot(false)
+	assert.Equal(t, m.MinGridSize, view.Size)
+	assert.Len(t, view.Grid, m.MinGridSize)
+}
+
+func TestRemoveShip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Clears the cells and occupancy", func(t *testing.T) {
+		t.Parallel()
+
+		b := m.NewBoard()
+		ship := mustNewShip(t, 3)
+		other := mustNewShip(t, 2)
+		require.NoError(t, b.PlaceShip(m.Coordinate{X: 2, Y: 2}, ship, m.Horizontal))
+		require.NoError(t, b.PlaceShip(m.Coordinate{X: 0, Y: 5}, other, m.Vertical))
+
+		require.NoError(t, b.RemoveShip(ship))
+
+		grid := b.GetSnapshot(false).Grid
+		for x := 2; x < 5; x++ {
+			assert.Equal(t, dto.CellEmpty, grid[2][x], "cell %d,2 should be water again", x)
+		}
+		assert.Equal(t, dto.CellShip, grid[5][0], "other ships stay in place")
+
+		// The freed cells can be used again
+		require.NoError(t, b.PlaceShip(m.Coordinate{X: 3, Y: 1}, ship, m.Vertical))
+		assert.Equal(t, m.ShotResultHit, b.ReceiveShot(m.Coordinate{X: 3, Y: 2}))
+		assert.Equal(t, m.ShotResultMiss, b.ReceiveShot(m.Coordinate{X: 2, Y: 2}))
+	})
+
+	t.Run("Removed ships no longer need sinking", func(t *testing.T) {
+		t.Parallel()
+
+		b := m.NewBoard()
+		ship := mustNewShip(t, 2)
+		require.NoError(t, b.PlaceShip(m.Coordinate{X: 0, Y: 0}, ship, m.Horizontal))
+
+		require.NoError(t, b.RemoveShip(ship))
+		assert.True(t, b.AllShipsSunk(), "an empty board has nothing left to sink")
+	})
+
+	t.Run("By coordinate", func(t *testing.T) {
+		t.Parallel()
+
+		b := m.NewBoard()
+		ship := mustNewShip(t, 3)
+		require.NoError(t, b.PlaceShip(m.Coordinate{X: 4, Y: 4}, ship, m.Vertical))
+
+		got, err := b.RemoveShipAt(m.Coordinate{X: 4, Y: 6})
+		require.NoError(t, err)
+		assert.Same(t, ship, got)
+		assert.NoError(t, b.PlaceShip(m.Coordinate{X: 3, Y: 5}, mustNewShip(t, 3), m.Horizontal))
+	})
+
+	t.Run("No ship there", func(t *testing.T) {
+		t.Parallel()
+
+		b := m.NewBoard()
+		ship := mustNewShip(t, 2)
+
+		assert.ErrorIs(t, b.RemoveShip(ship), m.ErrShipNotFound)
+
+		_, err := b.RemoveShipAt(m.Coordinate{X: 0, Y: 0})
+		assert.ErrorIs(t, err, m.ErrShipNotFound)
+		_, err = b.RemoveShipAt(m.Coordinate{X: -1, Y: 0})
+		assert.ErrorIs(t, err, m.ErrShipNotFound)
+
+		require.NoError(t, b.PlaceShip(m.Coordinate{X: 0, Y: 0}, ship, m.Horizontal))
+		require.NoError(t, b.RemoveShip(ship))
+		assert.ErrorIs(t, b.RemoveShip(ship), m.ErrShipNotFound, "a ship is only removed once")
+	})
+}
+
+func TestShips(t *testing.T) {
+	t.Parallel()
+
+	b := m.NewBoard()
+	first := mustNewShip(t, 3)
+	second := mustNewShip(t, 2)
+	third := mustNewShip(t, 1)
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: 5, Y: 5}, first, m.Horizontal))
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: 0, Y: 0}, second, m.Vertical))
+	require.NoError(t, b.PlaceShip(m.Coordinate{X: 9, Y: 9}, third, m.Horizontal))
+
+	b.ReceiveShot(m.Coordinate{X: 6, Y: 5})
+	b.ReceiveShot(m.Coordinate{X: 0, Y: 0})
+	b.ReceiveShot(m.Coordinate{X: 0, Y: 1})
+	b.ReceiveShot(m.Coordinate{X: 3, Y: 3}) // Miss
+
+	type status struct {
+		ship *m.Ship
+		hits int
+	}
+	var got []status
+	for ship, hits := range b.Ships() {
+		got = append(got, status{ship, hits})
+	}
+
+	assert.Equal(t, []status{{first, 1}, {second, 2}, {third, 0}}, got, "ships come in placement order")
+
+	require.NoError(t, b.RemoveShip(second))
+	got = got[:0]
+	for ship, hits := range b.Ships() {
+		got = append(got, status{ship, hits})
+	}
+	assert.Equal(t, []status{{first, 1}, {third, 0}}, got, "removed ships are no longer listed")
+
+	_, typed := first.Type()
+	assert.False(t, typed, "ships made with NewShip have no type")
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// GameBuilder assembles a Game in any phase for tests and fixtures, without spelling out
+// every call that leads there. It replays the joins, placements and shots through the same
+// methods a real game goes through, so the game it builds is one that could have been played.
+//
+//	g, err := model.NewGameBuilder().
+//		Fleet(map[int]int{2: 1}).
+//		Player("P1", model.Placement{Coord: model.Coordinate{X: 0, Y: 0}, Size: 2}).
+//		Player("P2", model.Placement{Coord: model.Coordinate{X: 5, Y: 5}, Size: 2}).
+//		Start().
+//		Shot("P1", model.Coordinate{X: 5, Y: 5}).
+//		Build()
+type GameBuilder struct {
+	size    int
+	fleet   map[int]int
+	players []builderPlayer
+	start   bool
+	shots   []builderShot
+	turn    string
+}
+
+// Placement is a ship a GameBuilder places for a player.
+type Placement struct {
+	Coord       Coordinate
+	Size        int
+	Orientation Orientation
+}
+
+type builderPlayer struct {
+	id    string
+	ships []Placement
+}
+
+type builderShot struct {
+	attackerID string
+	coord      Coordinate
+}
+
+// NewGameBuilder starts a game on the standard board with the standard fleet.
+func NewGameBuilder() *GameBuilder {
+	return &GameBuilder{size: GridSize}
+}
+
+// BoardSize sets the side length of both boards.
+func (b *GameBuilder) BoardSize(size int) *GameBuilder {
+	b.size = size
+	return b
+}
+
+// Fleet sets the fleet every player starts with; nil is the standard one.
+func (b *GameBuilder) Fleet(fleet map[int]int) *GameBuilder {
+	b.fleet = fleet
+	return b
+}
+
+// Player seats a player, in call order, with the given ships placed. With two players
+// the game is in setup; with a single one it still waits for an opponent.
+func (b *GameBuilder) Player(id string, ships ...Placement) *GameBuilder {
+	b.players = append(b.players, builderPlayer{id: id, ships: ships})
+	return b
+}
+
+// Start marks every player ready, which starts the game. Their fleets must be fully placed.
+func (b *GameBuilder) Start() *GameBuilder {
+	b.start = true
+	return b
+}
+
+// Shot fires a shot once the game has started. Shots are fired in call order and must
+// follow the turns: the first player opens, and the game ends once a fleet is sunk.
+func (b *GameBuilder) Shot(attackerID string, c Coordinate) *GameBuilder {
+	b.shots = append(b.shots, builderShot{attackerID: attackerID, coord: c})
+	return b
+}
+
+// Turn hands the turn to the given player after the shots, for states the turn order
+// alone would not reach, such as the second player opening.
+func (b *GameBuilder) Turn(playerID string) *GameBuilder {
+	b.turn = playerID
+	return b
+}
+
+// Build assembles the game, failing with the first step that the rules refuse.
+func (b *GameBuilder) Build() (*Game, error) {
+	g, err := NewGameOfSize(b.size)
+	if err != nil {
+		return nil, fmt.Errorf("build game: %w", err)
+	}
+
+	for _, p := range b.players {
+		if err := g.Join(p.id, b.fleet); err != nil {
+			return nil, fmt.Errorf("build game: join %s: %w", p.id, err)
+		}
+	}
+
+	for _, p := range b.players {
+		for _, s := range p.ships {
+			if err := g.PlaceShip(p.id, s.Coord, s.Size, s.Orientation); err != nil {
+				return nil, fmt.Errorf("build game: place %d-cell ship for %s at %s: %w", s.Size, p.id, s.Coord, err)
+			}
+		}
+	}
+
+	if b.start {
+		for _, p := range b.players {
+			if err := g.SetReady(p.id); err != nil {
+				return nil, fmt.Errorf("build game: ready %s: %w", p.id, err)
+			}
+		}
+	}
+
+	for _, s := range b.shots {
+		if _, err := g.Attack(s.attackerID, s.coord); err != nil {
+			return nil, fmt.Errorf("build game: %s fires at %s: %w", s.attackerID, s.coord, err)
+		}
+	}
+
+	if b.turn != "" {
+		if err := g.checkTurn(b.turn); err != nil && err != ErrNotYourTurn {
+			return nil, fmt.Errorf("build game: turn to %s: %w", b.turn, err)
+		}
+		g.turn = slices.IndexFunc(g.players, func(p *Player) bool { return p.id == b.turn })
+		g.version++
+	}
+
+	return g, nil
+}
@@ -0,0 +1,141 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	m "github.com/callegarimattia/battleship/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGameBuilder verifies games are built in each phase, with the placements, shots and turn asked for
+func TestGameBuilder(t *testing.T) {
+	t.Parallel()
+
+	p1 := m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2}
+	p2 := m.Placement{Coord: m.Coordinate{X: 3, Y: 3}, Size: 2, Orientation: m.Vertical}
+	fleet := map[int]int{2: 1}
+
+	t.Run("Waiting", func(t *testing.T) {
+		t.Parallel()
+		g, err := m.NewGameBuilder().BoardSize(6).Player("P1").Build()
+		require.NoError(t, err)
+		assert.Equal(t, dto.StateWaiting, g.State())
+		assert.Equal(t, 6, g.BoardSize())
+	})
+
+	t.Run("Setup", func(t *testing.T) {
+		t.Parallel()
+		g, err := m.NewGameBuilder().Fleet(fleet).Player("P1", p1).Player("P2").Build()
+		require.NoError(t, err)
+		assert.Equal(t, dto.StateSetup, g.State())
+		v1, err := g.GetView("P1")
+		require.NoError(t, err)
+		v2, err := g.GetView("P2")
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{2: 0}, v1.Me.Fleet, "P1's fleet should be placed")
+		assert.Equal(t, fleet, v2.Me.Fleet, "P2's fleet should still be to place")
+	})
+
+	t.Run("Playing", func(t *testing.T) {
+		t.Parallel()
+		g, err := m.NewGameBuilder().Fleet(fleet).
+			Player("P1", p1).Player("P2", p2).
+			Start().
+			Shot("P1", m.Coordinate{X: 3, Y: 3}).
+			Shot("P2", m.Coordinate{X: 9, Y: 9}).
+			Build()
+		require.NoError(t, err)
+		assert.Equal(t, dto.StatePlaying, g.State())
+		assert.True(t, g.IsPlayersTurn("P1"))
+		v1, err := g.GetView("P1")
+		require.NoError(t, err)
+		assert.Equal(t, dto.CellHit, v1.Enemy.Board.Grid[3][3])
+		assert.Equal(t, dto.CellMiss, v1.Me.Board.Grid[9][9])
+	})
+
+	t.Run("GameOver", func(t *testing.T) {
+		t.Parallel()
+		g, err := m.NewGameBuilder().Fleet(fleet).
+			Player("P1", p1).Player("P2", p2).
+			Start().
+			Shot("P1", m.Coordinate{X: 3, Y: 3}).
+			Shot("P2", m.Coordinate{X: 9, Y: 9}).
+			Shot("P1", m.Coordinate{X: 3, Y: 4}).
+			Build()
+		require.NoError(t, err)
+		assert.Equal(t, dto.StateFinished, g.State())
+		assert.Equal(t, "P1", g.Winner())
+	})
+
+	t.Run("Turn", func(t *testing.T) {
+		t.Parallel()
+		g, err := m.NewGameBuilder().Fleet(fleet).Player("P1", p1).Player("P2", p2).Start().Turn("P2").Build()
+		require.NoError(t, err)
+		assert.True(t, g.IsPlayersTurn("P2"))
+	})
+}
+
+// TestGameBuilder_Invalid verifies states the rules do not allow are refused with the rule broken
+func TestGameBuilder_Invalid(t *testing.T) {
+	t.Parallel()
+
+	p1 := m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2}
+	fleet := map[int]int{2: 1}
+
+	tests := []struct {
+		name    string
+		builder *m.GameBuilder
+		wantErr error
+	}{
+		{
+			name:    "BoardTooSmall",
+			builder: m.NewGameBuilder().BoardSize(2),
+			wantErr: m.ErrInvalidDimensions,
+		},
+		{
+			name:    "DuplicatePlayer",
+			builder: m.NewGameBuilder().Player("P1").Player("P1"),
+			wantErr: m.ErrAlreadySeated,
+		},
+		{
+			name:    "TooManyPlayers",
+			builder: m.NewGameBuilder().Player("P1").Player("P2").Player("P3"),
+			wantErr: m.ErrGameFull,
+		},
+		{
+			name:    "OverlappingShips",
+			builder: m.NewGameBuilder().Fleet(map[int]int{2: 2}).Player("P1", p1, p1).Player("P2"),
+			wantErr: m.ErrShipOverlap,
+		},
+		{
+			name:    "StartWithFleetToPlace",
+			builder: m.NewGameBuilder().Fleet(fleet).Player("P1", p1).Player("P2").Start(),
+			wantErr: m.ErrFleetNotPlaced,
+		},
+		{
+			name:    "ShotOutOfTurn",
+			builder: m.NewGameBuilder().Fleet(fleet).Player("P1", p1).Player("P2", p1).Start().Shot("P2", p1.Coord),
+			wantErr: m.ErrNotYourTurn,
+		},
+		{
+			name:    "TurnBeforeStart",
+			builder: m.NewGameBuilder().Fleet(fleet).Player("P1", p1).Player("P2", p1).Turn("P2"),
+			wantErr: m.ErrNotInPlay,
+		},
+		{
+			name:    "TurnToStranger",
+			builder: m.NewGameBuilder().Fleet(fleet).Player("P1", p1).Player("P2", p1).Start().Turn("P3"),
+			wantErr: m.ErrUnknownPlayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tt.builder.Build()
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCoordinate is returned when a coordinate string cannot be parsed or is off the board.
+var ErrInvalidCoordinate = errors.New("invalid coordinate")
+
+// Coordinate represents a position on the Battleship grid.
+type Coordinate struct{ X, Y int }
+
+// ParseCoordinate parses a chess-style coordinate such as "B7".
+// The letter selects the column (A-O maps to X 0-14) and the number selects the row (1-15 maps to Y 0-14).
+// Any coordinate of the largest supported board is accepted; use ParseCoordinateFor when the board is known.
+// Parsing is case-insensitive and ignores surrounding whitespace.
+func ParseCoordinate(s string) (Coordinate, error) {
+	return ParseCoordinateFor(s, MaxGridSize)
+}
+
+// ParseCoordinateFor parses a chess-style coordinate like ParseCoordinate, but only accepts
+// cells of a board with the given side length, so "K1" is refused on a 10x10 board.
+// Sizes outside MinGridSize-MaxGridSize return ErrInvalidDimensions.
+func ParseCoordinateFor(s string, size int) (Coordinate, error) {
+	if err := validateGridSize(size); err != nil {
+		return Coordinate{}, err
+	}
+
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return Coordinate{}, fmt.Errorf("%w: expected a letter followed by a number", ErrInvalidCoordinate)
+	}
+
+	lastCol := byte('A' + size - 1)
+	col := s[0]
+	if col < 'A' || col > lastCol {
+		return Coordinate{}, fmt.Errorf("%w: column must be A-%c", ErrInvalidCoordinate, lastCol)
+	}
+
+	row, err := strconv.Atoi(s[1:])
+	if err != nil || row < 1 || row > size {
+		return Coordinate{}, fmt.Errorf("%w: row must be 1-%d", ErrInvalidCoordinate, size)
+	}
+
+	return Coordinate{X: int(col - 'A'), Y: row - 1}, nil
+}
+
+// String returns the chess-style representation of the coordinate (e.g. "B7").
+// Coordinates outside the largest supported board are rendered as "(x,y)".
+func (c Coordinate) String() string {
+	if !c.inNotationRange() {
+		return fmt.Sprintf("(%d,%d)", c.X, c.Y)
+	}
+	return fmt.Sprintf("%c%d", 'A'+c.X, c.Y+1)
+}
+
+// Notation is a Coordinate that serializes to JSON as a compact chess-style string ("A1")
+// instead of an {"X":0,"Y":0} object. Convert with Notation(c) when encoding and
+// Coordinate(n) when decoding.
+type Notation Coordinate
+
+// String returns the chess-style representation of the coordinate.
+func (n Notation) String() string { return Coordinate(n).String() }
+
+// MarshalJSON encodes the coordinate as a chess-style JSON string.
+// Coordinates outside the board cannot be represented and return ErrInvalidCoordinate.
+func (n Notation) MarshalJSON() ([]byte, error) {
+	c := Coordinate(n)
+	if !c.inNotationRange() {
+		return nil, fmt.Errorf("%w: %s is off the board", ErrInvalidCoordinate, c)
+	}
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON decodes a chess-style JSON string such as "B7".
+func (n *Notation) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: expected a string", ErrInvalidCoordinate)
+	}
+
+	c, err := ParseCoordinate(s)
+	if err != nil {
+		return err
+	}
+
+	*n = Notation(c)
+	return nil
+}
+
+// inNotationRange reports whether the coordinate can be written in chess-style notation.
+func (c Coordinate) inNotationRange() bool {
+	return c.X >= 0 && c.X < MaxGridSize && c.Y >= 0 && c.Y < MaxGridSize
+}
@@ -0,0 +1,144 @@
+package model_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	m "github.com/callegarimattia/battleship/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinate_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for y := range m.MaxGridSize {
+		for x := range m.MaxGridSize {
+			c := m.Coordinate{X: x, Y: y}
+
+			parsed, err := m.ParseCoordinate(c.String())
+			require.NoError(t, err, "ParseCoordinate(%q)", c.String())
+			assert.Equal(t, c, parsed)
+		}
+	}
+}
+
+func TestCoordinate_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		coord m.Coordinate
+		want  string
+	}{
+		{m.Coordinate{X: 0, Y: 0}, "A1"},
+		{m.Coordinate{X: 1, Y: 6}, "B7"},
+		{m.Coordinate{X: 9, Y: 9}, "J10"},
+		{m.Coordinate{X: 14, Y: 14}, "O15"},
+		{m.Coordinate{X: -1, Y: 0}, "(-1,0)"},
+		{m.Coordinate{X: 0, Y: 15}, "(0,15)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.coord.String())
+		})
+	}
+}
+
+func TestParseCoordinate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    m.Coordinate
+		wantErr bool
+	}{
+		{input: "A1", want: m.Coordinate{X: 0, Y: 0}},
+		{input: "b7", want: m.Coordinate{X: 1, Y: 6}},
+		{input: "  J10 ", want: m.Coordinate{X: 9, Y: 9}},
+		{input: "o15", want: m.Coordinate{X: 14, Y: 14}},
+		{input: "", wantErr: true},
+		{input: "A", wantErr: true},
+		{input: "P1", wantErr: true},
+		{input: "A0", wantErr: true},
+		{input: "A16", wantErr: true},
+		{input: "A-1", wantErr: true},
+		{input: "1A", wantErr: true},
+		{input: "A1x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.input), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := m.ParseCoordinate(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, m.ErrInvalidCoordinate)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseCoordinateFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		size    int
+		want    m.Coordinate
+		wantErr error
+	}{
+		{input: "J10", size: 10, want: m.Coordinate{X: 9, Y: 9}},
+		{input: "e5", size: 5, want: m.Coordinate{X: 4, Y: 4}},
+		{input: "K1", size: 10, wantErr: m.ErrInvalidCoordinate},
+		{input: "A11", size: 10, wantErr: m.ErrInvalidCoordinate},
+		{input: "F1", size: 5, wantErr: m.ErrInvalidCoordinate},
+		{input: "O15", size: m.MaxGridSize, want: m.Coordinate{X: 14, Y: 14}},
+		{input: "A1", size: m.MaxGridSize + 1, wantErr: m.ErrInvalidDimensions},
+		{input: "A1", size: 0, wantErr: m.ErrInvalidDimensions},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q on %d", tt.input, tt.size), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := m.ParseCoordinateFor(tt.input, tt.size)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNotation_JSON(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		At m.Notation `json:"at"`
+	}
+
+	data, err := json.Marshal(event{At: m.Notation{X: 1, Y: 6}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"at":"B7"}`, string(data))
+
+	var decoded event
+	require.NoError(t, json.Unmarshal([]byte(`{"at":"j10"}`), &decoded))
+	assert.Equal(t, m.Coordinate{X: 9, Y: 9}, m.Coordinate(decoded.At))
+
+	_, err = json.Marshal(m.Notation{X: 15, Y: 0})
+	assert.ErrorIs(t, err, m.ErrInvalidCoordinate)
+
+	err = json.Unmarshal([]byte(`{"at":"Z1"}`), &decoded)
+	assert.ErrorIs(t, err, m.ErrInvalidCoordinate)
+
+	err = json.Unmarshal([]byte(`{"at":{"X":0,"Y":0}}`), &decoded)
+	assert.ErrorIs(t, err, m.ErrInvalidCoordinate)
+}
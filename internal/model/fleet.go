@@ -0,0 +1,229 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+var (
+	// ErrUnknownFleetPreset is returned when a fleet preset name is not recognized.
+	ErrUnknownFleetPreset = errors.New("unknown fleet preset")
+	// ErrFleetTooLarge is returned when a fleet cannot reasonably be placed on the chosen board.
+	ErrFleetTooLarge = errors.New("fleet does not fit the board")
+	// ErrUnknownShipType is returned when a ship type name is not recognized.
+	ErrUnknownShipType = errors.New("unknown ship type")
+	// ErrInvalidHandicap is returned when a handicap takes away ships a fleet does not have.
+	ErrInvalidHandicap = errors.New("invalid handicap")
+)
+
+// ShipType is a class of ship from the classic fleet. Different types may share a size,
+// as the Cruiser and the Submarine do.
+type ShipType int
+
+// Known ShipType values, from the largest ship to the smallest.
+const (
+	Carrier ShipType = iota + 1
+	Battleship
+	Cruiser
+	Submarine
+	Destroyer
+)
+
+var shipTypes = [...]struct {
+	name string
+	size int
+}{
+	Carrier:    {"Carrier", 5},
+	Battleship: {"Battleship", 4},
+	Cruiser:    {"Cruiser", 3},
+	Submarine:  {"Submarine", 3},
+	Destroyer:  {"Destroyer", 2},
+}
+
+// Name returns the name players know the ship type by, e.g. "Cruiser".
+func (t ShipType) Name() string {
+	if !t.valid() {
+		return fmt.Sprintf("ShipType(%d)", int(t))
+	}
+	return shipTypes[t].name
+}
+
+// Size returns the number of cells a ship of this type covers.
+func (t ShipType) Size() int {
+	if !t.valid() {
+		return 0
+	}
+	return shipTypes[t].size
+}
+
+func (t ShipType) String() string { return t.Name() }
+
+func (t ShipType) valid() bool { return t >= Carrier && t <= Destroyer }
+
+// ParseShipType returns the ship type with the given name, ignoring case.
+func ParseShipType(name string) (ShipType, error) {
+	for t := Carrier; t <= Destroyer; t++ {
+		if strings.EqualFold(name, shipTypes[t].name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownShipType, name)
+}
+
+// ShipTypeOfSize returns the first ship type of the given size, so the Cruiser for size 3.
+func ShipTypeOfSize(size int) (ShipType, bool) {
+	types := ShipTypesOfSize(size)
+	if len(types) == 0 {
+		return 0, false
+	}
+	return types[0], true
+}
+
+// ShipTypesOfSize returns the ship types of the given size, from the first to the last.
+func ShipTypesOfSize(size int) []ShipType {
+	var types []ShipType
+	for t := Carrier; t <= Destroyer; t++ {
+		if shipTypes[t].size == size {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// FleetShipTypes tells apart the ships of a fleet counted by size.
+// The ships of a size are shared out among the types of that size in turn,
+// so two 3-cell ships are a Cruiser and a Submarine. Sizes no type has are left out.
+func FleetShipTypes(fleet map[int]int) map[ShipType]int {
+	types := make(map[ShipType]int)
+	for size, count := range fleet {
+		ofSize := ShipTypesOfSize(size)
+		for i := range max(count, 0) {
+			if len(ofSize) > 0 {
+				types[ofSize[i%len(ofSize)]]++
+			}
+		}
+	}
+	return types
+}
+
+// DescribeFleet lists the ships of a fleet by type, from the largest type to the smallest,
+// with how many of each there are. Sizes no type has come last, without a name.
+func DescribeFleet(fleet map[int]int) []dto.ShipRule {
+	types := FleetShipTypes(fleet)
+	ships := []dto.ShipRule{}
+	for t := Carrier; t <= Destroyer; t++ {
+		if types[t] > 0 {
+			ships = append(ships, dto.ShipRule{Name: t.Name(), Size: t.Size(), Count: types[t]})
+		}
+	}
+	for _, size := range slices.Backward(slices.Sorted(maps.Keys(fleet))) {
+		if len(ShipTypesOfSize(size)) == 0 && fleet[size] > 0 {
+			ships = append(ships, dto.ShipRule{Size: size, Count: fleet[size]})
+		}
+	}
+	return ships
+}
+
+// Fleet preset names.
+const (
+	PresetStandard = "standard"
+	PresetSmall    = "small"
+	PresetLarge    = "large"
+)
+
+var fleetPresets = map[string]func() map[int]int{
+	PresetStandard: StandardFleet,
+	PresetSmall: func() map[int]int {
+		return map[int]int{
+			3: 1, // Cruiser
+			2: 2, // Destroyers
+		}
+	},
+	PresetLarge: func() map[int]int {
+		return map[int]int{
+			5: 1, // Carrier
+			4: 2, // Battleships
+			3: 2, // Cruisers
+			2: 2, // Destroyers
+		}
+	},
+}
+
+// FleetPreset returns a fresh copy of the fleet registered under name.
+func FleetPreset(name string) (map[int]int, error) {
+	preset, ok := fleetPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFleetPreset, name)
+	}
+	return preset(), nil
+}
+
+// FleetPresetNames returns the known preset names in alphabetical order.
+func FleetPresetNames() []string {
+	return slices.Sorted(maps.Keys(fleetPresets))
+}
+
+// Handicap returns a copy of the fleet with ships added or, for negative counts, taken away,
+// by ship type name, e.g. {"Destroyer": 1}. Fleets count ships by size, so taking away
+// a Submarine leaves a Cruiser. Every problem found is reported, joined into one error.
+func Handicap(fleet map[int]int, changes map[string]int) (map[int]int, error) {
+	adjusted := maps.Clone(fleet)
+	var problems []error
+	for _, name := range slices.Sorted(maps.Keys(changes)) {
+		t, err := ParseShipType(name)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		adjusted[t.Size()] += changes[name]
+	}
+
+	for _, size := range slices.Sorted(maps.Keys(adjusted)) {
+		switch {
+		case adjusted[size] < 0:
+			problems = append(problems, fmt.Errorf("%w: takes away %d more ships of size %d than the fleet has",
+				ErrInvalidHandicap, -adjusted[size], size))
+		case adjusted[size] == 0:
+			delete(adjusted, size)
+		}
+	}
+	if len(problems) == 0 && len(adjusted) == 0 {
+		problems = append(problems, fmt.Errorf("%w: leaves no ships", ErrInvalidHandicap))
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return adjusted, nil
+}
+
+// ValidateFleet checks that the board size is supported and that the fleet fits on it:
+// every ship must fit in a row and the whole fleet may cover at most half of the cells.
+// Every problem found is reported, joined into one error.
+func ValidateFleet(fleet map[int]int, boardSize int) error {
+	if err := validateGridSize(boardSize); err != nil {
+		return err
+	}
+
+	var problems []error
+	cells := 0
+	for _, size := range slices.Sorted(maps.Keys(fleet)) {
+		if size > boardSize {
+			problems = append(problems,
+				fmt.Errorf("%w: a ship of size %d needs a board of at least %d", ErrFleetTooLarge, size, size))
+		}
+		cells += size * fleet[size]
+	}
+
+	if limit := boardSize * boardSize / 2; cells > limit {
+		problems = append(problems, fmt.Errorf("%w: %d ship cells on a %dx%d board (max %d)",
+			ErrFleetTooLarge, cells, boardSize, boardSize, limit))
+	}
+
+	return errors.Join(problems...)
+}
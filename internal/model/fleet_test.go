@@ -0,0 +1,142 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	m "github.com/callegarimattia/battleship/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFleetPreset(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{m.PresetLarge, m.PresetSmall, m.PresetStandard}, m.FleetPresetNames())
+
+	standard, err := m.FleetPreset(m.PresetStandard)
+	require.NoError(t, err)
+	assert.Equal(t, m.StandardFleet(), standard)
+
+	standard[5] = 0
+	again, err := m.FleetPreset(m.PresetStandard)
+	require.NoError(t, err)
+	assert.Equal(t, 1, again[5], "presets must return a fresh copy")
+
+	_, err = m.FleetPreset("armada")
+	assert.ErrorIs(t, err, m.ErrUnknownFleetPreset)
+}
+
+func TestValidateFleet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		preset  string
+		size    int
+		wantErr error
+	}{
+		{"Standard on standard board", m.PresetStandard, m.GridSize, nil},
+		{"Small on smallest board", m.PresetSmall, m.MinGridSize, nil},
+		{"Standard too crowded", m.PresetStandard, m.MinGridSize, m.ErrFleetTooLarge},
+		{"Large on large board", m.PresetLarge, m.MaxGridSize, nil},
+		{"Board too small", m.PresetSmall, m.MinGridSize - 1, m.ErrInvalidDimensions},
+		{"Board too large", m.PresetSmall, m.MaxGridSize + 1, m.ErrInvalidDimensions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fleet, err := m.FleetPreset(tt.preset)
+			require.NoError(t, err)
+
+			err = m.ValidateFleet(fleet, tt.size)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	err := m.ValidateFleet(map[int]int{6: 1}, m.MinGridSize)
+	assert.ErrorIs(t, err, m.ErrFleetTooLarge, "a ship longer than the board never fits")
+
+	err = m.ValidateFleet(map[int]int{7: 1, 6: 1}, m.MinGridSize)
+	require.ErrorIs(t, err, m.ErrFleetTooLarge)
+	assert.Contains(t, err.Error(), "size 6", "every oversized ship is reported")
+	assert.Contains(t, err.Error(), "size 7", "every oversized ship is reported")
+}
+
+func TestHandicap(t *testing.T) {
+	t.Parallel()
+
+	base := m.StandardFleet()
+	fleet, err := m.Handicap(base, map[string]int{"destroyer": 1, "Submarine": -1})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{5: 1, 4: 1, 3: 1, 2: 2}, fleet)
+	assert.Equal(t, m.StandardFleet(), base, "the fleet given is left alone")
+
+	_, err = m.Handicap(m.StandardFleet(), map[string]int{"Carrier": -2, "Dinghy": 1})
+	require.ErrorIs(t, err, m.ErrInvalidHandicap)
+	require.ErrorIs(t, err, m.ErrUnknownShipType, "every problem is reported")
+
+	_, err = m.Handicap(map[int]int{2: 1}, map[string]int{"Destroyer": -1})
+	assert.ErrorIs(t, err, m.ErrInvalidHandicap, "a fleet needs a ship left")
+}
+
+func TestShipType(t *testing.T) {
+	t.Parallel()
+
+	sub, err := m.ParseShipType("submarine")
+	require.NoError(t, err)
+	assert.Equal(t, m.Submarine, sub)
+	assert.Equal(t, "Submarine", sub.Name())
+	assert.Equal(t, 3, sub.Size())
+
+	_, err = m.ParseShipType("Dinghy")
+	assert.ErrorIs(t, err, m.ErrUnknownShipType)
+
+	three, ok := m.ShipTypeOfSize(3)
+	assert.True(t, ok)
+	assert.Equal(t, m.Cruiser, three, "the Cruiser comes first among size 3 ships")
+
+	_, ok = m.ShipTypeOfSize(7)
+	assert.False(t, ok)
+
+	for size := range m.StandardFleet() {
+		_, ok := m.ShipTypeOfSize(size)
+		assert.True(t, ok, "every standard ship size has a type, size %d", size)
+	}
+}
+
+func TestFleetShipTypes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, map[m.ShipType]int{
+		m.Carrier: 1, m.Battleship: 1, m.Cruiser: 1, m.Submarine: 1, m.Destroyer: 1,
+	}, m.FleetShipTypes(m.StandardFleet()))
+
+	assert.Equal(t, map[m.ShipType]int{
+		m.Cruiser: 2, m.Submarine: 1, m.Destroyer: 2,
+	}, m.FleetShipTypes(map[int]int{3: 3, 2: 2, 1: 4}), "sizes without a type are left out")
+}
+
+func TestDescribeFleet(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []dto.ShipRule{
+		{Name: "Carrier", Size: 5, Count: 1},
+		{Name: "Battleship", Size: 4, Count: 1},
+		{Name: "Cruiser", Size: 3, Count: 1},
+		{Name: "Submarine", Size: 3, Count: 1},
+		{Name: "Destroyer", Size: 2, Count: 1},
+	}, m.DescribeFleet(m.StandardFleet()))
+
+	assert.Equal(t, []dto.ShipRule{
+		{Name: "Destroyer", Size: 2, Count: 1},
+		{Size: 6, Count: 1},
+		{Size: 1, Count: 2},
+	}, m.DescribeFleet(map[int]int{1: 2, 2: 1, 6: 1, 4: 0}), "sizes without a type are listed last, unnamed")
+}
@@ -1,17 +1,26 @@
 package model
 
 import (
+	"crypto/rand"
 	"errors"
+	"fmt"
 	"maps"
+	"slices"
 
+	"github.com/callegarimattia/battleship/internal/commitment"
 	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/rules"
 )
 
 var (
 	// ErrNotYourTurn is returned when a player tries to act out of turn.
 	ErrNotYourTurn = errors.New("not your turn")
 	// ErrInvalidShot is returned when a shot is made to an invalid coordinate.
-	ErrInvalidShot = errors.New("invalid shot")
+	ErrInvalidShot = rules.ErrInvalidShot
+	// ErrShotOutOfBounds is the ErrInvalidShot of a shot off the board.
+	ErrShotOutOfBounds = rules.ErrShotOutOfBounds
+	// ErrAlreadyAttacked is the ErrInvalidShot of a shot at a cell already attacked.
+	ErrAlreadyAttacked = rules.ErrAlreadyAttacked
 	// ErrUnknownPlayer is returned when an action is attempted by an unknown player.
 	ErrUnknownPlayer = errors.New("unknown player")
 	// ErrNoShipsRemaining is returned when a player tries to place a ship of which they have none left.
@@ -22,10 +31,42 @@ var (
 	ErrNotInSetup = errors.New("game not in setup state")
 	// ErrNotReadyToStart is returned when trying to start the game before both players have placed all their ships.
 	ErrNotReadyToStart = errors.New("not all ships placed by both players")
-	// ErrGameFull is returned when trying to join a game that already has two players.
-	ErrGameFull = errors.New("game already has two players")
+	// ErrGameFull is returned when trying to join a game whose seats are all taken.
+	ErrGameFull = errors.New("game has no free seats")
+	// ErrAlreadySeated is returned when a seat is handed to a player who already has one in the game.
+	ErrAlreadySeated = errors.New("player already has a seat in this game")
+	// ErrFleetNotPlaced is returned when a player declares ready before placing all their ships.
+	ErrFleetNotPlaced = errors.New("not all ships placed")
+	// ErrAlreadyFinished is returned when trying to end a game that has already finished.
+	ErrAlreadyFinished = errors.New("game already finished")
+	// ErrNotFinished is returned when asking for what only a won game may show, such as the fleets.
+	ErrNotFinished = errors.New("game not finished")
+	// ErrInvalidMoveLimit is returned when a move limit is negative.
+	ErrInvalidMoveLimit = errors.New("move limit must not be negative")
+	// ErrInvalidTeamSize is returned when a team size is outside 1-MaxTeamSize.
+	ErrInvalidTeamSize = errors.New("invalid team size")
+	// ErrSeatsFixed is returned when changing the teams or the number of sides once a second player has joined.
+	ErrSeatsFixed = errors.New("seating fixed once a second player joins")
+	// ErrInvalidPlayerCount is returned when a free-for-all is set up for fewer than 3 or more than MaxFreeForAllPlayers.
+	ErrInvalidPlayerCount = errors.New("invalid number of players")
+	// ErrTargetRequired is returned when a free-for-all shot does not name its target.
+	ErrTargetRequired = errors.New("target player required")
+	// ErrInvalidTarget is returned when firing at a teammate, at oneself or at a player already sunk.
+	ErrInvalidTarget = errors.New("invalid target player")
 )
 
+// MaxTeamSize is the most players a side may have. Two makes a 2v2 game.
+const MaxTeamSize = 2
+
+// MaxFreeForAllPlayers is the most players a free-for-all may have.
+const MaxFreeForAllPlayers = 6
+
+// defaultSides is how many sides a game has unless it is a free-for-all.
+const defaultSides = 2
+
+// noTurn is the turn index while nobody may fire.
+const noTurn = -1
+
 // GameState represents the current phase of the game.
 type GameState int
 
@@ -35,124 +76,500 @@ const (
 	StateSetup
 	StatePlaying
 	StateGameOver
+	StateAbandoned
+	StateDraw // Decided without a winner
 )
 
-// Game acts as the refeeree between two players.
+// Game acts as the refeeree between two sides, of one player each unless teams are set up.
 // It holds the state and enforces the rules of the game.
 type Game struct {
-	player1 *Player
-	player2 *Player
-	turn    string
-	state   GameState
-	winner  string
+	// players are seated in the order they joined, which is also the turn order.
+	// Seats alternate between the sides, so turns do too, and so do teammates within a side.
+	players   []*Player
+	sides     int // Sides seats are dealt to in turn, more than two in a free-for-all
+	teamSize  int // Players on each side, one for a plain 1v1 game
+	turn      int // Seat of the player who fires next, noTurn outside play
+	state     GameState
+	winner    string
+	version   int  // Incremented on every state change
+	boardSize int  // Side length of both boards
+	moveLimit int  // Shots both players may fire in total before the game is decided on ships afloat, zero for no limit
+	assist    bool // Misses tell whether a ship is right next to them
+	// extraTurnOnHit lets a player keep firing until they miss, instead of passing the turn after every shot
+	extraTurnOnHit bool
+	moves          []Move
+	// commitment hashes every fleet as placed when play started, see commitment.Placement
+	commitment string
 }
 
-// IsGameOver returns true if the game is in the finished state.
+// Move is a shot that was fired during the game.
+type Move struct {
+	Attacker   string
+	Target     string // Player whose board was fired at, only recorded in a free-for-all
+	Coord      Coordinate
+	Result     ShotResult
+	NearbyShip bool // In assist mode, a miss that has a ship on one of its four sides
+}
+
+// Moves returns every shot fired so far, in order.
+func (g *Game) Moves() []Move {
+	return slices.Clone(g.moves)
+}
+
+// LastMove returns the most recent shot, if any was fired.
+func (g *Game) LastMove() (Move, bool) {
+	if len(g.moves) == 0 {
+		return Move{}, false
+	}
+	return g.moves[len(g.moves)-1], true
+}
+
+// IsGameOver returns true if the game is finished, by a win, a draw or because it was abandoned.
 func (g *Game) IsGameOver() bool {
-	return g.state == StateGameOver
+	return g.state == StateGameOver || g.state == StateAbandoned || g.state == StateDraw
+}
+
+// IsDraw reports whether the game was decided without a winner.
+func (g *Game) IsDraw() bool {
+	return g.state == StateDraw
+}
+
+// SetMoveLimit sets how many shots both players may fire in total. Once the last one is fired
+// without sinking the whole enemy fleet, the game is settled by LimitWinner. Zero removes the limit.
+// The limit can only be changed before the game starts.
+func (g *Game) SetMoveLimit(limit int) error {
+	switch {
+	case limit < 0:
+		return ErrInvalidMoveLimit
+	case g.state != StateWaiting && g.state != StateSetup:
+		return g.phaseError(ErrNotInSetup, "set move limit", StateSetup)
+	}
+	g.moveLimit = limit
+	return nil
+}
+
+// SetAssist turns the beginner assist mode on or off. In assist mode every miss tells
+// whether one of the four cells next to it holds a ship.
+// The mode can only be changed before the game starts.
+func (g *Game) SetAssist(on bool) error {
+	if g.state != StateWaiting && g.state != StateSetup {
+		return g.phaseError(ErrNotInSetup, "set assist mode", StateSetup)
+	}
+	g.assist = on
+	return nil
+}
+
+// SetExtraTurnOnHit turns the extra-turn-on-hit variant on or off. In that variant a hit or
+// a sinking keeps the turn with the attacker, who fires until they miss or win.
+// The variant can only be changed before the game starts.
+func (g *Game) SetExtraTurnOnHit(on bool) error {
+	if g.state != StateWaiting && g.state != StateSetup {
+		return g.phaseError(ErrNotInSetup, "set extra turn on hit", StateSetup)
+	}
+	g.extraTurnOnHit = on
+	return nil
+}
+
+// SetTeamSize sets how many players each side has. Teammates share one board, fleet and
+// record of their shots: either may place the side's ships, and the side loses once they are all sunk.
+// The size can only be changed before a second player joins.
+func (g *Game) SetTeamSize(size int) error {
+	switch {
+	case size < 1 || size > MaxTeamSize:
+		return fmt.Errorf("%w: %d", ErrInvalidTeamSize, size)
+	case g.state != StateWaiting || len(g.players) > 1:
+		return ErrSeatsFixed
+	}
+	g.sides = defaultSides
+	g.teamSize = size
+	return nil
+}
+
+// SetFreeForAll makes the game a free-for-all between the given number of players, each on
+// their own side. Every shot names its target, turns rotate among the players still afloat,
+// and the game is won by the last one standing. It replaces any team size set before,
+// and can only be called before a second player joins.
+func (g *Game) SetFreeForAll(players int) error {
+	switch {
+	case players < 3 || players > MaxFreeForAllPlayers:
+		return fmt.Errorf("%w: %d", ErrInvalidPlayerCount, players)
+	case g.state != StateWaiting || len(g.players) > 1:
+		return ErrSeatsFixed
+	}
+	g.sides = players
+	g.teamSize = 1
+	return nil
+}
+
+// IsFreeForAll reports whether every shot has to name its target.
+func (g *Game) IsFreeForAll() bool { return g.sides > defaultSides }
+
+// LivingPlayers returns the players whose side still has ships afloat, in seat order.
+func (g *Game) LivingPlayers() []string {
+	var ids []string
+	for _, p := range g.players {
+		if !g.defeated(p.team) {
+			ids = append(ids, p.id)
+		}
+	}
+	return ids
+}
+
+// TeamSize returns how many players each side has.
+func (g *Game) TeamSize() int { return g.teamSize }
+
+// Sides returns how many sides the game is played between: two, or every player in a free-for-all.
+// The first player seated on each side places its fleet.
+func (g *Game) Sides() int { return g.sides }
+
+// Seats returns the players seated so far, in the order they joined.
+func (g *Game) Seats() []string {
+	ids := make([]string, 0, len(g.players))
+	for _, p := range g.players {
+		ids = append(ids, p.id)
+	}
+	return ids
+}
+
+// Teammates returns the other players on the given player's side, in seat order.
+func (g *Game) Teammates(playerID string) []string {
+	p := g.getPlayerByID(playerID)
+	if p == nil {
+		return nil
+	}
+	var ids []string
+	for _, other := range g.players {
+		if other != p && other.team == p.team {
+			ids = append(ids, other.id)
+		}
+	}
+	return ids
+}
+
+// Abandon ends a game that nobody is playing anymore. No winner is declared.
+func (g *Game) Abandon() error {
+	if g.IsGameOver() {
+		return g.phaseError(ErrAlreadyFinished, "abandon")
+	}
+
+	g.state = StateAbandoned
+	g.turn = noTurn
+	g.version++
+
+	return nil
 }
 
 // Player represents a participant in the Battleship game.
 type Player struct {
 	id    string
-	fleet map[int]int // Remaining ships to place by size
-	board *Board
+	fleet map[int]int      // Remaining ships to place by size
+	types map[ShipType]int // Remaining ships to place by type, for the sizes that have types
+	// starting is the fleet the player began with by type, which differs between players with a handicap
+	starting map[ShipType]int
+	board    *Board
+	// tracking is what the player learned about each enemy board from their shots, by side
+	tracking map[int]*trackingGrid
+	ready    bool
+	team     int    // Index of the player's side; teammates share it
+	salt     string // Hides the fleet in the placement commitment, told once the game is decided
+}
+
+func newPlayer(id string, board *Board, fleet map[int]int) *Player {
+	fleet = startingFleet(fleet)
+	return &Player{
+		id:       id,
+		board:    board,
+		fleet:    fleet,
+		types:    FleetShipTypes(fleet),
+		starting: FleetShipTypes(fleet),
+		tracking: make(map[int]*trackingGrid),
+	}
 }
 
 // NewFullGame initializes a new game with two players identified by their IDs.
 // A fleet configuration can be provided; if nil, the standard fleet is used.
 func NewFullGame(p1ID, p2ID string, fleet map[int]int) *Game {
+	p2 := newPlayer(p2ID, NewBoard(), fleet)
+	p2.team = 1
 	return &Game{
-		player1: &Player{id: p1ID, board: NewBoard(), fleet: startingFleet(fleet)},
-		player2: &Player{id: p2ID, board: NewBoard(), fleet: startingFleet(fleet)},
-		state:   StateSetup,
+		players:   []*Player{newPlayer(p1ID, NewBoard(), fleet), p2},
+		sides:     defaultSides,
+		teamSize:  1,
+		turn:      noTurn,
+		state:     StateSetup,
+		boardSize: GridSize,
 	}
 }
 
-// NewGame initializes a new empty game.
+// NewGame initializes a new empty game on the standard board.
 func NewGame() *Game {
-	return &Game{}
+	return &Game{boardSize: GridSize, sides: defaultSides, teamSize: 1, turn: noTurn}
+}
+
+// NewGameOfSize initializes a new empty game whose boards have the given side length.
+// Sizes outside MinGridSize-MaxGridSize return ErrInvalidDimensions.
+func NewGameOfSize(size int) (*Game, error) {
+	if err := validateGridSize(size); err != nil {
+		return nil, err
+	}
+	return &Game{boardSize: size, sides: defaultSides, teamSize: 1, turn: noTurn}, nil
 }
 
 // Join adds a player to the game with the specified fleet configuration.
+// Seats alternate between the two sides. A player joining a side that already has a player
+// shares their board and fleet, so the fleet configuration only counts for the first one.
+// A player who already has a seat cannot take another one.
 func (g *Game) Join(playerID string, fleet map[int]int) error {
-	switch {
-	case g.player1 == nil:
-		g.player1 = &Player{id: playerID, board: NewBoard(), fleet: startingFleet(fleet)}
+	seat := len(g.players)
+	if seat >= g.sides*g.teamSize {
+		return ErrGameFull
+	}
+	if g.getPlayerByID(playerID) != nil {
+		return ErrAlreadySeated
+	}
 
-		return nil
-	case g.player2 == nil:
-		g.player2 = &Player{id: playerID, board: NewBoard(), fleet: startingFleet(fleet)}
+	var p *Player
+	if seat < g.sides {
+		p = newPlayer(playerID, newBoard(g.boardSize), fleet)
+	} else {
+		teammate := g.players[seat-g.sides]
+		p = &Player{
+			id:       playerID,
+			board:    teammate.board,
+			fleet:    teammate.fleet,
+			types:    teammate.types,
+			starting: teammate.starting,
+			tracking: teammate.tracking,
+		}
+	}
+	p.team = seat % g.sides
+	g.players = append(g.players, p)
 
-		g.state = StateSetup // Once both players have joined, move to setup phase
+	if len(g.players) == g.sides*g.teamSize {
+		g.state = StateSetup // Once every seat is taken, move to setup phase
+	}
+	g.version++
 
-		return nil
-	default:
-		return ErrGameFull
+	return nil
+}
+
+// ReplacePlayer hands a player's seat to another ID, who carries on with their fleet, shots and turn.
+func (g *Game) ReplacePlayer(oldID, newID string) error {
+	var p *Player
+	for _, seat := range g.players {
+		switch {
+		case seat.id == newID:
+			return ErrAlreadySeated
+		case seat.id == oldID:
+			p = seat
+		}
+	}
+	if p == nil || newID == "" {
+		return ErrUnknownPlayer
 	}
+
+	p.id = newID
+	if g.winner == oldID {
+		g.winner = newID
+	}
+	for i := range g.moves {
+		if g.moves[i].Attacker == oldID {
+			g.moves[i].Attacker = newID
+		}
+		if g.moves[i].Target == oldID {
+			g.moves[i].Target = newID
+		}
+	}
+	g.version++
+	return nil
 }
 
 // PlaceShip places a ship for the specified player at the given coordinate and orientation.
 // Placing a ship can be done only during the setup phase, but turns are not enforced.
+// When ships of several types share the size, the first type still to be placed is used,
+// so the Cruiser goes before the Submarine.
 func (g *Game) PlaceShip(playerID string, c Coordinate, size int, o Orientation) error {
+	p, err := g.placingPlayer(playerID)
+	if err != nil {
+		return err
+	}
+
+	var kind ShipType
+	for _, t := range ShipTypesOfSize(size) {
+		if p.types[t] > 0 {
+			kind = t
+			break
+		}
+	}
+
+	return g.placeShip(p, c, &Ship{size: size, kind: kind}, o)
+}
+
+// PlaceShipType places a ship of the given type, following the same rules as PlaceShip.
+// Ships sharing a size are told apart, so a fleet with one Cruiser and one Submarine
+// does not accept two Submarines.
+func (g *Game) PlaceShipType(playerID string, c Coordinate, t ShipType, o Orientation) error {
+	if !t.valid() {
+		return fmt.Errorf("%w: %v", ErrUnknownShipType, t)
+	}
+
+	p, err := g.placingPlayer(playerID)
+	if err != nil {
+		return err
+	}
+
+	if p.types[t] <= 0 {
+		return ErrNoShipsRemaining
+	}
+
+	return g.placeShip(p, c, &Ship{size: t.Size(), kind: t}, o)
+}
+
+// CanPlaceShip reports why PlaceShip would fail with the same arguments, or nil if it would succeed.
+// The game is left untouched.
+func (g *Game) CanPlaceShip(playerID string, c Coordinate, size int, o Orientation) error {
+	p, err := g.placingPlayer(playerID)
+	if err != nil {
+		return err
+	}
+
+	if p.fleet[size] <= 0 {
+		return ErrNoShipsRemaining
+	}
+
+	return p.board.CanPlaceShip(c, size, o)
+}
+
+func (g *Game) placingPlayer(playerID string) (*Player, error) {
 	if g.state != StateSetup {
-		return ErrNotInSetup
+		return nil, g.phaseError(ErrNotInSetup, "place ship", StateSetup)
 	}
 
 	var p *Player
 	if p = g.getPlayerByID(playerID); p == nil {
-		return ErrUnknownPlayer
+		return nil, ErrUnknownPlayer
 	}
 
-	if shipCount, exists := p.fleet[size]; !exists || shipCount <= 0 {
+	return p, nil
+}
+
+func (g *Game) placeShip(p *Player, c Coordinate, s *Ship, o Orientation) error {
+	if shipCount, exists := p.fleet[s.size]; !exists || shipCount <= 0 {
 		return ErrNoShipsRemaining
 	}
 
-	if err := p.board.PlaceShip(c, &Ship{size}, o); err != nil {
+	if err := p.board.PlaceShip(c, s, o); err != nil {
 		return err
 	}
 
-	p.fleet[size]--
+	p.fleet[s.size]--
+	if s.kind != 0 {
+		p.types[s.kind]--
+	}
+	g.version++
 
 	return nil
 }
 
-// StartGame transitions the game from setup to playing state if both players have placed all their ships.
+// SetReady marks the player as ready to play once their whole fleet is placed.
+// Teammates share one board, so a player declaring ready does so for their whole side,
+// including teammates who had nothing left to place. The game starts as soon as every side is ready.
+func (g *Game) SetReady(playerID string) error {
+	if g.state != StateSetup {
+		return g.phaseError(ErrNotInSetup, "set ready", StateSetup)
+	}
+
+	var p *Player
+	if p = g.getPlayerByID(playerID); p == nil {
+		return ErrUnknownPlayer
+	}
+
+	if !g.playerShipsPlaced(p) {
+		return ErrFleetNotPlaced
+	}
+
+	for _, mate := range g.players {
+		if mate.team == p.team {
+			mate.ready = true
+		}
+	}
+	g.version++
+
+	for _, other := range g.players {
+		if !other.ready {
+			return nil
+		}
+	}
+
+	return g.StartGame()
+}
+
+// StartGame transitions the game from setup to playing state if every side has placed all its ships.
 func (g *Game) StartGame() error {
 	switch {
 	case g.state != StateSetup:
-		return ErrNotInSetup
+		return g.phaseError(ErrNotInSetup, "start game", StateSetup)
 	case !g.allShipsPlaced():
 		return ErrNotReadyToStart
 	default:
 		g.state = StatePlaying
-		g.turn = g.player1.id
+		g.turn = 0
+		g.commitPlacements()
+		g.version++
 		return nil
 	}
 }
 
-// Attack coordinates a shot from the attacker to the defender.
-func (g *Game) Attack(attackerID string, c Coordinate) (ShotResult, error) {
-	switch {
-	case g.state != StatePlaying:
-		return ShotResultInvalid, ErrNotInPlay
-	case g.getPlayerByID(attackerID) == nil:
-		return ShotResultInvalid, ErrUnknownPlayer
-	case g.turn != attackerID:
-		return ShotResultInvalid, ErrNotYourTurn
+// commitPlacements salts every fleet and hashes them in seat order, so that the fleets revealed
+// at the end can be checked against the ones play started with.
+func (g *Game) commitPlacements() {
+	boards := make([]commitment.SaltedBoard, 0, len(g.players))
+	for _, p := range g.players {
+		p.salt = rand.Text()
+		boards = append(boards, commitment.SaltedBoard{Salt: p.salt, Board: p.board})
 	}
+	g.commitment = commitment.Placement(boards)
+}
+
+// PlacementCommitment returns the hash of every fleet as placed when play started,
+// or an empty string before then. Reveal tells the salts it can be recomputed with.
+func (g *Game) PlacementCommitment() string { return g.commitment }
 
-	var d *Player
-	if d = g.getOpponent(attackerID); d == nil {
-		return ShotResultInvalid, ErrNotYourTurn
+// Attack coordinates a shot from the attacker to the other side's board.
+// The attacker's side wins once every ship on it is sunk.
+// In a free-for-all the target has to be named with AttackTarget.
+func (g *Game) Attack(attackerID string, c Coordinate) (ShotResult, error) {
+	return g.AttackTarget(attackerID, "", c)
+}
+
+// AttackTarget fires at the board of the named player, who must be on another side and
+// still afloat. A side whose ships are all sunk is out of the game, and the game is won once
+// a single side is left. An empty targetID picks the target as Attack does, outside a free-for-all.
+func (g *Game) AttackTarget(attackerID, targetID string, c Coordinate) (ShotResult, error) {
+	a, d, err := g.aim(attackerID, targetID, c)
+	if err != nil {
+		return ShotResultInvalid, err
 	}
+	res := d.board.ReceiveShot(c)
 
-	switch res := d.board.ReceiveShot(c); res {
-	case ShotResultInvalid:
-		return ShotResultInvalid, ErrInvalidShot
+	var sunk []Coordinate
+	if res == ShotResultSunk {
+		sunk = d.board.shipCellsAt(c)
+	}
+	a.trackingOf(d.team).markShotResult(c, res, sunk)
+	move := Move{Attacker: attackerID, Coord: c, Result: res}
+	if g.IsFreeForAll() {
+		move.Target = d.id
+	}
+	if g.assist && res == ShotResultMiss {
+		move.NearbyShip = d.board.ShipNearby(c)
+	}
+	g.moves = append(g.moves, move)
+	g.version++
 
+	switch res {
 	case ShotResultSunk:
-		if d.board.AllShipsSunk() {
+		if g.defeated(d.team) && g.sidesAfloat() == 1 {
 			g.state = StateGameOver
 			g.winner = attackerID
 			return res, nil
@@ -160,6 +577,13 @@ func (g *Game) Attack(attackerID string, c Coordinate) (ShotResult, error) {
 		fallthrough
 
 	case ShotResultHit, ShotResultMiss:
+		if g.moveLimit > 0 && len(g.moves) >= g.moveLimit {
+			g.endOnMoveLimit()
+			return res, nil
+		}
+		if g.extraTurnOnHit && res != ShotResultMiss {
+			return res, nil
+		}
 		g.passTurn()
 		return res, nil
 	}
@@ -167,7 +591,110 @@ func (g *Game) Attack(attackerID string, c Coordinate) (ShotResult, error) {
 	return ShotResultInvalid, ErrInvalidShot
 }
 
+// CanAttack reports why AttackTarget would refuse the shot with the same arguments, or nil if it
+// would be fired. The game is left untouched.
+func (g *Game) CanAttack(attackerID, targetID string, c Coordinate) error {
+	_, _, err := g.aim(attackerID, targetID, c)
+	return err
+}
+
+// aim checks a shot may be fired: the game is in play, it is the attacker's turn, the target is
+// one they may fire at and the cell is on the board and not attacked yet. It returns the attacker
+// and the target.
+func (g *Game) aim(attackerID, targetID string, c Coordinate) (a, d *Player, err error) {
+	if err := g.checkTurn(attackerID); err != nil {
+		return nil, nil, err
+	}
+
+	a = g.getPlayerByID(attackerID)
+	if d, err = g.pickTarget(a, targetID); err != nil {
+		return nil, nil, err
+	}
+
+	// Neither a shot off the board nor a repeat costs the attacker their turn
+	if err := d.board.CanReceiveShot(c); err != nil {
+		return nil, nil, err
+	}
+	return a, d, nil
+}
+
+// MovesLeft returns how many shots may still be fired before the move limit ends the game,
+// or -1 if there is no limit.
+func (g *Game) MovesLeft() int {
+	if g.moveLimit == 0 {
+		return -1
+	}
+	return max(g.moveLimit-len(g.moves), 0)
+}
+
+// LimitWinner returns who wins if the game ends on its move limit: the first player of the side
+// with the most ship cells still afloat. It returns an empty string when sides tie for the most.
+func (g *Game) LimitWinner() string {
+	if len(g.players) < 2 {
+		return ""
+	}
+	var best *Player
+	bestLeft, tied := -1, false
+	for _, p := range g.players[:min(g.sides, len(g.players))] {
+		switch left := p.board.CellsAfloat(); {
+		case left > bestLeft:
+			best, bestLeft, tied = p, left, false
+		case left == bestLeft:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+	return best.id
+}
+
+// lastNearbyShip reports whether the player's latest shot was a miss next to a ship.
+// It is always false outside assist mode.
+func (g *Game) lastNearbyShip(playerID string) bool {
+	for _, move := range slices.Backward(g.moves) {
+		if move.Attacker == playerID {
+			return move.NearbyShip
+		}
+	}
+	return false
+}
+
+// endOnMoveLimit finishes a game whose last allowed shot was fired.
+func (g *Game) endOnMoveLimit() {
+	g.turn = noTurn
+	if g.winner = g.LimitWinner(); g.winner != "" {
+		g.state = StateGameOver
+		return
+	}
+	g.state = StateDraw
+}
+
+// IsPlayersTurn reports whether the game is being played and it is the given player's turn to attack.
+func (g *Game) IsPlayersTurn(playerID string) bool {
+	return g.state == StatePlaying && g.turnPlayerID() == playerID
+}
+
+// turnPlayerID returns the ID of the player who fires next, or an empty string outside play.
+// A turn that points at no seat, as in a Game not made by a constructor, is nobody's.
+func (g *Game) turnPlayerID() string {
+	if g.turn < 0 || g.turn >= len(g.players) {
+		return ""
+	}
+	return g.players[g.turn].id
+}
+
+// Version returns a counter that increases every time the game state changes.
+func (g *Game) Version() int { return g.version }
+
+// State returns the current phase as reported to clients, including WAITING.
+func (g *Game) State() dto.GameState { return dto.GameState(phaseName(g.state)) }
+
+// BoardSize returns the side length of the boards in this game.
+func (g *Game) BoardSize() int { return g.boardSize }
+
 // Winner returns the ID of the winning player if the game has finished; otherwise, it returns an empty string.
+// In a team game it is the player who sank the last ship, and their teammates win with them.
 func (g *Game) Winner() string { return g.winner }
 
 // StandardFleet returns the standard Battleship fleet configuration.
@@ -181,33 +708,99 @@ func StandardFleet() map[int]int {
 	}
 }
 
-// GetView returns the DTO seen by a specific observer (playerID).
-func (g *Game) GetView(observerID string) (dto.GameView, error) {
-	var me, enemy *Player
+// LegalMoves lists the cells the player may fire at on the target's board, which are those
+// they have not shot at yet, along with what they have learned of that board. The target is
+// picked as in AttackTarget, so it must be named in a free-for-all. It fails like AttackTarget
+// when it is not the player's turn.
+func (g *Game) LegalMoves(playerID, targetID string) (dto.LegalMoves, error) {
+	if err := g.checkTurn(playerID); err != nil {
+		return dto.LegalMoves{}, err
+	}
+
+	p := g.getPlayerByID(playerID)
+	d, err := g.pickTarget(p, targetID)
+	if err != nil {
+		return dto.LegalMoves{}, err
+	}
+	tracking := p.trackingOf(d.team)
+	moves := dto.LegalMoves{Targets: []dto.Target{}, Board: tracking.view()}
+	for y := range tracking.size {
+		for x := range tracking.size {
+			if rules.CanAttack(tracking, x, y) == nil {
+				c := Coordinate{X: x, Y: y}
+				moves.Targets = append(moves.Targets, dto.Target{X: x, Y: y, Coord: c.String()})
+			}
+		}
+	}
+
+	return moves, nil
+}
 
-	// Check if player1 exists and matches
+// checkTurn reports why the player may not fire now, if they may not.
+func (g *Game) checkTurn(playerID string) error {
 	switch {
-	case g.player1 != nil && observerID == g.player1.id:
-		me = g.player1
-		enemy = g.player2
-	case g.player2 != nil && observerID == g.player2.id:
-		me = g.player2
-		enemy = g.player1
-	default:
+	case g.state != StatePlaying:
+		return g.phaseError(ErrNotInPlay, "attack", StatePlaying)
+	case g.getPlayerByID(playerID) == nil:
+		return ErrUnknownPlayer
+	case g.turnPlayerID() != playerID:
+		return ErrNotYourTurn
+	}
+	return nil
+}
+
+// Reveal returns every player's view with every ship shown, in seat order.
+// The fleets stop being secret once the game is decided by a win or a draw, so it fails before that.
+func (g *Game) Reveal() ([]dto.PlayerView, error) {
+	if g.state != StateGameOver && g.state != StateDraw {
+		return nil, g.phaseError(ErrNotFinished, "reveal the fleets", StateGameOver)
+	}
+	views := make([]dto.PlayerView, 0, len(g.players))
+	for _, p := range g.players {
+		view := p.GetView(false)
+		view.Salt = p.salt
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// GetView returns the DTO seen by a specific observer (playerID).
+// The enemy is the player the observer fires at by default; in a team game that side shares their board.
+func (g *Game) GetView(observerID string) (dto.GameView, error) {
+	me := g.getPlayerByID(observerID)
+	if me == nil {
 		return dto.GameView{}, ErrUnknownPlayer
 	}
+	enemy := g.target(me)
+	if enemy == nil {
+		enemy = g.opponent(me, false) // The game is over, and the enemy sunk
+	}
 
 	// Build the view
 	view := dto.GameView{
-		State:  toDTOState(g.state),
-		Turn:   g.turn,
-		Winner: g.winner,
-		Me:     me.GetView(false), // Full view
+		State:    toDTOState(g.state),
+		Turn:     g.turnPlayerID(),
+		YourTurn: g.IsPlayersTurn(observerID),
+		Winner:   g.winner,
+		Version:  g.version,
+		Me:       me.GetView(false), // Full view
+
+		PlacementCommitment: g.commitment,
+	}
+	if left := g.MovesLeft(); left > 0 {
+		view.MovesLeft = left
+	}
+	view.NearbyShip = g.lastNearbyShip(observerID)
+	if g.IsFreeForAll() {
+		view.LivingPlayers = g.LivingPlayers()
+		view.TargetIDs = g.targetIDs(me)
 	}
 
-	// Only add enemy view if enemy exists
+	// Only add enemy view if enemy exists.
+	// Its board is what the observer learned by firing at it, not a fogged copy of the real one.
 	if enemy != nil {
-		view.Enemy = enemy.GetView(true) // Fog of war
+		view.Enemy = enemy.GetView(true)
+		view.Enemy.Board = me.trackingOf(enemy.team).view()
 	}
 
 	return view, nil
@@ -219,42 +812,153 @@ func (p *Player) GetView(hideShips bool) dto.PlayerView {
 		ID:    p.id,
 		Board: p.board.GetSnapshot(hideShips),
 		Fleet: maps.Clone(p.fleet),
+		Ships: shipNames(p.types),
+		Ready: p.ready,
+
+		ShipsAfloat:   p.board.ShipsAfloat(),
+		StartingShips: shipNames(p.starting),
+	}
+}
+
+// phaseError wraps a phase sentinel error with the attempted action, the current phase
+// and, if given, the phase the action requires, e.g.
+// "game not in playing state: cannot attack: game is in SETUP, expected PLAYING".
+func (g *Game) phaseError(sentinel error, action string, expected ...GameState) error {
+	if len(expected) == 0 {
+		return fmt.Errorf("%w: cannot %s: game is in %s", sentinel, action, phaseName(g.state))
 	}
+	return fmt.Errorf(
+		"%w: cannot %s: game is in %s, expected %s",
+		sentinel, action, phaseName(g.state), phaseName(expected[0]),
+	)
+}
+
+// phaseName returns the name clients know a phase by.
+func phaseName(state GameState) string {
+	if state == StateWaiting {
+		return string(dto.StateWaiting) // Views leave the state empty while waiting for an opponent
+	}
+	return string(toDTOState(state))
 }
 
 func (g *Game) allShipsPlaced() bool {
-	return g.playerShipsPlaced(g.player1) && g.playerShipsPlaced(g.player2)
+	if len(g.players) < 2 {
+		return false
+	}
+	for _, p := range g.players {
+		if !g.playerShipsPlaced(p) {
+			return false
+		}
+	}
+	return true
 }
 
+// passTurn hands the turn to the next seat whose side is still afloat.
 func (g *Game) passTurn() {
-	switch g.turn {
-	case g.player1.id:
-		g.turn = g.player2.id
-	case g.player2.id:
-		g.turn = g.player1.id
+	if len(g.players) == 0 {
+		return
+	}
+	for range g.players {
+		g.turn = (g.turn + 1) % len(g.players)
+		if !g.defeated(g.players[g.turn].team) {
+			return
+		}
 	}
 }
 
 func (g *Game) getPlayerByID(playerID string) *Player {
-	switch playerID {
-	case g.player1.id:
-		return g.player1
-	case g.player2.id:
-		return g.player2
-	default: // Unknown player
-		return nil
+	for _, p := range g.players {
+		if p.id == playerID {
+			return p
+		}
 	}
+	return nil // Unknown player
 }
 
-func (g *Game) getOpponent(playerID string) *Player {
-	switch playerID {
-	case g.player1.id:
-		return g.player2
-	case g.player2.id:
-		return g.player1
-	default: // Unknown player
-		return nil
+// target returns the player whose board the given player fires at unless told otherwise:
+// the next one seated on another side still afloat. Teammates share a board, so any of them
+// would do. It returns nil for an unknown player or while no other side is afloat.
+func (g *Game) target(p *Player) *Player {
+	return g.opponent(p, true)
+}
+
+// opponent returns the next player seated on another side, only among the sides still afloat
+// if afloat is set. It returns nil for an unknown player or if there is none.
+func (g *Game) opponent(p *Player, afloat bool) *Player {
+	if p == nil {
+		return nil // Unknown player
+	}
+	seat := slices.Index(g.players, p)
+	for i := 1; i < len(g.players); i++ {
+		other := g.players[(seat+i)%len(g.players)]
+		if other.team != p.team && (!afloat || !g.defeated(other.team)) {
+			return other
+		}
 	}
+	return nil
+}
+
+// pickTarget returns the player the attacker fires at, by ID or, outside a free-for-all, implicitly.
+func (g *Game) pickTarget(a *Player, targetID string) (*Player, error) {
+	if targetID == "" {
+		if g.IsFreeForAll() {
+			return nil, ErrTargetRequired
+		}
+		if d := g.target(a); d != nil {
+			return d, nil
+		}
+		return nil, ErrNotYourTurn
+	}
+
+	d := g.getPlayerByID(targetID)
+	switch {
+	case d == nil:
+		return nil, ErrUnknownPlayer
+	case !g.canTarget(a, d):
+		return nil, ErrInvalidTarget
+	}
+	return d, nil
+}
+
+// canTarget reports whether a may fire at d: d is on another side, which is still afloat.
+func (g *Game) canTarget(a, d *Player) bool {
+	return d.team != a.team && !g.defeated(d.team)
+}
+
+// targetIDs returns the players p may fire at, in seat order.
+func (g *Game) targetIDs(p *Player) []string {
+	var ids []string
+	for _, other := range g.players {
+		if g.canTarget(p, other) {
+			ids = append(ids, other.id)
+		}
+	}
+	return ids
+}
+
+// sidesAfloat counts the sides that still have ships afloat.
+func (g *Game) sidesAfloat() int {
+	afloat := make(map[int]bool)
+	for _, p := range g.players {
+		if !g.defeated(p.team) {
+			afloat[p.team] = true
+		}
+	}
+	return len(afloat)
+}
+
+// defeated reports whether every ship of the side is sunk. No side is defeated before
+// the shooting starts, while boards are still empty or partly filled.
+func (g *Game) defeated(team int) bool {
+	if g.state == StateWaiting || g.state == StateSetup {
+		return false
+	}
+	for _, p := range g.players {
+		if p.team == team && !p.board.AllShipsSunk() {
+			return false
+		}
+	}
+	return true
 }
 
 func (g *Game) playerShipsPlaced(p *Player) bool {
@@ -266,6 +970,25 @@ func (g *Game) playerShipsPlaced(p *Player) bool {
 	return true
 }
 
+// trackingOf returns what the player's side learned about the given side's board.
+func (p *Player) trackingOf(team int) *trackingGrid {
+	t, ok := p.tracking[team]
+	if !ok {
+		t = newTrackingGrid(p.board.Size())
+		p.tracking[team] = t
+	}
+	return t
+}
+
+// shipNames returns the ships counted by type name.
+func shipNames(types map[ShipType]int) map[string]int {
+	ships := make(map[string]int, len(types))
+	for t, count := range types {
+		ships[t.Name()] = count
+	}
+	return ships
+}
+
 func startingFleet(fleet map[int]int) map[int]int {
 	if fleet == nil {
 		return StandardFleet()
@@ -282,6 +1005,10 @@ func toDTOState(state GameState) dto.GameState {
 		return dto.StatePlaying
 	case StateGameOver:
 		return dto.StateFinished
+	case StateAbandoned:
+		return dto.StateAbandoned
+	case StateDraw:
+		return dto.StateDraw
 	default:
 		return ""
 	}
@@ -3,6 +3,7 @@ package model_test
 import (
 	"testing"
 
+	"github.com/callegarimattia/battleship/internal/commitment"
 	"github.com/callegarimattia/battleship/internal/dto"
 	m "github.com/callegarimattia/battleship/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +48,9 @@ func TestJoin(t *testing.T) {
 	err := g.Join("Alice", nil)
 	require.NoError(t, err, "First player should join successfully")
 
+	err = g.Join("Alice", nil)
+	require.ErrorIs(t, err, m.ErrAlreadySeated, "A player cannot take both seats")
+
 	// 2. Join second player
 	err = g.Join("Bob", nil)
 	require.NoError(t, err, "Second player should join successfully")
@@ -79,6 +83,84 @@ func TestPlaceShip_Rules(t *testing.T) {
 
 	err = g.PlaceShip("Hacker", m.Coordinate{X: 0, Y: 0}, 3, m.Horizontal)
 	assert.ErrorIs(t, err, m.ErrUnknownPlayer, "Expected ErrUnknownPlayer")
+
+	err = g.PlaceShipType("Bob", m.Coordinate{X: 0, Y: 0}, m.Cruiser, m.Vertical)
+	assert.NoError(t, err, "Ships can be placed by type")
+
+	err = g.PlaceShipType("Bob", m.Coordinate{X: 1, Y: 0}, m.Submarine, m.Vertical)
+	assert.ErrorIs(t, err, m.ErrNoShipsRemaining, "Expected ErrNoShipsRemaining for a used up size")
+
+	err = g.PlaceShipType("Bob", m.Coordinate{X: 2, Y: 0}, m.ShipType(42), m.Vertical)
+	assert.ErrorIs(t, err, m.ErrUnknownShipType, "Expected ErrUnknownShipType")
+}
+
+// TestPlaceShip_ShipTypes verifies that ships sharing a size keep their identity
+func TestPlaceShip_ShipTypes(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("Alice", "Bob", nil)
+
+	require.NoError(t, g.PlaceShipType("Alice", m.Coordinate{X: 0, Y: 0}, m.Submarine, m.Horizontal))
+	err := g.PlaceShipType("Alice", m.Coordinate{X: 0, Y: 1}, m.Submarine, m.Horizontal)
+	assert.ErrorIs(t, err, m.ErrNoShipsRemaining, "The standard fleet has a single Submarine")
+
+	view, err := g.GetView("Alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, view.Me.Fleet[3])
+	assert.Equal(t, 1, view.Me.Ships["Cruiser"])
+	assert.Equal(t, 0, view.Me.Ships["Submarine"])
+
+	// Placing by size takes the type still missing
+	require.NoError(t, g.PlaceShip("Alice", m.Coordinate{X: 0, Y: 1}, 3, m.Horizontal))
+	err = g.PlaceShipType("Alice", m.Coordinate{X: 0, Y: 2}, m.Cruiser, m.Horizontal)
+	assert.ErrorIs(t, err, m.ErrNoShipsRemaining)
+
+	view, err = g.GetView("Alice")
+	require.NoError(t, err)
+	assert.Equal(t, 0, view.Me.Fleet[3])
+	assert.Equal(t, 0, view.Me.Ships["Cruiser"])
+
+	// Untyped sizes are still counted by size
+	custom := m.NewFullGame("Alice", "Bob", map[int]int{1: 1, 3: 1})
+	require.NoError(t, custom.PlaceShip("Alice", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal))
+	require.NoError(t, custom.PlaceShip("Alice", m.Coordinate{X: 0, Y: 1}, 3, m.Horizontal))
+	err = custom.PlaceShipType("Alice", m.Coordinate{X: 0, Y: 2}, m.Submarine, m.Horizontal)
+	assert.ErrorIs(t, err, m.ErrNoShipsRemaining, "A single 3-cell ship is the Cruiser")
+}
+
+// TestCanPlaceShip verifies that placements are checked without being made
+func TestCanPlaceShip(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("Alice", "Bob", map[int]int{3: 1, 2: 1})
+	mustPlace(t, g, "Alice", m.Coordinate{X: 0, Y: 0}, 3, m.Horizontal)
+	version := g.Version()
+
+	tests := []struct {
+		name    string
+		player  string
+		coord   m.Coordinate
+		size    int
+		wantErr error
+	}{
+		{"Legal", "Alice", m.Coordinate{X: 0, Y: 1}, 2, nil},
+		{"Overlap", "Alice", m.Coordinate{X: 1, Y: 0}, 2, m.ErrShipOverlap},
+		{"Out of bounds", "Alice", m.Coordinate{X: 9, Y: 5}, 2, m.ErrShipOutOfBounds},
+		{"Depleted", "Alice", m.Coordinate{X: 0, Y: 5}, 3, m.ErrNoShipsRemaining},
+		{"Unknown player", "Mallory", m.Coordinate{X: 0, Y: 5}, 2, m.ErrUnknownPlayer},
+	}
+
+	for _, tt := range tests {
+		err := g.CanPlaceShip(tt.player, tt.coord, tt.size, m.Horizontal)
+		if tt.wantErr != nil {
+			assert.ErrorIs(t, err, tt.wantErr, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+
+	assert.Equal(t, version, g.Version(), "checking a placement does not change the game")
+	mustPlace(t, g, "Alice", m.Coordinate{X: 0, Y: 1}, 2, m.Horizontal)
 }
 
 // TestStartGame_Transitions verifies the state machine
@@ -112,6 +194,61 @@ func TestStartGame_Transitions(t *testing.T) {
 	)
 }
 
+// TestSetReady verifies the game only starts once both players confirm their setup
+func TestSetReady(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+
+	err := g.SetReady("P1")
+	assert.ErrorIs(t, err, m.ErrFleetNotPlaced, "SetReady should fail before the fleet is placed")
+
+	err = g.SetReady("Ghost")
+	assert.ErrorIs(t, err, m.ErrUnknownPlayer, "SetReady should fail for unknown players")
+
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+
+	require.NoError(t, g.SetReady("P1"))
+	view, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateSetup, view.State, "Game should wait for P2 to be ready")
+	assert.True(t, view.Me.Ready)
+	assert.False(t, view.Enemy.Ready)
+
+	require.NoError(t, g.SetReady("P2"))
+	view, err = g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StatePlaying, view.State, "Game should start once both are ready")
+	assert.Equal(t, "P1", view.Turn)
+
+	err = g.SetReady("P1")
+	assert.ErrorIs(t, err, m.ErrNotInSetup, "SetReady should fail once the game started")
+}
+
+func TestAbandon(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	require.NoError(t, g.StartGame())
+
+	require.NoError(t, g.Abandon())
+	assert.True(t, g.IsGameOver(), "Abandoned game should count as over")
+	assert.False(t, g.IsPlayersTurn("P1"), "Nobody should be on turn after abandonment")
+
+	view, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateAbandoned, view.State)
+	assert.Empty(t, view.Winner)
+
+	_, err = g.Attack("P1", m.Coordinate{X: 0, Y: 0})
+	assert.ErrorIs(t, err, m.ErrNotInPlay)
+
+	assert.ErrorIs(t, g.Abandon(), m.ErrAlreadyFinished)
+}
+
 // TestAttack_TurnLogic verifies turn enforcement and switching
 func TestAttack_TurnLogic(t *testing.T) {
 	t.Parallel()
@@ -142,22 +279,239 @@ func TestAttack_TurnLogic(t *testing.T) {
 func TestAttack_GameEnd(t *testing.T) {
 	t.Parallel()
 
-	g := m.NewFullGame("Winner", "Loser", map[int]int{1: 1})
-
-	mustPlace(t, g, "Winner", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
-	mustPlace(t, g, "Loser", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
-
-	_ = g.StartGame()
+	ship := m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 1}
+	g, err := m.NewGameBuilder().Fleet(map[int]int{1: 1}).Player("Winner", ship).Player("Loser", ship).Start().Build()
+	require.NoError(t, err)
 
 	res := mustAttack(t, g, "Winner", m.Coordinate{X: 0, Y: 0})
 	assert.Equal(t, m.ShotResultSunk, res, "Expected Sunk")
 
-	_, err := g.Attack("Loser", m.Coordinate{X: 0, Y: 0})
+	_, err = g.Attack("Loser", m.Coordinate{X: 0, Y: 0})
 	assert.ErrorIs(t, err, m.ErrNotInPlay, "Expected ErrNotInPlay (Game Over)")
 
 	assert.Equal(t, "Winner", g.Winner(), "Expected winner to be 'Winner'")
 }
 
+// TestGame_Teams verifies a 2v2 game: shared boards and fleets, alternating turns and a side-wide win
+func TestGame_Teams(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewGame()
+	assert.ErrorIs(t, g.SetTeamSize(3), m.ErrInvalidTeamSize)
+	require.NoError(t, g.SetTeamSize(2))
+
+	require.NoError(t, g.Join("A1", map[int]int{1: 2}))
+	require.NoError(t, g.Join("B1", map[int]int{1: 2}))
+	assert.ErrorIs(t, g.SetTeamSize(1), m.ErrSeatsFixed, "Teams are fixed once a second player joins")
+	require.NoError(t, g.Join("A2", nil))
+	assert.Equal(t, dto.StateWaiting, g.State(), "Game should wait until every seat is taken")
+	require.NoError(t, g.Join("B2", nil))
+	assert.Equal(t, dto.StateSetup, g.State())
+	assert.ErrorIs(t, g.Join("C1", nil), m.ErrGameFull)
+	assert.Equal(t, []string{"A2"}, g.Teammates("A1"))
+	assert.Equal(t, []string{"A1", "B1", "A2", "B2"}, g.Seats(), "Seats should be listed in join order")
+	assert.Equal(t, 2, g.Sides())
+
+	// Teammates place from one fleet onto one board
+	mustPlace(t, g, "A1", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	mustPlace(t, g, "A2", m.Coordinate{X: 5, Y: 5}, 1, m.Horizontal)
+	assert.ErrorIs(t, g.PlaceShip("A1", m.Coordinate{X: 9, Y: 0}, 1, m.Horizontal), m.ErrNoShipsRemaining)
+	mustPlace(t, g, "B1", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	mustPlace(t, g, "B2", m.Coordinate{X: 9, Y: 9}, 1, m.Horizontal)
+
+	require.NoError(t, g.SetReady("A2"))
+	assert.Equal(t, dto.StateSetup, g.State(), "Game should start only once every side is ready")
+	require.NoError(t, g.SetReady("B1"))
+	assert.Equal(t, dto.StatePlaying, g.State(), "One player readies their whole side")
+
+	// Turns alternate between the sides and, within a side, between teammates
+	assert.Equal(t, m.ShotResultSunk, mustAttack(t, g, "A1", m.Coordinate{X: 0, Y: 0}))
+	_, err := g.Attack("A2", m.Coordinate{X: 9, Y: 9})
+	assert.ErrorIs(t, err, m.ErrNotYourTurn)
+	assert.Equal(t, m.ShotResultMiss, mustAttack(t, g, "B1", m.Coordinate{X: 9, Y: 0}))
+
+	view, err := g.GetView("A2")
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellSunk, view.Enemy.Board.Grid[0][0], "Teammates should share what their shots found")
+	view, err = g.GetView("B2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, view.Me.ShipsAfloat, "Teammates should share a board")
+
+	assert.Equal(t, m.ShotResultSunk, mustAttack(t, g, "A2", m.Coordinate{X: 9, Y: 9}))
+	assert.True(t, g.IsGameOver(), "Side should lose once all its ships are sunk")
+	assert.Equal(t, "A2", g.Winner())
+}
+
+// TestGame_TeamsStart verifies a 2v2 game starts when one teammate places and readies the whole fleet
+func TestGame_TeamsStart(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewGame()
+	require.NoError(t, g.SetTeamSize(2))
+	for _, id := range []string{"A1", "B1", "A2", "B2"} {
+		require.NoError(t, g.Join(id, map[int]int{1: 2}))
+	}
+
+	// A2 and B2 never place anything: their teammates fill the shared boards
+	mustPlace(t, g, "A1", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	mustPlace(t, g, "A1", m.Coordinate{X: 5, Y: 5}, 1, m.Horizontal)
+	mustPlace(t, g, "B1", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	mustPlace(t, g, "B1", m.Coordinate{X: 9, Y: 9}, 1, m.Horizontal)
+
+	require.NoError(t, g.SetReady("A1"))
+	view, err := g.GetView("A2")
+	require.NoError(t, err)
+	assert.True(t, view.Me.Ready, "A teammate with nothing left to place is ready with their side")
+
+	require.NoError(t, g.SetReady("B1"))
+	assert.Equal(t, dto.StatePlaying, g.State())
+}
+
+// TestGame_FreeForAll verifies named targets, eliminations and the last fleet standing winning
+func TestGame_FreeForAll(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewGame()
+	assert.ErrorIs(t, g.SetFreeForAll(2), m.ErrInvalidPlayerCount)
+	require.NoError(t, g.SetFreeForAll(3))
+
+	require.NoError(t, g.Join("A", map[int]int{1: 1}))
+	require.NoError(t, g.Join("B", map[int]int{1: 1}))
+	assert.Equal(t, dto.StateWaiting, g.State(), "Game should wait until every seat is taken")
+	require.NoError(t, g.Join("C", map[int]int{1: 1}))
+	assert.ErrorIs(t, g.Join("D", nil), m.ErrGameFull)
+
+	for _, id := range []string{"A", "B", "C"} {
+		mustPlace(t, g, id, m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+		require.NoError(t, g.SetReady(id))
+	}
+	require.Equal(t, dto.StatePlaying, g.State())
+
+	_, err := g.Attack("A", m.Coordinate{X: 0, Y: 0})
+	assert.ErrorIs(t, err, m.ErrTargetRequired)
+	_, err = g.AttackTarget("A", "A", m.Coordinate{X: 0, Y: 0})
+	assert.ErrorIs(t, err, m.ErrInvalidTarget)
+
+	// B is eliminated, so their turn is skipped and nobody may fire at them
+	res, err := g.AttackTarget("A", "B", m.Coordinate{X: 0, Y: 0})
+	require.NoError(t, err)
+	assert.Equal(t, m.ShotResultSunk, res)
+	assert.False(t, g.IsGameOver(), "Game should go on while two players are afloat")
+	assert.Equal(t, []string{"A", "C"}, g.LivingPlayers())
+
+	view, err := g.GetView("C")
+	require.NoError(t, err)
+	assert.True(t, view.YourTurn)
+	assert.Equal(t, []string{"A", "C"}, view.LivingPlayers)
+	assert.Equal(t, []string{"A"}, view.TargetIDs)
+
+	_, err = g.AttackTarget("C", "B", m.Coordinate{X: 1, Y: 0})
+	assert.ErrorIs(t, err, m.ErrInvalidTarget)
+	_, err = g.AttackTarget("C", "A", m.Coordinate{X: 5, Y: 5})
+	require.NoError(t, err)
+
+	// A's shots at B did not reveal anything about C's board
+	view, err = g.GetView("A")
+	require.NoError(t, err)
+	assert.Equal(t, "C", view.Enemy.ID)
+	assert.Equal(t, dto.CellUnknown, view.Enemy.Board.Grid[0][0])
+
+	_, err = g.AttackTarget("A", "C", m.Coordinate{X: 0, Y: 0})
+	require.NoError(t, err)
+	assert.True(t, g.IsGameOver())
+	assert.Equal(t, "A", g.Winner())
+	assert.Equal(t, "B", g.Moves()[0].Target)
+}
+
+func TestGame_FreeForAllSetupView(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewGame()
+	require.NoError(t, g.SetFreeForAll(3))
+	require.NoError(t, g.Join("A", map[int]int{1: 1}))
+	require.NoError(t, g.Join("B", map[int]int{1: 1}))
+
+	view, err := g.GetView("A")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, view.LivingPlayers, "nobody is out before the shooting starts")
+	assert.Equal(t, []string{"B"}, view.TargetIDs)
+
+	require.NoError(t, g.Join("C", map[int]int{1: 1}))
+	mustPlace(t, g, "A", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+
+	view, err = g.GetView("C")
+	require.NoError(t, err)
+	require.Equal(t, dto.StateSetup, view.State)
+	assert.Equal(t, []string{"A", "B", "C"}, view.LivingPlayers, "players yet to place a ship are still in")
+	assert.Equal(t, []string{"A", "B"}, view.TargetIDs)
+}
+
+// TestGame_Empty verifies that calls made before anyone joins fail with an error instead of panicking
+func TestGame_Empty(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewGame()
+	c := m.Coordinate{X: 0, Y: 0}
+
+	_, err := g.GetView("P1")
+	assert.ErrorIs(t, err, m.ErrUnknownPlayer)
+	assert.ErrorIs(t, g.StartGame(), m.ErrNotInSetup)
+	assert.ErrorIs(t, g.SetReady("P1"), m.ErrNotInSetup)
+	assert.ErrorIs(t, g.PlaceShip("P1", c, 2, m.Horizontal), m.ErrNotInSetup)
+	assert.ErrorIs(t, g.CanPlaceShip("P1", c, 2, m.Horizontal), m.ErrNotInSetup)
+	_, err = g.Attack("P1", c)
+	assert.ErrorIs(t, err, m.ErrNotInPlay)
+	_, err = g.LegalMoves("P1", "")
+	assert.ErrorIs(t, err, m.ErrNotInPlay)
+	_, err = g.Reveal()
+	assert.ErrorIs(t, err, m.ErrNotFinished)
+	assert.ErrorIs(t, g.ReplacePlayer("P1", "P2"), m.ErrUnknownPlayer)
+
+	assert.False(t, g.IsPlayersTurn("P1"))
+	assert.Empty(t, g.LimitWinner())
+	assert.Empty(t, g.LivingPlayers())
+	assert.Empty(t, g.Teammates("P1"))
+
+	// A single player is not enough to start either
+	require.NoError(t, g.Join("P1", nil))
+	view, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.Empty(t, view.Enemy.ID)
+	assert.ErrorIs(t, g.StartGame(), m.ErrNotInSetup)
+	_, err = g.Attack("P1", c)
+	assert.ErrorIs(t, err, m.ErrNotInPlay)
+	assert.NoError(t, g.Abandon())
+}
+
+func TestPhaseErrors(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{1: 1})
+
+	_, err := g.Attack("P1", m.Coordinate{X: 0, Y: 0})
+	require.ErrorIs(t, err, m.ErrNotInPlay)
+	assert.EqualError(t, err, "game not in playing state: cannot attack: game is in SETUP, expected PLAYING")
+
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 1, m.Vertical)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 1, m.Vertical)
+	require.NoError(t, g.StartGame())
+
+	err = g.PlaceShip("P1", m.Coordinate{X: 5, Y: 5}, 1, m.Vertical)
+	require.ErrorIs(t, err, m.ErrNotInSetup)
+	assert.EqualError(t, err, "game not in setup state: cannot place ship: game is in PLAYING, expected SETUP")
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0})
+
+	err = g.Abandon()
+	require.ErrorIs(t, err, m.ErrAlreadyFinished)
+	assert.EqualError(t, err, "game already finished: cannot abandon: game is in FINISHED")
+
+	waiting := m.NewGame()
+	err = waiting.SetReady("P1")
+	require.ErrorIs(t, err, m.ErrNotInSetup)
+	assert.Contains(t, err.Error(), "game is in WAITING")
+}
+
 // TestAttack_InvalidInputs verifies defensive checks
 func TestAttack_InvalidInputs(t *testing.T) {
 	t.Parallel()
@@ -175,8 +529,77 @@ func TestAttack_InvalidInputs(t *testing.T) {
 	assert.ErrorIs(t, err, m.ErrUnknownPlayer, "Unknown player: want ErrUnknownPlayer")
 
 	res, err := g.Attack("P1", m.Coordinate{X: 99, Y: 99})
+	assert.ErrorIs(t, err, m.ErrShotOutOfBounds, "Out of bounds: want ErrShotOutOfBounds")
 	assert.ErrorIs(t, err, m.ErrInvalidShot, "Out of bounds: want ErrInvalidShot")
 	assert.Equal(t, m.ShotResultInvalid, res, "Out of bounds: want ShotResultInvalid")
+	assert.True(t, g.IsPlayersTurn("P1"), "Out of bounds: the turn should not pass")
+
+	g, err = m.NewGameBuilder().BoardSize(5).Fleet(map[int]int{2: 1}).
+		Player("P1", m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2}).
+		Player("P2", m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2}).
+		Start().
+		Shot("P1", m.Coordinate{X: 4, Y: 4}).
+		Shot("P2", m.Coordinate{X: 4, Y: 4}).
+		Build()
+	require.NoError(t, err)
+
+	res, err = g.Attack("P1", m.Coordinate{X: 4, Y: 4})
+	assert.ErrorIs(t, err, m.ErrAlreadyAttacked, "Repeat: want ErrAlreadyAttacked")
+	assert.Equal(t, m.ShotResultInvalid, res, "Repeat: want ShotResultInvalid")
+	assert.True(t, g.IsPlayersTurn("P1"), "Repeat: the turn should not pass")
+}
+
+// TestGame_CanAttack verifies a shot is checked as Attack would, without being fired
+func TestGame_CanAttack(t *testing.T) {
+	t.Parallel()
+
+	builder := func() *m.GameBuilder {
+		return m.NewGameBuilder().BoardSize(5).Fleet(map[int]int{2: 1}).
+			Player("P1", m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2}).
+			Player("P2", m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2})
+	}
+	setup, err := builder().Build()
+	require.NoError(t, err)
+	g, err := builder().Start().
+		Shot("P1", m.Coordinate{X: 4, Y: 4}).
+		Shot("P2", m.Coordinate{X: 4, Y: 4}).
+		Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		game     *m.Game
+		attacker string
+		target   string
+		c        m.Coordinate
+		wantErr  error
+	}{
+		{name: "Legal", game: g, attacker: "P1", c: m.Coordinate{X: 0, Y: 0}},
+		{name: "Named Target", game: g, attacker: "P1", target: "P2", c: m.Coordinate{X: 0, Y: 0}},
+		{name: "Not In Play", game: setup, attacker: "P1", wantErr: m.ErrNotInPlay},
+		{name: "Not Your Turn", game: g, attacker: "P2", c: m.Coordinate{X: 0, Y: 0}, wantErr: m.ErrNotYourTurn},
+		{name: "Unknown Player", game: g, attacker: "Ghost", wantErr: m.ErrUnknownPlayer},
+		{name: "Own Board", game: g, attacker: "P1", target: "P1", wantErr: m.ErrInvalidTarget},
+		{name: "Out Of Bounds", game: g, attacker: "P1", c: m.Coordinate{X: 5, Y: 0}, wantErr: m.ErrShotOutOfBounds},
+		{name: "Already Attacked", game: g, attacker: "P1", c: m.Coordinate{X: 4, Y: 4}, wantErr: m.ErrAlreadyAttacked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.game.CanAttack(tt.attacker, tt.target, tt.c)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	version := g.Version()
+	require.NoError(t, g.CanAttack("P1", "", m.Coordinate{X: 0, Y: 0}))
+	assert.Equal(t, version, g.Version(), "checking fires nothing")
+	assert.Len(t, g.Moves(), 2)
 }
 
 // Helper: Places a ship and fails test if error occurs
@@ -224,8 +647,435 @@ func TestGame_GetView(t *testing.T) {
 	assert.Equal(t, "SHIP", string(v1.Me.Board.Grid[0][0]), "P1 should see own ship at 0,0")
 	assert.Equal(t, "SUNK", string(v1.Enemy.Board.Grid[9][9]), "P1 should see hit on P2 at 9,9")
 	assert.Equal(t, "???", string(v1.Enemy.Board.Grid[0][0]), "P1 should see fog at P2's 0,0")
+	assert.False(t, v1.YourTurn, "Nobody should be on turn once the game is over")
 
 	// Spectator / Unknown user
 	_, err = g.GetView("Ghost")
 	assert.ErrorIs(t, err, m.ErrUnknownPlayer, "GetView(Ghost) should fail with ErrUnknownPlayer")
 }
+
+func TestGame_MoveLimitTiebreak(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+	require.ErrorIs(t, g.SetMoveLimit(-1), m.ErrInvalidMoveLimit)
+	require.NoError(t, g.SetMoveLimit(3))
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	require.NoError(t, g.StartGame())
+	require.ErrorIs(t, g.SetMoveLimit(10), m.ErrNotInSetup, "the limit is fixed once playing")
+	assert.Equal(t, 3, g.MovesLeft())
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0})
+	mustAttack(t, g, "P2", m.Coordinate{X: 5, Y: 5})
+	assert.False(t, g.IsGameOver())
+	assert.Equal(t, 1, g.MovesLeft())
+	assert.Equal(t, "P1", g.LimitWinner(), "P1 has more ship cells afloat")
+
+	view, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, view.MovesLeft)
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 9, Y: 9})
+	assert.True(t, g.IsGameOver())
+	assert.False(t, g.IsDraw())
+	assert.Equal(t, "P1", g.Winner(), "the last shot missed, but P1 kept more of its fleet")
+	assert.Equal(t, 0, g.MovesLeft())
+
+	_, err = g.Attack("P2", m.Coordinate{X: 6, Y: 6})
+	require.ErrorIs(t, err, m.ErrNotInPlay)
+
+	view, err = g.GetView("P2")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateFinished, view.State)
+	assert.Equal(t, "P1", view.Winner)
+	assert.Empty(t, view.Turn)
+	assert.Zero(t, view.MovesLeft)
+}
+
+func TestGame_MoveLimitDraw(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+	require.NoError(t, g.SetMoveLimit(2))
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	require.NoError(t, g.StartGame())
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0})
+	mustAttack(t, g, "P2", m.Coordinate{X: 1, Y: 0})
+	assert.True(t, g.IsDraw(), "both players have one ship cell afloat")
+	assert.True(t, g.IsGameOver())
+	assert.Empty(t, g.Winner())
+	assert.Empty(t, g.LimitWinner())
+
+	view, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateDraw, view.State)
+	assert.Empty(t, view.Turn)
+	assert.False(t, view.YourTurn)
+
+	_, err = g.Reveal()
+	require.NoError(t, err, "a drawn game is decided too")
+}
+
+func TestGame_MoveLimitWinOnLastShot(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{1: 1})
+	require.NoError(t, g.SetMoveLimit(1))
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	require.NoError(t, g.StartGame())
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0})
+	assert.False(t, g.IsDraw(), "sinking the fleet with the last shot still wins")
+	assert.Equal(t, "P1", g.Winner())
+}
+
+func TestGame_AssistHints(t *testing.T) {
+	t.Parallel()
+
+	newGame := func(assist bool) *m.Game {
+		g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+		require.NoError(t, g.SetAssist(assist))
+		mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+		mustPlace(t, g, "P2", m.Coordinate{X: 4, Y: 4}, 2, m.Horizontal)
+		require.NoError(t, g.StartGame())
+		return g
+	}
+
+	g := newGame(true)
+	require.ErrorIs(t, g.SetAssist(false), m.ErrNotInSetup, "the mode is fixed once playing")
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 3})
+	move, ok := g.LastMove()
+	require.True(t, ok)
+	assert.True(t, move.NearbyShip, "the miss is right above the ship")
+
+	mustAttack(t, g, "P2", m.Coordinate{X: 9, Y: 9})
+	view, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.True(t, view.NearbyShip, "the hint is about the observer's own last shot")
+	view, err = g.GetView("P2")
+	require.NoError(t, err)
+	assert.False(t, view.NearbyShip)
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 4})
+	move, _ = g.LastMove()
+	assert.False(t, move.NearbyShip, "hits carry no hint")
+
+	g = newGame(false)
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 3})
+	move, _ = g.LastMove()
+	assert.False(t, move.NearbyShip, "competitive games give no hints")
+}
+
+func TestGame_ExtraTurnOnHit(t *testing.T) {
+	t.Parallel()
+
+	newGame := func(extraTurn bool) *m.Game {
+		g := m.NewFullGame("P1", "P2", map[int]int{2: 2})
+		require.NoError(t, g.SetExtraTurnOnHit(extraTurn))
+		for _, id := range []string{"P1", "P2"} {
+			mustPlace(t, g, id, m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+			mustPlace(t, g, id, m.Coordinate{X: 0, Y: 1}, 2, m.Horizontal)
+		}
+		require.NoError(t, g.StartGame())
+		return g
+	}
+
+	g := newGame(true)
+	require.ErrorIs(t, g.SetExtraTurnOnHit(false), m.ErrNotInSetup, "the variant is fixed once playing")
+
+	assert.Equal(t, m.ShotResultHit, mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0}))
+	assert.True(t, g.IsPlayersTurn("P1"), "a hit keeps the turn")
+	assert.Equal(t, m.ShotResultSunk, mustAttack(t, g, "P1", m.Coordinate{X: 1, Y: 0}))
+	assert.True(t, g.IsPlayersTurn("P1"), "sinking a ship keeps the turn")
+	assert.Equal(t, m.ShotResultMiss, mustAttack(t, g, "P1", m.Coordinate{X: 9, Y: 9}))
+	assert.True(t, g.IsPlayersTurn("P2"), "a miss passes the turn")
+
+	mustAttack(t, g, "P2", m.Coordinate{X: 9, Y: 9})
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 1})
+	assert.Equal(t, m.ShotResultSunk, mustAttack(t, g, "P1", m.Coordinate{X: 1, Y: 1}))
+	assert.Equal(t, "P1", g.Winner(), "the last ship sunk still wins")
+	assert.False(t, g.IsPlayersTurn("P1"), "nobody fires once the game is over")
+
+	g = newGame(false)
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0})
+	assert.True(t, g.IsPlayersTurn("P2"), "in the standard game a hit passes the turn")
+}
+
+func TestGame_ReplacePlayer(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	require.NoError(t, g.StartGame())
+	mustAttack(t, g, "P1", m.Coordinate{X: 0, Y: 0})
+
+	require.ErrorIs(t, g.ReplacePlayer("Ghost", "P3"), m.ErrUnknownPlayer)
+	require.ErrorIs(t, g.ReplacePlayer("P2", "P1"), m.ErrAlreadySeated)
+	require.NoError(t, g.ReplacePlayer("P2", "P3"))
+
+	assert.True(t, g.IsPlayersTurn("P3"), "the turn moves with the seat")
+	_, err := g.GetView("P2")
+	require.ErrorIs(t, err, m.ErrUnknownPlayer)
+
+	view, err := g.GetView("P3")
+	require.NoError(t, err)
+	assert.Equal(t, "P3", view.Me.ID)
+	assert.Equal(t, "HIT", string(view.Me.Board.Grid[0][0]), "the fleet moves with the seat")
+
+	mustAttack(t, g, "P3", m.Coordinate{X: 0, Y: 0})
+	moves := g.Moves()
+	assert.Equal(t, "P1", moves[0].Attacker)
+	assert.Equal(t, "P3", moves[1].Attacker)
+}
+
+func TestGame_Reveal(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{1: 1, 2: 1})
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 1, m.Horizontal)
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 1}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 9, Y: 9}, 1, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 5, Y: 5}, 2, m.Vertical)
+	require.NoError(t, g.StartGame())
+
+	_, err := g.Reveal()
+	require.ErrorIs(t, err, m.ErrNotFinished, "the fleets are secret while playing")
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 9, Y: 9})
+	mustAttack(t, g, "P2", m.Coordinate{X: 0, Y: 0})
+	mustAttack(t, g, "P1", m.Coordinate{X: 5, Y: 5})
+	mustAttack(t, g, "P2", m.Coordinate{X: 3, Y: 3})
+	mustAttack(t, g, "P1", m.Coordinate{X: 5, Y: 6})
+	require.Equal(t, "P1", g.Winner())
+
+	players, err := g.Reveal()
+	require.NoError(t, err)
+	require.Len(t, players, 2)
+
+	winner, loser := players[0], players[1]
+	assert.Equal(t, "P1", winner.ID)
+	assert.Equal(t, "P2", loser.ID)
+	assert.Equal(t, dto.CellSunk, winner.Board.Grid[0][0])
+	assert.Equal(t, dto.CellShip, winner.Board.Grid[1][0], "unhit ships are shown")
+	assert.Equal(t, dto.CellMiss, winner.Board.Grid[3][3])
+	assert.Equal(t, dto.CellSunk, loser.Board.Grid[6][5])
+	assert.Equal(t, dto.CellEmpty, loser.Board.Grid[0][0])
+}
+
+// TestGame_PlacementCommitment verifies the fleets are committed to when play starts,
+// and that the fleets and salts revealed at the end hash to that commitment
+func TestGame_PlacementCommitment(t *testing.T) {
+	t.Parallel()
+
+	p1 := m.Placement{Coord: m.Coordinate{X: 0, Y: 0}, Size: 2}
+	p2 := m.Placement{Coord: m.Coordinate{X: 3, Y: 3}, Size: 2, Orientation: m.Vertical}
+	builder := func() *m.GameBuilder {
+		return m.NewGameBuilder().Fleet(map[int]int{2: 1}).Player("P1", p1).Player("P2", p2)
+	}
+
+	setup, err := builder().Build()
+	require.NoError(t, err)
+	assert.Empty(t, setup.PlacementCommitment(), "nothing is committed before play starts")
+
+	g, err := builder().Start().Build()
+	require.NoError(t, err)
+	committed := g.PlacementCommitment()
+	require.NotEmpty(t, committed)
+	view, err := g.GetView("P2")
+	require.NoError(t, err)
+	assert.Equal(t, committed, view.PlacementCommitment, "the players are shown the commitment")
+	assert.Empty(t, view.Me.Salt, "the salts stay secret while playing")
+
+	again, err := builder().Start().Build()
+	require.NoError(t, err)
+	assert.NotEqual(t, committed, again.PlacementCommitment(), "the salts keep equal fleets from hashing alike")
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 3, Y: 3})
+	mustAttack(t, g, "P2", m.Coordinate{X: 9, Y: 9})
+	mustAttack(t, g, "P1", m.Coordinate{X: 3, Y: 4})
+	assert.Equal(t, committed, g.PlacementCommitment(), "the commitment outlives the shots")
+
+	players, err := g.Reveal()
+	require.NoError(t, err)
+	for _, p := range players {
+		assert.NotEmpty(t, p.Salt, "%s's salt is revealed", p.ID)
+	}
+	assert.Equal(t, committed, commitment.Reveal(dto.RevealView{Players: players}))
+}
+
+// TestGame_GetView_Tracking verifies that the enemy board is what the observer learned from their shots
+func TestGame_GetView_Tracking(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1, 3: 1})
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 5}, 3, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 4, Y: 4}, 2, m.Vertical)
+	mustPlace(t, g, "P2", m.Coordinate{X: 7, Y: 0}, 3, m.Vertical)
+	require.NoError(t, g.StartGame())
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 4}) // Hit
+	mustAttack(t, g, "P2", m.Coordinate{X: 9, Y: 9}) // Miss
+	mustAttack(t, g, "P1", m.Coordinate{X: 3, Y: 4}) // Miss
+	mustAttack(t, g, "P2", m.Coordinate{X: 0, Y: 0}) // Hit
+
+	v1, err := g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellHit, v1.Enemy.Board.Grid[4][4])
+	assert.Equal(t, dto.CellMiss, v1.Enemy.Board.Grid[4][3])
+	assert.Equal(t, dto.CellUnknown, v1.Enemy.Board.Grid[5][4], "unhit ship cells stay unknown")
+	assert.Equal(t, dto.CellUnknown, v1.Enemy.Board.Grid[9][9], "the opponent's shots are not on my tracking grid")
+
+	v2, err := g.GetView("P2")
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellMiss, v2.Enemy.Board.Grid[9][9])
+	assert.Equal(t, dto.CellHit, v2.Enemy.Board.Grid[0][0])
+	assert.Equal(t, dto.CellUnknown, v2.Enemy.Board.Grid[4][4])
+
+	// Sinking a ship reveals all of it as sunk
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 5})
+	v1, err = g.GetView("P1")
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellSunk, v1.Enemy.Board.Grid[4][4])
+	assert.Equal(t, dto.CellSunk, v1.Enemy.Board.Grid[5][4])
+	assert.Equal(t, dto.CellUnknown, v1.Enemy.Board.Grid[0][7], "other ships stay hidden")
+	assert.Equal(t, v1.Me.Board.Size, len(v1.Enemy.Board.Grid))
+	assert.Equal(t, 1, v1.Enemy.ShipsAfloat, "the sunk ship no longer counts")
+	assert.Equal(t, 2, v1.Me.ShipsAfloat, "hit but not sunk still counts")
+}
+
+func TestGame_LegalMoves(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1, 3: 1})
+
+	_, err := g.LegalMoves("P1", "")
+	require.ErrorIs(t, err, m.ErrNotInPlay)
+
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 5}, 3, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 4, Y: 4}, 2, m.Vertical)
+	mustPlace(t, g, "P2", m.Coordinate{X: 7, Y: 0}, 3, m.Vertical)
+	require.NoError(t, g.StartGame())
+
+	moves, err := g.LegalMoves("P1", "")
+	require.NoError(t, err)
+	assert.Len(t, moves.Targets, m.GridSize*m.GridSize)
+	assert.Equal(t, dto.Target{X: 0, Y: 0, Coord: "A1"}, moves.Targets[0])
+
+	_, err = g.LegalMoves("P2", "")
+	require.ErrorIs(t, err, m.ErrNotYourTurn)
+	_, err = g.LegalMoves("P3", "")
+	require.ErrorIs(t, err, m.ErrUnknownPlayer)
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 4}) // Hit
+	mustAttack(t, g, "P2", m.Coordinate{X: 9, Y: 9}) // Miss
+	mustAttack(t, g, "P1", m.Coordinate{X: 3, Y: 4}) // Miss
+	mustAttack(t, g, "P2", m.Coordinate{X: 0, Y: 0}) // Hit
+
+	moves, err = g.LegalMoves("P1", "")
+	require.NoError(t, err)
+	assert.Len(t, moves.Targets, m.GridSize*m.GridSize-2)
+	assert.NotContains(t, moves.Targets, dto.Target{X: 4, Y: 4, Coord: "E5"})
+	assert.NotContains(t, moves.Targets, dto.Target{X: 3, Y: 4, Coord: "D5"})
+	assert.Contains(t, moves.Targets, dto.Target{X: 9, Y: 9, Coord: "J10"}, "the opponent's shots do not count")
+	assert.Equal(t, dto.CellHit, moves.Board.Grid[4][4])
+	assert.Equal(t, dto.CellMiss, moves.Board.Grid[4][3])
+}
+
+func TestGame_LegalMovesFreeForAll(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewGame()
+	require.NoError(t, g.SetFreeForAll(3))
+	for _, id := range []string{"A", "B", "C"} {
+		require.NoError(t, g.Join(id, map[int]int{1: 1}))
+	}
+	for _, id := range []string{"A", "B", "C"} {
+		mustPlace(t, g, id, m.Coordinate{X: 9, Y: 9}, 1, m.Horizontal)
+		require.NoError(t, g.SetReady(id))
+	}
+
+	_, err := g.LegalMoves("A", "")
+	require.ErrorIs(t, err, m.ErrTargetRequired, "the board must be named, as for a shot")
+	_, err = g.LegalMoves("A", "A")
+	require.ErrorIs(t, err, m.ErrInvalidTarget)
+
+	mustAttackTarget := func(attacker, target string, c m.Coordinate) {
+		t.Helper()
+		_, err := g.AttackTarget(attacker, target, c)
+		require.NoError(t, err)
+	}
+	mustAttackTarget("A", "B", m.Coordinate{X: 0, Y: 0})
+	mustAttackTarget("B", "C", m.Coordinate{X: 0, Y: 0})
+	mustAttackTarget("C", "A", m.Coordinate{X: 0, Y: 0})
+
+	moves, err := g.LegalMoves("A", "B")
+	require.NoError(t, err)
+	assert.Len(t, moves.Targets, m.GridSize*m.GridSize-1)
+	assert.NotContains(t, moves.Targets, dto.Target{X: 0, Y: 0, Coord: "A1"})
+	assert.Equal(t, dto.CellMiss, moves.Board.Grid[0][0])
+
+	moves, err = g.LegalMoves("A", "C")
+	require.NoError(t, err)
+	assert.Len(t, moves.Targets, m.GridSize*m.GridSize, "shots at B tell nothing about C's board")
+	assert.Equal(t, dto.CellUnknown, moves.Board.Grid[0][0])
+}
+
+func TestGame_Moves(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	mustPlace(t, g, "P2", m.Coordinate{X: 4, Y: 4}, 2, m.Vertical)
+	require.NoError(t, g.StartGame())
+	assert.Empty(t, g.Moves())
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 4})
+	_, err := g.Attack("P2", m.Coordinate{X: 10, Y: 0})
+	require.Error(t, err)
+	mustAttack(t, g, "P2", m.Coordinate{X: 9, Y: 9})
+	mustAttack(t, g, "P1", m.Coordinate{X: 4, Y: 5})
+
+	assert.Equal(t, []m.Move{
+		{Attacker: "P1", Coord: m.Coordinate{X: 4, Y: 4}, Result: m.ShotResultHit},
+		{Attacker: "P2", Coord: m.Coordinate{X: 9, Y: 9}, Result: m.ShotResultMiss},
+		{Attacker: "P1", Coord: m.Coordinate{X: 4, Y: 5}, Result: m.ShotResultSunk},
+	}, g.Moves(), "rejected shots are not moves")
+}
+
+func TestGame_Version(t *testing.T) {
+	t.Parallel()
+
+	g := m.NewFullGame("P1", "P2", map[int]int{2: 1})
+	v := g.Version()
+
+	mustPlace(t, g, "P1", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	assert.Greater(t, g.Version(), v, "Placing a ship should bump the version")
+	v = g.Version()
+
+	err := g.PlaceShip("P1", m.Coordinate{X: 0, Y: 5}, 2, m.Horizontal)
+	require.Error(t, err)
+	assert.Equal(t, v, g.Version(), "A rejected action should not bump the version")
+
+	mustPlace(t, g, "P2", m.Coordinate{X: 0, Y: 0}, 2, m.Horizontal)
+	require.NoError(t, g.StartGame())
+	v = g.Version()
+
+	_, err = g.Attack("P2", m.Coordinate{X: 0, Y: 0})
+	require.ErrorIs(t, err, m.ErrNotYourTurn)
+	assert.Equal(t, v, g.Version())
+
+	mustAttack(t, g, "P1", m.Coordinate{X: 5, Y: 5})
+	assert.Greater(t, g.Version(), v, "An attack should bump the version")
+
+	view, err := g.GetView("P2")
+	require.NoError(t, err)
+	assert.Equal(t, g.Version(), view.Version)
+}
@@ -54,11 +54,13 @@ func _() {
 	_ = x[StateSetup-1]
 	_ = x[StatePlaying-2]
 	_ = x[StateGameOver-3]
+	_ = x[StateAbandoned-4]
+	_ = x[StateDraw-5]
 }
 
-const _GameState_name = "StateWaitingStateSetupStatePlayingStateGameOver"
+const _GameState_name = "StateWaitingStateSetupStatePlayingStateGameOverStateAbandonedStateDraw"
 
-var _GameState_index = [...]uint8{0, 12, 22, 34, 47}
+var _GameState_index = [...]uint8{0, 12, 22, 34, 47, 61, 70}
 
 func (i GameState) String() string {
 	idx := int(i) - 0
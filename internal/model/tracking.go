@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/rules"
+)
+
+// trackingGrid is what a player has learned about the enemy board from their own shots.
+// Unlike a fogged snapshot of the enemy board, it only changes when the player fires.
+type trackingGrid struct {
+	size  int
+	cells [MaxGridSize][MaxGridSize]dto.CellState
+}
+
+var _ rules.Board = (*trackingGrid)(nil)
+
+func newTrackingGrid(size int) *trackingGrid {
+	t := &trackingGrid{size: size}
+	for y := range size {
+		for x := range size {
+			t.cells[y][x] = dto.CellUnknown
+		}
+	}
+	return t
+}
+
+// markShotResult records the outcome of a shot at c.
+// When the shot sinks a ship, sunk holds every cell of that ship, which the player now knows.
+func (t *trackingGrid) markShotResult(c Coordinate, res ShotResult, sunk []Coordinate) {
+	switch res {
+	case ShotResultMiss:
+		t.cells[c.Y][c.X] = dto.CellMiss
+	case ShotResultHit:
+		t.cells[c.Y][c.X] = dto.CellHit
+	case ShotResultSunk:
+		for _, s := range sunk {
+			t.cells[s.Y][s.X] = dto.CellSunk
+		}
+	}
+}
+
+// view returns the grid as the player sees the enemy board.
+func (t *trackingGrid) view() dto.BoardView {
+	grid := make([][]dto.CellState, t.size)
+	for y := range grid {
+		grid[y] = make([]dto.CellState, t.size)
+		copy(grid[y], t.cells[y][:t.size])
+	}
+	return dto.BoardView{Grid: grid, Size: t.size}
+}
+
+// Size returns the side length of the enemy board.
+func (t *trackingGrid) Size() int { return t.size }
+
+// Cell returns what the player knows of the enemy cell at x,y.
+func (t *trackingGrid) Cell(x, y int) dto.CellState { return t.cells[y][x] }
@@ -0,0 +1,175 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/client"
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+const (
+	// reconnectDelay is the wait before following a match again after the connection dropped.
+	reconnectDelay = 2 * time.Second
+	// maxConnectFailures is how many connection attempts in a row may fail before a match is given up on.
+	maxConnectFailures = 5
+)
+
+// followKey identifies one player's view of a match.
+type followKey struct {
+	matchID  string
+	playerID string
+}
+
+// following is a running relay, cancelled to stop it.
+type following struct {
+	cancel context.CancelFunc
+}
+
+// follow starts relaying the player's events in the match to the notifier, unless it already is.
+// since is the game version the player has already been told about, or -1 to only relay what happens next.
+//
+// The server's WebSocket only says that the game changed, so on every update the events
+// since the last one are fetched, and those meant for the player are published.
+func (b *Backend) follow(matchID, playerID string, since int) {
+	key := followKey{matchID: matchID, playerID: playerID}
+
+	b.mu.Lock()
+	if _, ok := b.follows[key]; ok {
+		b.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &following{cancel: cancel}
+	b.follows[key] = f
+	b.mu.Unlock()
+
+	go func() {
+		b.relay(ctx, key, since)
+
+		b.mu.Lock()
+		if b.follows[key] == f {
+			delete(b.follows, key)
+		}
+		b.mu.Unlock()
+		cancel()
+	}()
+}
+
+// unfollow stops relaying the player's events in the match.
+func (b *Backend) unfollow(matchID, playerID string) {
+	key := followKey{matchID: matchID, playerID: playerID}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if f, ok := b.follows[key]; ok {
+		f.cancel()
+		delete(b.follows, key)
+	}
+}
+
+// followView follows the match unless the view shows it is already over.
+func (b *Backend) followView(matchID, playerID string, view *dto.GameView) {
+	if !isOver(view) {
+		b.follow(matchID, playerID, -1)
+	}
+}
+
+// relay follows the match until it is over, reconnecting when the connection drops.
+func (b *Backend) relay(ctx context.Context, key followKey, since int) {
+	for failures := 0; failures < maxConnectFailures; {
+		connected, finished := b.relayOnce(ctx, key, &since)
+		if finished || ctx.Err() != nil {
+			return
+		}
+
+		if connected {
+			failures = 0
+		} else {
+			failures++
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+
+	b.logger.Warn("Stopped following match: the server cannot be reached",
+		"match_id", key.matchID, "player_id", key.playerID)
+}
+
+// relayOnce follows the match over a single WebSocket connection.
+// It reports whether it managed to connect and whether there is nothing left to follow.
+func (b *Backend) relayOnce(ctx context.Context, key followKey, since *int) (connected, finished bool) {
+	c, err := b.clientFor(key.playerID)
+	if err != nil {
+		return false, true
+	}
+
+	updates, err := c.SubscribeToMatchContext(ctx, key.matchID)
+	if err != nil {
+		return false, false
+	}
+
+	for evt := range updates {
+		switch evt.Type {
+		case "game_update":
+			if evt.Payload != nil && b.catchUp(c, key, evt.Payload, since) {
+				return true, true
+			}
+		case "error":
+			// The server could not read the match any more: check whether it is gone
+			if _, err := c.GetGameState(key.matchID); errors.Is(translate(err), controller.ErrMatchNotFound) {
+				b.publishCancelled(key)
+				return true, true
+			}
+		}
+	}
+
+	return true, false
+}
+
+// catchUp publishes the player's events up to the view's version and reports whether the game is over.
+func (b *Backend) catchUp(c *client.Client, key followKey, view *dto.GameView, since *int) bool {
+	if *since < 0 {
+		*since = view.Version // Only what happens from now on is news
+	}
+
+	if view.Version > *since {
+		events, err := c.Events(key.matchID, *since)
+		if err != nil {
+			b.logger.Error("Failed to fetch match events", "match_id", key.matchID, "error", err)
+			return false // Retried on the next update
+		}
+
+		for i := range events {
+			*since = max(*since, events[i].Version)
+			if events[i].TargetID == key.playerID {
+				b.notifier.Publish(&events[i])
+			}
+		}
+		*since = max(*since, view.Version)
+	}
+
+	return isOver(view)
+}
+
+// publishCancelled tells the player that the match disappeared from the server.
+// Its history went with it, so the server's own event cannot be fetched.
+func (b *Backend) publishCancelled(key followKey) {
+	b.notifier.Publish(&dto.GameEvent{
+		Type:      dto.EventMatchCancelled,
+		MatchID:   key.matchID,
+		TargetID:  key.playerID,
+		Timestamp: time.Now(),
+	})
+}
+
+func isOver(view *dto.GameView) bool {
+	return view.State == dto.StateFinished || view.State == dto.StateDraw || view.State == dto.StateAbandoned
+}
@@ -0,0 +1,236 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/callegarimattia/battleship/internal/client"
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+// PlaceShip places a ship of the given size on the player's board.
+func (b *Backend) PlaceShip(
+	_ context.Context,
+	matchID, playerID string,
+	size int,
+	x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	view, err := c.PlaceShip(matchID, size, x, y, vertical)
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.followView(matchID, playerID, view)
+	return *view, nil
+}
+
+// PlaceShipType places a ship of the given type on the player's board.
+func (b *Backend) PlaceShipType(
+	_ context.Context,
+	matchID, playerID string,
+	shipType string,
+	x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	view, err := c.PlaceShipType(matchID, shipType, x, y, vertical)
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.followView(matchID, playerID, view)
+	return *view, nil
+}
+
+// ValidatePlacement asks the server whether the placement would be accepted.
+func (b *Backend) ValidatePlacement(
+	_ context.Context,
+	matchID, playerID string,
+	size, x, y int,
+	vertical bool,
+) (dto.PlacementCheck, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.PlacementCheck{}, err
+	}
+
+	check, err := c.ValidatePlacement(matchID, size, x, y, vertical)
+	if err != nil {
+		return dto.PlacementCheck{}, translate(err)
+	}
+	return *check, nil
+}
+
+// ValidateAttack asks the server whether the shot would be fired.
+func (b *Backend) ValidateAttack(
+	_ context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (dto.AttackCheck, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.AttackCheck{}, err
+	}
+
+	check, err := c.ValidateAttack(matchID, targetID, x, y)
+	if err != nil {
+		return dto.AttackCheck{}, translate(err)
+	}
+	return *check, nil
+}
+
+// LegalMoves asks the server where the player may fire.
+func (b *Backend) LegalMoves(_ context.Context, matchID, playerID, targetID string) (dto.LegalMoves, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.LegalMoves{}, err
+	}
+
+	moves, err := c.LegalMoves(matchID, targetID)
+	if err != nil {
+		return dto.LegalMoves{}, translate(err)
+	}
+	return *moves, nil
+}
+
+// Ready confirms the player's fleet.
+func (b *Backend) Ready(_ context.Context, matchID, playerID string) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	view, err := c.Ready(matchID)
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.followView(matchID, playerID, view)
+	return *view, nil
+}
+
+// Attack fires at the opponent's board.
+// A version set with controller.WithExpectedVersion is passed on to the server.
+func (b *Backend) Attack(ctx context.Context, matchID, playerID string, x, y int) (dto.GameView, error) {
+	return b.AttackTarget(ctx, matchID, playerID, "", x, y)
+}
+
+// AttackTarget fires at the named player's board, or at the opponent's without a target.
+// A version set with controller.WithExpectedVersion is passed on to the server.
+func (b *Backend) AttackTarget(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	var view *dto.GameView
+	version, ok := controller.ExpectedVersion(ctx)
+	switch {
+	case targetID != "" && ok:
+		view, err = c.AttackTarget(matchID, targetID, x, y, &version)
+	case targetID != "":
+		view, err = c.AttackTarget(matchID, targetID, x, y, nil)
+	case ok:
+		view, err = c.AttackAtVersion(matchID, x, y, version)
+	default:
+		view, err = c.Attack(matchID, x, y)
+	}
+	var apiErr *client.APIError
+	if ok && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return dto.GameView{}, fmt.Errorf("%w: %s", controller.ErrVersionConflict, apiErr.Message)
+	}
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.followView(matchID, playerID, view)
+	return *view, nil
+}
+
+// IsPlayersTurn reports whether the server expects the player to attack next.
+func (b *Backend) IsPlayersTurn(ctx context.Context, matchID, playerID string) (bool, error) {
+	view, err := b.GetState(ctx, matchID, playerID)
+	if err != nil {
+		return false, err
+	}
+	return view.YourTurn, nil
+}
+
+// GetState fetches the player's view of the match.
+func (b *Backend) GetState(_ context.Context, matchID, playerID string) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	view, err := c.GetGameState(matchID)
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.followView(matchID, playerID, view)
+	return *view, nil
+}
+
+// EventsSince fetches the events the player can see after the given game version.
+func (b *Backend) EventsSince(_ context.Context, matchID, playerID string, since int) ([]dto.GameEvent, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := c.Events(matchID, since)
+	if err != nil {
+		return nil, translate(err)
+	}
+	return events, nil
+}
+
+// MatchRecord downloads the move history of the match.
+func (b *Backend) MatchRecord(_ context.Context, matchID, playerID string) (dto.MatchRecord, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.MatchRecord{}, err
+	}
+
+	record, err := c.MatchRecord(matchID)
+	if err != nil {
+		return dto.MatchRecord{}, translate(err)
+	}
+	return *record, nil
+}
+
+// Reveal fetches both fleets of a won match. It needs no player, since anyone may see them.
+func (b *Backend) Reveal(_ context.Context, matchID string) (dto.RevealView, error) {
+	reveal, err := client.New(b.baseURL).Reveal(matchID)
+	if err != nil {
+		return dto.RevealView{}, translate(err)
+	}
+	return *reveal, nil
+}
+
+// Rules fetches how a match is played. It needs no player, since anyone may see them.
+func (b *Backend) Rules(_ context.Context, matchID string) (dto.Rules, error) {
+	rules, err := client.New(b.baseURL).GetRules(matchID)
+	if err != nil {
+		return dto.Rules{}, translate(err)
+	}
+	return *rules, nil
+}
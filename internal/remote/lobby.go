@@ -0,0 +1,122 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/callegarimattia/battleship/internal/client"
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+// CreateMatch hosts a match on the server and starts relaying its events to the host.
+func (b *Backend) CreateMatch(_ context.Context, hostID string, settings dto.MatchSettings) (string, error) {
+	c, err := b.clientFor(hostID)
+	if err != nil {
+		return "", err
+	}
+
+	matchID, err := c.CreateMatch(settings)
+	if err != nil {
+		return "", translate(err)
+	}
+
+	b.follow(matchID, hostID, 0) // Everything that happens in a new match is news
+	return matchID, nil
+}
+
+// ListMatches lists the server's matches. The lobby is public, so no player is needed.
+func (b *Backend) ListMatches(_ context.Context, filter dto.MatchFilter) ([]dto.MatchSummary, error) {
+	matches, err := client.New(b.baseURL).ListMatches(filter)
+	if err != nil {
+		return nil, translate(err)
+	}
+	return matches, nil
+}
+
+// Scoreboard fetches the server's live overview of the matches in progress. It is public too.
+func (b *Backend) Scoreboard(_ context.Context) ([]dto.ScoreboardEntry, error) {
+	entries, err := client.New(b.baseURL).Scoreboard()
+	if err != nil {
+		return nil, translate(err)
+	}
+	return entries, nil
+}
+
+// PracticeBoard fetches the server's sample placed board.
+func (b *Backend) PracticeBoard(_ context.Context, settings dto.MatchSettings) (dto.BoardView, error) {
+	board, err := client.New(b.baseURL).PracticeBoard(settings)
+	if err != nil {
+		return dto.BoardView{}, translate(err)
+	}
+	return *board, nil
+}
+
+// JoinMatch joins the match on the server and starts relaying its events to the player.
+func (b *Backend) JoinMatch(_ context.Context, matchID, playerID string) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	view, err := c.JoinMatch(matchID)
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.follow(matchID, playerID, view.Version)
+	return *view, nil
+}
+
+// ResumeMatch takes back a seat on the server and starts relaying the match's events to the player.
+func (b *Backend) ResumeMatch(_ context.Context, matchID, playerID, token string) (dto.GameView, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	view, err := c.ResumeMatch(matchID, token)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+		return dto.GameView{}, controller.ErrInvalidResumeToken
+	}
+	if err != nil {
+		return dto.GameView{}, translate(err)
+	}
+
+	b.follow(matchID, playerID, view.Version)
+	return *view, nil
+}
+
+// DeleteMatch cancels the match on the server. The host is not told about their own cancellation.
+func (b *Backend) DeleteMatch(_ context.Context, matchID, playerID string) error {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return err
+	}
+
+	b.unfollow(matchID, playerID)
+	if err := c.CancelMatch(matchID); err != nil {
+		b.follow(matchID, playerID, -1)
+		return translate(err)
+	}
+	return nil
+}
+
+// ActiveMatch asks the server for the player's unfinished match, e.g. after the bot restarted,
+// and resumes relaying its events.
+func (b *Backend) ActiveMatch(_ context.Context, playerID string) (string, error) {
+	c, err := b.clientFor(playerID)
+	if err != nil {
+		return "", err
+	}
+
+	matchID, err := c.ActiveMatch()
+	if err != nil {
+		return "", translate(err)
+	}
+
+	b.follow(matchID, playerID, -1)
+	return matchID, nil
+}
@@ -0,0 +1,148 @@
+// Package remote implements the controller services on top of a Battleship server's HTTP API,
+// so that an integration such as the Discord bot plays on the same server as web and TUI players.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/callegarimattia/battleship/internal/client"
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+var (
+	_ controller.IdentityService = (*Backend)(nil)
+	_ controller.LobbyService    = (*Backend)(nil)
+	_ controller.GameService     = (*Backend)(nil)
+)
+
+// player is a user this backend has logged in, with the client carrying their token.
+type player struct {
+	user   dto.User
+	client *client.Client
+}
+
+// Backend talks to a Battleship server on behalf of many players at once.
+// It logs them in with the server's API key and sends each player's requests with their own token.
+// The events of the matches they play are relayed to the notifier given to New.
+type Backend struct {
+	baseURL  string
+	apiKey   string
+	notifier controller.NotificationService
+	logger   *slog.Logger
+
+	mu      sync.RWMutex
+	players map[string]*player       // Map[UserID]player
+	follows map[followKey]*following // Matches whose events are being relayed
+}
+
+// Option configures optional Backend behavior.
+type Option func(*Backend)
+
+// WithLogger sets the logger the backend reports relay failures to.
+// The default logger is used otherwise.
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *Backend) { b.logger = logger }
+}
+
+// New creates a backend for the server at baseURL, authenticating platform logins with apiKey.
+// The events the server reports for the backend's players are published to notifier.
+func New(baseURL, apiKey string, notifier controller.NotificationService, opts ...Option) *Backend {
+	b := &Backend{
+		baseURL:  baseURL,
+		apiKey:   apiKey,
+		notifier: notifier,
+		logger:   slog.Default(),
+		players:  make(map[string]*player),
+		follows:  make(map[followKey]*following),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Close stops relaying events.
+func (b *Backend) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, f := range b.follows {
+		f.cancel()
+		delete(b.follows, key)
+	}
+}
+
+// LoginOrRegister logs the platform user in on the server, which owns the identities and signs the token.
+func (b *Backend) LoginOrRegister(
+	_ context.Context,
+	username, source, extID string,
+) (dto.AuthResponse, error) {
+	c := client.New(b.baseURL)
+	c.APIKey = b.apiKey
+
+	res, err := c.PlatformLogin(username, source, extID)
+	if err != nil {
+		return dto.AuthResponse{}, fmt.Errorf("server login failed: %w", err)
+	}
+
+	// Later requests are made as the player, not as the integration
+	c.APIKey = ""
+
+	b.mu.Lock()
+	b.players[res.User.ID] = &player{user: res.User, client: c}
+	b.mu.Unlock()
+
+	return *res, nil
+}
+
+// GetUser returns a user this backend has logged in.
+func (b *Backend) GetUser(_ context.Context, userID string) (dto.User, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	p, ok := b.players[userID]
+	if !ok {
+		return dto.User{}, controller.ErrUserNotFound
+	}
+	return p.user, nil
+}
+
+// clientFor returns the client acting as the player, who must have logged in through this backend.
+func (b *Backend) clientFor(playerID string) (*client.Client, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	p, ok := b.players[playerID]
+	if !ok {
+		return nil, controller.ErrUserNotFound
+	}
+	return p.client, nil
+}
+
+// translate maps the server's error responses back to the controller errors they stand for.
+func translate(err error) error {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	// The server answers these with the error's own message, so nothing is lost
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		return controller.ErrMatchNotFound
+	case http.StatusForbidden:
+		return controller.ErrNotHost
+	case http.StatusServiceUnavailable:
+		return fmt.Errorf("%w: %s", controller.ErrServerAtCapacity, apiErr.Message)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,196 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/server"
+	"github.com/callegarimattia/battleship/internal/service"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer runs the server's routes on an in-memory game service.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	notifier := service.NewNotificationService()
+	memory := service.NewMemoryService(notifier)
+	t.Cleanup(memory.Close)
+	ctrl := controller.NewAppController(service.NewIdentityService("secret"), memory, memory, notifier)
+	h := server.NewEchoHandler(ctrl)
+
+	e := echo.New()
+	requireJWT := echojwt.WithConfig(echojwt.Config{ParseTokenFunc: server.ParseToken([]byte("secret"), "", "")})
+
+	e.POST("/login/platform", h.PlatformLogin, server.RequireAPIKey("key"))
+	e.GET("/me/match", h.ActiveMatch, requireJWT, server.RequirePlayerID)
+
+	g := e.Group("/matches", server.ValidMatchID)
+	g.GET("", h.ListMatches)
+	g.GET("/:id/reveal", h.Reveal)
+	protected := g.Group("", requireJWT, server.RequirePlayerID)
+	protected.POST("", h.HostMatch)
+	protected.POST("/:id/join", h.JoinMatch)
+	protected.GET("/:id", h.GetState)
+	protected.GET("/:id/events", h.Events)
+	protected.GET("/:id/export", h.Export)
+	protected.DELETE("/:id", h.CancelMatch)
+	protected.POST("/:id/place", h.PlaceShip)
+	protected.POST("/:id/validate-placement", h.ValidatePlacement)
+	protected.POST("/:id/ready", h.Ready)
+	protected.POST("/:id/attack", h.Attack)
+	protected.POST("/:id/validate-attack", h.ValidateAttack)
+	protected.GET("/:id/legal-moves", h.LegalMoves)
+	g.GET("/:id/ws", h.StreamMatchEvents, server.WebSocketToken, requireJWT, server.RequirePlayerID)
+
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBackend_LoginOrRegister(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t)
+	ctx := context.Background()
+
+	t.Run("Logs in through the server", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(srv.URL, "key", service.NewNotificationService())
+		res, err := b.LoginOrRegister(ctx, "Alice", "discord", "42")
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+
+		user, err := b.GetUser(ctx, res.User.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", user.Username)
+	})
+
+	t.Run("Wrong API key", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(srv.URL, "guess", service.NewNotificationService())
+		_, err := b.LoginOrRegister(ctx, "Bob", "discord", "43")
+		require.Error(t, err)
+	})
+
+	t.Run("Unknown player", func(t *testing.T) {
+		t.Parallel()
+
+		b := New(srv.URL, "key", service.NewNotificationService())
+		_, err := b.CreateMatch(ctx, "stranger", dto.MatchSettings{})
+		assert.ErrorIs(t, err, controller.ErrUserNotFound)
+	})
+}
+
+func TestBackend_PlaysOnTheServer(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t)
+	ctx := context.Background()
+
+	notifier := service.NewNotificationService()
+	_, events := notifier.Subscribe("*")
+
+	b := New(srv.URL, "key", notifier)
+	t.Cleanup(b.Close)
+
+	alice, err := b.LoginOrRegister(ctx, "Alice", "discord", "1")
+	require.NoError(t, err)
+	bob, err := b.LoginOrRegister(ctx, "Bob", "discord", "2")
+	require.NoError(t, err)
+
+	matchID, err := b.CreateMatch(ctx, alice.User.ID, dto.MatchSettings{FleetPreset: "small"})
+	require.NoError(t, err)
+
+	active, err := b.ActiveMatch(ctx, alice.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, matchID, active)
+
+	matches, err := b.ListMatches(ctx, dto.MatchFilter{})
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	view, err := b.JoinMatch(ctx, matchID, bob.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateSetup, view.State)
+
+	next := func() *dto.GameEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no event relayed")
+			return nil
+		}
+	}
+
+	joined := next()
+	assert.Equal(t, dto.EventPlayerJoined, joined.Type)
+	assert.Equal(t, alice.User.ID, joined.TargetID)
+
+	check, err := b.ValidatePlacement(ctx, matchID, bob.User.ID, 3, 9, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PlacementOutOfBounds, check.Code)
+
+	_, err = b.LegalMoves(ctx, matchID, bob.User.ID, "")
+	require.Error(t, err, "there is nothing to shoot at during setup")
+	assert.Contains(t, err.Error(), "not in playing state")
+
+	shot, err := b.ValidateAttack(ctx, matchID, bob.User.ID, "", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, dto.AttackNotInPlay, shot.Code)
+
+	record, err := b.MatchRecord(ctx, matchID, bob.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, matchID, record.MatchID)
+	assert.Empty(t, record.Moves)
+
+	_, err = b.Reveal(ctx, matchID)
+	require.Error(t, err, "the fleets are secret until someone wins")
+	assert.Contains(t, err.Error(), "game not finished")
+
+	_, err = b.PlaceShip(ctx, matchID, bob.User.ID, 3, 0, 0, false)
+	require.NoError(t, err)
+
+	placed := next()
+	assert.Equal(t, dto.EventShipPlaced, placed.Type)
+	assert.Equal(t, bob.User.ID, placed.PlayerID)
+	assert.Equal(t, alice.User.ID, placed.TargetID)
+
+	_, err = b.EventsSince(ctx, "game-00000000-0000-0000-0000-000000000000", alice.User.ID, 0)
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
+
+	err = b.DeleteMatch(ctx, matchID, bob.User.ID)
+	assert.ErrorIs(t, err, controller.ErrNotHost)
+
+	require.NoError(t, b.DeleteMatch(ctx, matchID, alice.User.ID))
+
+	cancelled := next()
+	assert.Equal(t, dto.EventMatchCancelled, cancelled.Type)
+	assert.Equal(t, bob.User.ID, cancelled.TargetID, "the host is not told about their own cancellation")
+}
+
+func TestGameEvent_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	raw, err := json.Marshal(dto.GameEvent{
+		Type: dto.EventAttackMade,
+		Data: dto.AttackEventData{X: 1, Y: 2, Coord: "C2", Result: "hit"},
+	})
+	require.NoError(t, err)
+
+	var event dto.GameEvent
+	require.NoError(t, json.Unmarshal(raw, &event))
+	assert.Equal(t, dto.AttackEventData{X: 1, Y: 2, Coord: "C2", Result: "hit"}, event.Data,
+		"events read from the API carry the same data as in-process ones")
+}
@@ -0,0 +1,106 @@
+// Package rules holds the placement and targeting rules shared by the game model and its clients.
+// The model checks moves against its own board, while the TUI checks them against the board view
+// it was sent, so both apply the same rules and report the same errors.
+package rules
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+var (
+	// ErrOutOfBounds is returned when a ship placement goes out of the board bounds.
+	ErrOutOfBounds = errors.New("ship placement out of bounds")
+	// ErrOverlap is returned when a ship placement overlaps with another ship.
+	ErrOverlap = errors.New("ship placement overlaps with another ship")
+	// ErrInvalidShipSize is returned when a ship has a non-positive size.
+	ErrInvalidShipSize = errors.New("invalid ship size")
+	// ErrInvalidShot is returned when a shot is off the board or at a cell already attacked.
+	ErrInvalidShot = errors.New("invalid shot")
+	// ErrShotOutOfBounds is the ErrInvalidShot of a shot off the board.
+	ErrShotOutOfBounds = fmt.Errorf("%w: coordinate out of bounds", ErrInvalidShot)
+	// ErrAlreadyAttacked is the ErrInvalidShot of a shot at a cell already attacked.
+	ErrAlreadyAttacked = fmt.Errorf("%w: cell already attacked", ErrInvalidShot)
+)
+
+// Board is what the rules need to know about a board: its side length and
+// the state of each cell as far as the caller can see it.
+type Board interface {
+	Size() int
+	Cell(x, y int) dto.CellState
+}
+
+// View adapts a board view sent to clients so the rules can be checked against it.
+func View(b dto.BoardView) Board {
+	return boardView{view: b}
+}
+
+type boardView struct {
+	view dto.BoardView
+}
+
+func (v boardView) Size() int { return v.view.Size }
+
+func (v boardView) Cell(x, y int) dto.CellState { return v.view.Grid[y][x] }
+
+// CanAttack checks if a cell can be attacked.
+// It returns an error wrapping ErrShotOutOfBounds if the cell is off the board and ErrAlreadyAttacked
+// if it was already attacked, both of which are ErrInvalidShot.
+func CanAttack(board Board, x, y int) error {
+	if !inBounds(board, x, y) {
+		return fmt.Errorf("%w: %d,%d", ErrShotOutOfBounds, x, y)
+	}
+
+	switch board.Cell(x, y) {
+	case dto.CellHit, dto.CellMiss, dto.CellSunk:
+		return fmt.Errorf("%w: %d,%d", ErrAlreadyAttacked, x, y)
+	}
+
+	return nil
+}
+
+// CanPlaceShip checks if a ship of the given size can be placed starting at x,y.
+// It returns ErrInvalidShipSize, ErrOutOfBounds or ErrOverlap, or nil if the placement is valid.
+func CanPlaceShip(board Board, size, x, y int, vertical bool) error {
+	if err := CanFitShip(board, size, x, y, vertical); err != nil {
+		return err
+	}
+
+	dx, dy := direction(vertical)
+	for i := range size {
+		if board.Cell(x+i*dx, y+i*dy) != dto.CellEmpty {
+			return ErrOverlap
+		}
+	}
+
+	return nil
+}
+
+// CanFitShip checks the size and bounds of a placement but not what the cells hold,
+// for boards that look for overlaps their own way.
+// It returns ErrInvalidShipSize or ErrOutOfBounds, or nil if the ship fits on the board.
+func CanFitShip(board Board, size, x, y int, vertical bool) error {
+	if size <= 0 {
+		return ErrInvalidShipSize
+	}
+
+	dx, dy := direction(vertical)
+	if !inBounds(board, x, y) || !inBounds(board, x+(size-1)*dx, y+(size-1)*dy) {
+		return ErrOutOfBounds
+	}
+
+	return nil
+}
+
+func direction(vertical bool) (dx, dy int) {
+	if vertical {
+		return 0, 1
+	}
+	return 1, 0
+}
+
+func inBounds(board Board, x, y int) bool {
+	return x >= 0 && x < board.Size() && y >= 0 && y < board.Size()
+}
@@ -0,0 +1,104 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+// newView builds a square board view from rows of cells, '.' for empty, 'S' for a ship,
+// 'X' for a hit, 'O' for a miss and '#' for a sunk ship.
+func newView(rows ...string) rules.Board {
+	states := map[rune]dto.CellState{
+		'.': dto.CellEmpty,
+		'S': dto.CellShip,
+		'X': dto.CellHit,
+		'O': dto.CellMiss,
+		'#': dto.CellSunk,
+	}
+
+	grid := make([][]dto.CellState, len(rows))
+	for y, row := range rows {
+		for _, r := range row {
+			grid[y] = append(grid[y], states[r])
+		}
+	}
+	return rules.View(dto.BoardView{Grid: grid, Size: len(rows)})
+}
+
+func TestCanPlaceShip(t *testing.T) {
+	t.Parallel()
+
+	board := newView(
+		".....",
+		".S...",
+		".S...",
+		".....",
+		".....",
+	)
+
+	tests := []struct {
+		name     string
+		size     int
+		x, y     int
+		vertical bool
+		wantErr  error
+	}{
+		{name: "Valid Horizontal", size: 3, x: 2, y: 0},
+		{name: "Valid Vertical", size: 5, x: 4, y: 0, vertical: true},
+		{name: "Off Right Edge", size: 3, x: 3, y: 0, wantErr: rules.ErrOutOfBounds},
+		{name: "Off Bottom Edge", size: 3, x: 0, y: 3, vertical: true, wantErr: rules.ErrOutOfBounds},
+		{name: "Negative Start", size: 2, x: -1, y: 0, wantErr: rules.ErrOutOfBounds},
+		{name: "Overlap", size: 3, x: 0, y: 2, wantErr: rules.ErrOverlap},
+		{name: "Zero Size", size: 0, x: 0, y: 0, wantErr: rules.ErrInvalidShipSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := rules.CanPlaceShip(board, tt.size, tt.x, tt.y, tt.vertical)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCanAttack(t *testing.T) {
+	t.Parallel()
+
+	board := newView(
+		"X....",
+		".O...",
+		"..#..",
+		".....",
+		".....",
+	)
+
+	tests := []struct {
+		name    string
+		x, y    int
+		wantErr error
+	}{
+		{name: "Unknown Cell", x: 4, y: 4},
+		{name: "Hit Cell", x: 0, y: 0, wantErr: rules.ErrAlreadyAttacked},
+		{name: "Missed Cell", x: 1, y: 1, wantErr: rules.ErrAlreadyAttacked},
+		{name: "Sunk Cell", x: 2, y: 2, wantErr: rules.ErrAlreadyAttacked},
+		{name: "Out Of Bounds", x: 5, y: 0, wantErr: rules.ErrShotOutOfBounds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := rules.CanAttack(board, tt.x, tt.y)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, rules.ErrInvalidShot)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,15 @@
 package server
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/callegarimattia/battleship/internal/controller"
 	"github.com/callegarimattia/battleship/internal/dto"
@@ -11,11 +19,46 @@ import (
 )
 
 // EchoHandler has the handlers for the http.Server
-type EchoHandler struct{ ctrl *controller.AppController }
+type EchoHandler struct {
+	ctrl           *controller.AppController
+	allowedOrigins []string
+	pingPeriod     time.Duration
+	pongWait       time.Duration
+	writeTimeout   time.Duration
+	pollTimeout    time.Duration
+	coalesceWindow time.Duration
+	upgrader       websocket.Upgrader
+}
+
+// HandlerOption configures optional EchoHandler behavior.
+type HandlerOption func(*EchoHandler)
+
+// WithAllowedOrigins sets the origins allowed to open WebSocket connections.
+// "*" allows any origin. Same-origin requests and clients that send no Origin are always allowed.
+func WithAllowedOrigins(origins []string) HandlerOption {
+	return func(h *EchoHandler) { h.allowedOrigins = origins }
+}
+
+// WithPollTimeout sets how long a long-poll request waits for the game to change before answering 204.
+func WithPollTimeout(d time.Duration) HandlerOption {
+	return func(h *EchoHandler) { h.pollTimeout = d }
+}
 
 // NewEchoHandler creates a new http handler using echo
-func NewEchoHandler(c *controller.AppController) *EchoHandler {
-	return &EchoHandler{ctrl: c}
+func NewEchoHandler(c *controller.AppController, opts ...HandlerOption) *EchoHandler {
+	h := &EchoHandler{
+		ctrl:           c,
+		pingPeriod:     defaultPingPeriod,
+		pongWait:       defaultPongWait,
+		writeTimeout:   defaultWriteTimeout,
+		pollTimeout:    defaultPollTimeout,
+		coalesceWindow: defaultCoalesceWindow,
+		upgrader:       newUpgrader(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Login handles the user login request.
@@ -36,10 +79,75 @@ func (h *EchoHandler) Login(c echo.Context) error {
 	return c.JSON(http.StatusOK, user)
 }
 
-// ListMatches retrieves a list of all available matches.
+// PlatformLogin logs in a user of another platform (e.g. Discord) on behalf of a trusted integration,
+// which must present the server's API key. Unlike /login, the caller chooses the source and external ID.
+// POST /login/platform
+func (h *EchoHandler) PlatformLogin(c echo.Context) error {
+	var req struct {
+		Username   string `json:"username"`
+		Source     string `json:"source"`
+		ExternalID string `json:"external_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
+	}
+	if req.Username == "" || req.Source == "" || req.ExternalID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "username, source and external_id are required")
+	}
+
+	user, err := h.ctrl.Login(c.Request().Context(), req.Username, req.Source, req.ExternalID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// Me returns the user the token belongs to, letting clients check a stored token.
+// GET /me
+func (h *EchoHandler) Me(c echo.Context) error {
+	playerID := c.Get("player_id").(string)
+
+	user, err := h.ctrl.CurrentUser(c.Request().Context(), playerID)
+	if errors.Is(err, controller.ErrUserNotFound) {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// ActiveMatch returns the unfinished match the user is taking part in, letting clients resume it.
+// GET /me/match
+func (h *EchoHandler) ActiveMatch(c echo.Context) error {
+	playerID := c.Get("player_id").(string)
+
+	matchID, err := h.ctrl.ActiveMatchAction(c.Request().Context(), playerID)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"match_id": matchID})
+}
+
+// ListMatches retrieves the matches waiting for an opponent; ?live=true adds the ones in progress.
 // GET /matches
 func (h *EchoHandler) ListMatches(c echo.Context) error {
-	matches, err := h.ctrl.ListGamesAction(c.Request().Context())
+	var filter dto.MatchFilter
+	if raw := c.QueryParam("live"); raw != "" {
+		live, err := strconv.ParseBool(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "live must be a boolean")
+		}
+		filter.IncludeLive = live
+	}
+
+	matches, err := h.ctrl.ListGamesAction(c.Request().Context(), filter)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -47,19 +155,78 @@ func (h *EchoHandler) ListMatches(c echo.Context) error {
 	return c.JSON(http.StatusOK, matches)
 }
 
+// Scoreboard gives the live overview of every match in setup or in play, for spectators.
+// GET /scoreboard
+func (h *EchoHandler) Scoreboard(c echo.Context) error {
+	entries, err := h.ctrl.ScoreboardAction(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// PracticeBoard returns a sample placed board for tutorials.
+// The optional board_size and fleet_preset pick the rules; the standard ones are used otherwise.
+// GET /practice-board
+func (h *EchoHandler) PracticeBoard(c echo.Context) error {
+	var settings dto.MatchSettings
+	if raw := c.QueryParam("board_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "board_size must be an integer")
+		}
+		settings.BoardSize = size
+	}
+	settings.FleetPreset = c.QueryParam("fleet_preset")
+
+	board, err := h.ctrl.PracticeBoardAction(c.Request().Context(), settings)
+	switch {
+	case errors.Is(err, controller.ErrInvalidSettings):
+		return invalidRequest(err)
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, board)
+}
+
 // HostMatch allows a player to host a new match.
+// The optional body picks the board size and fleet preset.
 // POST /matches
 func (h *EchoHandler) HostMatch(c echo.Context) error {
 	playerID := c.Get("player_id").(string)
 
-	matchID, err := h.ctrl.HostGameAction(c.Request().Context(), playerID)
-	if err != nil {
+	var settings dto.MatchSettings
+	if err := c.Bind(&settings); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
+	}
+
+	matchID, err := h.ctrl.HostGameAction(c.Request().Context(), playerID, settings)
+	switch {
+	case errors.Is(err, controller.ErrInvalidSettings):
+		return invalidRequest(err)
+	case errors.Is(err, controller.ErrTooManyActiveGames):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, controller.ErrServerAtCapacity):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	case err != nil:
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"match_id": matchID})
 }
 
+// invalidRequest answers 400 for a request that failed validation, listing every problem found.
+func invalidRequest(err error) error {
+	body := dto.ErrorResponse{Message: err.Error()}
+	var invalid *controller.ValidationError
+	if errors.As(err, &invalid) {
+		body.Problems = invalid.Messages()
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, body)
+}
+
 // JoinMatch allows a player to join an existing match.
 // POST /matches/:id/join
 func (h *EchoHandler) JoinMatch(c echo.Context) error {
@@ -67,30 +234,305 @@ func (h *EchoHandler) JoinMatch(c echo.Context) error {
 	playerID := c.Get("player_id").(string)
 
 	view, err := h.ctrl.JoinGameAction(c.Request().Context(), matchID, playerID)
-	if err != nil {
+	switch {
+	case errors.Is(err, controller.ErrTooManyActiveGames):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, view)
 }
 
+// ResumeMatch gives the player the seat the resume token in the body belongs to,
+// so they can carry on a match from another device or login.
+// POST /matches/:id/resume
+func (h *EchoHandler) ResumeMatch(c echo.Context) error {
+	var req struct {
+		ResumeToken string `json:"resume_token"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
+	}
+	if req.ResumeToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "resume_token is required")
+	}
+
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	view, err := h.ctrl.ResumeGameAction(c.Request().Context(), matchID, playerID, req.ResumeToken)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, controller.ErrInvalidResumeToken):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, controller.ErrTooManyActiveGames):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// CancelMatch lets the host remove their match.
+// DELETE /matches/:id
+func (h *EchoHandler) CancelMatch(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	err := h.ctrl.CancelGameAction(c.Request().Context(), matchID, playerID)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, controller.ErrNotHost):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// TerminateMatch forcibly removes a match, for operators clearing one that got stuck.
+// POST /admin/matches/:id/terminate
+func (h *EchoHandler) TerminateMatch(c echo.Context) error {
+	err := h.ctrl.TerminateMatchAction(c.Request().Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // GetState retrieves the current state of a match.
+// The response carries an ETag derived from the game version. It answers 304 Not Modified
+// when If-None-Match matches it, or with ?since=N when the version has not moved past N.
 // GET /matches/:id
 func (h *EchoHandler) GetState(c echo.Context) error {
 	matchID := c.Param("id")
 	playerID := c.Get("player_id").(string)
 
+	since := -1
+	if raw := c.QueryParam("since"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be a non-negative integer")
+		}
+		since = v
+	}
+
 	view, err := h.ctrl.GetGameStateAction(c.Request().Context(), matchID, playerID)
-	if err != nil {
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
+	etag := versionETag(view.Version)
+	c.Response().Header().Set("ETag", etag)
+
+	// The client already has this version (or a newer one)
+	if view.Version <= since || etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, view)
 }
 
+// Poll waits until the game moves past version ?since=N and returns the new state, for clients that
+// can use no WebSocket. It answers 204 No Content if nothing changed within the poll timeout,
+// and the client polls again.
+// GET /matches/:id/poll
+func (h *EchoHandler) Poll(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+	ctx := c.Request().Context()
+
+	since, err := strconv.Atoi(c.QueryParam("since"))
+	if err != nil || since < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "since must be a non-negative integer")
+	}
+
+	// The request is held open for longer than the server's write timeout
+	_ = http.NewResponseController(c.Response()).SetWriteDeadline(time.Now().Add(h.pollTimeout + h.writeTimeout))
+
+	// Subscribe before reading the state, so that a change in between wakes the poll
+	sub, eventChan := h.ctrl.SubscribeToMatch(matchID)
+	defer sub.Unsubscribe()
+
+	timeout := time.NewTimer(h.pollTimeout)
+	defer timeout.Stop()
+
+	for {
+		view, err := h.ctrl.GetGameStateAction(ctx, matchID, playerID)
+		switch {
+		case errors.Is(err, controller.ErrMatchNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case err != nil:
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+
+		if view.Version > since {
+			c.Response().Header().Set("ETag", versionETag(view.Version))
+			return c.JSON(http.StatusOK, view)
+		}
+
+		select {
+		case _, ok := <-eventChan:
+			if !ok {
+				return c.NoContent(http.StatusNoContent)
+			}
+		case <-timeout.C:
+			return c.NoContent(http.StatusNoContent)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Events returns the events the player can see that happened after game version ?since=N (default 0),
+// so that clients following the match can tell what changed and not only the resulting state.
+// GET /matches/:id/events
+func (h *EchoHandler) Events(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	since := 0
+	if raw := c.QueryParam("since"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be a non-negative integer")
+		}
+		since = v
+	}
+
+	events, err := h.ctrl.EventsSinceAction(c.Request().Context(), matchID, playerID, since)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, events)
+}
+
+// Export downloads the move history of a match as ?format=json (default) or csv.
+// The CSV has one row per shot; the outcome of the match is only in the JSON.
+// GET /matches/:id/export
+func (h *EchoHandler) Export(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	format := c.QueryParam("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "csv":
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be json or csv")
+	}
+
+	record, err := h.ctrl.MatchRecordAction(c.Request().Context(), matchID, playerID)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", "match-"+record.MatchID+"."+format))
+
+	if format == "json" {
+		return c.JSON(http.StatusOK, record)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	return writeMovesCSV(c.Response(), record.Moves)
+}
+
+// writeMovesCSV writes one row per move under a header row.
+func writeMovesCSV(w io.Writer, moves []dto.MoveRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"move", "attacker", "target", "coord", "result"}); err != nil {
+		return err
+	}
+	for _, m := range moves {
+		if err := cw.Write([]string{strconv.Itoa(m.Number), m.Attacker, m.Target, m.Coord, m.Result}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// versionETag formats a game version as a strong entity tag.
+func versionETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// etagMatches reports whether an If-None-Match header value matches the given tag.
+func etagMatches(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // PlaceShip allows a player to place a ship on their board.
+// The ship is chosen by its type name when one is given, and by its size otherwise.
+// With a version, the ship is only placed if the game is still at that version.
 // POST /matches/:id/place
 func (h *EchoHandler) PlaceShip(c echo.Context) error {
+	var req struct {
+		Ship     string `json:"ship"`
+		Size     int    `json:"size"`
+		X        int    `json:"x"`
+		Y        int    `json:"y"`
+		Vertical bool   `json:"vertical"`
+		Version  *int   `json:"version"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
+	}
+
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+	ctx := expectVersion(c.Request().Context(), req.Version)
+
+	var (
+		view dto.GameView
+		err  error
+	)
+	if req.Ship != "" {
+		view, err = h.ctrl.PlaceShipTypeAction(ctx, matchID, playerID, req.Ship, req.X, req.Y, req.Vertical)
+	} else {
+		view, err = h.ctrl.PlaceShipAction(ctx, matchID, playerID, req.Size, req.X, req.Y, req.Vertical)
+	}
+	switch {
+	case errors.Is(err, controller.ErrVersionConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// ValidatePlacement tells a player whether a ship placement would be accepted, without making it.
+// POST /matches/:id/validate-placement
+func (h *EchoHandler) ValidatePlacement(c echo.Context) error {
 	var req struct {
 		Size     int  `json:"size"`
 		X        int  `json:"x"`
@@ -104,7 +546,7 @@ func (h *EchoHandler) PlaceShip(c echo.Context) error {
 	matchID := c.Param("id")
 	playerID := c.Get("player_id").(string)
 
-	view, err := h.ctrl.PlaceShipAction(
+	check, err := h.ctrl.ValidatePlacementAction(
 		c.Request().Context(),
 		matchID,
 		playerID,
@@ -113,6 +555,69 @@ func (h *EchoHandler) PlaceShip(c echo.Context) error {
 		req.Y,
 		req.Vertical,
 	)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, check)
+}
+
+// LegalMoves lists the cells the player may attack on their turn, on the board of the player
+// named by the target query parameter, which a free-for-all requires.
+// GET /matches/:id/legal-moves?target=
+func (h *EchoHandler) LegalMoves(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	moves, err := h.ctrl.LegalMovesAction(c.Request().Context(), matchID, playerID, c.QueryParam("target"))
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, moves)
+}
+
+// Rules tells how a match is played, so that clients need not assume the standard game.
+// GET /matches/:id/rules
+func (h *EchoHandler) Rules(c echo.Context) error {
+	rules, err := h.ctrl.RulesAction(c.Request().Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// Reveal shows both fleets of a won match with every ship.
+// GET /matches/:id/reveal
+func (h *EchoHandler) Reveal(c echo.Context) error {
+	reveal, err := h.ctrl.RevealAction(c.Request().Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, reveal)
+}
+
+// Ready confirms that a player has finished placing their ships.
+// POST /matches/:id/ready
+func (h *EchoHandler) Ready(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	view, err := h.ctrl.ReadyAction(c.Request().Context(), matchID, playerID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -121,11 +626,16 @@ func (h *EchoHandler) PlaceShip(c echo.Context) error {
 }
 
 // Attack allows a player to attack the opponent's board.
+// The optional target names the player fired at; without it the shot goes to the sole opponent.
+// With a version, the shot is only fired if the game is still at that version, so that
+// a retried request cannot fire twice.
 // POST /matches/:id/attack
 func (h *EchoHandler) Attack(c echo.Context) error {
 	var req struct {
-		X int `json:"x"`
-		Y int `json:"y"`
+		Target  string `json:"target"`
+		X       int    `json:"x"`
+		Y       int    `json:"y"`
+		Version *int   `json:"version"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
@@ -133,74 +643,57 @@ func (h *EchoHandler) Attack(c echo.Context) error {
 
 	matchID := c.Param("id")
 	playerID := c.Get("player_id").(string)
+	ctx := expectVersion(c.Request().Context(), req.Version)
 
-	view, err := h.ctrl.AttackAction(c.Request().Context(), matchID, playerID, req.X, req.Y)
-	if err != nil {
+	var (
+		view dto.GameView
+		err  error
+	)
+	if req.Target != "" {
+		view, err = h.ctrl.AttackTargetAction(ctx, matchID, playerID, req.Target, req.X, req.Y)
+	} else {
+		view, err = h.ctrl.AttackAction(ctx, matchID, playerID, req.X, req.Y)
+	}
+	switch {
+	case errors.Is(err, controller.ErrVersionConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, view)
 }
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for dev simplicity
-	},
-}
+// ValidateAttack tells a player whether a shot would be fired, without firing it.
+// POST /matches/:id/validate-attack
+func (h *EchoHandler) ValidateAttack(c echo.Context) error {
+	var req struct {
+		Target string `json:"target"`
+		X      int    `json:"x"`
+		Y      int    `json:"y"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
+	}
 
-// StreamMatchEvents upgrades the connection to WebSocket and streams match events.
-// GET /matches/:id/ws
-func (h *EchoHandler) StreamMatchEvents(c echo.Context) error {
 	matchID := c.Param("id")
 	playerID := c.Get("player_id").(string)
 
-	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = ws.Close() }()
-
-	sub, eventChan := h.ctrl.SubscribeToMatch(matchID)
-	defer sub.Unsubscribe()
-
-	// Send initial state
-	initialView, err := h.ctrl.GetGameStateAction(c.Request().Context(), matchID, playerID)
-	if err == nil {
-		if wErr := ws.WriteJSON(dto.WSEvent{
-			Type:    "game_update",
-			Payload: &initialView,
-		}); wErr != nil {
-			return nil
-		}
-	} else {
-		_ = ws.WriteJSON(dto.WSEvent{
-			Type:  "error",
-			Error: err.Error(),
-		})
+	check, err := h.ctrl.ValidateAttackAction(c.Request().Context(), matchID, playerID, req.Target, req.X, req.Y)
+	switch {
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	for {
-		select {
-		case <-eventChan:
-			// Fetch fresh state for this player
-			view, err := h.ctrl.GetGameStateAction(c.Request().Context(), matchID, playerID)
-			if err != nil {
-				// Try to send error to client
-				_ = ws.WriteJSON(dto.WSEvent{
-					Type:  "error",
-					Error: "failed to fetch state: " + err.Error(),
-				})
-				continue
-			}
+	return c.JSON(http.StatusOK, check)
+}
 
-			if wErr := ws.WriteJSON(dto.WSEvent{
-				Type:    "game_update",
-				Payload: &view,
-			}); wErr != nil {
-				return nil
-			}
-		case <-c.Request().Context().Done():
-			return nil
-		}
+// expectVersion makes the move in ctx conditional on the game version, when the client sent one.
+func expectVersion(ctx context.Context, version *int) context.Context {
+	if version == nil {
+		return ctx
 	}
+	return controller.WithExpectedVersion(ctx, *version)
 }
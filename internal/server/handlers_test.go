@@ -2,10 +2,13 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // --- Test Helpers ---
@@ -130,48 +134,66 @@ func TestLogin(t *testing.T) {
 	}
 }
 
-func TestListMatches(t *testing.T) {
+func TestPlatformLogin(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name           string
-		mockSetup      func(*mocks.MockLobbyService)
+		reqBody        any
+		mockSetup      func(*mocks.MockIdentityService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name: "Success",
-			mockSetup: func(m *mocks.MockLobbyService) {
-				m.EXPECT().ListMatches(mock.Anything).
-					Return([]dto.MatchSummary{
-						{ID: "m1", HostName: "H1", PlayerCount: 1, CreatedAt: time.Now()},
+			name:    "Success",
+			reqBody: map[string]string{"username": "Alice", "source": "discord", "external_id": "42"},
+			mockSetup: func(m *mocks.MockIdentityService) {
+				m.EXPECT().LoginOrRegister(mock.Anything, "Alice", "discord", "42").
+					Return(dto.AuthResponse{
+						Token: "t1",
+						User:  dto.User{ID: "user-123", Username: "Alice"},
 					}, nil).
 					Once()
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "m1",
+			expectedBody:   "user-123",
 		},
 		{
-			name: "Service Error",
-			mockSetup: func(m *mocks.MockLobbyService) {
-				m.EXPECT().ListMatches(mock.Anything).
-					Return(nil, errors.New("db fail")).
+			name:           "Invalid JSON",
+			reqBody:        "{invalid-json",
+			mockSetup:      func(m *mocks.MockIdentityService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+		{
+			name:           "Missing External ID",
+			reqBody:        map[string]string{"username": "Alice", "source": "discord"},
+			mockSetup:      func(m *mocks.MockIdentityService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "external_id",
+		},
+		{
+			name:    "Service Error",
+			reqBody: map[string]string{"username": "Alice", "source": "discord", "external_id": "42"},
+			mockSetup: func(m *mocks.MockIdentityService) {
+				m.EXPECT().LoginOrRegister(mock.Anything, "Alice", "discord", "42").
+					Return(dto.AuthResponse{}, errors.New("db down")).
 					Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "db fail",
+			expectedBody:   "db down",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			e, h, _, mockLobby, _, _ := setupTest(t)
-			tt.mockSetup(mockLobby)
+			e, h, mockAuth, _, _, _ := setupTest(t)
+			tt.mockSetup(mockAuth)
 
-			req, rec := makeRequest(http.MethodGet, "/matches", nil, nil)
+			req, rec := makeRequest(http.MethodPost, "/login/platform", tt.reqBody, nil)
 			c := e.NewContext(req, rec)
 
-			err := h.ListMatches(c)
+			err := h.PlatformLogin(c)
 			if err != nil {
 				he := &echo.HTTPError{}
 				ok := errors.As(err, &he)
@@ -187,52 +209,47 @@ func TestListMatches(t *testing.T) {
 	}
 }
 
-func TestHostMatch(t *testing.T) {
+func TestMe(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name           string
-		headers        map[string]string
-		mockSetup      func(*mocks.MockLobbyService)
+		mockSetup      func(*mocks.MockIdentityService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:    "Success",
-			headers: map[string]string{"X-Player-ID": "user-123"},
-			mockSetup: func(m *mocks.MockLobbyService) {
-				m.EXPECT().CreateMatch(mock.Anything, "user-123").
-					Return("match-new-id", nil).
+			name: "Success",
+			mockSetup: func(m *mocks.MockIdentityService) {
+				m.EXPECT().GetUser(mock.Anything, "p1").
+					Return(dto.User{ID: "p1", Username: "Alice"}, nil).
 					Once()
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "match-new-id",
+			expectedBody:   `"username":"Alice"`,
 		},
 		{
-			name:    "Service Error",
-			headers: map[string]string{"X-Player-ID": "user-123"},
-			mockSetup: func(m *mocks.MockLobbyService) {
-				m.EXPECT().CreateMatch(mock.Anything, "user-123").
-					Return("", errors.New("create fail")).
+			name: "Unknown User",
+			mockSetup: func(m *mocks.MockIdentityService) {
+				m.EXPECT().GetUser(mock.Anything, "p1").
+					Return(dto.User{}, controller.ErrUserNotFound).
 					Once()
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "create fail",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "user not found",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			e, h, _, mockLobby, _, _ := setupTest(t)
-			tt.mockSetup(mockLobby)
+			e, h, mockAuth, _, _, _ := setupTest(t)
+			tt.mockSetup(mockAuth)
 
-			req, rec := makeRequest(http.MethodPost, "/matches", nil, tt.headers)
+			req, rec := makeRequest(http.MethodGet, "/me", nil, nil)
 			c := e.NewContext(req, rec)
-			if id := tt.headers["X-Player-ID"]; id != "" {
-				c.Set("player_id", id)
-			}
+			c.Set("player_id", "p1")
 
-			err := h.HostMatch(c)
+			err := h.Me(c)
 			if err != nil {
 				he := &echo.HTTPError{}
 				ok := errors.As(err, &he)
@@ -248,39 +265,29 @@ func TestHostMatch(t *testing.T) {
 	}
 }
 
-func TestJoinMatch(t *testing.T) {
+func TestActiveMatch(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name           string
-		headers        map[string]string
-		paramID        string
 		mockSetup      func(*mocks.MockLobbyService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:    "Success",
-			headers: map[string]string{"X-Player-ID": "p2"},
-			paramID: "m1",
+			name: "Success",
 			mockSetup: func(m *mocks.MockLobbyService) {
-				m.EXPECT().JoinMatch(mock.Anything, "m1", "p2").
-					Return(dto.GameView{State: "SETUP"}, nil).
-					Once()
+				m.EXPECT().ActiveMatch(mock.Anything, "p1").Return("m1", nil).Once()
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "SETUP",
+			expectedBody:   `"match_id":"m1"`,
 		},
 		{
-			name:    "Service Error",
-			headers: map[string]string{"X-Player-ID": "p2"},
-			paramID: "m1",
+			name: "No Active Match",
 			mockSetup: func(m *mocks.MockLobbyService) {
-				m.EXPECT().JoinMatch(mock.Anything, "m1", "p2").
-					Return(dto.GameView{}, errors.New("game full")).
-					Once()
+				m.EXPECT().ActiveMatch(mock.Anything, "p1").Return("", controller.ErrMatchNotFound).Once()
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "game full",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
 		},
 	}
 
@@ -290,20 +297,11 @@ func TestJoinMatch(t *testing.T) {
 			e, h, _, mockLobby, _, _ := setupTest(t)
 			tt.mockSetup(mockLobby)
 
-			req, rec := makeRequest(
-				http.MethodPost,
-				"/matches/"+tt.paramID+"/join",
-				nil,
-				tt.headers,
-			)
+			req, rec := makeRequest(http.MethodGet, "/me/match", nil, nil)
 			c := e.NewContext(req, rec)
-			if id := tt.headers["X-Player-ID"]; id != "" {
-				c.Set("player_id", id)
-			}
-			c.SetParamNames("id")
-			c.SetParamValues(tt.paramID)
+			c.Set("player_id", "p1")
 
-			err := h.JoinMatch(c)
+			err := h.ActiveMatch(c)
 			if err != nil {
 				he := &echo.HTTPError{}
 				ok := errors.As(err, &he)
@@ -319,57 +317,69 @@ func TestJoinMatch(t *testing.T) {
 	}
 }
 
-func TestGetState(t *testing.T) {
+func TestListMatches(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name           string
-		headers        map[string]string
-		paramID        string
-		mockSetup      func(*mocks.MockGameService)
+		query          string
+		mockSetup      func(*mocks.MockLobbyService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:    "Success",
-			headers: map[string]string{"X-Player-ID": "p1"},
-			paramID: "m1",
-			mockSetup: func(m *mocks.MockGameService) {
-				m.EXPECT().GetState(mock.Anything, "m1", "p1").
-					Return(dto.GameView{State: "PLAYING"}, nil).
+			name: "Success",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().ListMatches(mock.Anything, dto.MatchFilter{}).
+					Return([]dto.MatchSummary{
+						{ID: "m1", HostName: "H1", PlayerCount: 1, CreatedAt: time.Now()},
+					}, nil).
 					Once()
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "PLAYING",
+			expectedBody:   "m1",
 		},
 		{
-			name:    "Service Error",
-			headers: map[string]string{"X-Player-ID": "p1"},
-			paramID: "m1",
-			mockSetup: func(m *mocks.MockGameService) {
-				m.EXPECT().GetState(mock.Anything, "m1", "p1").
-					Return(dto.GameView{}, errors.New("not found")).
+			name:  "Include Live",
+			query: "?live=true",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().ListMatches(mock.Anything, dto.MatchFilter{IncludeLive: true}).
+					Return([]dto.MatchSummary{
+						{ID: "m2", PlayerCount: 2, State: dto.StatePlaying, Watchers: 3},
+					}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"state":"PLAYING"`,
+		},
+		{
+			name:           "Invalid Live",
+			query:          "?live=maybe",
+			mockSetup:      func(*mocks.MockLobbyService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "live must be a boolean",
+		},
+		{
+			name: "Service Error",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().ListMatches(mock.Anything, mock.Anything).
+					Return(nil, errors.New("db fail")).
 					Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "not found",
+			expectedBody:   "db fail",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			e, h, _, _, mockGame, _ := setupTest(t)
-			tt.mockSetup(mockGame)
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
 
-			req, rec := makeRequest(http.MethodGet, "/matches/"+tt.paramID, nil, tt.headers)
+			req, rec := makeRequest(http.MethodGet, "/matches"+tt.query, nil, nil)
 			c := e.NewContext(req, rec)
-			if id := tt.headers["X-Player-ID"]; id != "" {
-				c.Set("player_id", id)
-			}
-			c.SetParamNames("id")
-			c.SetParamValues(tt.paramID)
 
-			err := h.GetState(c)
+			err := h.ListMatches(c)
 			if err != nil {
 				he := &echo.HTTPError{}
 				ok := errors.As(err, &he)
@@ -385,71 +395,52 @@ func TestGetState(t *testing.T) {
 	}
 }
 
-func TestPlaceShip(t *testing.T) {
+func TestScoreboard(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name           string
-		headers        map[string]string
-		reqBody        any
-		mockSetup      func(*mocks.MockGameService)
+		mockSetup      func(*mocks.MockLobbyService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:    "Success",
-			headers: map[string]string{"X-Player-ID": "p1"},
-			reqBody: map[string]any{"size": 3, "x": 0, "y": 0, "vertical": true},
-			mockSetup: func(m *mocks.MockGameService) {
-				m.EXPECT().PlaceShip(mock.Anything, "m1", "p1", 3, 0, 0, true).
-					Return(dto.GameView{State: "SETUP"}, nil).
+			name: "Success",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().Scoreboard(mock.Anything).
+					Return([]dto.ScoreboardEntry{{MatchID: "m1", State: dto.StatePlaying, Shots: 4}}, nil).
 					Once()
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "SETUP",
-		},
-		{
-			name:           "Invalid JSON",
-			headers:        map[string]string{"X-Player-ID": "p1"},
-			reqBody:        "{bad-json",
-			mockSetup:      func(m *mocks.MockGameService) {},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "Invalid JSON",
+			expectedBody:   `"shots":4`,
 		},
 		{
-			name:    "Service Error",
-			headers: map[string]string{"X-Player-ID": "p1"},
-			reqBody: map[string]any{"size": 3, "x": 0, "y": 0, "vertical": true},
-			mockSetup: func(m *mocks.MockGameService) {
-				m.EXPECT().PlaceShip(mock.Anything, "m1", "p1", 3, 0, 0, true).
-					Return(dto.GameView{}, errors.New("overlap")).
+			name: "Service Error",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().Scoreboard(mock.Anything).
+					Return(nil, errors.New("db fail")).
 					Once()
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "overlap",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db fail",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			e, h, _, _, mockGame, _ := setupTest(t)
-			tt.mockSetup(mockGame)
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
 
-			req, rec := makeRequest(http.MethodPost, "/matches/m1/place", tt.reqBody, tt.headers)
+			req, rec := makeRequest(http.MethodGet, "/scoreboard", nil, nil)
 			c := e.NewContext(req, rec)
-			if id := tt.headers["X-Player-ID"]; id != "" {
-				c.Set("player_id", id)
-			}
-			c.SetParamNames("id")
-			c.SetParamValues("m1")
 
-			err := h.PlaceShip(c)
+			err := h.Scoreboard(c)
 			if err != nil {
 				he := &echo.HTTPError{}
 				ok := errors.As(err, &he)
 				if assert.True(t, ok) {
 					assert.Equal(t, tt.expectedStatus, he.Code)
-					assert.Contains(t, he.Message, tt.expectedBody)
+					assert.Contains(t, fmt.Sprint(he.Message), tt.expectedBody)
 				}
 			} else {
 				assert.Equal(t, tt.expectedStatus, rec.Code)
@@ -459,71 +450,82 @@ func TestPlaceShip(t *testing.T) {
 	}
 }
 
-func TestAttack(t *testing.T) {
+func TestPracticeBoard(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name           string
-		headers        map[string]string
-		reqBody        any
-		mockSetup      func(*mocks.MockGameService)
+		query          string
+		mockSetup      func(*mocks.MockLobbyService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:    "Hit",
-			headers: map[string]string{"X-Player-ID": "p1"},
-			reqBody: map[string]any{"x": 5, "y": 5},
-			mockSetup: func(m *mocks.MockGameService) {
-				m.EXPECT().Attack(mock.Anything, "m1", "p1", 5, 5).
-					Return(dto.GameView{State: "playing", Turn: "p2"}, nil).
+			name: "Success",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().PracticeBoard(mock.Anything, dto.MatchSettings{}).
+					Return(dto.BoardView{Size: 10}, nil).
 					Once()
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   "playing",
+			expectedBody:   `"size":10`,
 		},
 		{
-			name:           "Invalid JSON",
-			headers:        map[string]string{"X-Player-ID": "p1"},
-			reqBody:        "{bad",
-			mockSetup:      func(m *mocks.MockGameService) {},
+			name:  "Custom Rules",
+			query: "?board_size=8&fleet_preset=small",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().PracticeBoard(mock.Anything, dto.MatchSettings{BoardSize: 8, FleetPreset: "small"}).
+					Return(dto.BoardView{Size: 8}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"size":8`,
+		},
+		{
+			name:           "Invalid Board Size",
+			query:          "?board_size=big",
+			mockSetup:      func(*mocks.MockLobbyService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "Invalid JSON",
+			expectedBody:   "board_size must be an integer",
 		},
 		{
-			name:    "Service Error",
-			headers: map[string]string{"X-Player-ID": "p1"},
-			reqBody: map[string]any{"x": 5, "y": 5},
-			mockSetup: func(m *mocks.MockGameService) {
-				m.EXPECT().Attack(mock.Anything, "m1", "p1", 5, 5).
-					Return(dto.GameView{}, errors.New("not your turn")).
+			name:  "Invalid Settings",
+			query: "?fleet_preset=armada",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().PracticeBoard(mock.Anything, dto.MatchSettings{FleetPreset: "armada"}).
+					Return(dto.BoardView{}, controller.ErrInvalidSettings).
 					Once()
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "not your turn",
+			expectedBody:   controller.ErrInvalidSettings.Error(),
+		},
+		{
+			name: "Service Error",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().PracticeBoard(mock.Anything, mock.Anything).
+					Return(dto.BoardView{}, errors.New("db fail")).
+					Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db fail",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			e, h, _, _, mockGame, _ := setupTest(t)
-			tt.mockSetup(mockGame)
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
 
-			req, rec := makeRequest(http.MethodPost, "/matches/m1/attack", tt.reqBody, tt.headers)
+			req, rec := makeRequest(http.MethodGet, "/practice-board"+tt.query, nil, nil)
 			c := e.NewContext(req, rec)
-			if id := tt.headers["X-Player-ID"]; id != "" {
-				c.Set("player_id", id)
-			}
-			c.SetParamNames("id")
-			c.SetParamValues("m1")
 
-			err := h.Attack(c)
+			err := h.PracticeBoard(c)
 			if err != nil {
 				he := &echo.HTTPError{}
 				ok := errors.As(err, &he)
 				if assert.True(t, ok) {
 					assert.Equal(t, tt.expectedStatus, he.Code)
-					assert.Contains(t, he.Message, tt.expectedBody)
+					assert.Contains(t, fmt.Sprint(he.Message), tt.expectedBody)
 				}
 			} else {
 				assert.Equal(t, tt.expectedStatus, rec.Code)
@@ -532,20 +534,1528 @@ func TestAttack(t *testing.T) {
 		})
 	}
 }
-
-func TestStreamMatchEvents(t *testing.T) { //nolint:paralleltest
-	e, h, _, _, mockGame, mockNotifier := setupTest(t)
-
-	mockSub := mocks.NewMockSubscription(t)
-	mockSub.EXPECT().Unsubscribe().Return().Maybe()
-
-	eventChan := make(chan *dto.GameEvent, 1)
-
+func TestHostMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		body           any
+		mockSetup      func(*mocks.MockLobbyService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", dto.MatchSettings{}).
+					Return("match-new-id", nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "match-new-id",
+		},
+		{
+			name:    "Custom Settings",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			body:    dto.MatchSettings{BoardSize: 8, FleetPreset: "small"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				settings := dto.MatchSettings{BoardSize: 8, FleetPreset: "small"}
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", settings).
+					Return("match-small", nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "match-small",
+		},
+		{
+			name:    "Invalid Settings",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			body:    dto.MatchSettings{BoardSize: 42},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", mock.Anything).
+					Return("", controller.ErrInvalidSettings).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid match settings",
+		},
+		{
+			name:    "Every Problem Listed",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			body:    dto.MatchSettings{MoveLimit: -1, FleetPreset: "armada"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				problems := &controller.ValidationError{Problems: []error{
+					errors.New("move limit must not be negative"),
+					errors.New("unknown fleet preset"),
+				}}
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", mock.Anything).
+					Return("", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, problems)).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "[move limit must not be negative unknown fleet preset]",
+		},
+		{
+			name:    "Server At Capacity",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", mock.Anything).
+					Return("", controller.ErrServerAtCapacity).
+					Once()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "server at capacity",
+		},
+		{
+			name:    "Too Many Active Games",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", mock.Anything).
+					Return("", controller.ErrTooManyActiveGames).
+					Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "active game limit reached",
+		},
+		{
+			name:    "Service Error",
+			headers: map[string]string{"X-Player-ID": "user-123"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().CreateMatch(mock.Anything, "user-123", mock.Anything).
+					Return("", errors.New("create fail")).
+					Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "create fail",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
+
+			req, rec := makeRequest(http.MethodPost, "/matches", tt.body, tt.headers)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+
+			err := h.HostMatch(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, fmt.Sprint(he.Message), tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestJoinMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		paramID        string
+		mockSetup      func(*mocks.MockLobbyService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			headers: map[string]string{"X-Player-ID": "p2"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().JoinMatch(mock.Anything, "m1", "p2").
+					Return(dto.GameView{State: "SETUP"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "SETUP",
+		},
+		{
+			name:    "Service Error",
+			headers: map[string]string{"X-Player-ID": "p2"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().JoinMatch(mock.Anything, "m1", "p2").
+					Return(dto.GameView{}, errors.New("game full")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "game full",
+		},
+		{
+			name:    "Too Many Active Games",
+			headers: map[string]string{"X-Player-ID": "p2"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().JoinMatch(mock.Anything, "m1", "p2").
+					Return(dto.GameView{}, controller.ErrTooManyActiveGames).
+					Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "active game limit reached",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
+
+			req, rec := makeRequest(
+				http.MethodPost,
+				"/matches/"+tt.paramID+"/join",
+				nil,
+				tt.headers,
+			)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues(tt.paramID)
+
+			err := h.JoinMatch(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestResumeMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		reqBody        any
+		mockSetup      func(*mocks.MockLobbyService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			reqBody: map[string]string{"resume_token": "tok"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().ResumeMatch(mock.Anything, "m1", "p2", "tok").
+					Return(dto.GameView{State: "PLAYING"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "PLAYING",
+		},
+		{
+			name:           "Missing Token",
+			reqBody:        map[string]string{},
+			mockSetup:      func(*mocks.MockLobbyService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "resume_token is required",
+		},
+		{
+			name:    "Wrong Token",
+			reqBody: map[string]string{"resume_token": "nope"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().ResumeMatch(mock.Anything, "m1", "p2", "nope").
+					Return(dto.GameView{}, controller.ErrInvalidResumeToken).
+					Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "invalid resume token",
+		},
+		{
+			name:    "Match Not Found",
+			reqBody: map[string]string{"resume_token": "tok"},
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().ResumeMatch(mock.Anything, "m1", "p2", "tok").
+					Return(dto.GameView{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
+
+			req, rec := makeRequest(http.MethodPost, "/matches/m1/resume", tt.reqBody, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p2")
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.ResumeMatch(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestTerminateMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusNoContent},
+		{name: "Not Found", err: controller.ErrMatchNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Service Error", err: errors.New("db fail"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			mockAdmin := mocks.NewMockAdminService(t)
+			ctrl := controller.NewAppController(
+				mocks.NewMockIdentityService(t),
+				mocks.NewMockLobbyService(t),
+				mocks.NewMockGameService(t),
+				mocks.NewMockNotificationService(t),
+				controller.WithAdmin(mockAdmin),
+			)
+			h := NewEchoHandler(ctrl)
+			mockAdmin.EXPECT().TerminateMatch(mock.Anything, "m1").Return(tt.err).Once()
+
+			req, rec := makeRequest(http.MethodPost, "/admin/matches/m1/terminate", nil, nil)
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.TerminateMatch(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				if assert.True(t, errors.As(err, &he)) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestCancelMatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		paramID        string
+		mockSetup      func(*mocks.MockLobbyService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().DeleteMatch(mock.Anything, "m1", "p1").
+					Return(nil).
+					Once()
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
+		{
+			name:    "Not Host",
+			headers: map[string]string{"X-Player-ID": "p2"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().DeleteMatch(mock.Anything, "m1", "p2").
+					Return(controller.ErrNotHost).
+					Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "only the host",
+		},
+		{
+			name:    "Not Found",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m404",
+			mockSetup: func(m *mocks.MockLobbyService) {
+				m.EXPECT().DeleteMatch(mock.Anything, "m404", "p1").
+					Return(controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, mockLobby, _, _ := setupTest(t)
+			tt.mockSetup(mockLobby)
+
+			req, rec := makeRequest(http.MethodDelete, "/matches/"+tt.paramID, nil, tt.headers)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues(tt.paramID)
+
+			err := h.CancelMatch(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestGetState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		paramID        string
+		query          string
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().GetState(mock.Anything, "m1", "p1").
+					Return(dto.GameView{State: "PLAYING"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "PLAYING",
+		},
+		{
+			name:    "Service Error",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().GetState(mock.Anything, "m1", "p1").
+					Return(dto.GameView{}, errors.New("not found")).
+					Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "not found",
+		},
+		{
+			name:    "Match Not Found",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m1",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().GetState(mock.Anything, "m1", "p1").
+					Return(dto.GameView{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+		{
+			name:    "Unchanged Since Version",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m1",
+			query:   "?since=3",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().GetState(mock.Anything, "m1", "p1").
+					Return(dto.GameView{State: "PLAYING", Version: 3}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusNotModified,
+			expectedBody:   "",
+		},
+		{
+			name:    "Changed Since Version",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			paramID: "m1",
+			query:   "?since=2",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().GetState(mock.Anything, "m1", "p1").
+					Return(dto.GameView{State: "PLAYING", Version: 3}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"version":3`,
+		},
+		{
+			name:           "Invalid Since",
+			headers:        map[string]string{"X-Player-ID": "p1"},
+			paramID:        "m1",
+			query:          "?since=abc",
+			mockSetup:      func(_ *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "since must be a non-negative integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodGet, "/matches/"+tt.paramID+tt.query, nil, tt.headers)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues(tt.paramID)
+
+			err := h.GetState(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestGetState_ETag(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, _ := setupTest(t)
+
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: "PLAYING", Version: 3}, nil).
+		Twice()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: "PLAYING", Version: 4}, nil).
+		Once()
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		headers := map[string]string{"X-Player-ID": "p1"}
+		if ifNoneMatch != "" {
+			headers["If-None-Match"] = ifNoneMatch
+		}
+		req, rec := makeRequest(http.MethodGet, "/matches/m1", nil, headers)
+		c := e.NewContext(req, rec)
+		c.Set("player_id", "p1")
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		require.NoError(t, h.GetState(c))
+		return rec
+	}
+
+	rec := get("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	rec = get(etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code, "Unchanged game should not be re-sent")
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+	rec = get(etag)
+	assert.Equal(t, http.StatusOK, rec.Code, "Changed game should be sent again")
+	assert.Contains(t, rec.Body.String(), `"version":4`)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Since Version",
+			query: "?since=2",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().EventsSince(mock.Anything, "m1", "p1", 2).
+					Return([]dto.GameEvent{{Type: dto.EventAttackMade, MatchID: "m1", Version: 3}}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"type":"attack.made"`,
+		},
+		{
+			name: "Defaults To The Start",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().EventsSince(mock.Anything, "m1", "p1", 0).
+					Return([]dto.GameEvent{}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[]",
+		},
+		{
+			name:           "Invalid Since",
+			query:          "?since=-1",
+			mockSetup:      func(_ *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "since must be a non-negative integer",
+		},
+		{
+			name: "Match Not Found",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().EventsSince(mock.Anything, "m1", "p1", 0).
+					Return(nil, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodGet, "/matches/m1/events"+tt.query, nil, nil)
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+			c.Set("player_id", "p1")
+
+			err := h.Events(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestPlaceShip(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		reqBody        any
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"size": 3, "x": 0, "y": 0, "vertical": true},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().PlaceShip(mock.Anything, "m1", "p1", 3, 0, 0, true).
+					Return(dto.GameView{State: "SETUP"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "SETUP",
+		},
+		{
+			name:    "By Ship Type",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"ship": "Submarine", "size": 2, "x": 1, "y": 2},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().PlaceShipType(mock.Anything, "m1", "p1", "Submarine", 1, 2, false).
+					Return(dto.GameView{State: "SETUP"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "SETUP",
+		},
+		{
+			name:           "Invalid JSON",
+			headers:        map[string]string{"X-Player-ID": "p1"},
+			reqBody:        "{bad-json",
+			mockSetup:      func(m *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+		{
+			name:    "Unknown Ship Type",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"ship": "Dinghy", "x": 0, "y": 0},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().PlaceShipType(mock.Anything, "m1", "p1", "Dinghy", 0, 0, false).
+					Return(dto.GameView{}, errors.New("unknown ship type")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unknown ship type",
+		},
+		{
+			name:    "Service Error",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"size": 3, "x": 0, "y": 0, "vertical": true},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().PlaceShip(mock.Anything, "m1", "p1", 3, 0, 0, true).
+					Return(dto.GameView{}, errors.New("overlap")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "overlap",
+		},
+		{
+			name:    "Stale Version",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"size": 3, "x": 0, "y": 0, "version": 2},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().PlaceShip(expectsVersion(2), "m1", "p1", 3, 0, 0, false).
+					Return(dto.GameView{}, controller.ErrVersionConflict).
+					Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   controller.ErrVersionConflict.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodPost, "/matches/m1/place", tt.reqBody, tt.headers)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.PlaceShip(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestValidateAttack(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		reqBody        any
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Legal",
+			reqBody: map[string]any{"x": 1, "y": 2},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().ValidateAttack(mock.Anything, "m1", "p1", "", 1, 2).
+					Return(dto.AttackCheck{Valid: true}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"valid":true`,
+		},
+		{
+			name:    "Refused",
+			reqBody: map[string]any{"target": "p2", "x": 1, "y": 2},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().ValidateAttack(mock.Anything, "m1", "p1", "p2", 1, 2).
+					Return(dto.AttackCheck{Code: dto.AttackNotYourTurn, Reason: "not your turn"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"code":"not_your_turn"`,
+		},
+		{
+			name:           "Invalid JSON",
+			reqBody:        "{bad-json",
+			mockSetup:      func(m *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+		{
+			name:    "Match Not Found",
+			reqBody: map[string]any{"x": 0, "y": 0},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().ValidateAttack(mock.Anything, "m1", "p1", "", 0, 0).
+					Return(dto.AttackCheck{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodPost, "/matches/m1/validate-attack", tt.reqBody, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p1")
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.ValidateAttack(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestValidatePlacement(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		reqBody        any
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Legal",
+			reqBody: map[string]any{"size": 3, "x": 0, "y": 0, "vertical": true},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().ValidatePlacement(mock.Anything, "m1", "p1", 3, 0, 0, true).
+					Return(dto.PlacementCheck{Valid: true}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"valid":true`,
+		},
+		{
+			name:    "Refused",
+			reqBody: map[string]any{"size": 3, "x": 9, "y": 0},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().ValidatePlacement(mock.Anything, "m1", "p1", 3, 9, 0, false).
+					Return(dto.PlacementCheck{Code: dto.PlacementOutOfBounds, Reason: "out of bounds"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"code":"out_of_bounds"`,
+		},
+		{
+			name:           "Invalid JSON",
+			reqBody:        "{bad-json",
+			mockSetup:      func(m *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+		{
+			name:    "Match Not Found",
+			reqBody: map[string]any{"size": 3},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().ValidatePlacement(mock.Anything, "m1", "p1", 3, 0, 0, false).
+					Return(dto.PlacementCheck{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodPost, "/matches/m1/validate-placement", tt.reqBody, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p1")
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.ValidatePlacement(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestLegalMoves(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		target         string
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().LegalMoves(mock.Anything, "m1", "p1", "").
+					Return(dto.LegalMoves{Targets: []dto.Target{{X: 1, Y: 6, Coord: "B7"}}}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"coord":"B7"`,
+		},
+		{
+			name:   "Named Target",
+			target: "p3",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().LegalMoves(mock.Anything, "m1", "p1", "p3").
+					Return(dto.LegalMoves{Targets: []dto.Target{{X: 0, Y: 0, Coord: "A1"}}}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"coord":"A1"`,
+		},
+		{
+			name: "Not Your Turn",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().LegalMoves(mock.Anything, "m1", "p1", "").
+					Return(dto.LegalMoves{}, errors.New("not your turn")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "not your turn",
+		},
+		{
+			name: "Match Not Found",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().LegalMoves(mock.Anything, "m1", "p1", "").
+					Return(dto.LegalMoves{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodGet, "/matches/m1/legal-moves?target="+tt.target, nil, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p1")
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.LegalMoves(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+	record := dto.MatchRecord{
+		MatchID: "m1",
+		State:   dto.StateFinished,
+		Winner:  "p1",
+		Moves: []dto.MoveRecord{
+			{Number: 1, Attacker: "p1", Coord: "B7", Result: "hit"},
+			{Number: 2, Attacker: "p2", Coord: "A1", Result: "miss"},
+		},
+	}
+	freeForAll := dto.MatchRecord{
+		MatchID: "m1",
+		State:   dto.StatePlaying,
+		Moves: []dto.MoveRecord{
+			{Number: 1, Attacker: "p1", Target: "p3", Coord: "B7", Result: "hit"},
+			{Number: 2, Attacker: "p2", Target: "p1", Coord: "A1", Result: "miss"},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		mockSetup           func(*mocks.MockGameService)
+		expectedStatus      int
+		expectedType        string
+		expectedDisposition string
+		expectedBody        string
+	}{
+		{
+			name: "Default JSON",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().MatchRecord(mock.Anything, "m1", "p1").Return(record, nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedType:        echo.MIMEApplicationJSON,
+			expectedDisposition: `attachment; filename="match-m1.json"`,
+			expectedBody:        `"winner":"p1"`,
+		},
+		{
+			name:  "CSV",
+			query: "?format=csv",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().MatchRecord(mock.Anything, "m1", "p1").Return(record, nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedType:        "text/csv; charset=utf-8",
+			expectedDisposition: `attachment; filename="match-m1.csv"`,
+			expectedBody:        "move,attacker,target,coord,result\n1,p1,,B7,hit\n2,p2,,A1,miss\n",
+		},
+		{
+			name: "Free-for-all JSON",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().MatchRecord(mock.Anything, "m1", "p1").Return(freeForAll, nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedType:        echo.MIMEApplicationJSON,
+			expectedDisposition: `attachment; filename="match-m1.json"`,
+			expectedBody:        `{"move":1,"attacker":"p1","target":"p3","coord":"B7","result":"hit"}`,
+		},
+		{
+			name:  "Free-for-all CSV",
+			query: "?format=csv",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().MatchRecord(mock.Anything, "m1", "p1").Return(freeForAll, nil).Once()
+			},
+			expectedStatus:      http.StatusOK,
+			expectedType:        "text/csv; charset=utf-8",
+			expectedDisposition: `attachment; filename="match-m1.csv"`,
+			expectedBody:        "move,attacker,target,coord,result\n1,p1,p3,B7,hit\n2,p2,p1,A1,miss\n",
+		},
+		{
+			name:           "Unknown Format",
+			query:          "?format=xml",
+			mockSetup:      func(m *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "format must be json or csv",
+		},
+		{
+			name: "Match Not Found",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().MatchRecord(mock.Anything, "m1", "p1").
+					Return(dto.MatchRecord{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodGet, "/matches/m1/export"+tt.query, nil, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p1")
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.Export(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+				return
+			}
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tt.expectedDisposition, rec.Header().Get(echo.HeaderContentDisposition))
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestReveal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Reveal(mock.Anything, "m1").
+					Return(dto.RevealView{MatchID: "m1", Winner: "p1"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"winner":"p1"`,
+		},
+		{
+			name: "Not Finished",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Reveal(mock.Anything, "m1").
+					Return(dto.RevealView{}, errors.New("game not finished")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "game not finished",
+		},
+		{
+			name: "Match Not Found",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Reveal(mock.Anything, "m1").
+					Return(dto.RevealView{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodGet, "/matches/m1/reveal", nil, nil)
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.Reveal(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+				return
+			}
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Rules(mock.Anything, "m1").
+					Return(dto.Rules{
+						BoardSize: 8,
+						HostFleet: []dto.ShipRule{{Name: "Destroyer", Size: 2, Count: 1}},
+					}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"host_fleet":[{"name":"Destroyer","size":2,"count":1}]`,
+		},
+		{
+			name: "Match Not Found",
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Rules(mock.Anything, "m1").
+					Return(dto.Rules{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "match not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodGet, "/matches/m1/rules", nil, nil)
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.Rules(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+				return
+			}
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestReady(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Ready(mock.Anything, "m1", "p1").
+					Return(dto.GameView{State: "PLAYING"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "PLAYING",
+		},
+		{
+			name:    "Service Error",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Ready(mock.Anything, "m1", "p1").
+					Return(dto.GameView{}, errors.New("not all ships placed")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "not all ships placed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodPost, "/matches/m1/ready", nil, tt.headers)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.Ready(c)
+
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+// expectsVersion matches a context that makes the move conditional on the given game version.
+func expectsVersion(version int) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		v, ok := controller.ExpectedVersion(ctx)
+		return ok && v == version
+	})
+}
+
+func TestAttack(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		reqBody        any
+		mockSetup      func(*mocks.MockGameService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Hit",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"x": 5, "y": 5},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Attack(mock.Anything, "m1", "p1", 5, 5).
+					Return(dto.GameView{State: "playing", Turn: "p2"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "playing",
+		},
+		{
+			name:    "Named Target",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"target": "p2", "x": 5, "y": 5},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().AttackTarget(mock.Anything, "m1", "p1", "p2", 5, 5).
+					Return(dto.GameView{State: "playing", Turn: "p2"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "playing",
+		},
+		{
+			name:    "Invalid Target",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"target": "p1", "x": 5, "y": 5},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().AttackTarget(mock.Anything, "m1", "p1", "p1", 5, 5).
+					Return(dto.GameView{}, errors.New("invalid target player")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid target player",
+		},
+		{
+			name:           "Invalid JSON",
+			headers:        map[string]string{"X-Player-ID": "p1"},
+			reqBody:        "{bad",
+			mockSetup:      func(m *mocks.MockGameService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+		{
+			name:    "Service Error",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"x": 5, "y": 5},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Attack(mock.Anything, "m1", "p1", 5, 5).
+					Return(dto.GameView{}, errors.New("not your turn")).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "not your turn",
+		},
+		{
+			name:    "Stale Version",
+			headers: map[string]string{"X-Player-ID": "p1"},
+			reqBody: map[string]any{"x": 5, "y": 5, "version": 7},
+			mockSetup: func(m *mocks.MockGameService) {
+				m.EXPECT().Attack(expectsVersion(7), "m1", "p1", 5, 5).
+					Return(dto.GameView{}, controller.ErrVersionConflict).
+					Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   controller.ErrVersionConflict.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockGame)
+
+			req, rec := makeRequest(http.MethodPost, "/matches/m1/attack", tt.reqBody, tt.headers)
+			c := e.NewContext(req, rec)
+			if id := tt.headers["X-Player-ID"]; id != "" {
+				c.Set("player_id", id)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues("m1")
+
+			err := h.Attack(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, he.Message, tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestPoll(t *testing.T) {
+	t.Parallel()
+
+	poll := func(t *testing.T, h *EchoHandler, e *echo.Echo, query string) (*httptest.ResponseRecorder, error) {
+		t.Helper()
+		req, rec := makeRequest(http.MethodGet, "/matches/m1/poll"+query, nil, nil)
+		c := e.NewContext(req, rec)
+		c.Set("player_id", "p1")
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		return rec, h.Poll(c)
+	}
+
+	subscribe := func(t *testing.T, m *mocks.MockNotificationService) chan *dto.GameEvent {
+		t.Helper()
+		sub := mocks.NewMockSubscription(t)
+		sub.EXPECT().Unsubscribe().Return().Once()
+		events := make(chan *dto.GameEvent, 1)
+		m.EXPECT().Subscribe("m1").Return(sub, (<-chan *dto.GameEvent)(events)).Once()
+		return events
+	}
+
+	t.Run("Already Changed", func(t *testing.T) {
+		t.Parallel()
+		e, h, _, _, mockGame, mockNotifier := setupTest(t)
+		subscribe(t, mockNotifier)
+		mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").Return(dto.GameView{Version: 4}, nil).Once()
+
+		rec, err := poll(t, h, e, "?since=3")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("Woken By Event", func(t *testing.T) {
+		t.Parallel()
+		e, h, _, _, mockGame, mockNotifier := setupTest(t)
+		events := subscribe(t, mockNotifier)
+		mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").Return(dto.GameView{Version: 3}, nil).Once()
+		mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").Return(dto.GameView{Version: 5, Turn: "p2"}, nil).Once()
+		events <- &dto.GameEvent{Type: dto.EventAttackMade, MatchID: "m1"}
+
+		rec, err := poll(t, h, e, "?since=3")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"turn":"p2"`)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		e, h, _, _, mockGame, mockNotifier := setupTest(t)
+		WithPollTimeout(10 * time.Millisecond)(h)
+		subscribe(t, mockNotifier)
+		mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").Return(dto.GameView{Version: 3}, nil).Once()
+
+		rec, err := poll(t, h, e, "?since=3")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("Match Not Found", func(t *testing.T) {
+		t.Parallel()
+		e, h, _, _, mockGame, mockNotifier := setupTest(t)
+		subscribe(t, mockNotifier)
+		mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+			Return(dto.GameView{}, controller.ErrMatchNotFound).
+			Once()
+
+		_, err := poll(t, h, e, "?since=0")
+		he := &echo.HTTPError{}
+		require.ErrorAs(t, err, &he)
+		assert.Equal(t, http.StatusNotFound, he.Code)
+	})
+
+	t.Run("Missing Since", func(t *testing.T) {
+		t.Parallel()
+		e, h, _, _, _, _ := setupTest(t)
+
+		_, err := poll(t, h, e, "")
+		he := &echo.HTTPError{}
+		require.ErrorAs(t, err, &he)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+	})
+}
+
+func TestStreamMatchEvents(t *testing.T) { //nolint:paralleltest
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Return().Maybe()
+
+	eventChan := make(chan *dto.GameEvent, 1)
+
 	mockNotifier.EXPECT().Subscribe("m1").
 		Return(mockSub, (<-chan *dto.GameEvent)(eventChan)).
 		Once()
 
-	initialView := dto.GameView{State: "WAITING", Turn: "p1"}
+	initialView := dto.GameView{State: "WAITING", Turn: "p1", Version: 1}
 	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
 		Return(initialView, nil).
 		Once()
@@ -578,7 +2088,7 @@ func TestStreamMatchEvents(t *testing.T) { //nolint:paralleltest
 	// Updated view expectations? Maybe redundant if we don't call GetState again
 	// Actually StreamMatchEvents fetches fresh state in the loop.
 
-	updatedView := dto.GameView{State: "PLAYING", Turn: "p2"}
+	updatedView := dto.GameView{State: "PLAYING", Turn: "p2", Version: 2}
 	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
 		Return(updatedView, nil).
 		Maybe()
@@ -590,4 +2100,460 @@ func TestStreamMatchEvents(t *testing.T) { //nolint:paralleltest
 	assert.Equal(t, "game_update", evt.Type)
 	assert.NotNil(t, evt.Payload)
 	assert.Equal(t, dto.GameState("PLAYING"), evt.Payload.State)
+	assert.Nil(t, evt.Attack, "only attacks carry a shot")
+}
+
+func TestStreamMatchEvents_Filter(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Return().Maybe()
+	mockNotifier.EXPECT().Subscribe("m1", dto.EventAttackMade, dto.EventGameOver).
+		Return(mockSub, (<-chan *dto.GameEvent)(make(chan *dto.GameEvent))).
+		Once()
+	mockGame.EXPECT().EventsSince(mock.Anything, "m1", "p1", 0).
+		Return([]dto.GameEvent{
+			{Type: dto.EventShipPlaced, MatchID: "m1", Version: 1},
+			{Type: dto.EventAttackMade, MatchID: "m1", Version: 2},
+		}, nil).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: dto.StatePlaying, Version: 2}, nil).
+		Once()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.NewContext(r, w)
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		c.Set("player_id", "p1")
+		if err := h.StreamMatchEvents(c); err != nil {
+			e.HTTPErrorHandler(err, c)
+		}
+	}))
+	defer ts.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws?events=attack.made,bogus", nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "unknown event types are refused")
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws?since=0&events=attack.made,game.over", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var evt dto.WSEvent
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Equal(t, "game_event", evt.Type)
+	require.NotNil(t, evt.Event)
+	assert.Equal(t, dto.EventAttackMade, evt.Event.Type, "replayed events are filtered too")
+
+	evt = dto.WSEvent{}
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Equal(t, "game_update", evt.Type)
+}
+
+func TestStreamMatchEvents_Attack(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Return().Maybe()
+	eventChan := make(chan *dto.GameEvent, 1)
+	mockNotifier.EXPECT().Subscribe("m1").
+		Return(mockSub, (<-chan *dto.GameEvent)(eventChan)).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: dto.StatePlaying, Turn: "p2", Version: 1}, nil).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: dto.StatePlaying, Turn: "p1", Version: 2}, nil).
+		Maybe()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.NewContext(r, w)
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		c.Set("player_id", "p1")
+		assert.NoError(t, h.StreamMatchEvents(c))
+	}))
+	defer ts.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var evt dto.WSEvent
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Nil(t, evt.Attack)
+
+	shot := dto.AttackEventData{Attacker: "p2", X: 1, Y: 2, Coord: "B3", Result: "hit"}
+	eventChan <- &dto.GameEvent{Type: dto.EventAttackMade, MatchID: "m1", PlayerID: "p2", Data: shot}
+
+	evt = dto.WSEvent{}
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Equal(t, "game_update", evt.Type)
+	require.NotNil(t, evt.Payload)
+	assert.Equal(t, 2, evt.Payload.Version)
+	require.NotNil(t, evt.Attack)
+	assert.Equal(t, shot, *evt.Attack)
+}
+
+func TestStreamMatchEvents_Close(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		query      string
+		types      []any
+		event      *dto.GameEvent // Sent before the channel is closed, if any
+		closedWith string         // Reason the subscription reports once its channel is closed
+		wantType   string         // Last message before the close frame, if any
+		wantReason string
+	}{
+		{
+			name:       "Game Over",
+			event:      &dto.GameEvent{Type: dto.EventGameOver, MatchID: "m1"},
+			wantType:   "game_update",
+			wantReason: dto.StreamClosedGameOver,
+		},
+		{
+			name:       "Terminated",
+			event:      &dto.GameEvent{Type: dto.EventMatchTerminated, MatchID: "m1"},
+			wantType:   "game_event",
+			wantReason: dto.StreamClosedMatchTerminated,
+		},
+		{
+			name:       "Cleaned Up",
+			wantReason: dto.StreamClosedIdleTimeout,
+		},
+		{
+			name:       "Cancelled While Filtered",
+			query:      "?events=attack.made",
+			types:      []any{dto.EventAttackMade},
+			closedWith: dto.StreamClosedMatchCancelled,
+			wantReason: dto.StreamClosedMatchCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, mockNotifier := setupTest(t)
+			h = NewEchoHandler(h.ctrl, WithCoalesceWindow(time.Hour))
+
+			mockSub := mocks.NewMockSubscription(t)
+			mockSub.EXPECT().Unsubscribe().Return().Maybe()
+			mockSub.EXPECT().Reason().Return(tt.closedWith).Maybe()
+			eventChan := make(chan *dto.GameEvent, 1)
+			mockNotifier.EXPECT().Subscribe("m1", tt.types...).
+				Return(mockSub, (<-chan *dto.GameEvent)(eventChan)).
+				Once()
+			mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+				Return(dto.GameView{State: dto.StatePlaying, Version: 1}, nil).
+				Once()
+			mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+				Return(dto.GameView{State: dto.StateFinished, Version: 2}, nil).
+				Maybe()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c := e.NewContext(r, w)
+				c.SetParamNames("id")
+				c.SetParamValues("m1")
+				c.Set("player_id", "p1")
+				assert.NoError(t, h.StreamMatchEvents(c))
+			}))
+			defer ts.Close()
+
+			ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws"+tt.query, nil)
+			require.NoError(t, err)
+			defer ws.Close()
+
+			var evt dto.WSEvent
+			require.NoError(t, ws.ReadJSON(&evt))
+
+			if tt.event != nil {
+				eventChan <- tt.event
+			} else {
+				close(eventChan)
+			}
+			if tt.wantType != "" {
+				evt = dto.WSEvent{}
+				require.NoError(t, ws.ReadJSON(&evt))
+				assert.Equal(t, tt.wantType, evt.Type, "the end is not held back by the coalesce window")
+			}
+
+			err = ws.ReadJSON(&evt)
+			var closeErr *websocket.CloseError
+			require.ErrorAs(t, err, &closeErr)
+			assert.Equal(t, websocket.CloseNormalClosure, closeErr.Code)
+			assert.Equal(t, tt.wantReason, closeErr.Text)
+		})
+	}
+}
+
+func TestStreamMatchEvents_Origin(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{name: "No Origin", origin: "", allowed: true},
+		{name: "Allowed Origin", origin: "https://battleship.example", allowed: true},
+		{name: "Same Origin", origin: "same", allowed: true},
+		{name: "Disallowed Origin", origin: "https://evil.example", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, _, mockGame, mockNotifier := setupTest(t)
+			h = NewEchoHandler(h.ctrl, WithAllowedOrigins([]string{"https://battleship.example"}))
+
+			if tt.allowed {
+				mockSub := mocks.NewMockSubscription(t)
+				mockSub.EXPECT().Unsubscribe().Return().Maybe()
+				mockNotifier.EXPECT().Subscribe("m1").
+					Return(mockSub, (<-chan *dto.GameEvent)(make(chan *dto.GameEvent))).
+					Once()
+				mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+					Return(dto.GameView{State: "WAITING", Version: 1}, nil).
+					Once()
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c := e.NewContext(r, w)
+				c.SetParamNames("id")
+				c.SetParamValues("m1")
+				c.Set("player_id", "p1")
+
+				if err := h.StreamMatchEvents(c); err != nil {
+					e.HTTPErrorHandler(err, c)
+				}
+			}))
+			defer ts.Close()
+
+			header := http.Header{}
+			switch tt.origin {
+			case "":
+			case "same":
+				header.Set("Origin", ts.URL)
+			default:
+				header.Set("Origin", tt.origin)
+			}
+
+			ws, resp, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws", header)
+			if !tt.allowed {
+				require.Error(t, err)
+				require.NotNil(t, resp)
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				return
+			}
+
+			require.NoError(t, err)
+			defer ws.Close()
+
+			var evt dto.WSEvent
+			require.NoError(t, ws.ReadJSON(&evt))
+			assert.Equal(t, "game_update", evt.Type)
+		})
+	}
+}
+
+func TestStreamMatchEvents_DropsSilentPeer(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+	h = NewEchoHandler(h.ctrl, WithKeepalive(20*time.Millisecond, 100*time.Millisecond))
+
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Return().Once()
+	mockNotifier.EXPECT().Subscribe("m1").
+		Return(mockSub, (<-chan *dto.GameEvent)(make(chan *dto.GameEvent))).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: "WAITING", Version: 1}, nil).
+		Once()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.NewContext(r, w)
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		c.Set("player_id", "p1")
+		assert.NoError(t, h.StreamMatchEvents(c))
+	}))
+	defer ts.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	// Never answer pings
+	pings := 0
+	ws.SetPingHandler(func(string) error {
+		pings++
+		return nil
+	})
+
+	var evt dto.WSEvent
+	require.NoError(t, ws.ReadJSON(&evt))
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err = ws.ReadMessage()
+	require.Error(t, err, "Server should close the connection")
+
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) {
+		assert.False(t, netErr.Timeout(), "Connection should be closed by the server, not time out")
+	}
+	assert.Positive(t, pings, "Server should have sent pings")
+}
+
+func TestStreamMatchEvents_Backfill(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Return().Maybe()
+	mockNotifier.EXPECT().Subscribe("m1").
+		Return(mockSub, (<-chan *dto.GameEvent)(make(chan *dto.GameEvent))).
+		Once()
+
+	missed := []dto.GameEvent{
+		{Type: dto.EventAttackMade, PlayerID: "p2", Version: 3},
+		{Type: dto.EventAttackMade, PlayerID: "p1", Version: 4},
+	}
+	mockGame.EXPECT().EventsSince(mock.Anything, "m1", "p1", 2).
+		Return(missed, nil).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: "PLAYING", Version: 4}, nil).
+		Once()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.NewContext(r, w)
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		c.Set("player_id", "p1")
+		assert.NoError(t, h.StreamMatchEvents(c))
+	}))
+	defer ts.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws?since=2", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	for _, want := range missed {
+		var evt dto.WSEvent
+		require.NoError(t, ws.ReadJSON(&evt))
+		assert.Equal(t, "game_event", evt.Type)
+		require.NotNil(t, evt.Event)
+		assert.Equal(t, want.Version, evt.Event.Version)
+	}
+
+	var evt dto.WSEvent
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Equal(t, "game_update", evt.Type)
+	assert.Equal(t, 4, evt.Payload.Version)
+}
+
+func TestStreamMatchEvents_Coalesce(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+	h = NewEchoHandler(h.ctrl, WithCoalesceWindow(time.Hour))
+
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Return().Maybe()
+	eventChan := make(chan *dto.GameEvent, 10)
+	mockNotifier.EXPECT().Subscribe("m1").
+		Return(mockSub, (<-chan *dto.GameEvent)(eventChan)).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: dto.StatePlaying, Version: 1}, nil).
+		Once()
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		Return(dto.GameView{State: dto.StateFinished, Winner: "p1", Version: 4}, nil).
+		Once()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.NewContext(r, w)
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		c.Set("player_id", "p1")
+		assert.NoError(t, h.StreamMatchEvents(c))
+	}))
+	defer ts.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var evt dto.WSEvent
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Equal(t, 1, evt.Payload.Version)
+
+	// The window never ends, so only the end of the game lets the burst through
+	first := dto.AttackEventData{Attacker: "p1", X: 0, Y: 0, Result: "hit"}
+	last := dto.AttackEventData{Attacker: "p1", X: 1, Y: 0, Result: "sunk"}
+	eventChan <- &dto.GameEvent{Type: dto.EventAttackMade, Data: first}
+	eventChan <- &dto.GameEvent{Type: dto.EventAttackMade, Data: last}
+	eventChan <- &dto.GameEvent{Type: dto.EventGameOver}
+
+	evt = dto.WSEvent{}
+	require.NoError(t, ws.ReadJSON(&evt))
+	assert.Equal(t, "game_update", evt.Type)
+	assert.Equal(t, 4, evt.Payload.Version, "the burst is sent as the latest state")
+	require.NotNil(t, evt.Attack)
+	assert.Equal(t, last, *evt.Attack, "the update carries the latest shot")
+}
+
+func TestStreamMatchEvents_DropsStuckReader(t *testing.T) {
+	t.Parallel()
+	e, h, _, _, mockGame, mockNotifier := setupTest(t)
+	h = NewEchoHandler(h.ctrl,
+		WithWriteTimeout(50*time.Millisecond),
+		WithWebSocketBuffers(512, 512),
+		WithCoalesceWindow(0), // One update per event
+	)
+
+	unsubscribed := make(chan struct{})
+	mockSub := mocks.NewMockSubscription(t)
+	mockSub.EXPECT().Unsubscribe().Run(func() { close(unsubscribed) }).Return().Once()
+
+	// Keep the stream busy with large, always-changing updates
+	eventChan := make(chan *dto.GameEvent, 1000)
+	for range cap(eventChan) {
+		eventChan <- &dto.GameEvent{Type: dto.EventAttackMade}
+	}
+	mockNotifier.EXPECT().Subscribe("m1").
+		Return(mockSub, (<-chan *dto.GameEvent)(eventChan)).
+		Once()
+
+	version := 0
+	bigTurn := strings.Repeat("x", 64*1024)
+	mockGame.EXPECT().GetState(mock.Anything, "m1", "p1").
+		RunAndReturn(func(context.Context, string, string) (dto.GameView, error) {
+			version++
+			return dto.GameView{State: "PLAYING", Turn: bigTurn, Version: version}, nil
+		}).
+		Maybe()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.NewContext(r, w)
+		c.SetParamNames("id")
+		c.SetParamValues("m1")
+		c.Set("player_id", "p1")
+		assert.NoError(t, h.StreamMatchEvents(c))
+	}))
+	defer ts.Close()
+
+	// Connect and never read
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+ts.URL[4:]+"/matches/m1/ws", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	select {
+	case <-unsubscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stuck client should be dropped after the write timeout")
+	}
 }
@@ -1,12 +1,85 @@
 package server
 
 import (
+	"crypto/rsa"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
 
+	"github.com/callegarimattia/battleship/internal/dto"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
 )
 
+// ParseToken returns a token parser for the JWT middleware (echojwt.Config.ParseTokenFunc).
+// The key selects the signing method: a shared secret ([]byte) accepts HS256 tokens only,
+// an *rsa.PublicKey RS256 tokens only.
+// Besides the signature and expiry it checks the issuer and audience claims when they are set,
+// so a token minted for another service sharing the secret is rejected.
+func ParseToken(key any, issuer, audience string) func(echo.Context, string) (any, error) {
+	method := jwt.SigningMethodHS256.Alg()
+	if _, ok := key.(*rsa.PublicKey); ok {
+		method = jwt.SigningMethodRS256.Alg()
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{method})}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	parser := jwt.NewParser(opts...)
+
+	return func(_ echo.Context, auth string) (any, error) {
+		token, err := parser.Parse(auth, func(*jwt.Token) (any, error) { return key, nil })
+		if err != nil {
+			return nil, err
+		}
+		return token, nil
+	}
+}
+
+// LoadRSAPublicKey reads a PEM encoded RSA public key to verify RS256 tokens.
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path comes from the server configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// APIKeyHeader carries the key trusted integrations authenticate with.
+const APIKeyHeader = "X-API-Key"
+
+// RequireAPIKey only lets through requests presenting the given key in the X-API-Key header.
+func RequireAPIKey(key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			got := c.Request().Header.Get(APIKeyHeader)
+			if key == "" || subtle.ConstantTimeCompare([]byte(got), []byte(key)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or missing API key")
+			}
+			return next(c)
+		}
+	}
+}
+
 // RequirePlayerID extracts the user ID from the JWT and validates it.
 // It sets "player_id" in the context.
 func RequirePlayerID(next echo.HandlerFunc) echo.HandlerFunc {
@@ -32,3 +105,189 @@ func RequirePlayerID(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
+
+// WebSocketTokenSubprotocol is the subprotocol a browser offers, followed by the token itself,
+// to authenticate a WebSocket handshake: new WebSocket(url, ["bearer", token]).
+const WebSocketTokenSubprotocol = "bearer"
+
+// WebSocketToken lets WebSocket handshakes authenticate without an Authorization header,
+// which browsers cannot set. When the header is missing, the token is taken from the
+// "token" query parameter or from the subprotocol list and moved into the header,
+// so the regular JWT middleware can validate it.
+func WebSocketToken(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if req.Header.Get(echo.HeaderAuthorization) != "" {
+			return next(c)
+		}
+
+		token := c.QueryParam("token")
+		if token == "" {
+			token = subprotocolToken(req)
+		}
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+
+		return next(c)
+	}
+}
+
+// subprotocolToken returns the protocol offered right after WebSocketTokenSubprotocol, if any.
+func subprotocolToken(r *http.Request) string {
+	var protocols []string
+	for _, value := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for p := range strings.SplitSeq(value, ",") {
+			protocols = append(protocols, strings.TrimSpace(p))
+		}
+	}
+
+	for i, p := range protocols {
+		if p == WebSocketTokenSubprotocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
+// RequestLogger logs every request like echo's default request logger, except that the "token"
+// query parameter WebSocket handshakes may authenticate with is redacted from the logged URI.
+func RequestLogger(logger *slog.Logger) echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogLatency:       true,
+		LogRemoteIP:      true,
+		LogHost:          true,
+		LogMethod:        true,
+		LogURI:           true,
+		LogRequestID:     true,
+		LogUserAgent:     true,
+		LogStatus:        true,
+		LogError:         true,
+		LogContentLength: true,
+		LogResponseSize:  true,
+		HandleError:      true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", redactToken(v.URI)),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("host", v.Host),
+				slog.String("bytes_in", v.ContentLength),
+				slog.Int64("bytes_out", v.ResponseSize),
+				slog.String("user_agent", v.UserAgent),
+				slog.String("remote_ip", v.RemoteIP),
+				slog.String("request_id", v.RequestID),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("err", v.Error.Error()))
+				logger.LogAttrs(c.Request().Context(), slog.LevelError, "REQUEST_ERROR", attrs...)
+				return nil
+			}
+			logger.LogAttrs(c.Request().Context(), slog.LevelInfo, "REQUEST", attrs...)
+			return nil
+		},
+	})
+}
+
+// redactToken replaces the value of the "token" query parameter in uri, if any.
+// A URI that does not parse is logged by its path alone, so that no token slips through.
+func redactToken(uri string) string {
+	u, err := url.ParseRequestURI(uri)
+	if err != nil {
+		path, _, _ := strings.Cut(uri, "?")
+		return path
+	}
+	q := u.Query()
+	if !q.Has("token") {
+		return uri
+	}
+	q.Set("token", "REDACTED")
+	u.RawQuery = q.Encode()
+	return u.RequestURI()
+}
+
+// Recover turns a panic in a later handler into a plain 500, logged with its stack and with the
+// match and player the request was about, so that it can be traced back to a game.
+// Nothing about the panic is told to the client. An aborted response panics on, as net/http expects.
+func Recover(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if e, ok := r.(error); ok && errors.Is(e, http.ErrAbortHandler) {
+					panic(r)
+				}
+
+				req := c.Request()
+				attrs := []any{"panic", r, "method", req.Method, "route", c.Path(), "stack", string(debug.Stack())}
+				if matchID := c.Param("id"); matchID != "" {
+					attrs = append(attrs, "match_id", matchID)
+				}
+				if playerID, ok := c.Get("player_id").(string); ok {
+					attrs = append(attrs, "player_id", playerID)
+				}
+				logger.ErrorContext(req.Context(), "Recovered from panic", attrs...)
+
+				err = echo.NewHTTPError(http.StatusInternalServerError, "Internal server error")
+			}()
+			return next(c)
+		}
+	}
+}
+
+// RateLimit allows each client IP limit requests per second. Requests over the limit get a 429
+// with a Retry-After header and a body coded RATE_LIMITED so that clients can back off without
+// parsing the message. Retry-After is the fixed time the limiter takes to refill one request,
+// in whole seconds rounded up, not read from the client's own limiter: a denied client has less
+// than one request left, so it is the longest they may have to wait.
+func RateLimit(limit rate.Limit) echo.MiddlewareFunc {
+	retryAfter := strconv.Itoa(retryAfterSeconds(limit))
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		// A burst of at least one, as echo would round a limit below one request per second down to zero
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:  limit,
+			Burst: max(1, int(limit)),
+		}),
+		DenyHandler: func(c echo.Context, _ string, _ error) error {
+			c.Response().Header().Set("Retry-After", retryAfter)
+			return echo.NewHTTPError(http.StatusTooManyRequests, dto.ErrorResponse{
+				Message: "Too many requests",
+				Code:    dto.ErrorCodeRateLimited,
+			})
+		},
+	})
+}
+
+// retryAfterSeconds is how long a limiter allowing limit requests per second takes to grant
+// another one, rounded up to the whole seconds Retry-After is given in.
+func retryAfterSeconds(limit rate.Limit) int {
+	if limit <= 0 {
+		return 1
+	}
+	return max(1, int(math.Ceil(1/float64(limit))))
+}
+
+// matchIDPrefix starts every match ID the lobby hands out, followed by a UUID.
+const matchIDPrefix = "game-"
+
+// ValidMatchID rejects requests whose :id parameter is not shaped like a match ID, e.g. "game-<uuid>",
+// with a 400 before anything looks the match up. Routes without the parameter are let through.
+func ValidMatchID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if id := c.Param("id"); id != "" && !isMatchID(id) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed match ID")
+		}
+		return next(c)
+	}
+}
+
+// isMatchID reports whether id is the prefix followed by a UUID in its canonical 36 character form.
+// The length is checked first so that huge IDs are turned down without parsing them.
+func isMatchID(id string) bool {
+	rest, ok := strings.CutPrefix(id, matchIDPrefix)
+	return ok && len(rest) == 36 && uuid.Validate(rest) == nil
+}
@@ -1,15 +1,24 @@
 package server
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/callegarimattia/battleship/internal/dto"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 func TestRequirePlayerID(t *testing.T) {
@@ -119,3 +128,394 @@ func TestRequirePlayerID(t *testing.T) {
 		})
 	}
 }
+
+func TestParseToken(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		require.NoError(t, err)
+		return token
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name        string
+		issuer      string
+		audience    string
+		claims      jwt.MapClaims
+		expectError bool
+	}{
+		{
+			name:     "Matching Issuer And Audience",
+			issuer:   "battleship",
+			audience: "battleship-api",
+			claims:   jwt.MapClaims{"sub": "p1", "exp": exp, "iss": "battleship", "aud": "battleship-api"},
+		},
+		{
+			name:        "Wrong Issuer",
+			issuer:      "battleship",
+			audience:    "battleship-api",
+			claims:      jwt.MapClaims{"sub": "p1", "exp": exp, "iss": "other", "aud": "battleship-api"},
+			expectError: true,
+		},
+		{
+			name:        "Wrong Audience",
+			issuer:      "battleship",
+			audience:    "battleship-api",
+			claims:      jwt.MapClaims{"sub": "p1", "exp": exp, "iss": "battleship", "aud": "other-api"},
+			expectError: true,
+		},
+		{
+			name:        "Missing Claims",
+			issuer:      "battleship",
+			audience:    "battleship-api",
+			claims:      jwt.MapClaims{"sub": "p1", "exp": exp},
+			expectError: true,
+		},
+		{
+			name:   "Claims Not Required",
+			claims: jwt.MapClaims{"sub": "p1", "exp": exp},
+		},
+		{
+			name:        "Expired",
+			claims:      jwt.MapClaims{"sub": "p1", "exp": time.Now().Add(-time.Hour).Unix()},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parse := ParseToken(secret, tt.issuer, tt.audience)
+			token, err := parse(nil, sign(tt.claims))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, &jwt.Token{}, token)
+		})
+	}
+
+	t.Run("Wrong Secret", func(t *testing.T) {
+		t.Parallel()
+
+		parse := ParseToken([]byte("another-secret"), "", "")
+		_, err := parse(nil, sign(jwt.MapClaims{"sub": "p1", "exp": exp}))
+		assert.Error(t, err)
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		claims := jwt.MapClaims{"sub": "p1", "exp": exp}
+
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+		require.NoError(t, err)
+
+		parse := ParseToken(&key.PublicKey, "", "")
+		_, err = parse(nil, signed)
+		require.NoError(t, err)
+
+		// A public key never validates HS256 tokens, whatever secret signed them
+		_, err = parse(nil, sign(claims))
+		assert.Error(t, err)
+	})
+}
+
+func TestWebSocketToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		target       string
+		headers      map[string]string
+		expectedAuth string
+	}{
+		{
+			name:         "Authorization Header Kept",
+			target:       "/?token=from-query",
+			headers:      map[string]string{echo.HeaderAuthorization: "Bearer from-header"},
+			expectedAuth: "Bearer from-header",
+		},
+		{
+			name:         "Query Parameter",
+			target:       "/?token=from-query",
+			expectedAuth: "Bearer from-query",
+		},
+		{
+			name:         "Subprotocol",
+			target:       "/",
+			headers:      map[string]string{"Sec-WebSocket-Protocol": "bearer, from-protocol"},
+			expectedAuth: "Bearer from-protocol",
+		},
+		{
+			name:         "Subprotocol Without Token",
+			target:       "/",
+			headers:      map[string]string{"Sec-WebSocket-Protocol": "bearer"},
+			expectedAuth: "",
+		},
+		{
+			name:         "No Token",
+			target:       "/",
+			expectedAuth: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var gotAuth string
+			next := func(c echo.Context) error {
+				gotAuth = c.Request().Header.Get(echo.HeaderAuthorization)
+				return nil
+			}
+
+			handler := WebSocketToken(next)
+			err := handler(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAuth, gotAuth)
+		})
+	}
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		key         string
+		header      string
+		expectError bool
+	}{
+		{name: "Matching Key", key: "secret", header: "secret"},
+		{name: "Wrong Key", key: "secret", header: "guess", expectError: true},
+		{name: "Missing Key", key: "secret", expectError: true},
+		{name: "No Key Configured", key: "", header: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/login/platform", nil)
+			if tt.header != "" {
+				req.Header.Set(APIKeyHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			called := false
+			next := func(c echo.Context) error {
+				called = true
+				return nil
+			}
+
+			handler := RequireAPIKey(tt.key)(next)
+			err := handler(c)
+
+			if tt.expectError {
+				he := &echo.HTTPError{}
+				require.ErrorAs(t, err, &he)
+				assert.Equal(t, http.StatusUnauthorized, he.Code)
+				assert.False(t, called)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, called)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		limit      rate.Limit
+		retryAfter string
+	}{
+		{name: "Several Per Second", limit: 1, retryAfter: "1"},
+		{name: "Fewer Than One Per Second", limit: 0.25, retryAfter: "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Use(RateLimit(tt.limit))
+			e.GET("/lobby", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+			send := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, "/lobby", nil)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				return rec
+			}
+
+			first := send()
+			assert.Equal(t, http.StatusOK, first.Code)
+			assert.Empty(t, first.Header().Get("Retry-After"))
+
+			limited := send()
+			require.Equal(t, http.StatusTooManyRequests, limited.Code)
+			assert.Equal(t, tt.retryAfter, limited.Header().Get("Retry-After"))
+
+			var body dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(limited.Body.Bytes(), &body))
+			assert.Equal(t, dto.ErrorCodeRateLimited, body.Code)
+			assert.NotEmpty(t, body.Message)
+		})
+	}
+}
+
+func TestValidMatchID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		id          string
+		expectError bool
+	}{
+		{name: "Match ID", id: "game-3f2c9a4e-8d1b-4c55-9a3e-2b7f0c1d6e8a"},
+		{name: "No ID", id: ""},
+		{name: "Other Prefix", id: "tournament-3f2c9a4e-8d1b-4c55-9a3e-2b7f0c1d6e8a", expectError: true},
+		{name: "Not A UUID", id: "game-not-a-uuid", expectError: true},
+		{name: "Braced UUID", id: "game-{3f2c9a4e-8d1b-4c55-9a3e-2b7f0c1d6e8a}", expectError: true},
+		{name: "Huge", id: "game-" + strings.Repeat("a", 10000), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/matches/x", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(tt.id)
+			}
+
+			called := false
+			err := ValidMatchID(func(echo.Context) error {
+				called = true
+				return nil
+			})(c)
+
+			if tt.expectError {
+				he := &echo.HTTPError{}
+				require.ErrorAs(t, err, &he)
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.False(t, called)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, called)
+			}
+		})
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		target  string
+		wantURI string
+	}{
+		{
+			name:    "Token Redacted",
+			target:  "/matches/m1/ws?since=3&token=secret.jwt",
+			wantURI: "/matches/m1/ws?since=3&token=REDACTED",
+		},
+		{name: "No Token", target: "/matches/m1?since=3", wantURI: "/matches/m1?since=3"},
+		{name: "No Query", target: "/health", wantURI: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+			e := echo.New()
+			e.Use(RequestLogger(slog.New(slog.NewJSONHandler(&logs, nil))))
+			e.GET("/*", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, "REQUEST", entry["msg"])
+			assert.Equal(t, tt.wantURI, entry["uri"])
+			assert.InDelta(t, http.StatusOK, entry["status"], 0)
+			assert.NotContains(t, logs.String(), "secret.jwt")
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	e := echo.New()
+	e.Use(Recover(logger))
+	withPlayer := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("player_id", "p1")
+			return next(c)
+		}
+	}
+	e.GET("/matches/:id", func(c echo.Context) error {
+		var view *struct{ Turn string }
+		return c.String(http.StatusOK, view.Turn) // Nil pointer dereference
+	}, withPlayer)
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/matches/m1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"message":"Internal server error"}`, rec.Body.String(), "nothing about the panic leaks")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "Recovered from panic", entry["msg"])
+	assert.Equal(t, "m1", entry["match_id"])
+	assert.Equal(t, "p1", entry["player_id"])
+	assert.Equal(t, "/matches/:id", entry["route"])
+	assert.Contains(t, entry["panic"], "nil pointer dereference")
+	assert.Contains(t, entry["stack"], "TestRecover")
+
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "requests that do not panic go through untouched")
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+// JSON-RPC 2.0 error codes. Errors of the game itself use the HTTP status the REST API would answer with.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// errRPCParams is returned by a method whose params could not be decoded or are missing a field.
+var errRPCParams = errors.New("invalid params")
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"` // Absent for notifications, which get no response
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"` // Every problem found, when the call failed validation
+}
+
+// rpcMethod runs one JSON-RPC method for the player with the given params.
+type rpcMethod func(h *EchoHandler, ctx context.Context, playerID string, params json.RawMessage) (any, error)
+
+// rpcMethods are the game actions offered over JSON-RPC, matching the REST routes.
+var rpcMethods = map[string]rpcMethod{
+	"match.host":   (*EchoHandler).rpcHost,
+	"match.join":   (*EchoHandler).rpcJoin,
+	"match.place":  (*EchoHandler).rpcPlace,
+	"match.ready":  (*EchoHandler).rpcReady,
+	"match.attack": (*EchoHandler).rpcAttack,
+	"match.state":  (*EchoHandler).rpcState,
+}
+
+// RPC runs a JSON-RPC 2.0 call, for integrators who prefer it to the REST routes.
+// Logging in and following a match stay on /login and the WebSocket.
+// POST /rpc
+func (h *EchoHandler) RPC(c echo.Context) error {
+	var req rpcRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusOK, rpcFailure(nil, rpcParseError, "Parse error"))
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return c.JSON(http.StatusOK, rpcFailure(req.ID, rpcInvalidRequest, "Invalid Request"))
+	}
+
+	method, ok := rpcMethods[req.Method]
+	if !ok {
+		return c.JSON(http.StatusOK, rpcFailure(req.ID, rpcMethodNotFound, "Method not found"))
+	}
+
+	playerID := c.Get("player_id").(string)
+	result, err := method(h, c.Request().Context(), playerID, req.Params)
+	if len(req.ID) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+	if err != nil {
+		failure := rpcFailure(req.ID, rpcErrorCode(err), err.Error())
+		var invalid *controller.ValidationError
+		if errors.As(err, &invalid) {
+			failure.Error.Data = map[string][]string{"problems": invalid.Messages()}
+		}
+		return c.JSON(http.StatusOK, failure)
+	}
+
+	return c.JSON(http.StatusOK, rpcResponse{JSONRPC: "2.0", Result: result, ID: req.ID})
+}
+
+func rpcFailure(id json.RawMessage, code int, message string) rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: code, Message: message}, ID: id}
+}
+
+// rpcErrorCode picks the code of a failed call, the same status the REST routes answer with.
+func rpcErrorCode(err error) int {
+	switch {
+	case errors.Is(err, errRPCParams):
+		return rpcInvalidParams
+	case errors.Is(err, controller.ErrMatchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, controller.ErrNotHost):
+		return http.StatusForbidden
+	case errors.Is(err, controller.ErrTooManyActiveGames), errors.Is(err, controller.ErrVersionConflict):
+		return http.StatusConflict
+	case errors.Is(err, controller.ErrServerAtCapacity):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// matchParams are the params of every method acting on an existing match.
+type matchParams struct {
+	MatchID string `json:"match_id"`
+}
+
+// decodeParams reads the params of a call. Methods on a match require a well-formed match ID.
+func decodeParams[T any](raw json.RawMessage, matchID func(*T) string) (T, error) {
+	var params T
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return params, fmt.Errorf("%w: %w", errRPCParams, err)
+		}
+	}
+	if matchID != nil && !isMatchID(matchID(&params)) {
+		return params, fmt.Errorf("%w: invalid match ID", errRPCParams)
+	}
+	return params, nil
+}
+
+func (h *EchoHandler) rpcHost(ctx context.Context, playerID string, raw json.RawMessage) (any, error) {
+	settings, err := decodeParams[dto.MatchSettings](raw, nil)
+	if err != nil {
+		return nil, err
+	}
+	matchID, err := h.ctrl.HostGameAction(ctx, playerID, settings)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"match_id": matchID}, nil
+}
+
+func (h *EchoHandler) rpcJoin(ctx context.Context, playerID string, raw json.RawMessage) (any, error) {
+	params, err := decodeParams(raw, func(p *matchParams) string { return p.MatchID })
+	if err != nil {
+		return nil, err
+	}
+	return h.ctrl.JoinGameAction(ctx, params.MatchID, playerID)
+}
+
+func (h *EchoHandler) rpcPlace(ctx context.Context, playerID string, raw json.RawMessage) (any, error) {
+	type placeParams struct {
+		matchParams
+		Ship     string `json:"ship"`
+		Size     int    `json:"size"`
+		X        int    `json:"x"`
+		Y        int    `json:"y"`
+		Vertical bool   `json:"vertical"`
+		Version  *int   `json:"version"`
+	}
+	params, err := decodeParams(raw, func(p *placeParams) string { return p.MatchID })
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = expectVersion(ctx, params.Version)
+	if params.Ship != "" {
+		return h.ctrl.PlaceShipTypeAction(ctx, params.MatchID, playerID, params.Ship, params.X, params.Y, params.Vertical)
+	}
+	return h.ctrl.PlaceShipAction(ctx, params.MatchID, playerID, params.Size, params.X, params.Y, params.Vertical)
+}
+
+func (h *EchoHandler) rpcReady(ctx context.Context, playerID string, raw json.RawMessage) (any, error) {
+	params, err := decodeParams(raw, func(p *matchParams) string { return p.MatchID })
+	if err != nil {
+		return nil, err
+	}
+	return h.ctrl.ReadyAction(ctx, params.MatchID, playerID)
+}
+
+func (h *EchoHandler) rpcAttack(ctx context.Context, playerID string, raw json.RawMessage) (any, error) {
+	type attackParams struct {
+		matchParams
+		Target  string `json:"target"`
+		X       int    `json:"x"`
+		Y       int    `json:"y"`
+		Version *int   `json:"version"`
+	}
+	params, err := decodeParams(raw, func(p *attackParams) string { return p.MatchID })
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = expectVersion(ctx, params.Version)
+	if params.Target != "" {
+		return h.ctrl.AttackTargetAction(ctx, params.MatchID, playerID, params.Target, params.X, params.Y)
+	}
+	return h.ctrl.AttackAction(ctx, params.MatchID, playerID, params.X, params.Y)
+}
+
+func (h *EchoHandler) rpcState(ctx context.Context, playerID string, raw json.RawMessage) (any, error) {
+	params, err := decodeParams(raw, func(p *matchParams) string { return p.MatchID })
+	if err != nil {
+		return nil, err
+	}
+	return h.ctrl.GetGameStateAction(ctx, params.MatchID, playerID)
+}
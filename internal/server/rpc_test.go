@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	mocks "github.com/callegarimattia/battleship/internal/mocks/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPC(t *testing.T) {
+	t.Parallel()
+	const matchID = "game-00000000-0000-0000-0000-000000000000"
+
+	tests := []struct {
+		name           string
+		reqBody        any
+		mockSetup      func(*mocks.MockLobbyService, *mocks.MockGameService)
+		expectedStatus int
+		expectedResult string
+		expectedCode   int
+	}{
+		{
+			name:    "Attack",
+			reqBody: `{"jsonrpc":"2.0","id":1,"method":"match.attack","params":{"match_id":"` + matchID + `","x":5,"y":5,"version":3}}`,
+			mockSetup: func(_ *mocks.MockLobbyService, m *mocks.MockGameService) {
+				m.EXPECT().Attack(expectsVersion(3), matchID, "p1", 5, 5).
+					Return(dto.GameView{State: "PLAYING"}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedResult: "PLAYING",
+		},
+		{
+			name:    "Host",
+			reqBody: `{"jsonrpc":"2.0","id":"a","method":"match.host","params":{"board_size":8}}`,
+			mockSetup: func(m *mocks.MockLobbyService, _ *mocks.MockGameService) {
+				m.EXPECT().CreateMatch(mock.Anything, "p1", dto.MatchSettings{BoardSize: 8}).
+					Return(matchID, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedResult: matchID,
+		},
+		{
+			name:    "Match Not Found",
+			reqBody: `{"jsonrpc":"2.0","id":2,"method":"match.state","params":{"match_id":"` + matchID + `"}}`,
+			mockSetup: func(_ *mocks.MockLobbyService, m *mocks.MockGameService) {
+				m.EXPECT().GetState(mock.Anything, matchID, "p1").
+					Return(dto.GameView{}, controller.ErrMatchNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedCode:   http.StatusNotFound,
+		},
+		{
+			name:           "Malformed Match ID",
+			reqBody:        `{"jsonrpc":"2.0","id":3,"method":"match.join","params":{"match_id":"nope"}}`,
+			mockSetup:      func(*mocks.MockLobbyService, *mocks.MockGameService) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   rpcInvalidParams,
+		},
+		{
+			name:           "Unknown Method",
+			reqBody:        `{"jsonrpc":"2.0","id":4,"method":"match.cheat"}`,
+			mockSetup:      func(*mocks.MockLobbyService, *mocks.MockGameService) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   rpcMethodNotFound,
+		},
+		{
+			name:           "Not JSON-RPC",
+			reqBody:        `{"method":"match.state","id":5}`,
+			mockSetup:      func(*mocks.MockLobbyService, *mocks.MockGameService) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   rpcInvalidRequest,
+		},
+		{
+			name:           "Parse Error",
+			reqBody:        "{bad",
+			mockSetup:      func(*mocks.MockLobbyService, *mocks.MockGameService) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   rpcParseError,
+		},
+		{
+			name:    "Notification",
+			reqBody: `{"jsonrpc":"2.0","method":"match.ready","params":{"match_id":"` + matchID + `"}}`,
+			mockSetup: func(_ *mocks.MockLobbyService, m *mocks.MockGameService) {
+				m.EXPECT().Ready(mock.Anything, matchID, "p1").
+					Return(dto.GameView{}, nil).
+					Once()
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, _, mockLobby, mockGame, _ := setupTest(t)
+			tt.mockSetup(mockLobby, mockGame)
+
+			req, rec := makeRequest(http.MethodPost, "/rpc", tt.reqBody, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p1")
+
+			require.NoError(t, h.RPC(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusNoContent {
+				return
+			}
+
+			var resp struct {
+				JSONRPC string          `json:"jsonrpc"`
+				Result  json.RawMessage `json:"result"`
+				Error   *rpcError       `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, "2.0", resp.JSONRPC)
+			if tt.expectedCode != 0 {
+				require.NotNil(t, resp.Error)
+				assert.Equal(t, tt.expectedCode, resp.Error.Code)
+				return
+			}
+			assert.Nil(t, resp.Error)
+			assert.Contains(t, string(resp.Result), tt.expectedResult)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+// CreateTournament opens a tournament hosted by the player. The body holds the settings of every match.
+// POST /tournaments
+func (h *EchoHandler) CreateTournament(c echo.Context) error {
+	playerID := c.Get("player_id").(string)
+
+	var settings dto.MatchSettings
+	if err := c.Bind(&settings); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON")
+	}
+
+	view, err := h.ctrl.CreateTournamentAction(c.Request().Context(), playerID, settings)
+	if err != nil {
+		return tournamentError(err)
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// JoinTournament signs the player up for a tournament.
+// POST /tournaments/:id/join
+func (h *EchoHandler) JoinTournament(c echo.Context) error {
+	tournamentID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	view, err := h.ctrl.JoinTournamentAction(c.Request().Context(), tournamentID, playerID)
+	if err != nil {
+		return tournamentError(err)
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// StartTournament closes sign-ups and creates the first round's matches.
+// POST /tournaments/:id/start
+func (h *EchoHandler) StartTournament(c echo.Context) error {
+	tournamentID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	view, err := h.ctrl.StartTournamentAction(c.Request().Context(), tournamentID, playerID)
+	if err != nil {
+		return tournamentError(err)
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// GetTournament returns the bracket and standings of a tournament.
+// GET /tournaments/:id
+func (h *EchoHandler) GetTournament(c echo.Context) error {
+	view, err := h.ctrl.GetTournamentAction(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return tournamentError(err)
+	}
+
+	return c.JSON(http.StatusOK, view)
+}
+
+// tournamentError maps the errors of tournament actions to HTTP errors.
+func tournamentError(err error) error {
+	switch {
+	case errors.Is(err, controller.ErrTournamentNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, controller.ErrNotHost):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, controller.ErrInvalidSettings):
+		return invalidRequest(err)
+	case errors.Is(err, controller.ErrTournamentStarted),
+		errors.Is(err, controller.ErrTournamentFull),
+		errors.Is(err, controller.ErrNotEnoughPlayers),
+		errors.Is(err, controller.ErrTooManyActiveGames):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, controller.ErrServerAtCapacity):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	mocks "github.com/callegarimattia/battleship/internal/mocks/controller"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupTournamentTest(t *testing.T) (*echo.Echo, *EchoHandler, *mocks.MockTournamentService) {
+	e := echo.New()
+	mockTournaments := mocks.NewMockTournamentService(t)
+	ctrl := controller.NewAppController(
+		mocks.NewMockIdentityService(t),
+		mocks.NewMockLobbyService(t),
+		mocks.NewMockGameService(t),
+		mocks.NewMockNotificationService(t),
+		controller.WithTournaments(mockTournaments),
+	)
+	return e, NewEchoHandler(ctrl), mockTournaments
+}
+
+func TestTournamentHandlers(t *testing.T) {
+	t.Parallel()
+	view := dto.TournamentView{ID: "t1", HostID: "p1", State: dto.TournamentRegistering}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		reqBody        any
+		handler        func(*EchoHandler) echo.HandlerFunc
+		mockSetup      func(*mocks.MockTournamentService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Create",
+			method:  http.MethodPost,
+			path:    "/tournaments",
+			reqBody: map[string]any{"board_size": 8},
+			handler: func(h *EchoHandler) echo.HandlerFunc { return h.CreateTournament },
+			mockSetup: func(m *mocks.MockTournamentService) {
+				m.EXPECT().CreateTournament(mock.Anything, "p1", dto.MatchSettings{BoardSize: 8}).
+					Return(view, nil).
+					Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"tournament_id":"t1"`,
+		},
+		{
+			name:    "Create Invalid Settings",
+			method:  http.MethodPost,
+			path:    "/tournaments",
+			reqBody: map[string]any{"vs_bot": true},
+			handler: func(h *EchoHandler) echo.HandlerFunc { return h.CreateTournament },
+			mockSetup: func(m *mocks.MockTournamentService) {
+				m.EXPECT().CreateTournament(mock.Anything, "p1", dto.MatchSettings{VsBot: true}).
+					Return(dto.TournamentView{}, controller.ErrInvalidSettings).
+					Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid match settings",
+		},
+		{
+			name:    "Join Started",
+			method:  http.MethodPost,
+			path:    "/tournaments/t1/join",
+			handler: func(h *EchoHandler) echo.HandlerFunc { return h.JoinTournament },
+			mockSetup: func(m *mocks.MockTournamentService) {
+				m.EXPECT().JoinTournament(mock.Anything, "t1", "p1").
+					Return(dto.TournamentView{}, controller.ErrTournamentStarted).
+					Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "tournament already started",
+		},
+		{
+			name:    "Start Not Host",
+			method:  http.MethodPost,
+			path:    "/tournaments/t1/start",
+			handler: func(h *EchoHandler) echo.HandlerFunc { return h.StartTournament },
+			mockSetup: func(m *mocks.MockTournamentService) {
+				m.EXPECT().StartTournament(mock.Anything, "t1", "p1").
+					Return(dto.TournamentView{}, controller.ErrNotHost).
+					Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "only the host",
+		},
+		{
+			name:    "Get",
+			method:  http.MethodGet,
+			path:    "/tournaments/t1",
+			handler: func(h *EchoHandler) echo.HandlerFunc { return h.GetTournament },
+			mockSetup: func(m *mocks.MockTournamentService) {
+				m.EXPECT().GetTournament(mock.Anything, "t1").Return(view, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"state":"REGISTERING"`,
+		},
+		{
+			name:    "Get Not Found",
+			method:  http.MethodGet,
+			path:    "/tournaments/t1",
+			handler: func(h *EchoHandler) echo.HandlerFunc { return h.GetTournament },
+			mockSetup: func(m *mocks.MockTournamentService) {
+				m.EXPECT().GetTournament(mock.Anything, "t1").
+					Return(dto.TournamentView{}, controller.ErrTournamentNotFound).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "tournament not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, h, mockTournaments := setupTournamentTest(t)
+			tt.mockSetup(mockTournaments)
+
+			req, rec := makeRequest(tt.method, tt.path, tt.reqBody, nil)
+			c := e.NewContext(req, rec)
+			c.Set("player_id", "p1")
+			c.SetParamNames("id")
+			c.SetParamValues("t1")
+
+			err := tt.handler(h)(c)
+			if err != nil {
+				he := &echo.HTTPError{}
+				ok := errors.As(err, &he)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.expectedStatus, he.Code)
+					assert.Contains(t, fmt.Sprint(he.Message), tt.expectedBody)
+				}
+			} else {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
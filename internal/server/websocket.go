@@ -0,0 +1,346 @@
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// Time allowed for the peer to answer a ping before the connection is considered dead
+	defaultPongWait = 60 * time.Second
+	// Pings are sent often enough to get a pong back before the deadline expires
+	defaultPingPeriod = defaultPongWait * 9 / 10
+	// Time allowed to write a single message before a stuck client is dropped
+	defaultWriteTimeout = 10 * time.Second
+	// How long a long-poll request waits for the game to change
+	defaultPollTimeout = 25 * time.Second
+	// Events arriving this close together are streamed as a single update
+	defaultCoalesceWindow = 50 * time.Millisecond
+)
+
+// WithKeepalive sets how often the WebSocket stream pings the client and how long
+// it waits for a pong before dropping the connection.
+func WithKeepalive(pingPeriod, pongWait time.Duration) HandlerOption {
+	return func(h *EchoHandler) {
+		h.pingPeriod = pingPeriod
+		h.pongWait = pongWait
+	}
+}
+
+// WithWriteTimeout sets how long a single WebSocket write may block before the connection is dropped.
+func WithWriteTimeout(d time.Duration) HandlerOption {
+	return func(h *EchoHandler) { h.writeTimeout = d }
+}
+
+// WithCoalesceWindow sets how long the WebSocket stream waits for more events before pushing the latest state,
+// so that a burst of events costs the client a single update. Zero pushes one update per event.
+func WithCoalesceWindow(d time.Duration) HandlerOption {
+	return func(h *EchoHandler) { h.coalesceWindow = d }
+}
+
+// WithWebSocketBuffers sets the WebSocket read and write buffer sizes in bytes.
+// Zero keeps the library default.
+func WithWebSocketBuffers(readSize, writeSize int) HandlerOption {
+	return func(h *EchoHandler) {
+		h.upgrader.ReadBufferSize = readSize
+		h.upgrader.WriteBufferSize = writeSize
+	}
+}
+
+func newUpgrader() websocket.Upgrader {
+	return websocket.Upgrader{
+		// Echoed back to browsers that authenticate with the token subprotocol
+		Subprotocols: []string{WebSocketTokenSubprotocol},
+		CheckOrigin: func(_ *http.Request) bool {
+			return true // Origin is checked by StreamMatchEvents before upgrading
+		},
+	}
+}
+
+// writeJSON sends a message, giving up once the write timeout expires.
+func (h *EchoHandler) writeJSON(ws *websocket.Conn, msg dto.WSEvent) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+		return err
+	}
+	return ws.WriteJSON(msg)
+}
+
+// isOriginAllowed guards against cross-site WebSocket hijacking.
+func (h *EchoHandler) isOriginAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true // Non-browser client
+	}
+
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// readPump consumes incoming frames so that pongs and close frames are processed.
+// The returned channel is closed once the peer goes away or stops answering pings.
+func (h *EchoHandler) readPump(ws *websocket.Conn) <-chan struct{} {
+	done := make(chan struct{})
+
+	_ = ws.SetReadDeadline(time.Now().Add(h.pongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(h.pongWait))
+	})
+
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return done
+}
+
+// eventTypes are the event types a stream can be narrowed to.
+var eventTypes = map[dto.EventType]bool{
+	dto.EventPlayerJoined:    true,
+	dto.EventShipPlaced:      true,
+	dto.EventPlayerReady:     true,
+	dto.EventAttackMade:      true,
+	dto.EventGameStarted:     true,
+	dto.EventGameOver:        true,
+	dto.EventTurnChanged:     true,
+	dto.EventGameAbandoned:   true,
+	dto.EventMatchCancelled:  true,
+	dto.EventPlayerResumed:   true,
+	dto.EventMatchTerminated: true,
+}
+
+// parseEventTypes reads a comma-separated list of event types. An empty list selects every type.
+func parseEventTypes(raw string) ([]dto.EventType, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var types []dto.EventType
+	for name := range strings.SplitSeq(raw, ",") {
+		t := dto.EventType(strings.TrimSpace(name))
+		if !eventTypes[t] {
+			return nil, fmt.Errorf("unknown event type %q", t)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// StreamMatchEvents upgrades the connection to WebSocket and streams match events.
+// With ?since=N, the events that happened after game version N are replayed before the current state,
+// so a reconnecting client can catch up on what it missed.
+// With ?events=attack.made,game.over, only events of those types are streamed and replayed.
+// GET /matches/:id/ws
+func (h *EchoHandler) StreamMatchEvents(c echo.Context) error {
+	matchID := c.Param("id")
+	playerID := c.Get("player_id").(string)
+
+	if !h.isOriginAllowed(c.Request()) {
+		return echo.NewHTTPError(http.StatusForbidden, "origin not allowed")
+	}
+
+	since := -1
+	if raw := c.QueryParam("since"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be a non-negative integer")
+		}
+		since = v
+	}
+
+	types, err := parseEventTypes(c.QueryParam("events"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ws, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ws.Close() }()
+
+	sub, eventChan := h.ctrl.SubscribeToMatch(matchID, types...)
+	defer sub.Unsubscribe()
+
+	peerGone := h.readPump(ws)
+
+	ping := time.NewTicker(h.pingPeriod)
+	defer ping.Stop()
+
+	// Version of the last state sent, so that events which did not change the game are not re-sent
+	lastVersion := -1
+
+	// Replay missed events
+	if since >= 0 {
+		events, err := h.ctrl.EventsSinceAction(c.Request().Context(), matchID, playerID, since)
+		if err != nil {
+			_ = h.writeJSON(ws, dto.WSEvent{
+				Type:  "error",
+				Error: "failed to fetch missed events: " + err.Error(),
+			})
+		}
+		for i := range events {
+			if len(types) > 0 && !slices.Contains(types, events[i].Type) {
+				continue
+			}
+			if wErr := h.writeJSON(ws, dto.WSEvent{
+				Type:  "game_event",
+				Event: &events[i],
+			}); wErr != nil {
+				return nil
+			}
+		}
+	}
+
+	// Send initial state
+	initialView, err := h.ctrl.GetGameStateAction(c.Request().Context(), matchID, playerID)
+	if err == nil {
+		lastVersion = initialView.Version
+		if wErr := h.writeJSON(ws, dto.WSEvent{
+			Type:    "game_update",
+			Payload: &initialView,
+		}); wErr != nil {
+			return nil
+		}
+	} else {
+		_ = h.writeJSON(ws, dto.WSEvent{
+			Type:  "error",
+			Error: err.Error(),
+		})
+	}
+
+	// push sends the player's current state, along with the latest shot since the last push
+	var lastShot *dto.AttackEventData
+	push := func() error {
+		// Fetch fresh state for this player
+		view, err := h.ctrl.GetGameStateAction(c.Request().Context(), matchID, playerID)
+		if err != nil {
+			// Try to send error to client
+			_ = h.writeJSON(ws, dto.WSEvent{
+				Type:  "error",
+				Error: "failed to fetch state: " + err.Error(),
+			})
+			return nil
+		}
+
+		if view.Version == lastVersion {
+			return nil
+		}
+		lastVersion = view.Version
+
+		update := dto.WSEvent{
+			Type:    "game_update",
+			Payload: &view,
+			Attack:  lastShot,
+		}
+		lastShot = nil
+		return h.writeJSON(ws, update)
+	}
+
+	// Fires once the coalesce window of the first unsent event is over
+	var flush <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				// The match was removed, and the event saying why may have been filtered out
+				h.writeClose(ws, cmp.Or(sub.Reason(), dto.StreamClosedIdleTimeout))
+				return nil
+			}
+			reason, ends := closeReason(event)
+			if event.Type == dto.EventMatchCancelled || event.Type == dto.EventMatchTerminated {
+				// There is no state left to push, so the event itself tells the client what happened
+				if wErr := h.writeJSON(ws, dto.WSEvent{Type: "game_event", Event: event}); wErr == nil {
+					h.writeClose(ws, reason)
+				}
+				return nil
+			}
+			if data, ok := attackData(event); ok {
+				lastShot = &data
+			}
+			if h.coalesceWindow > 0 && !ends {
+				if flush == nil {
+					flush = time.After(h.coalesceWindow)
+				}
+				continue
+			}
+			// The end of a game is pushed right away, along with anything still held back
+			flush = nil
+			if wErr := push(); wErr != nil {
+				return nil
+			}
+			if ends {
+				h.writeClose(ws, reason)
+				return nil
+			}
+		case <-flush:
+			flush = nil
+			if wErr := push(); wErr != nil {
+				return nil
+			}
+		case <-ping.C:
+			deadline := time.Now().Add(h.writeTimeout)
+			if wErr := ws.WriteControl(websocket.PingMessage, nil, deadline); wErr != nil {
+				return nil
+			}
+		case <-peerGone:
+			return nil
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// closeReason tells whether the event leaves the match in a final state, which ends its stream,
+// and the reason the stream's close frame gives for it.
+func closeReason(event *dto.GameEvent) (string, bool) {
+	if event == nil {
+		return "", false
+	}
+	switch event.Type {
+	case dto.EventGameOver, dto.EventGameAbandoned:
+		return dto.StreamClosedGameOver, true
+	case dto.EventMatchCancelled:
+		return dto.StreamClosedMatchCancelled, true
+	case dto.EventMatchTerminated:
+		return dto.StreamClosedMatchTerminated, true
+	default:
+		return "", false
+	}
+}
+
+// writeClose sends a close frame with the reason the stream ends. The connection is closed
+// right after, so a client that does not answer is not waited for.
+func (h *EchoHandler) writeClose(ws *websocket.Conn, reason string) {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason)
+	_ = ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(h.writeTimeout))
+}
+
+// attackData returns the shot of an attack event, so clients can animate the cell it hit.
+func attackData(event *dto.GameEvent) (dto.AttackEventData, bool) {
+	if event == nil || event.Type != dto.EventAttackMade {
+		return dto.AttackEventData{}, false
+	}
+	data, ok := event.Data.(dto.AttackEventData)
+	return data, ok
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
+	"github.com/google/uuid"
+)
+
+// errUnknownDifficulty is returned when a match against the built-in opponent asks for an unknown difficulty.
+var errUnknownDifficulty = errors.New("unknown difficulty")
+
+// maxPlacementAttempts bounds the random tries to place a single ship.
+const maxPlacementAttempts = 1000
+
+// Sides of a match, each drawing from its own random stream so that a seed gives
+// the same fleet to the same side whatever happens on the other one.
+// The side seated after the guest's draws from the stream after guestSide, and so on.
+const (
+	hostSide uint64 = iota + 1
+	guestSide
+)
+
+// newSeed picks a seed for a match that was not given one.
+func newSeed() uint64 {
+	for {
+		if seed := rand.Uint64(); seed != 0 { // Zero means no seed in the settings
+			return seed
+		}
+	}
+}
+
+// matchRNG returns the random source of one side of a match.
+func matchRNG(seed, side uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, side)) //nolint:gosec // Not security sensitive
+}
+
+// aiOpponent plays one side of a match on behalf of the built-in bot.
+// It only looks at its own view of the game, so it cannot see the human's ships.
+type aiOpponent struct {
+	id         string
+	difficulty string
+	rng        *rand.Rand
+}
+
+// newAIOpponent creates an opponent drawing its fleet and shots from rng.
+func newAIOpponent(difficulty string, rng *rand.Rand) *aiOpponent {
+	return &aiOpponent{
+		id:         dto.AIPlayerPrefix + uuid.NewString()[:8],
+		difficulty: difficulty,
+		rng:        rng,
+	}
+}
+
+// validateDifficulty checks that the difficulty names a known opponent.
+func validateDifficulty(difficulty string) error {
+	switch difficulty {
+	case dto.DifficultyEasy, dto.DifficultyMedium, dto.DifficultyHard:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errUnknownDifficulty, difficulty)
+	}
+}
+
+// placeFleet places the whole fleet at random and declares the opponent ready.
+func (ai *aiOpponent) placeFleet(game *model.Game, fleet map[int]int) error {
+	if err := placeFleetAtRandom(game, ai.id, fleet, ai.rng); err != nil {
+		return err
+	}
+	return game.SetReady(ai.id)
+}
+
+// placeFleetAtRandom places the player's whole fleet at random, largest ships first while there is room.
+func placeFleetAtRandom(game *model.Game, playerID string, fleet map[int]int, rng *rand.Rand) error {
+	sizes := slices.Sorted(maps.Keys(fleet))
+	slices.Reverse(sizes)
+
+	for _, size := range sizes {
+		for range fleet[size] {
+			if err := placeShipAtRandom(game, playerID, size, rng); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func placeShipAtRandom(game *model.Game, playerID string, size int, rng *rand.Rand) error {
+	n := game.BoardSize()
+	for range maxPlacementAttempts {
+		orientation := model.Horizontal
+		if rng.IntN(2) == 1 {
+			orientation = model.Vertical
+		}
+		c := model.Coordinate{X: rng.IntN(n), Y: rng.IntN(n)}
+
+		err := game.PlaceShip(playerID, c, size, orientation)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, model.ErrShipOverlap), errors.Is(err, model.ErrShipOutOfBounds):
+			continue
+		default:
+			return err
+		}
+	}
+	return fmt.Errorf("could not place a ship of size %d", size)
+}
+
+// nextShot picks the next cell to fire at from the opponent's view of the enemy board.
+func (ai *aiOpponent) nextShot(view dto.GameView) model.Coordinate {
+	grid := view.Enemy.Board.Grid
+
+	if ai.difficulty != dto.DifficultyEasy {
+		if targets := targetCells(grid); len(targets) > 0 {
+			return targets[ai.rng.IntN(len(targets))]
+		}
+	}
+
+	var unknown, parity []model.Coordinate
+	for y, row := range grid {
+		for x, cell := range row {
+			if cell != dto.CellUnknown {
+				continue
+			}
+			c := model.Coordinate{X: x, Y: y}
+			unknown = append(unknown, c)
+			if (x+y)%2 == 0 {
+				parity = append(parity, c)
+			}
+		}
+	}
+
+	// A checkerboard finds ships of two or more cells in half the shots. Fleets may hold
+	// single-cell ships it can miss, so once it is used up every unknown cell is fair game again.
+	if ai.difficulty == dto.DifficultyHard && len(parity) > 0 {
+		unknown = parity
+	}
+	if len(unknown) == 0 {
+		return model.Coordinate{} // Unreachable while the game is still being played
+	}
+
+	return unknown[ai.rng.IntN(len(unknown))]
+}
+
+// targetCells returns the unknown cells next to hit but not yet sunk ships.
+// Cells extending a line of hits are preferred over the others.
+func targetCells(grid [][]dto.CellState) []model.Coordinate {
+	at := func(x, y int) dto.CellState {
+		if y < 0 || y >= len(grid) || x < 0 || x >= len(grid[y]) {
+			return ""
+		}
+		return grid[y][x]
+	}
+
+	var inLine, around []model.Coordinate
+	for y, row := range grid {
+		for x, cell := range row {
+			if cell != dto.CellHit {
+				continue
+			}
+			for _, dir := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				nx, ny := x+dir[0], y+dir[1]
+				if at(nx, ny) != dto.CellUnknown {
+					continue
+				}
+				next := model.Coordinate{X: nx, Y: ny}
+				if at(x-dir[0], y-dir[1]) == dto.CellHit {
+					inLine = append(inLine, next)
+				} else {
+					around = append(around, next)
+				}
+			}
+		}
+	}
+
+	if len(inLine) > 0 {
+		return inLine
+	}
+	return around
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unknownGrid returns a size x size enemy grid where nothing has been fired at yet.
+func unknownGrid(size int) [][]dto.CellState {
+	grid := make([][]dto.CellState, size)
+	for y := range grid {
+		grid[y] = make([]dto.CellState, size)
+		for x := range grid[y] {
+			grid[y][x] = dto.CellUnknown
+		}
+	}
+	return grid
+}
+
+func TestAIOpponent_PlaceFleet(t *testing.T) {
+	t.Parallel()
+
+	for _, preset := range model.FleetPresetNames() {
+		fleet, err := model.FleetPreset(preset)
+		require.NoError(t, err)
+
+		game, err := model.NewGameOfSize(8)
+		require.NoError(t, err)
+		require.NoError(t, game.Join("human", fleet))
+
+		ai := newAIOpponent(dto.DifficultyMedium, matchRNG(newSeed(), guestSide))
+		require.NoError(t, game.Join(ai.id, fleet))
+		require.NoError(t, ai.placeFleet(game, fleet), preset)
+
+		view, err := game.GetView(ai.id)
+		require.NoError(t, err)
+		assert.True(t, view.Me.Ready, preset)
+		for size, remaining := range view.Me.Fleet {
+			assert.Zero(t, remaining, "%s: ships of size %d left to place", preset, size)
+		}
+	}
+}
+
+func TestAIOpponent_NextShot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Extends a line of hits", func(t *testing.T) {
+		t.Parallel()
+
+		grid := unknownGrid(model.GridSize)
+		grid[4][4] = dto.CellHit
+		grid[4][5] = dto.CellHit
+		grid[4][6] = dto.CellMiss
+
+		ai := newAIOpponent(dto.DifficultyMedium, matchRNG(newSeed(), guestSide))
+		for range 20 {
+			shot := ai.nextShot(dto.GameView{Enemy: dto.PlayerView{Board: dto.BoardView{Grid: grid}}})
+			assert.Equal(t, model.Coordinate{X: 3, Y: 4}, shot)
+		}
+	})
+
+	t.Run("Targets around a single hit", func(t *testing.T) {
+		t.Parallel()
+
+		grid := unknownGrid(model.GridSize)
+		grid[0][0] = dto.CellHit
+
+		ai := newAIOpponent(dto.DifficultyHard, matchRNG(newSeed(), guestSide))
+		for range 20 {
+			shot := ai.nextShot(dto.GameView{Enemy: dto.PlayerView{Board: dto.BoardView{Grid: grid}}})
+			assert.Contains(t, []model.Coordinate{{X: 1, Y: 0}, {X: 0, Y: 1}}, shot)
+		}
+	})
+
+	t.Run("Hard hunts on a checkerboard", func(t *testing.T) {
+		t.Parallel()
+
+		grid := unknownGrid(model.GridSize)
+		grid[2][2] = dto.CellSunk
+		grid[2][3] = dto.CellSunk
+
+		ai := newAIOpponent(dto.DifficultyHard, matchRNG(newSeed(), guestSide))
+		for range 50 {
+			shot := ai.nextShot(dto.GameView{Enemy: dto.PlayerView{Board: dto.BoardView{Grid: grid}}})
+			assert.Equal(t, 0, (shot.X+shot.Y)%2, "shot %v", shot)
+			assert.Equal(t, dto.CellUnknown, grid[shot.Y][shot.X])
+		}
+	})
+
+	t.Run("Easy ignores hits", func(t *testing.T) {
+		t.Parallel()
+
+		grid := unknownGrid(model.MinGridSize)
+		grid[0][0] = dto.CellHit
+
+		ai := newAIOpponent(dto.DifficultyEasy, matchRNG(newSeed(), guestSide))
+		seen := make(map[model.Coordinate]bool)
+		for range 200 {
+			seen[ai.nextShot(dto.GameView{Enemy: dto.PlayerView{Board: dto.BoardView{Grid: grid}}})] = true
+		}
+		assert.Greater(t, len(seen), 2)
+	})
+}
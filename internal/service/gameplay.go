@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/callegarimattia/battleship/internal/controller"
 	"github.com/callegarimattia/battleship/internal/dto"
 	"github.com/callegarimattia/battleship/internal/model"
 )
@@ -11,10 +14,89 @@ import (
 // PlaceShip handles the complex logic of setup.
 // It bridges the gap between simple inputs (bool, int) and Model types (Orientation, pointers).
 func (s *MemoryService) PlaceShip(
+	ctx context.Context,
+	matchID, playerID string,
+	size, x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	return s.placeShip(ctx, matchID, playerID, x, y, vertical, dto.ShipPlacedEventData{Size: size},
+		func(g *model.Game, c model.Coordinate, o model.Orientation) error {
+			return g.PlaceShip(playerID, c, size, o)
+		})
+}
+
+// PlaceShipType places a ship chosen by its type name, e.g. "Submarine", rather than by size.
+func (s *MemoryService) PlaceShipType(
+	ctx context.Context,
+	matchID, playerID string,
+	shipType string,
+	x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	t, err := model.ParseShipType(shipType)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	return s.placeShip(ctx, matchID, playerID, x, y, vertical, dto.ShipPlacedEventData{Size: t.Size(), Ship: t.Name()},
+		func(g *model.Game, c model.Coordinate, o model.Orientation) error {
+			return g.PlaceShipType(playerID, c, t, o)
+		})
+}
+
+// ValidatePlacement reports whether PlaceShip would accept the placement, and why not.
+// Nothing is placed; an error is only returned when the match cannot be found.
+func (s *MemoryService) ValidatePlacement(
 	_ context.Context,
 	matchID, playerID string,
 	size, x, y int,
 	vertical bool,
+) (dto.PlacementCheck, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return dto.PlacementCheck{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	orientation := model.Horizontal
+	if vertical {
+		orientation = model.Vertical
+	}
+
+	err = sg.game.CanPlaceShip(playerID, model.Coordinate{X: x, Y: y}, size, orientation)
+	if err == nil {
+		return dto.PlacementCheck{Valid: true}, nil
+	}
+	return dto.PlacementCheck{Code: placementCode(err), Reason: err.Error()}, nil
+}
+
+// placementCode names the reason a placement was refused for clients.
+func placementCode(err error) string {
+	switch {
+	case errors.Is(err, model.ErrShipOutOfBounds):
+		return dto.PlacementOutOfBounds
+	case errors.Is(err, model.ErrShipOverlap):
+		return dto.PlacementOverlap
+	case errors.Is(err, model.ErrNoShipsRemaining):
+		return dto.PlacementDepleted
+	case errors.Is(err, model.ErrNotInSetup):
+		return dto.PlacementNotInSetup
+	default:
+		return dto.PlacementInvalid
+	}
+}
+
+// placeShip runs a placement on the match and tells the opponent about it.
+// data describes the ship; its coordinates are filled in here.
+func (s *MemoryService) placeShip(
+	ctx context.Context,
+	matchID, playerID string,
+	x, y int,
+	vertical bool,
+	data dto.ShipPlacedEventData,
+	place func(*model.Game, model.Coordinate, model.Orientation) error,
 ) (dto.GameView, error) {
 	sg, err := s.getSafeGame(matchID)
 	if err != nil {
@@ -24,6 +106,10 @@ func (s *MemoryService) PlaceShip(
 	sg.mu.Lock()
 	defer sg.mu.Unlock()
 
+	if err := checkVersion(ctx, sg); err != nil {
+		return dto.GameView{}, err
+	}
+
 	orientation := model.Horizontal
 	if vertical {
 		orientation = model.Vertical
@@ -31,53 +117,94 @@ func (s *MemoryService) PlaceShip(
 
 	coord := model.Coordinate{X: x, Y: y}
 
-	if err := sg.game.PlaceShip(playerID, coord, size, orientation); err != nil { //nolint
+	if err := place(sg.game, coord, orientation); err != nil {
 		return dto.GameView{}, err // Returns ErrShipOverlap, ErrNoShipsRemaining, etc.
 	}
 
-	_ = sg.game.StartGame()
+	if s.autoReady {
+		_ = sg.game.SetReady(playerID) // Fails harmlessly until the fleet is complete
+	}
 	sg.updatedAt = time.Now()
 
-	view, err := sg.game.GetView(playerID)
+	view, err := sg.view(playerID)
 	if err != nil {
 		return dto.GameView{}, err
 	}
 
 	// Emit event: ship placed
-	if s.notifier != nil {
-		// Get opponent ID
-		opponentID := ""
-		if sg.host == playerID {
-			opponentID = sg.guest
-		} else {
-			opponentID = sg.host
-		}
+	data.X, data.Y, data.Vertical = x, y, vertical
+	s.publishToSeats(sg, dto.GameEvent{
+		Type:      dto.EventShipPlaced,
+		MatchID:   matchID,
+		PlayerID:  playerID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
 
-		if opponentID != "" {
-			s.notifier.Publish(&dto.GameEvent{
-				Type:      dto.EventShipPlaced,
-				MatchID:   matchID,
-				PlayerID:  playerID,
-				TargetID:  opponentID,
-				Timestamp: time.Now(),
-				Data: dto.ShipPlacedEventData{
-					Size:     size,
-					X:        x,
-					Y:        y,
-					Vertical: vertical,
-				},
-			})
-		}
+	return view, nil
+}
+
+// Ready confirms that a player is done with setup, for their whole side.
+// The game starts once every side is ready.
+func (s *MemoryService) Ready(
+	_ context.Context,
+	matchID, playerID string,
+) (dto.GameView, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if err := sg.game.SetReady(playerID); err != nil {
+		return dto.GameView{}, err // Returns ErrFleetNotPlaced, ErrNotInSetup, etc.
+	}
+
+	sg.updatedAt = time.Now()
+
+	view, err := sg.view(playerID)
+	if err != nil {
+		return dto.GameView{}, err
+	}
+
+	// Emit events: player ready, and game started if every side is ready
+	s.publishToSeats(sg, dto.GameEvent{
+		Type:      dto.EventPlayerReady,
+		MatchID:   matchID,
+		PlayerID:  playerID,
+		Timestamp: time.Now(),
+	})
+	if view.State == dto.StatePlaying {
+		s.publishToSeats(sg, dto.GameEvent{
+			Type:      dto.EventGameStarted,
+			MatchID:   matchID,
+			PlayerID:  playerID,
+			Timestamp: time.Now(),
+		})
 	}
 
 	return view, nil
 }
 
 // Attack handles the firing logic.
+// Against the built-in opponent, its reply is played before the view is returned.
 func (s *MemoryService) Attack(
-	_ context.Context,
+	ctx context.Context,
 	matchID, playerID string,
 	x, y int,
+) (dto.GameView, error) {
+	return s.AttackTarget(ctx, matchID, playerID, "", x, y)
+}
+
+// AttackTarget fires at the named player's board like Attack. The target is checked against
+// every seat of the match: it fails with model.ErrInvalidTarget for the attacker, a teammate or
+// a player already sunk, and with model.ErrUnknownPlayer for someone who is not in the match.
+func (s *MemoryService) AttackTarget(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
 ) (dto.GameView, error) {
 	sg, err := s.getSafeGame(matchID)
 	if err != nil {
@@ -87,54 +214,205 @@ func (s *MemoryService) Attack(
 	sg.mu.Lock()
 	defer sg.mu.Unlock()
 
+	if err := checkVersion(ctx, sg); err != nil {
+		return dto.GameView{}, err
+	}
+
 	coord := model.Coordinate{X: x, Y: y}
-	result, err := sg.game.Attack(playerID, coord)
+	result, err := sg.game.AttackTarget(playerID, targetID, coord)
 	if err != nil {
 		return dto.GameView{}, err // Returns ErrNotYourTurn, ErrInvalidShot, etc.
 	}
 
 	sg.updatedAt = time.Now()
+	s.publishAttack(sg, playerID, coord, result)
+	s.playOpponent(sg)
 
-	view, err := sg.game.GetView(playerID)
+	return sg.view(playerID)
+}
+
+// ValidateAttack reports whether AttackTarget would fire the shot, and why not: the match not
+// in play, another player's turn, a target that may not be fired at, or a cell off the board
+// or already attacked. Nothing is fired; an error is only returned when the match cannot be found.
+func (s *MemoryService) ValidateAttack(
+	_ context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (dto.AttackCheck, error) {
+	sg, err := s.getSafeGame(matchID)
 	if err != nil {
-		return dto.GameView{}, err
+		return dto.AttackCheck{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	err = sg.game.CanAttack(playerID, targetID, model.Coordinate{X: x, Y: y})
+	if err == nil {
+		return dto.AttackCheck{Valid: true}, nil
+	}
+	return dto.AttackCheck{Code: attackCode(err), Reason: err.Error()}, nil
+}
+
+// attackCode names the reason a shot was refused for clients.
+func attackCode(err error) string {
+	switch {
+	case errors.Is(err, model.ErrNotInPlay):
+		return dto.AttackNotInPlay
+	case errors.Is(err, model.ErrNotYourTurn):
+		return dto.AttackNotYourTurn
+	case errors.Is(err, model.ErrShotOutOfBounds):
+		return dto.AttackOutOfBounds
+	case errors.Is(err, model.ErrAlreadyAttacked):
+		return dto.AttackAlreadyAttacked
+	case errors.Is(err, model.ErrTargetRequired):
+		return dto.AttackTargetRequired
+	case errors.Is(err, model.ErrInvalidTarget):
+		return dto.AttackInvalidTarget
+	default:
+		return dto.AttackInvalid
+	}
+}
+
+// LegalMoves lists the cells of the target's board the player has not fired at yet.
+// It returns ErrNotYourTurn when the player is waiting for the opponent.
+func (s *MemoryService) LegalMoves(
+	_ context.Context,
+	matchID, playerID, targetID string,
+) (dto.LegalMoves, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return dto.LegalMoves{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	return sg.game.LegalMoves(playerID, targetID)
+}
+
+// playOpponent lets the built-in opponent, if any, take its turns.
+// It must be called with sg.mu held.
+func (s *MemoryService) playOpponent(sg *safeGame) {
+	if sg.ai == nil {
+		return
 	}
 
-	// Emit event: attack made
-	if s.notifier != nil {
-		// Get opponent ID
-		opponentID := ""
-		if sg.host == playerID {
-			opponentID = sg.guest
-		} else {
-			opponentID = sg.host
+	for sg.game.IsPlayersTurn(sg.ai.id) {
+		coord, result, err := s.opponentAttack(sg)
+		if err != nil {
+			s.logger.Error("Built-in opponent could not take its turn", "match_id", sg.id, "error", err)
+			return
 		}
 
-		if opponentID != "" {
-			resultStr := "miss"
-			switch result {
-			case model.ShotResultHit:
-				resultStr = "hit"
-			case model.ShotResultSunk:
-				resultStr = "sunk"
-			}
-
-			s.notifier.Publish(&dto.GameEvent{
-				Type:      dto.EventAttackMade,
-				MatchID:   matchID,
-				PlayerID:  playerID,
-				TargetID:  opponentID,
-				Timestamp: time.Now(),
-				Data: dto.AttackEventData{
-					X:      x,
-					Y:      y,
-					Result: resultStr,
-				},
-			})
+		sg.updatedAt = time.Now()
+		s.publishAttack(sg, sg.ai.id, coord, result)
+	}
+}
+
+// opponentAttack fires the built-in opponent's next shot. Should the shot it picked fail,
+// the failure is logged and the first legal cell is fired at instead, so that the opponent
+// always finishes its turn. It must be called with sg.mu held.
+func (s *MemoryService) opponentAttack(sg *safeGame) (model.Coordinate, model.ShotResult, error) {
+	view, err := sg.game.GetView(sg.ai.id)
+	if err == nil {
+		coord := sg.ai.nextShot(view)
+		result, err := sg.game.Attack(sg.ai.id, coord)
+		if err == nil {
+			return coord, result, nil
 		}
+		s.logger.Warn("Built-in opponent picked an illegal shot", "match_id", sg.id, "coord", coord.String(), "error", err)
+	} else {
+		s.logger.Warn("Built-in opponent could not see the game", "match_id", sg.id, "error", err)
 	}
 
-	return view, nil
+	moves, err := sg.game.LegalMoves(sg.ai.id, "")
+	if err != nil {
+		return model.Coordinate{}, 0, err
+	}
+	if len(moves.Targets) == 0 {
+		return model.Coordinate{}, 0, errors.New("no cell left to fire at")
+	}
+
+	coord := model.Coordinate{X: moves.Targets[0].X, Y: moves.Targets[0].Y}
+	result, err := sg.game.Attack(sg.ai.id, coord)
+	return coord, result, err
+}
+
+// checkVersion fails with ErrVersionConflict when the move expects a game version other than
+// the current one. It must be called with sg.mu held.
+func checkVersion(ctx context.Context, sg *safeGame) error {
+	if version, ok := controller.ExpectedVersion(ctx); ok && version != sg.game.Version() {
+		return fmt.Errorf("%w: expected %d, game is at %d", controller.ErrVersionConflict, version, sg.game.Version())
+	}
+	return nil
+}
+
+// publishAttack emits the attack-made event for a shot to every other player, telling them whose
+// turn it now is, and the game-over event if it decided the game. It must be called with sg.mu held.
+func (s *MemoryService) publishAttack(
+	sg *safeGame,
+	attackerID string,
+	coord model.Coordinate,
+	result model.ShotResult,
+) {
+	move, _ := sg.game.LastMove()
+	view, err := sg.game.GetView(attackerID)
+	if err != nil {
+		return
+	}
+	s.publishToSeats(sg, dto.GameEvent{
+		Type:      dto.EventAttackMade,
+		MatchID:   sg.id,
+		PlayerID:  attackerID,
+		Timestamp: time.Now(),
+		Data: dto.AttackEventData{
+			Attacker:   attackerID,
+			Target:     move.Target,
+			X:          coord.X,
+			Y:          coord.Y,
+			Coord:      coord.String(),
+			Result:     shotResultName(result),
+			NearbyShip: move.NearbyShip,
+			NextTurn:   view.Turn,
+		},
+	})
+
+	// Running out of moves can hand the win to a defender, who then announces it; a draw is
+	// announced by the last shooter
+	if winner := sg.game.Winner(); winner != "" || sg.game.IsDraw() {
+		playerID := attackerID
+		if winner != "" {
+			playerID = winner
+		}
+		s.publishToSeats(sg, dto.GameEvent{
+			Type:      dto.EventGameOver,
+			MatchID:   sg.id,
+			PlayerID:  playerID,
+			Timestamp: time.Now(),
+			Data:      dto.GameOverEventData{Winner: winner},
+		})
+	}
+}
+
+// IsPlayersTurn reports whether it is the given player's turn to attack in the match.
+func (s *MemoryService) IsPlayersTurn(
+	_ context.Context,
+	matchID, playerID string,
+) (bool, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return false, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if !sg.isSeated(playerID) {
+		return false, model.ErrUnknownPlayer
+	}
+
+	return sg.game.IsPlayersTurn(playerID), nil
 }
 
 // GetState retrieves the current game state for a player.
@@ -150,5 +428,150 @@ func (s *MemoryService) GetState(
 	sg.mu.Lock()
 	defer sg.mu.Unlock()
 
-	return sg.game.GetView(playerID)
+	return sg.view(playerID)
+}
+
+// EventsSince returns the events a reconnecting player missed after the given game version, oldest first.
+// At most the latest maxBackfill events are returned. Opponent ship positions are never included.
+func (s *MemoryService) EventsSince(
+	_ context.Context,
+	matchID, playerID string,
+	since int,
+) ([]dto.GameEvent, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if !sg.isSeated(playerID) {
+		return nil, model.ErrUnknownPlayer
+	}
+
+	events := make([]dto.GameEvent, 0)
+	for _, event := range sg.history {
+		if event.Version <= since {
+			continue
+		}
+		if event.Type == dto.EventShipPlaced && event.PlayerID != playerID {
+			event.Data = nil // Fog of war
+		}
+		events = append(events, event)
+	}
+
+	if len(events) > maxBackfill {
+		events = events[len(events)-maxBackfill:]
+	}
+
+	return events, nil
+}
+
+// MatchRecord returns every shot fired in the match and how it ended, for export.
+// Only the match's players may read it.
+func (s *MemoryService) MatchRecord(
+	_ context.Context,
+	matchID, playerID string,
+) (dto.MatchRecord, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return dto.MatchRecord{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if !sg.isSeated(playerID) {
+		return dto.MatchRecord{}, model.ErrUnknownPlayer
+	}
+
+	record := dto.MatchRecord{
+		MatchID: sg.id,
+		State:   sg.game.State(),
+		Winner:  sg.game.Winner(),
+		Moves:   []dto.MoveRecord{},
+
+		PlacementCommitment: sg.game.PlacementCommitment(),
+	}
+	for i, move := range sg.game.Moves() {
+		record.Moves = append(record.Moves, dto.MoveRecord{
+			Number:     i + 1,
+			Attacker:   move.Attacker,
+			Target:     move.Target,
+			Coord:      move.Coord.String(),
+			Result:     shotResultName(move.Result),
+			NearbyShip: move.NearbyShip,
+		})
+	}
+
+	return record, nil
+}
+
+// Reveal returns both fleets of a won match. Anyone may see them, since they are no longer secret.
+func (s *MemoryService) Reveal(_ context.Context, matchID string) (dto.RevealView, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return dto.RevealView{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	players, err := sg.game.Reveal()
+	if err != nil {
+		return dto.RevealView{}, err
+	}
+
+	return dto.RevealView{
+		MatchID: sg.id,
+		Winner:  sg.game.Winner(),
+		Players: players,
+
+		PlacementCommitment: sg.game.PlacementCommitment(),
+	}, nil
+}
+
+// Rules returns how the match is played. Anyone may see them, as they give nothing away.
+func (s *MemoryService) Rules(_ context.Context, matchID string) (dto.Rules, error) {
+	sg, err := s.getSafeGame(matchID)
+	if err != nil {
+		return dto.Rules{}, err
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	return sg.rules(), nil
+}
+
+// rules describes the match's settings and fleets. It must be called with sg.mu held.
+func (sg *safeGame) rules() dto.Rules {
+	return dto.Rules{
+		BoardSize:   sg.settings.BoardSize,
+		FleetPreset: sg.settings.FleetPreset,
+		HostFleet:   model.DescribeFleet(sg.fleets.host),
+		GuestFleet:  model.DescribeFleet(sg.fleets.guest),
+		MoveLimit:   sg.settings.MoveLimit,
+		Assist:      sg.settings.Assist,
+		AutoPlace:   sg.settings.AutoPlace,
+		VsBot:       sg.settings.VsBot,
+		Difficulty:  sg.settings.Difficulty,
+		TeamSize:    sg.game.TeamSize(),
+		FreeForAll:  sg.settings.FreeForAll,
+
+		ExtraTurnOnHit: sg.settings.ExtraTurnOnHit,
+	}
+}
+
+// shotResultName is how shot results are spelled for clients.
+func shotResultName(result model.ShotResult) string {
+	switch result {
+	case model.ShotResultHit:
+		return "hit"
+	case model.ShotResultSunk:
+		return "sunk"
+	default:
+		return "miss"
+	}
 }
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"crypto/rsa"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -24,19 +26,58 @@ type MemoryIdentityService struct {
 	// Key: "source:extID" -> Value: "user-uuid"
 	identities map[string]string
 
-	jwtSecret string
+	jwtSecret  string
+	signingKey *rsa.PrivateKey // Signs with RS256 instead of the shared secret when set
+	issuer     string          // "iss" claim of issued tokens, omitted when empty
+	audience   string          // "aud" claim of issued tokens, omitted when empty
+}
+
+// IdentityOption configures optional MemoryIdentityService behavior.
+type IdentityOption func(*MemoryIdentityService)
+
+// WithTokenIssuer sets the issuer ("iss") of the tokens the service mints.
+func WithTokenIssuer(issuer string) IdentityOption {
+	return func(s *MemoryIdentityService) { s.issuer = issuer }
+}
+
+// WithTokenAudience sets the audience ("aud") of the tokens the service mints.
+func WithTokenAudience(audience string) IdentityOption {
+	return func(s *MemoryIdentityService) { s.audience = audience }
+}
+
+// WithRS256 signs tokens with the private key (RS256) instead of the shared secret (HS256),
+// so verifiers only need the public key.
+func WithRS256(key *rsa.PrivateKey) IdentityOption {
+	return func(s *MemoryIdentityService) { s.signingKey = key }
+}
+
+// LoadRSAPrivateKey reads a PEM encoded RSA private key for WithRS256.
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // The path comes from the server configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+	return key, nil
 }
 
 // NewIdentityService initializes the storage.
-func NewIdentityService(jwtSecret string) *MemoryIdentityService {
+func NewIdentityService(jwtSecret string, opts ...IdentityOption) *MemoryIdentityService {
 	if jwtSecret == "" {
 		jwtSecret = "secret"
 	}
-	return &MemoryIdentityService{
+	s := &MemoryIdentityService{
 		users:      make(map[string]dto.User),
 		identities: make(map[string]string),
 		jwtSecret:  jwtSecret,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // LoginOrRegister finds an existing user or creates a new one.
@@ -72,9 +113,14 @@ func (s *MemoryIdentityService) LoginOrRegister(
 		"name": user.Username,
 		"exp":  time.Now().Add(time.Hour * 24).Unix(),
 	}
+	if s.issuer != "" {
+		claims["iss"] = s.issuer
+	}
+	if s.audience != "" {
+		claims["aud"] = s.audience
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.jwtSecret))
+	signedToken, err := s.sign(claims)
 	if err != nil {
 		return dto.AuthResponse{}, err
 	}
@@ -84,3 +130,24 @@ func (s *MemoryIdentityService) LoginOrRegister(
 		User:  user,
 	}, nil
 }
+
+// GetUser returns a registered user.
+// Users only live in memory, so a token issued before a restart refers to an unknown user.
+func (s *MemoryIdentityService) GetUser(_ context.Context, userID string) (dto.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return dto.User{}, controller.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// sign signs the claims with the private key if one is configured, with the shared secret otherwise.
+func (s *MemoryIdentityService) sign(claims jwt.MapClaims) (string, error) {
+	if s.signingKey != nil {
+		return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.signingKey)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtSecret))
+}
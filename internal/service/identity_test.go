@@ -2,9 +2,13 @@ package service_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 
+	"github.com/callegarimattia/battleship/internal/controller"
 	"github.com/callegarimattia/battleship/internal/service"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,3 +40,59 @@ func TestMemoryIdentityService_LoginOrRegister(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEqual(t, resp1.User.ID, resp3.User.ID)
 }
+
+func TestMemoryIdentityService_TokenClaims(t *testing.T) {
+	t.Parallel()
+	auth := service.NewIdentityService(
+		"test-secret",
+		service.WithTokenIssuer("battleship"),
+		service.WithTokenAudience("battleship-api"),
+	)
+
+	resp, err := auth.LoginOrRegister(context.Background(), "Alice", "web", "Alice")
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(resp.Token, claims, func(*jwt.Token) (any, error) {
+		return []byte("test-secret"), nil
+	}, jwt.WithIssuer("battleship"), jwt.WithAudience("battleship-api"))
+	require.NoError(t, err)
+	assert.Equal(t, resp.User.ID, claims["sub"])
+}
+
+func TestMemoryIdentityService_RS256(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	auth := service.NewIdentityService("test-secret", service.WithRS256(key))
+	resp, err := auth.LoginOrRegister(context.Background(), "Alice", "web", "Alice")
+	require.NoError(t, err)
+
+	// Verifiable with the public key alone, and no longer with the shared secret
+	_, err = jwt.Parse(resp.Token, func(*jwt.Token) (any, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(resp.Token, func(*jwt.Token) (any, error) {
+		return []byte("test-secret"), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+	assert.Error(t, err)
+}
+
+func TestMemoryIdentityService_GetUser(t *testing.T) {
+	t.Parallel()
+	auth := service.NewIdentityService("test-secret")
+	ctx := context.Background()
+
+	resp, err := auth.LoginOrRegister(ctx, "Alice", "web", "Alice")
+	require.NoError(t, err)
+
+	user, err := auth.GetUser(ctx, resp.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, resp.User, user)
+
+	_, err = auth.GetUser(ctx, "user-unknown")
+	assert.ErrorIs(t, err, controller.ErrUserNotFound)
+}
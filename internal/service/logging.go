@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+)
+
+// LoggingLobbyService logs the lobby calls that change matches, with their outcome and duration,
+// on the way to the lobby it wraps. Reads go straight through.
+type LoggingLobbyService struct {
+	controller.LobbyService
+	logger *slog.Logger
+}
+
+// NewLoggingLobbyService logs the calls made to next at debug level.
+func NewLoggingLobbyService(next controller.LobbyService, logger *slog.Logger) *LoggingLobbyService {
+	return &LoggingLobbyService{LobbyService: next, logger: logger}
+}
+
+// logCall logs a finished service call. Deferred at the start of the call, with err pointing at its result.
+func logCall(ctx context.Context, logger *slog.Logger, op string, start time.Time, err *error, attrs ...any) {
+	attrs = append(attrs, "duration", time.Since(start))
+	if *err != nil {
+		attrs = append(attrs, "error", *err)
+	}
+	logger.DebugContext(ctx, "Service call "+op, attrs...)
+}
+
+// CreateMatch is logged with the host and the new match ID.
+func (s *LoggingLobbyService) CreateMatch(
+	ctx context.Context,
+	hostID string,
+	settings dto.MatchSettings,
+) (matchID string, err error) {
+	defer func(start time.Time) {
+		logCall(ctx, s.logger, "create_match", start, &err, "player_id", hostID, "match_id", matchID)
+	}(time.Now())
+	return s.LobbyService.CreateMatch(ctx, hostID, settings)
+}
+
+// JoinMatch is logged with the match and the player.
+func (s *LoggingLobbyService) JoinMatch(ctx context.Context, matchID, playerID string) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "join_match", time.Now(), &err, "match_id", matchID, "player_id", playerID)
+	return s.LobbyService.JoinMatch(ctx, matchID, playerID)
+}
+
+// ResumeMatch is logged with the match and the player, never with the token.
+func (s *LoggingLobbyService) ResumeMatch(
+	ctx context.Context,
+	matchID, playerID, token string,
+) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "resume_match", time.Now(), &err, "match_id", matchID, "player_id", playerID)
+	return s.LobbyService.ResumeMatch(ctx, matchID, playerID, token)
+}
+
+// DeleteMatch is logged with the match and the player.
+func (s *LoggingLobbyService) DeleteMatch(ctx context.Context, matchID, playerID string) (err error) {
+	defer logCall(ctx, s.logger, "delete_match", time.Now(), &err, "match_id", matchID, "player_id", playerID)
+	return s.LobbyService.DeleteMatch(ctx, matchID, playerID)
+}
+
+// LoggingGameService logs the moves made through the game service it wraps,
+// with their outcome and duration. Reads go straight through.
+type LoggingGameService struct {
+	controller.GameService
+	logger *slog.Logger
+}
+
+// NewLoggingGameService logs the calls made to next at debug level.
+func NewLoggingGameService(next controller.GameService, logger *slog.Logger) *LoggingGameService {
+	return &LoggingGameService{GameService: next, logger: logger}
+}
+
+// PlaceShip is logged with the match, the player and the placement.
+func (s *LoggingGameService) PlaceShip(
+	ctx context.Context,
+	matchID, playerID string,
+	shipID int,
+	x, y int,
+	vertical bool,
+) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "place_ship", time.Now(), &err,
+		"match_id", matchID, "player_id", playerID, "size", shipID, "x", x, "y", y, "vertical", vertical)
+	return s.GameService.PlaceShip(ctx, matchID, playerID, shipID, x, y, vertical)
+}
+
+// PlaceShipType is logged with the match, the player and the placement.
+func (s *LoggingGameService) PlaceShipType(
+	ctx context.Context,
+	matchID, playerID string,
+	shipType string,
+	x, y int,
+	vertical bool,
+) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "place_ship", time.Now(), &err,
+		"match_id", matchID, "player_id", playerID, "ship", shipType, "x", x, "y", y, "vertical", vertical)
+	return s.GameService.PlaceShipType(ctx, matchID, playerID, shipType, x, y, vertical)
+}
+
+// Ready is logged with the match and the player.
+func (s *LoggingGameService) Ready(ctx context.Context, matchID, playerID string) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "ready", time.Now(), &err, "match_id", matchID, "player_id", playerID)
+	return s.GameService.Ready(ctx, matchID, playerID)
+}
+
+// Attack is logged with the match, the player and the target cell.
+func (s *LoggingGameService) Attack(
+	ctx context.Context,
+	matchID, playerID string,
+	x, y int,
+) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "attack", time.Now(), &err, "match_id", matchID, "player_id", playerID, "x", x, "y", y)
+	return s.GameService.Attack(ctx, matchID, playerID, x, y)
+}
+
+// AttackTarget is logged with the match, the player, the target player and the target cell.
+func (s *LoggingGameService) AttackTarget(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (_ dto.GameView, err error) {
+	defer logCall(ctx, s.logger, "attack", time.Now(), &err,
+		"match_id", matchID, "player_id", playerID, "target_id", targetID, "x", x, "y", y)
+	return s.GameService.AttackTarget(ctx, matchID, playerID, targetID, x, y)
+}
@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/metrics"
+	"github.com/callegarimattia/battleship/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecorators(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	latency := metrics.NewHistogram("calls", "Calls.", "operation", nil)
+
+	// Composed the way the server does it
+	lobby := service.NewMetricsLobbyService(service.NewLoggingLobbyService(s, logger), latency)
+	game := service.NewMetricsGameService(service.NewLoggingGameService(s, logger), latency)
+
+	matchID, err := lobby.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = lobby.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	_, err = game.Attack(ctx, matchID, "host", 0, 0)
+	require.Error(t, err, "the game has not started")
+	_, err = game.GetState(ctx, matchID, "host")
+	require.NoError(t, err)
+
+	assert.Contains(t, logs.String(), "msg=\"Service call create_match\" player_id=host match_id="+matchID)
+	assert.Contains(t, logs.String(), "msg=\"Service call join_match\" match_id="+matchID+" player_id=guest")
+	assert.Contains(t, logs.String(), "msg=\"Service call attack\"")
+	assert.Contains(t, logs.String(), "error=", "failed calls are logged with their error")
+	assert.NotContains(t, logs.String(), "get_state", "reads are not logged")
+
+	var out bytes.Buffer
+	_, err = latency.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `calls_count{operation="create_match"} 1`)
+	assert.Contains(t, out.String(), `calls_count{operation="attack"} 1`)
+	assert.Contains(t, out.String(), `calls_count{operation="get_state"} 1`)
+	assert.NotContains(t, out.String(), "join_match", "only the busiest calls are timed")
+
+	// Calls without a decorator reach the wrapped service unchanged
+	active, err := lobby.ActiveMatch(ctx, "guest")
+	require.NoError(t, err)
+	assert.Equal(t, matchID, active)
+}
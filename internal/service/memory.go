@@ -1,9 +1,15 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,41 +22,163 @@ import (
 var (
 	_ controller.LobbyService = (*MemoryService)(nil)
 	_ controller.GameService  = (*MemoryService)(nil)
+	_ controller.AdminService = (*MemoryService)(nil)
+)
+
+var (
+	// errSeedUnused is returned when a match is given a seed but nothing in it is random.
+	errSeedUnused = errors.New("seed needs vs_bot or auto_place")
+	// errBotOneOnOne is returned when a match against the built-in opponent asks for more players.
+	errBotOneOnOne = errors.New("vs_bot is played one on one")
+	// errTeamsInFreeForAll is returned when a free-for-all is also given teams.
+	errTeamsInFreeForAll = errors.New("a free-for-all has no teams")
 )
 
 // MemoryService is an in-memory implementation of the lobby and game service.
 type MemoryService struct {
-	games    map[string]*safeGame
-	gamesMu  sync.RWMutex
-	notifier controller.NotificationService
+	games        map[string]*safeGame
+	byPlayer     map[string]map[string]*safeGame // Map[PlayerID]matches they host or joined, guarded by gamesMu
+	gamesMu      sync.RWMutex
+	notifier     controller.NotificationService
+	autoReady    bool
+	abandonAfter time.Duration
+	maxActive    int // Unfinished matches a player may take part in at once; zero or less for no limit
+	maxTotal     int // Matches the server holds at once, finished ones being evicted first; zero or less for no limit
+	gcConfig     GCConfig
+	logger       *slog.Logger
+
+	done      chan struct{} // Closed to stop the cleanup loop
+	stopped   chan struct{} // Closed once the cleanup loop has returned
+	closeOnce sync.Once
+}
+
+// GCConfig controls how often the service looks for matches to reclaim and how long they are kept.
+type GCConfig struct {
+	Interval    time.Duration // Time between two sweeps
+	FinishedTTL time.Duration // How long a finished match is kept after its last update
+	StaleTTL    time.Duration // How long an unfinished match is kept without any update
+}
+
+// DefaultGCConfig returns the garbage collection settings used when none are given.
+func DefaultGCConfig() GCConfig {
+	return GCConfig{
+		Interval:    time.Minute,
+		FinishedTTL: 10 * time.Minute,
+		StaleTTL:    24 * time.Hour,
+	}
+}
+
+// Option configures optional MemoryService behavior.
+type Option func(*MemoryService)
+
+// WithAutoReady marks players ready as soon as their fleet is fully placed,
+// so the game starts without an explicit Ready call.
+func WithAutoReady(enabled bool) Option {
+	return func(s *MemoryService) { s.autoReady = enabled }
+}
+
+// WithAbandonGracePeriod marks an in-progress match as abandoned once it has had
+// no subscribers and no activity for the given duration. Zero disables the check.
+func WithAbandonGracePeriod(d time.Duration) Option {
+	return func(s *MemoryService) { s.abandonAfter = d }
+}
+
+// WithMaxActiveGames sets how many unfinished matches a player may take part in at once,
+// counting the ones they host. Zero or less removes the limit. The default is one.
+func WithMaxActiveGames(n int) Option {
+	return func(s *MemoryService) { s.maxActive = n }
+}
+
+// WithMaxTotalGames sets how many matches the server holds at once, across all players.
+// Past it, finished matches are evicted to make room, least recently updated first, and new matches
+// are only refused while every match held is unfinished. Zero or less removes the limit, which is the default.
+func WithMaxTotalGames(n int) Option {
+	return func(s *MemoryService) { s.maxTotal = n }
+}
+
+// WithLogger sets the logger the service reports match cleanup, and the failures it recovers from, to.
+// The default logger is used otherwise.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *MemoryService) { s.logger = logger }
+}
+
+// WithGC overrides the garbage collection interval and TTLs.
+func WithGC(cfg GCConfig) Option {
+	return func(s *MemoryService) { s.gcConfig = cfg }
 }
 
 type safeGame struct {
 	id        string
-	game      *model.Game
-	host      string
-	guest     string
+	game      *model.Game // Its seats only change under MemoryService.gamesMu
 	createdAt time.Time
 	updatedAt time.Time
+	lastSeen  time.Time       // Last time the gc saw a subscriber on this match
+	history   []dto.GameEvent // Most recent events, replayed to reconnecting clients
+	settings  dto.MatchSettings
+	fleets    fleets            // Fleets the players start with
+	ai        *aiOpponent       // Built-in opponent in the second seat, if any
+	resume    map[string]string // Map[PlayerID]token that lets them take their seat back
 	mu        sync.Mutex
 }
 
+// fleets are the fleets the host and the guest start with, which differ when either has a handicap.
+// Every seat but the host's gets the guest's fleet.
+type fleets struct {
+	host, guest map[int]int
+}
+
+const (
+	// defaultMaxActiveGames is how many unfinished matches a player may take part in unless configured otherwise.
+	defaultMaxActiveGames = 1
+	// maxHistory bounds the events kept per match
+	maxHistory = 256
+	// maxBackfill bounds the events replayed to a single reconnecting client
+	maxBackfill = 50
+)
+
 // NewMemoryService creates a new in-memory lobby and game service.
-func NewMemoryService(n controller.NotificationService) *MemoryService {
+func NewMemoryService(n controller.NotificationService, opts ...Option) *MemoryService {
 	s := &MemoryService{
-		games:    make(map[string]*safeGame),
-		notifier: n,
+		games:     make(map[string]*safeGame),
+		byPlayer:  make(map[string]map[string]*safeGame),
+		notifier:  n,
+		maxActive: defaultMaxActiveGames,
+		gcConfig:  DefaultGCConfig(),
+		logger:    slog.Default(),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.gcConfig.Interval <= 0 {
+		s.gcConfig.Interval = DefaultGCConfig().Interval // NewTicker needs a positive interval
 	}
 	go s.cleanupLoop()
 	return s
 }
 
+// Close stops the background cleanup loop and waits for it to exit.
+// It is safe to call more than once.
+func (s *MemoryService) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+	<-s.stopped
+}
+
 func (s *MemoryService) cleanupLoop() {
-	ticker := time.NewTicker(time.Minute)
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.gcConfig.Interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		s.gc()
+	for {
+		select {
+		case <-ticker.C:
+			s.gc()
+		case <-s.done:
+			return
+		}
 	}
 }
 
@@ -59,142 +187,724 @@ func (s *MemoryService) gc() {
 	defer s.gamesMu.Unlock()
 
 	now := time.Now()
-	for id, g := range s.games {
+	for _, g := range s.games {
+		s.abandonIfIdle(g, now)
+
 		g.mu.Lock()
 		isFinished := g.game.IsGameOver()
 		lastUpdate := g.updatedAt
 		g.mu.Unlock()
 
 		if isFinished {
-			// Remove finished games after 10m
-			if now.Sub(lastUpdate) > 10*time.Minute {
-				delete(s.games, id)
+			// Remove finished games after a short while
+			if now.Sub(lastUpdate) > s.gcConfig.FinishedTTL {
+				s.removeGame(g, dto.StreamClosedGameOver)
+				s.logger.Debug("Removed finished match", "match_id", g.id)
 			}
 		} else {
-			// Remove stale games after 24h
-			if now.Sub(lastUpdate) > 24*time.Hour {
-				delete(s.games, id)
+			// Remove stale games
+			if now.Sub(lastUpdate) > s.gcConfig.StaleTTL {
+				s.removeGame(g, dto.StreamClosedIdleTimeout)
+				s.logger.Info("Removed stale match", "match_id", g.id, "last_update", lastUpdate)
 			}
 		}
 	}
 }
 
-// isUserInActiveGame checks if a user is currently in any active game.
-// Returns true and the match ID if found, false and empty string otherwise.
-func (s *MemoryService) isUserInActiveGame(playerID string) (isInGame bool, matchID string) {
+// abandonIfIdle ends a started match once nobody has been subscribed to it and
+// nobody has played for the abandon grace period.
+func (s *MemoryService) abandonIfIdle(g *safeGame, now time.Time) {
+	if s.abandonAfter <= 0 || s.notifier == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.playerCount() < 2 || g.game.IsGameOver() {
+		return
+	}
+
+	if s.notifier.SubscriberCount(g.id) > 0 {
+		g.lastSeen = now
+		return
+	}
+
+	lastActivity := g.updatedAt
+	if g.lastSeen.After(lastActivity) {
+		lastActivity = g.lastSeen
+	}
+	if now.Sub(lastActivity) <= s.abandonAfter {
+		return
+	}
+
+	if err := g.game.Abandon(); err != nil {
+		return
+	}
+	g.updatedAt = now
+	s.logger.Info("Abandoned idle match", "match_id", g.id, "last_activity", lastActivity)
+
+	s.publish(g, &dto.GameEvent{
+		Type:      dto.EventGameAbandoned,
+		MatchID:   g.id,
+		Timestamp: now,
+	})
+}
+
+// ActiveMatch returns the ID of the unfinished match the player is in.
+// When the limit allows several, the most recently created one is returned.
+func (s *MemoryService) ActiveMatch(_ context.Context, playerID string) (string, error) {
 	s.gamesMu.RLock()
 	defer s.gamesMu.RUnlock()
 
-	for id, sg := range s.games {
-		if sg.host == playerID || sg.guest == playerID {
-			sg.mu.Lock()
-			isGameOver := sg.game.IsGameOver()
-			sg.mu.Unlock()
+	active := s.activeMatches(playerID)
+	if len(active) == 0 {
+		return "", controller.ErrMatchNotFound
+	}
+	return active[len(active)-1].id, nil
+}
 
-			if !isGameOver {
-				return true, id
-			}
+// addPlayer records that the player takes part in the match, in any seat.
+// The caller must hold s.gamesMu for writing.
+func (s *MemoryService) addPlayer(playerID string, sg *safeGame) {
+	matches, ok := s.byPlayer[playerID]
+	if !ok {
+		matches = make(map[string]*safeGame)
+		s.byPlayer[playerID] = matches
+	}
+	matches[sg.id] = sg
+}
+
+// removePlayer forgets that the player takes part in the match.
+// The caller must hold s.gamesMu for writing.
+func (s *MemoryService) removePlayer(playerID string, sg *safeGame) {
+	if matches, ok := s.byPlayer[playerID]; ok {
+		delete(matches, sg.id)
+		if len(matches) == 0 {
+			delete(s.byPlayer, playerID)
+		}
+	}
+}
+
+// removeGame forgets the match, along with its place in every seated player's index, and ends
+// its subscriptions with the reason since nothing more will happen in it. Events published before
+// are still delivered. The caller must hold s.gamesMu for writing, which keeps the seats from
+// changing without sg.mu.
+func (s *MemoryService) removeGame(sg *safeGame, reason string) {
+	delete(s.games, sg.id)
+
+	for _, playerID := range sg.game.Seats() {
+		s.removePlayer(playerID, sg)
+	}
+
+	if s.notifier != nil {
+		s.notifier.Close(sg.id, reason)
+	}
+}
+
+// activeMatches returns the matches the player hosts or has joined that are not over
+// (neither finished nor abandoned), oldest first.
+// Matches stay indexed until they are removed, since they may end while only their own lock is held.
+// The caller must hold s.gamesMu.
+func (s *MemoryService) activeMatches(playerID string) []*safeGame {
+	var active []*safeGame
+	for _, sg := range s.byPlayer[playerID] {
+		sg.mu.Lock()
+		over := sg.game.IsGameOver()
+		sg.mu.Unlock()
+
+		if !over {
+			active = append(active, sg)
 		}
 	}
-	return false, ""
+
+	slices.SortFunc(active, func(a, b *safeGame) int { return a.createdAt.Compare(b.createdAt) })
+	return active
+}
+
+// checkActiveLimit fails with ErrTooManyActiveGames if the player may not take part in another match.
+// The caller must hold s.gamesMu.
+func (s *MemoryService) checkActiveLimit(playerID string) error {
+	if s.maxActive <= 0 {
+		return nil
+	}
+
+	active := s.activeMatches(playerID)
+	if len(active) < s.maxActive {
+		return nil
+	}
+
+	latest := active[len(active)-1].id
+	if len(active) == 1 {
+		return fmt.Errorf("%w: player is already in an active game (Match ID: %s)",
+			controller.ErrTooManyActiveGames, latest)
+	}
+	return fmt.Errorf("%w: player is already in %d active games (latest Match ID: %s)",
+		controller.ErrTooManyActiveGames, len(active), latest)
+}
+
+// checkCapacity makes room for another match once the server holds as many as it may.
+// Finished matches are evicted before their time, least recently updated first; if every match
+// held is unfinished, it fails with ErrServerAtCapacity instead.
+// The caller must hold s.gamesMu for writing.
+func (s *MemoryService) checkCapacity() error {
+	if s.maxTotal <= 0 || len(s.games) < s.maxTotal {
+		return nil
+	}
+
+	type finishedGame struct {
+		sg        *safeGame
+		updatedAt time.Time
+	}
+	var finished []finishedGame
+	for _, sg := range s.games {
+		sg.mu.Lock()
+		if sg.game.IsGameOver() {
+			finished = append(finished, finishedGame{sg: sg, updatedAt: sg.updatedAt})
+		}
+		sg.mu.Unlock()
+	}
+
+	if active := len(s.games) - len(finished); active >= s.maxTotal {
+		return fmt.Errorf("%w: %d active games", controller.ErrServerAtCapacity, active)
+	}
+
+	slices.SortFunc(finished, func(a, b finishedGame) int { return a.updatedAt.Compare(b.updatedAt) })
+	for _, f := range finished[:len(s.games)-s.maxTotal+1] {
+		s.removeGame(f.sg, dto.StreamClosedGameOver)
+		s.logger.Debug("Evicted finished match to make room", "match_id", f.sg.id)
+	}
+	return nil
 }
 
 // CreateMatch initializes a new game with the host player joined.
-func (s *MemoryService) CreateMatch(_ context.Context, hostID string) (string, error) {
-	// Check if user is already in an active game
-	if inGame, matchID := s.isUserInActiveGame(hostID); inGame {
-		return "", fmt.Errorf("player is already in an active game (Match ID: %s)", matchID)
+// Zero-valued settings fall back to the standard board and fleet.
+func (s *MemoryService) CreateMatch(
+	_ context.Context,
+	hostID string,
+	settings dto.MatchSettings,
+) (string, error) {
+	settings, fleets, err := resolveSettings(settings)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+	if settings.Seed == 0 && (settings.VsBot || settings.AutoPlace) {
+		settings.Seed = newSeed()
+	}
+
+	game, err := model.NewGameOfSize(settings.BoardSize)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+	if err := game.SetMoveLimit(settings.MoveLimit); err != nil {
+		return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+	if err := game.SetAssist(settings.Assist); err != nil {
+		return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+	if err := game.SetExtraTurnOnHit(settings.ExtraTurnOnHit); err != nil {
+		return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+	if settings.TeamSize != 0 {
+		if err := game.SetTeamSize(settings.TeamSize); err != nil {
+			return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+		}
+	}
+	if settings.FreeForAll != 0 {
+		if err := game.SetFreeForAll(settings.FreeForAll); err != nil {
+			return "", fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+		}
 	}
 
 	gameID := fmt.Sprintf("game-%v", uuid.NewString())
 	sg := &safeGame{
-		game:      model.NewGame(),
+		game:      game,
 		id:        gameID,
 		createdAt: time.Now(),
 		updatedAt: time.Now(),
-		host:      hostID,
+		settings:  settings,
+		fleets:    fleets,
+		resume:    map[string]string{hostID: rand.Text()},
 	}
 
-	err := sg.game.Join(hostID, model.StandardFleet())
+	err = sg.game.Join(hostID, sg.fleets.host)
 	if err != nil {
 		return "", err
 	}
 
+	if settings.VsBot {
+		if err := sg.seatOpponent(settings.Difficulty); err != nil {
+			return "", err
+		}
+		if err := sg.autoPlace(s.autoReady); err != nil {
+			return "", err
+		}
+	}
+
 	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
+
+	// Checked while holding the lock, so that concurrent requests cannot both get through
+	if err := s.checkActiveLimit(hostID); err != nil {
+		return "", err
+	}
+	if err := s.checkCapacity(); err != nil {
+		return "", err
+	}
 	s.games[gameID] = sg
-	s.gamesMu.Unlock()
+	s.addPlayer(hostID, sg)
 
 	return gameID, nil
 }
 
-// ListMatches returns all games and their summaries.
-func (s *MemoryService) ListMatches(_ context.Context) ([]dto.MatchSummary, error) {
+// practiceSeed fixes the practice board, so that every player is shown the same layout.
+const practiceSeed = 1
+
+// PracticeBoard places the fleet the board size and fleet preset pick the way auto placement would,
+// with a fixed seed. Other settings are ignored.
+func (s *MemoryService) PracticeBoard(_ context.Context, settings dto.MatchSettings) (dto.BoardView, error) {
+	settings, fleets, err := resolveSettings(dto.MatchSettings{
+		BoardSize:   settings.BoardSize,
+		FleetPreset: settings.FleetPreset,
+	})
+	if err != nil {
+		return dto.BoardView{}, fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+
+	game, err := model.NewGameOfSize(settings.BoardSize)
+	if err != nil {
+		return dto.BoardView{}, fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+	// Ships are placed during setup, which starts once a second player joins
+	const playerID = "practice"
+	for _, id := range []string{playerID, "practice-opponent"} {
+		if err := game.Join(id, fleets.host); err != nil {
+			return dto.BoardView{}, err
+		}
+	}
+	if err := placeFleetAtRandom(game, playerID, fleets.host, matchRNG(practiceSeed, hostSide)); err != nil {
+		return dto.BoardView{}, err
+	}
+
+	view, err := game.GetView(playerID)
+	if err != nil {
+		return dto.BoardView{}, err
+	}
+	return view.Me.Board, nil
+}
+
+// ListMatches returns the summaries of the matches selected by filter.
+// Finished and abandoned matches are never listed.
+func (s *MemoryService) ListMatches(
+	_ context.Context,
+	filter dto.MatchFilter,
+) ([]dto.MatchSummary, error) {
 	s.gamesMu.RLock()
 	defer s.gamesMu.RUnlock()
 
-	matches := make([]dto.MatchSummary, len(s.games))
+	matches := make([]dto.MatchSummary, 0, len(s.games))
 	for matchID, sg := range s.games {
 		sg.mu.Lock()
-		matches = append(matches, dto.MatchSummary{
-			ID:          matchID,
-			CreatedAt:   sg.createdAt,
-			HostName:    sg.host,
-			PlayerCount: sg.playerCount(),
-		})
+		if state := sg.game.State(); isListed(state, filter) {
+			settings := sg.settings
+			settings.Seed = 0 // Only told to the players once the match is over
+			matches = append(matches, dto.MatchSummary{
+				ID:          matchID,
+				CreatedAt:   sg.createdAt,
+				HostName:    sg.host(),
+				PlayerCount: sg.playerCount(),
+				State:       state,
+				Watchers:    s.watchers(matchID),
+				Settings:    settings,
+			})
+		}
 		sg.mu.Unlock()
 	}
 
 	return matches, nil
 }
 
+// Scoreboard summarizes every match in setup or in play, the most recently active first.
+// It tells only what a spectator may know: who plays, whose turn it is and how many ships are afloat.
+func (s *MemoryService) Scoreboard(_ context.Context) ([]dto.ScoreboardEntry, error) {
+	s.gamesMu.RLock()
+	defer s.gamesMu.RUnlock()
+
+	entries := make([]dto.ScoreboardEntry, 0, len(s.games))
+	for matchID, sg := range s.games {
+		sg.mu.Lock()
+		if entry, ok := sg.scoreboardEntry(matchID); ok {
+			entry.Watchers = s.watchers(matchID)
+			entries = append(entries, entry)
+		}
+		sg.mu.Unlock()
+	}
+
+	slices.SortFunc(entries, func(a, b dto.ScoreboardEntry) int {
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), strings.Compare(a.MatchID, b.MatchID))
+	})
+	return entries, nil
+}
+
+// scoreboardEntry describes the match for the scoreboard, if it is in setup or in play.
+// It must be called with sg.mu held.
+func (sg *safeGame) scoreboardEntry(matchID string) (dto.ScoreboardEntry, bool) {
+	state := sg.game.State()
+	if state != dto.StateSetup && state != dto.StatePlaying {
+		return dto.ScoreboardEntry{}, false
+	}
+	seats := sg.game.Seats()
+	players := make([]dto.ScoreboardPlayer, 0, len(seats))
+	var turn string
+	for _, playerID := range seats {
+		view, err := sg.game.GetView(playerID)
+		if err != nil {
+			return dto.ScoreboardEntry{}, false
+		}
+		players = append(players, dto.ScoreboardPlayer{ID: playerID, ShipsAfloat: view.Me.ShipsAfloat})
+		turn = view.Turn
+	}
+
+	return dto.ScoreboardEntry{
+		MatchID:   matchID,
+		State:     state,
+		Players:   players,
+		Turn:      turn,
+		Shots:     len(sg.game.Moves()),
+		UpdatedAt: sg.updatedAt,
+	}, true
+}
+
+// isListed reports whether a match in the given state belongs in a listing built with filter.
+func isListed(state dto.GameState, filter dto.MatchFilter) bool {
+	switch state {
+	case dto.StateWaiting:
+		return true
+	case dto.StateSetup, dto.StatePlaying:
+		return filter.IncludeLive
+	default:
+		return false
+	}
+}
+
 // JoinMatch adds a player to an existing match.
 func (s *MemoryService) JoinMatch(
 	_ context.Context,
 	matchID, playerID string,
 ) (dto.GameView, error) {
-	// Check if user is already in an active game
-	if inGame, existingMatchID := s.isUserInActiveGame(playerID); inGame {
-		return dto.GameView{}, fmt.Errorf(
-			"player is already in an active game (Match ID: %s)",
-			existingMatchID,
-		)
-	}
+	// Held for writing, so that the limit check and the join are atomic
+	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
 
-	s.gamesMu.RLock()
-	defer s.gamesMu.RUnlock()
+	game, exists := s.games[matchID]
+	if !exists {
+		return dto.GameView{}, controller.ErrMatchNotFound
+	}
 
-	game, err := s.getSafeGame(matchID)
-	if err != nil {
+	if err := s.checkActiveLimit(playerID); err != nil {
 		return dto.GameView{}, err
 	}
 
 	game.mu.Lock()
-	err = game.game.Join(playerID, model.StandardFleet())
-	game.guest = playerID
+	defer game.mu.Unlock()
+
+	if err := game.game.Join(playerID, game.fleets.guest); err != nil {
+		return dto.GameView{}, err
+	}
+	game.resume[playerID] = rand.Text()
 	game.updatedAt = time.Now()
-	game.mu.Unlock()
+	s.addPlayer(playerID, game)
 
+	// The player holds the seat by now, so a failed placement leaves the fleets to be placed by hand
+	if err := game.autoPlace(s.autoReady); err != nil {
+		s.logger.Error("Could not place the fleets at random", "match_id", matchID, "error", err)
+	}
+
+	view, err := game.view(playerID)
 	if err != nil {
 		return dto.GameView{}, err
 	}
 
-	view, err := game.game.GetView(playerID)
-	if err != nil {
+	// Emit event: player joined, to everyone seated before them
+	s.publishToSeats(game, dto.GameEvent{
+		Type:      dto.EventPlayerJoined,
+		MatchID:   matchID,
+		PlayerID:  playerID,
+		Timestamp: time.Now(),
+	})
+
+	return view, nil
+}
+
+// ResumeMatch hands the seat the token belongs to over to the player, e.g. after logging in
+// again on another device. The seat keeps its fleet, shots and turn, and the same token.
+func (s *MemoryService) ResumeMatch(
+	_ context.Context,
+	matchID, playerID, token string,
+) (dto.GameView, error) {
+	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
+
+	sg, exists := s.games[matchID]
+	if !exists {
+		return dto.GameView{}, controller.ErrMatchNotFound
+	}
+
+	// Seats only change hands here, under s.gamesMu, so the holder cannot change before the swap
+	sg.mu.Lock()
+	previous := sg.seatOf(token)
+	seated := sg.isSeated(playerID)
+	sg.mu.Unlock()
+	switch {
+	case previous == "":
+		return dto.GameView{}, controller.ErrInvalidResumeToken
+	case seated && previous != playerID:
+		return dto.GameView{}, model.ErrAlreadySeated
+	case previous != playerID:
+		if err := s.checkActiveLimit(playerID); err != nil {
+			return dto.GameView{}, err
+		}
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if previous == playerID {
+		return sg.view(playerID)
+	}
+	if err := sg.game.ReplacePlayer(previous, playerID); err != nil {
 		return dto.GameView{}, err
 	}
 
-	// Emit event: player joined
+	sg.resume[playerID] = token
+	delete(sg.resume, previous)
+	for i := range sg.history {
+		sg.history[i].PlayerID = replaceID(sg.history[i].PlayerID, previous, playerID)
+		sg.history[i].TargetID = replaceID(sg.history[i].TargetID, previous, playerID)
+	}
+	sg.updatedAt = time.Now()
+	s.removePlayer(previous, sg)
+	s.addPlayer(playerID, sg)
+
+	s.publishToSeats(sg, dto.GameEvent{
+		Type:      dto.EventPlayerResumed,
+		MatchID:   matchID,
+		PlayerID:  playerID,
+		Timestamp: time.Now(),
+		Data:      dto.PlayerResumedEventData{Previous: previous},
+	})
+
+	return sg.view(playerID)
+}
+
+// replaceID returns newID if id is oldID, and id otherwise.
+func replaceID(id, oldID, newID string) string {
+	if id == oldID {
+		return newID
+	}
+	return id
+}
+
+// publishToSeats publishes a copy of the event to every seated player but the one who caused it,
+// each copy naming its player as the target. The history keeps the event once, naming the target
+// only when there is a single one, so that replays hold each event once whatever the number of seats.
+// Nothing is published while nobody else is seated. The caller must hold sg.mu.
+func (s *MemoryService) publishToSeats(sg *safeGame, event dto.GameEvent) {
+	targets := slices.DeleteFunc(sg.game.Seats(), func(id string) bool { return id == event.PlayerID })
+	if len(targets) == 0 {
+		return
+	}
+
+	event.Version = sg.game.Version()
+	recorded := event
+	if len(targets) == 1 {
+		recorded.TargetID = targets[0]
+	}
+	sg.record(recorded)
+
+	for _, playerID := range targets {
+		e := event
+		e.TargetID = playerID
+		s.notify(&e)
+	}
+}
+
+// publish stamps the event with the game version, records it in the match history and notifies subscribers.
+// The caller must hold sg.mu.
+func (s *MemoryService) publish(sg *safeGame, event *dto.GameEvent) {
+	event.Version = sg.game.Version()
+	sg.record(*event)
+	s.notify(event)
+}
+
+// notify sends the event to the subscribers, if there is a notifier.
+func (s *MemoryService) notify(event *dto.GameEvent) {
 	if s.notifier != nil {
-		s.notifier.Publish(&dto.GameEvent{
-			Type:      dto.EventPlayerJoined,
-			MatchID:   matchID,
-			PlayerID:  playerID,
-			TargetID:  game.host, // Notify the host
-			Timestamp: time.Now(),
-		})
+		s.notifier.Publish(event)
 	}
+}
 
-	return view, nil
+// watchers counts the subscribers to the match, none if there is no notifier.
+func (s *MemoryService) watchers(matchID string) int {
+	if s.notifier == nil {
+		return 0
+	}
+	return s.notifier.SubscriberCount(matchID)
+}
+
+// record appends the event to the match history, dropping the oldest beyond maxHistory.
+// The caller must hold sg.mu.
+func (sg *safeGame) record(event dto.GameEvent) {
+	sg.history = append(sg.history, event)
+	if len(sg.history) > maxHistory {
+		sg.history = slices.Delete(sg.history, 0, len(sg.history)-maxHistory)
+	}
+}
+
+// DeleteMatch removes a match at its host's request, notifying every other player seated.
+// A match whose settings name an owner, such as a tournament, is only removed at the owner's request.
+func (s *MemoryService) DeleteMatch(_ context.Context, matchID, playerID string) error {
+	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
+
+	sg, exists := s.games[matchID]
+	if !exists {
+		return controller.ErrMatchNotFound
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	if cmp.Or(sg.settings.Owner, sg.host()) != playerID {
+		return controller.ErrNotHost
+	}
+
+	// Emit event: match cancelled, before removing the match ends its subscriptions
+	s.publishToSeats(sg, dto.GameEvent{
+		Type:      dto.EventMatchCancelled,
+		MatchID:   matchID,
+		PlayerID:  playerID,
+		Timestamp: time.Now(),
+	})
+	s.removeGame(sg, dto.StreamClosedMatchCancelled)
+
+	return nil
+}
+
+// TerminateMatch removes a match whatever its state, for operators clearing one that got stuck.
+// Its subscribers are sent a match.terminated event, after which their streams are closed.
+func (s *MemoryService) TerminateMatch(_ context.Context, matchID string) error {
+	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
+
+	sg, exists := s.games[matchID]
+	if !exists {
+		return controller.ErrMatchNotFound
+	}
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+
+	s.publish(sg, &dto.GameEvent{
+		Type:      dto.EventMatchTerminated,
+		MatchID:   matchID,
+		Timestamp: time.Now(),
+	})
+	s.removeGame(sg, dto.StreamClosedMatchTerminated)
+
+	s.logger.Warn("Terminated match", "match_id", matchID, "state", sg.game.State())
+	return nil
+}
+
+// resolveSettings fills in the defaults for zero-valued settings
+// and returns the fleet they select, checked against the board size.
+func resolveSettings(settings dto.MatchSettings) (dto.MatchSettings, fleets, error) {
+	if settings.BoardSize == 0 {
+		settings.BoardSize = model.GridSize
+	}
+	if settings.FleetPreset == "" {
+		settings.FleetPreset = model.PresetStandard
+	}
+
+	// Every problem is collected, so that they can all be fixed at once
+	var problems []error
+	if settings.Seed != 0 && !settings.VsBot && !settings.AutoPlace {
+		problems = append(problems, errSeedUnused)
+	}
+	if settings.MoveLimit < 0 {
+		problems = append(problems, model.ErrInvalidMoveLimit)
+	}
+	if settings.TeamSize < 0 || settings.TeamSize > model.MaxTeamSize {
+		problems = append(problems, fmt.Errorf("%w: %d", model.ErrInvalidTeamSize, settings.TeamSize))
+	}
+	if settings.FreeForAll != 0 && (settings.FreeForAll < 3 || settings.FreeForAll > model.MaxFreeForAllPlayers) {
+		problems = append(problems, fmt.Errorf("%w: %d", model.ErrInvalidPlayerCount, settings.FreeForAll))
+	}
+	if settings.FreeForAll != 0 && settings.TeamSize > 1 {
+		problems = append(problems, errTeamsInFreeForAll)
+	}
+	if settings.VsBot && (settings.TeamSize > 1 || settings.FreeForAll != 0) {
+		problems = append(problems, errBotOneOnOne)
+	}
+
+	switch {
+	case !settings.VsBot:
+		settings.Difficulty = ""
+	case settings.Difficulty == "":
+		settings.Difficulty = dto.DifficultyMedium
+	default:
+		if err := validateDifficulty(settings.Difficulty); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	var seats fleets
+	fleet, err := model.FleetPreset(settings.FleetPreset)
+	if err != nil {
+		problems = append(problems, err)
+	} else if err := model.ValidateFleet(fleet, settings.BoardSize); err != nil {
+		problems = append(problems, splitJoined(err)...)
+	} else {
+		// The handicapped fleets are only checked once the shared one is fine, so no problem is told twice
+		var hostProblems, guestProblems []error
+		seats.host, hostProblems = handicapFleet(fleet, settings.HostHandicap, settings.BoardSize, "host")
+		seats.guest, guestProblems = handicapFleet(fleet, settings.GuestHandicap, settings.BoardSize, "guest")
+		problems = append(append(problems, hostProblems...), guestProblems...)
+	}
+
+	if len(problems) > 0 {
+		return dto.MatchSettings{}, fleets{}, &controller.ValidationError{Problems: problems}
+	}
+	return settings, seats, nil
+}
+
+// handicapFleet applies a seat's handicap to the fleet and checks the result against the board.
+// The problems found name the seat.
+func handicapFleet(fleet map[int]int, handicap map[string]int, boardSize int, seat string) (map[int]int, []error) {
+	if len(handicap) == 0 {
+		return fleet, nil
+	}
+
+	adjusted, err := model.Handicap(fleet, handicap)
+	if err == nil {
+		err = model.ValidateFleet(adjusted, boardSize)
+	}
+	if err != nil {
+		var problems []error
+		for _, problem := range splitJoined(err) {
+			problems = append(problems, fmt.Errorf("%s handicap: %w", seat, problem))
+		}
+		return nil, problems
+	}
+	return adjusted, nil
+}
+
+// splitJoined returns the errors joined into err, or err alone.
+func splitJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
 }
 
 func (s *MemoryService) getSafeGame(matchID string) (*safeGame, error) {
@@ -203,20 +913,100 @@ func (s *MemoryService) getSafeGame(matchID string) (*safeGame, error) {
 
 	sg, exists := s.games[matchID]
 	if !exists {
-		return nil, errors.New("match not found")
+		return nil, controller.ErrMatchNotFound
 	}
 
 	return sg, nil
 }
 
-// playerCount returns the number of players in the game.
-func (sg *safeGame) playerCount() int {
-	count := 0
-	if sg.host != "" {
-		count++
+// seatOpponent seats the built-in opponent as the guest, with its fleet placed and ready.
+func (sg *safeGame) seatOpponent(difficulty string) error {
+	// The opponent draws from the guest's stream, so a seed gives it the fleet a guest would get
+	ai := newAIOpponent(difficulty, matchRNG(sg.settings.Seed, guestSide))
+	if err := sg.game.Join(ai.id, sg.fleets.guest); err != nil {
+		return err
+	}
+	if err := ai.placeFleet(sg.game, sg.fleets.guest); err != nil {
+		return err
+	}
+
+	sg.ai = ai
+	return nil
+}
+
+// autoPlace places the human players' fleets at random once every seat is taken, when the match
+// asks for it, marking them ready if ready is set. The built-in opponent places its own.
+func (sg *safeGame) autoPlace(ready bool) error {
+	if !sg.settings.AutoPlace || sg.game.State() != dto.StateSetup {
+		return nil
+	}
+
+	// The first player seated on each side places the fleet their teammates share
+	for seat, playerID := range sg.game.Seats()[:sg.game.Sides()] {
+		if sg.ai != nil && playerID == sg.ai.id {
+			continue
+		}
+		rng := matchRNG(sg.settings.Seed, hostSide+uint64(seat))
+		if err := placeFleetAtRandom(sg.game, playerID, sg.fleetOf(seat), rng); err != nil {
+			return err
+		}
+		if ready {
+			if err := sg.game.SetReady(playerID); err != nil {
+				return err
+			}
+		}
 	}
-	if sg.guest != "" {
-		count++
+	return nil
+}
+
+// view returns the game as the player sees it. The seed is only told once the match is over,
+// as it would otherwise let a player rebuild the opponent's fleet.
+func (sg *safeGame) view(playerID string) (dto.GameView, error) {
+	view, err := sg.game.GetView(playerID)
+	if err != nil {
+		return dto.GameView{}, err
 	}
-	return count
+	if sg.game.IsGameOver() {
+		view.Seed = sg.settings.Seed
+	}
+	view.ResumeToken = sg.resume[playerID]
+	view.Rules = sg.rules()
+	return view, nil
+}
+
+// seatOf returns the player whose seat the resume token belongs to, or an empty string.
+func (sg *safeGame) seatOf(token string) string {
+	for playerID, t := range sg.resume {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return playerID
+		}
+	}
+	return ""
+}
+
+// host returns the player who created the match, who holds the first seat.
+func (sg *safeGame) host() string {
+	seats := sg.game.Seats()
+	if len(seats) == 0 {
+		return ""
+	}
+	return seats[0]
+}
+
+// isSeated reports whether the player has a seat in the match.
+func (sg *safeGame) isSeated(playerID string) bool {
+	return slices.Contains(sg.game.Seats(), playerID)
+}
+
+// fleetOf returns the fleet the given seat starts with: the host's for the first one, the guest's otherwise.
+func (sg *safeGame) fleetOf(seat int) map[int]int {
+	if seat == 0 {
+		return sg.fleets.host
+	}
+	return sg.fleets.guest
+}
+
+// playerCount returns the number of players seated in the game.
+func (sg *safeGame) playerCount() int {
+	return len(sg.game.Seats())
 }
@@ -1,10 +1,16 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
+	"maps"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -13,12 +19,13 @@ func TestMemoryService_Cleanup(t *testing.T) {
 	t.Parallel()
 
 	s := NewMemoryService(NewNotificationService())
+	t.Cleanup(s.Close)
 	ctx := context.Background()
 
-	activeID, err := s.CreateMatch(ctx, "host")
+	activeID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
 	require.NoError(t, err)
 
-	staleID, mlErr := s.CreateMatch(ctx, "stale")
+	staleID, mlErr := s.CreateMatch(ctx, "stale", dto.MatchSettings{})
 	require.NoError(t, mlErr)
 
 	s.gamesMu.Lock()
@@ -35,3 +42,216 @@ func TestMemoryService_Cleanup(t *testing.T) {
 	assert.True(t, activeExists, "Active game should exist")
 	assert.False(t, staleExists, "Stale game should be removed")
 }
+
+func TestMemoryService_AbandonIdleMatch(t *testing.T) {
+	t.Parallel()
+
+	n := NewNotificationService()
+	s := NewMemoryService(n, WithAbandonGracePeriod(time.Minute))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+
+	sub, events := n.Subscribe("*")
+	defer sub.Unsubscribe()
+
+	watchSub, _ := n.Subscribe(matchID)
+	assert.Equal(t, 1, n.SubscriberCount(matchID))
+
+	s.gamesMu.Lock()
+	s.games[matchID].updatedAt = time.Now().Add(-time.Hour)
+	s.gamesMu.Unlock()
+
+	s.gc()
+
+	view, err := s.GetState(ctx, matchID, "host")
+	require.NoError(t, err)
+	assert.NotEqual(t, dto.StateAbandoned, view.State, "Watched match should not be abandoned")
+
+	watchSub.Unsubscribe()
+	assert.Equal(t, 0, n.SubscriberCount(matchID))
+
+	s.gamesMu.Lock()
+	s.games[matchID].updatedAt = time.Now().Add(-time.Hour)
+	s.games[matchID].lastSeen = time.Now().Add(-time.Hour)
+	s.gamesMu.Unlock()
+
+	s.gc()
+
+	view, err = s.GetState(ctx, matchID, "host")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateAbandoned, view.State)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, dto.EventGameAbandoned, event.Type)
+		assert.Equal(t, matchID, event.MatchID)
+	default:
+		t.Fatal("expected a game.abandoned event")
+	}
+
+	_, err = s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	assert.NoError(t, err, "Host should be free to start a new match")
+}
+
+func TestMemoryService_GCReclaimsFinishedGame(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryService(NewNotificationService(), WithGC(GCConfig{
+		Interval:    5 * time.Millisecond,
+		FinishedTTL: time.Millisecond,
+		StaleTTL:    time.Hour,
+	}))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	finishedID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	activeID, err := s.CreateMatch(ctx, "other", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	// Play a one-ship game to the end
+	g := model.NewFullGame("host", "guest", map[int]int{1: 1})
+	require.NoError(t, g.PlaceShip("host", model.Coordinate{X: 0, Y: 0}, 1, model.Horizontal))
+	require.NoError(t, g.PlaceShip("guest", model.Coordinate{X: 0, Y: 0}, 1, model.Horizontal))
+	require.NoError(t, g.StartGame())
+	_, err = g.Attack("host", model.Coordinate{X: 0, Y: 0})
+	require.NoError(t, err)
+	require.True(t, g.IsGameOver())
+
+	s.gamesMu.Lock()
+	s.games[finishedID].mu.Lock()
+	s.games[finishedID].game = g
+	s.games[finishedID].mu.Unlock()
+	s.gamesMu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		s.gamesMu.RLock()
+		defer s.gamesMu.RUnlock()
+		_, exists := s.games[finishedID]
+		return !exists
+	}, time.Second, 5*time.Millisecond, "Finished game should be reclaimed")
+
+	s.gamesMu.RLock()
+	_, activeExists := s.games[activeID]
+	s.gamesMu.RUnlock()
+	assert.True(t, activeExists, "Active game should be kept")
+}
+
+func TestMemoryService_Close(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryService(NewNotificationService(), WithGC(GCConfig{
+		Interval:    time.Millisecond,
+		FinishedTTL: time.Minute,
+		StaleTTL:    time.Hour,
+	}))
+
+	s.Close()
+
+	select {
+	case <-s.stopped:
+	default:
+		t.Fatal("cleanup loop should have exited")
+	}
+
+	assert.NotPanics(t, s.Close, "Close should be idempotent")
+}
+
+func TestMemoryService_PlayerIndex(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryService(NewNotificationService(), WithMaxActiveGames(0))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	hosted, err := s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+	require.NoError(t, err)
+	joined, err := s.CreateMatch(ctx, "bob", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, joined, "alice")
+	require.NoError(t, err)
+
+	indexed := func(playerID string) []string {
+		s.gamesMu.RLock()
+		defer s.gamesMu.RUnlock()
+		return slices.Sorted(maps.Keys(s.byPlayer[playerID]))
+	}
+
+	assert.ElementsMatch(t, []string{hosted, joined}, indexed("alice"), "both roles are indexed")
+	assert.Equal(t, []string{joined}, indexed("bob"))
+
+	require.NoError(t, s.DeleteMatch(ctx, joined, "bob"))
+	assert.Equal(t, []string{hosted}, indexed("alice"))
+	assert.Empty(t, indexed("bob"))
+
+	s.gamesMu.Lock()
+	s.games[hosted].updatedAt = time.Now().Add(-25 * time.Hour)
+	s.gamesMu.Unlock()
+
+	s.gc()
+
+	s.gamesMu.RLock()
+	assert.Empty(t, s.byPlayer, "reclaimed matches leave the index")
+	s.gamesMu.RUnlock()
+}
+
+func TestMemoryService_PlayerIndexTeams(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryService(NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "a1", dto.MatchSettings{TeamSize: 2})
+	require.NoError(t, err)
+	for _, playerID := range []string{"b1", "a2", "b2"} {
+		_, err := s.JoinMatch(ctx, matchID, playerID)
+		require.NoError(t, err)
+	}
+
+	s.gamesMu.RLock()
+	assert.Len(t, s.byPlayer, 4, "every seat is indexed")
+	s.gamesMu.RUnlock()
+
+	require.NoError(t, s.DeleteMatch(ctx, matchID, "a1"))
+
+	s.gamesMu.RLock()
+	assert.Empty(t, s.byPlayer, "every seat leaves the index")
+	s.gamesMu.RUnlock()
+
+	_, err = s.CreateMatch(ctx, "a2", dto.MatchSettings{})
+	assert.NoError(t, err, "a player from a removed match may start another one")
+}
+
+func TestMemoryService_JoinAutoPlaceFails(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	s := NewMemoryService(NewNotificationService(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small", AutoPlace: true})
+	require.NoError(t, err)
+
+	// A ship longer than the board cannot be placed at random
+	s.gamesMu.Lock()
+	s.games[matchID].fleets.guest = map[int]int{7: 1}
+	s.gamesMu.Unlock()
+
+	view, err := s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err, "the seat is taken even though the fleets could not be placed")
+	assert.Equal(t, dto.StateSetup, view.State)
+	assert.Equal(t, 1, view.Me.Fleet[7], "the ship is left to be placed by hand")
+	assert.NotEmpty(t, view.ResumeToken)
+	assert.Contains(t, logs.String(), "Could not place the fleets at random")
+
+	active, err := s.ActiveMatch(ctx, "guest")
+	require.NoError(t, err)
+	assert.Equal(t, matchID, active)
+}
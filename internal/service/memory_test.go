@@ -2,9 +2,17 @@ package service_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/callegarimattia/battleship/internal/commitment"
+	"github.com/callegarimattia/battleship/internal/controller"
 	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/model"
 	"github.com/callegarimattia/battleship/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,13 +21,14 @@ import (
 func TestMemoryService_LobbyFlow(t *testing.T) {
 	t.Parallel()
 	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
 	ctx := context.Background()
 
-	matchID, err := s.CreateMatch(ctx, "host-1")
+	matchID, err := s.CreateMatch(ctx, "host-1", dto.MatchSettings{})
 	require.NoError(t, err)
 	assert.NotEmpty(t, matchID)
 
-	matches, err := s.ListMatches(ctx)
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{})
 	require.NoError(t, err)
 	assert.NotEmpty(t, matches)
 	found := false
@@ -37,17 +46,159 @@ func TestMemoryService_LobbyFlow(t *testing.T) {
 	assert.Equal(t, dto.StateSetup, view.State)
 	assert.Equal(t, "guest-1", view.Me.ID)
 
-	matches, _ = s.ListMatches(ctx)
+	matches, _ = s.ListMatches(ctx, dto.MatchFilter{})
+	assert.Empty(t, matches, "Started matches are not listed by default")
+
+	matches, _ = s.ListMatches(ctx, dto.MatchFilter{IncludeLive: true})
+	require.Len(t, matches, 1)
+	assert.Equal(t, 2, matches[0].PlayerCount)
+	assert.Equal(t, dto.StateSetup, matches[0].State)
+}
+
+func TestMemoryService_ListMatchesFilter(t *testing.T) {
+	t.Parallel()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	waitingID, err := s.CreateMatch(ctx, "host-1", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	liveID, err := s.CreateMatch(ctx, "host-2", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, liveID, "guest-2")
+	require.NoError(t, err)
+
+	sub, _ := notifier.Subscribe(liveID)
+	defer sub.Unsubscribe()
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, waitingID, matches[0].ID)
+	assert.Equal(t, dto.StateWaiting, matches[0].State)
+
+	matches, err = s.ListMatches(ctx, dto.MatchFilter{IncludeLive: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
 	for _, m := range matches {
-		if m.ID == matchID {
-			assert.Equal(t, 2, m.PlayerCount)
+		if m.ID == liveID {
+			assert.Equal(t, dto.StateSetup, m.State)
+			assert.Equal(t, 1, m.Watchers)
 		}
 	}
 }
 
+func TestMemoryService_NoNotifier(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(nil)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{IncludeLive: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Zero(t, matches[0].Watchers, "nobody can watch without a notifier")
+
+	entries, err := s.Scoreboard(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Zero(t, entries[0].Watchers)
+
+	require.NoError(t, s.DeleteMatch(ctx, matchID, "host"))
+}
+
+func TestMemoryService_Scoreboard(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.CreateMatch(ctx, "lonely", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	setupID, err := s.CreateMatch(ctx, "host-1", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, setupID, "guest-1")
+	require.NoError(t, err)
+
+	playingID, err := s.CreateMatch(ctx, "host-2", dto.MatchSettings{FleetPreset: "small", AutoPlace: true})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, playingID, "guest-2")
+	require.NoError(t, err)
+	for _, id := range []string{"host-2", "guest-2"} {
+		_, err = s.Ready(ctx, playingID, id)
+		require.NoError(t, err)
+	}
+	_, err = s.Attack(ctx, playingID, "host-2", 0, 0)
+	require.NoError(t, err)
+
+	entries, err := s.Scoreboard(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "matches waiting for an opponent are not in progress")
+
+	playing := entries[0]
+	assert.Equal(t, playingID, playing.MatchID, "the most recently active match comes first")
+	assert.Equal(t, dto.StatePlaying, playing.State)
+	assert.Equal(t, []dto.ScoreboardPlayer{{ID: "host-2", ShipsAfloat: 3}, {ID: "guest-2", ShipsAfloat: 3}},
+		playing.Players)
+	assert.Equal(t, "guest-2", playing.Turn)
+	assert.Equal(t, 1, playing.Shots)
+
+	setup := entries[1]
+	assert.Equal(t, setupID, setup.MatchID)
+	assert.Equal(t, dto.StateSetup, setup.State)
+	assert.Equal(t, []dto.ScoreboardPlayer{{ID: "host-1"}, {ID: "guest-1"}}, setup.Players)
+	assert.Empty(t, setup.Turn)
+}
+
+func TestMemoryService_TerminateMatch(t *testing.T) {
+	t.Parallel()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	require.ErrorIs(t, s.TerminateMatch(ctx, "missing"), controller.ErrMatchNotFound)
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+
+	hostSub, hostEvents := notifier.Subscribe(matchID)
+	defer hostSub.Unsubscribe() // Must not close the channel a second time
+	guestSub, guestEvents := notifier.Subscribe(matchID, dto.EventAttackMade)
+
+	require.NoError(t, s.TerminateMatch(ctx, matchID))
+
+	event, ok := <-hostEvents
+	require.True(t, ok, "the termination is delivered before the stream ends")
+	assert.Equal(t, dto.EventMatchTerminated, event.Type)
+	assert.Equal(t, matchID, event.MatchID)
+	_, ok = <-hostEvents
+	assert.False(t, ok, "the host's stream is closed")
+	_, ok = <-guestEvents
+	assert.False(t, ok, "streams not subscribed to the event are closed too")
+	assert.Equal(t, dto.StreamClosedMatchTerminated, guestSub.Reason(), "the filtered stream still learns why")
+	assert.Zero(t, notifier.SubscriberCount(matchID))
+
+	_, err = s.GetState(ctx, matchID, "host")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+	_, err = s.ActiveMatch(ctx, "guest")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound, "the players are free to play again")
+}
+
 func TestMemoryService_JoinErrors(t *testing.T) {
 	t.Parallel()
 	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
 	ctx := context.Background()
 
 	_, err := s.JoinMatch(ctx, "non-existent", "p1")
@@ -55,12 +206,35 @@ func TestMemoryService_JoinErrors(t *testing.T) {
 	assert.Contains(t, err.Error(), "match not found")
 }
 
+func TestMemoryService_HostCannotJoinOwnMatch(t *testing.T) {
+	t.Parallel()
+	// A limit high enough that it is the seat, not the active game count, that turns the host away
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxActiveGames(5))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	_, err = s.JoinMatch(ctx, matchID, "p1")
+	require.ErrorIs(t, err, model.ErrAlreadySeated)
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1, "the seat stays open for an opponent")
+	assert.Equal(t, dto.StateWaiting, matches[0].State)
+
+	_, err = s.JoinMatch(ctx, matchID, "p2")
+	require.NoError(t, err)
+}
+
 func TestMemoryService_GameplayFlow(t *testing.T) {
 	t.Parallel()
 	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
 	ctx := context.Background()
 
-	matchID, _ := s.CreateMatch(ctx, "p1")
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
 	_, _ = s.JoinMatch(ctx, matchID, "p2")
 
 	view, err := s.PlaceShip(ctx, matchID, "p1", 3, 0, 0, true)
@@ -75,36 +249,1476 @@ func TestMemoryService_GameplayFlow(t *testing.T) {
 	assert.Equal(t, dto.StateSetup, state.State)
 }
 
-func TestMemoryService_Attack_NotStarted(t *testing.T) {
+func TestMemoryService_ValidatePlacement(t *testing.T) {
 	t.Parallel()
 	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
 	ctx := context.Background()
 
-	matchID, _ := s.CreateMatch(ctx, "p1")
-	_, err := s.Attack(ctx, matchID, "p1", 0, 0)
-	assert.Error(t, err) // Game not started
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{FleetPreset: "small"})
+
+	check, err := s.ValidatePlacement(ctx, matchID, "p1", 3, 0, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PlacementNotInSetup, check.Code, "ships are placed once the opponent joined")
+
+	_, _ = s.JoinMatch(ctx, matchID, "p2")
+	_, err = s.PlaceShip(ctx, matchID, "p1", 3, 0, 0, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		size     int
+		x, y     int
+		vertical bool
+		want     dto.PlacementCheck
+	}{
+		{"Legal", 2, 0, 1, false, dto.PlacementCheck{Valid: true}},
+		{"Overlap", 2, 1, 0, true, dto.PlacementCheck{Code: dto.PlacementOverlap}},
+		{"Out of bounds", 2, 9, 0, false, dto.PlacementCheck{Code: dto.PlacementOutOfBounds}},
+		{"Depleted", 3, 0, 5, false, dto.PlacementCheck{Code: dto.PlacementDepleted}},
+	}
+	for _, tt := range tests {
+		check, err := s.ValidatePlacement(ctx, matchID, "p1", tt.size, tt.x, tt.y, tt.vertical)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want.Valid, check.Valid, tt.name)
+		assert.Equal(t, tt.want.Code, check.Code, tt.name)
+		assert.Equal(t, check.Valid, check.Reason == "", "%s: a reason is given for refusals only", tt.name)
+	}
+
+	view, err := s.GetState(ctx, matchID, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, view.Me.Fleet[2], "validating places nothing")
+
+	_, err = s.ValidatePlacement(ctx, "missing", "p1", 2, 0, 0, false)
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
 }
 
-func TestMemoryService_SingleActiveGameLimit(t *testing.T) {
+func TestMemoryService_PlaceShipType(t *testing.T) {
+	t.Parallel()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
+	_, _ = s.JoinMatch(ctx, matchID, "p2")
+	_, events := notifier.Subscribe(matchID)
+
+	view, err := s.PlaceShipType(ctx, matchID, "p1", "submarine", 0, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellShip, view.Me.Board.Grid[0][2])
+	assert.Equal(t, 1, view.Me.Fleet[3])
+
+	select {
+	case event := <-events:
+		assert.Equal(t, dto.ShipPlacedEventData{Size: 3, Ship: "Submarine"}, event.Data)
+	case <-time.After(time.Second):
+		require.FailNow(t, "no ship placed event")
+	}
+
+	_, err = s.PlaceShipType(ctx, matchID, "p1", "Dinghy", 0, 1, false)
+	assert.ErrorIs(t, err, model.ErrUnknownShipType)
+}
+
+func TestMemoryService_ReadyFlow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	placeFleet := func(s *service.MemoryService, matchID, playerID string) {
+		for y, size := range []int{5, 4, 3, 3, 2} {
+			_, err := s.PlaceShip(ctx, matchID, playerID, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("Explicit", func(t *testing.T) {
+		t.Parallel()
+
+		s := service.NewMemoryService(service.NewNotificationService())
+		t.Cleanup(s.Close)
+		matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
+		_, _ = s.JoinMatch(ctx, matchID, "p2")
+
+		_, err := s.Ready(ctx, matchID, "p1")
+		require.Error(t, err, "should not be ready before placing the fleet")
+
+		placeFleet(s, matchID, "p1")
+		placeFleet(s, matchID, "p2")
+
+		view, err := s.Ready(ctx, matchID, "p1")
+		require.NoError(t, err)
+		assert.Equal(t, dto.StateSetup, view.State, "should wait for the opponent")
+
+		view, err = s.Ready(ctx, matchID, "p2")
+		require.NoError(t, err)
+		assert.Equal(t, dto.StatePlaying, view.State)
+	})
+
+	t.Run("AutoReady", func(t *testing.T) {
+		t.Parallel()
+
+		s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+		t.Cleanup(s.Close)
+		matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
+		_, _ = s.JoinMatch(ctx, matchID, "p2")
+
+		placeFleet(s, matchID, "p1")
+		placeFleet(s, matchID, "p2")
+
+		view, err := s.GetState(ctx, matchID, "p1")
+		require.NoError(t, err)
+		assert.Equal(t, dto.StatePlaying, view.State)
+		assert.True(t, view.YourTurn)
+
+		yourTurn, err := s.IsPlayersTurn(ctx, matchID, "p2")
+		require.NoError(t, err)
+		assert.False(t, yourTurn)
+
+		_, err = s.IsPlayersTurn(ctx, matchID, "ghost")
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryService_DeleteMatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	n := service.NewNotificationService()
+	s := service.NewMemoryService(n)
+	t.Cleanup(s.Close)
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+
+	sub, events := n.Subscribe(matchID)
+	defer sub.Unsubscribe()
+	attackSub, attacks := n.Subscribe(matchID, dto.EventAttackMade)
+
+	err = s.DeleteMatch(ctx, matchID, "guest")
+	require.ErrorIs(t, err, controller.ErrNotHost)
+
+	require.NoError(t, s.DeleteMatch(ctx, matchID, "host"))
+
+	_, ok := <-attacks
+	assert.False(t, ok, "a stream filtering out the cancellation is closed")
+	assert.Equal(t, dto.StreamClosedMatchCancelled, attackSub.Reason(), "the filtered stream still learns why")
+
+	select {
+	case event := <-events:
+		assert.Equal(t, dto.EventMatchCancelled, event.Type)
+		assert.Equal(t, "guest", event.TargetID)
+	default:
+		t.Fatal("expected a match.cancelled event")
+	}
+
+	_, err = s.GetState(ctx, matchID, "host")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+
+	err = s.DeleteMatch(ctx, matchID, "host")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+
+	_, err = s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	assert.NoError(t, err, "host should be free to create a new match")
+}
+
+func TestMemoryService_Handicap(t *testing.T) {
 	t.Parallel()
 	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
 	ctx := context.Background()
 
-	// Create first game
-	game1, err := s.CreateMatch(ctx, "alice")
-	require.NoError(t, err, "should create first game")
-	require.NotEmpty(t, game1)
+	_, err := s.CreateMatch(ctx, "host", dto.MatchSettings{
+		BoardSize:     5,
+		FleetPreset:   "small",
+		HostHandicap:  map[string]int{"Dinghy": 1},
+		GuestHandicap: map[string]int{"Carrier": 2},
+	})
+	var invalid *controller.ValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Problems, 2, "both fleets are checked")
+	assert.Contains(t, err.Error(), "host handicap")
+	assert.Contains(t, err.Error(), "guest handicap")
 
-	// Try to create second game while first is active - should fail
-	_, err = s.CreateMatch(ctx, "alice")
-	require.Error(t, err, "should not allow creating second game")
-	require.Contains(t, err.Error(), "already in an active game")
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{GuestHandicap: map[string]int{"Destroyer": 1}})
+	require.NoError(t, err)
+	view, err := s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
 
-	// Try to join another game while in first game - should fail
-	game2, err := s.CreateMatch(ctx, "bob")
+	// The handicap shows on both sides
+	assert.Equal(t, map[int]int{5: 1, 4: 1, 3: 2, 2: 2}, view.Me.Fleet)
+	assert.Equal(t, 2, view.Me.StartingShips["Destroyer"])
+	assert.Equal(t, 1, view.Enemy.StartingShips["Destroyer"])
+	_, err = s.PlaceShip(ctx, matchID, "host", 2, 0, 0, false)
 	require.NoError(t, err)
+	_, err = s.PlaceShip(ctx, matchID, "host", 2, 0, 1, false)
+	assert.ErrorIs(t, err, model.ErrNoShipsRemaining, "the host has no extra Destroyer")
+}
 
-	_, err = s.JoinMatch(ctx, game2, "alice")
-	require.Error(t, err, "should not allow joining another game")
-	require.Contains(t, err.Error(), "already in an active game")
+func TestMemoryService_Rules(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.Rules(ctx, "missing")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{
+		BoardSize:      8,
+		FleetPreset:    "small",
+		MoveLimit:      30,
+		ExtraTurnOnHit: true,
+		GuestHandicap:  map[string]int{"Cruiser": 1},
+	})
+	require.NoError(t, err)
+
+	rules, err := s.Rules(ctx, matchID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.Rules{
+		BoardSize:   8,
+		FleetPreset: "small",
+		HostFleet: []dto.ShipRule{
+			{Name: "Cruiser", Size: 3, Count: 1},
+			{Name: "Destroyer", Size: 2, Count: 2},
+		},
+		GuestFleet: []dto.ShipRule{
+			{Name: "Cruiser", Size: 3, Count: 1},
+			{Name: "Submarine", Size: 3, Count: 1},
+			{Name: "Destroyer", Size: 2, Count: 2},
+		},
+		MoveLimit:      30,
+		ExtraTurnOnHit: true,
+		TeamSize:       1,
+	}, rules)
+
+	view, err := s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	assert.Equal(t, rules, view.Rules, "every view carries the rules")
+}
+
+func TestMemoryService_CreateMatchSettings(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 42})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+	_, err = s.CreateMatch(ctx, "host", dto.MatchSettings{FleetPreset: "armada"})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+	_, err = s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 5, FleetPreset: "standard"})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+
+	_, err = s.CreateMatch(ctx, "host", dto.MatchSettings{Seed: 7, MoveLimit: -1, FleetPreset: "armada"})
+	var invalid *controller.ValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Problems, 3, "every problem is reported at once")
+	require.ErrorIs(t, err, model.ErrInvalidMoveLimit)
+	require.ErrorIs(t, err, model.ErrUnknownFleetPreset)
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 8, FleetPreset: "small"})
+	require.NoError(t, err)
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, dto.MatchSettings{BoardSize: 8, FleetPreset: "small"}, matches[0].Settings)
+
+	view, err := s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	assert.Equal(t, 8, view.Me.Board.Size)
+	assert.Equal(t, map[int]int{3: 1, 2: 2}, view.Me.Fleet)
+
+	defaultID, err := s.CreateMatch(ctx, "other", dto.MatchSettings{})
+	require.NoError(t, err)
+	matches, err = s.ListMatches(ctx, dto.MatchFilter{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, defaultID, matches[0].ID)
+	assert.Equal(t, dto.MatchSettings{BoardSize: 10, FleetPreset: "standard"}, matches[0].Settings)
+}
+
+func TestMemoryService_Teams(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxActiveGames(0))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.CreateMatch(ctx, "a1", dto.MatchSettings{TeamSize: 3})
+	require.ErrorIs(t, err, model.ErrInvalidTeamSize)
+	_, err = s.CreateMatch(ctx, "a1", dto.MatchSettings{TeamSize: 2, VsBot: true})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings, "the built-in opponent plays one on one")
+
+	matchID, err := s.CreateMatch(ctx, "a1", dto.MatchSettings{TeamSize: 2, FleetPreset: "small"})
+	require.NoError(t, err)
+	for _, playerID := range []string{"b1", "a2"} {
+		view, err := s.JoinMatch(ctx, matchID, playerID)
+		require.NoError(t, err)
+		assert.Empty(t, view.State, "the match waits until every seat is taken")
+	}
+	view, err := s.JoinMatch(ctx, matchID, "b2")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateSetup, view.State)
+	assert.Equal(t, 2, view.Rules.TeamSize)
+	_, err = s.JoinMatch(ctx, matchID, "c1")
+	require.ErrorIs(t, err, model.ErrGameFull, "a fifth player does not take anyone's seat")
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{IncludeLive: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "a1", matches[0].HostName)
+	assert.Equal(t, 4, matches[0].PlayerCount)
+
+	// Each side places one fleet, and a teammate readies it
+	for _, playerID := range []string{"a1", "b1"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err := s.PlaceShip(ctx, matchID, playerID, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+	_, err = s.Ready(ctx, matchID, "a2")
+	require.NoError(t, err)
+	view, err = s.Ready(ctx, matchID, "b2")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StatePlaying, view.State)
+
+	board, err := s.Scoreboard(ctx)
+	require.NoError(t, err)
+	require.Len(t, board, 1)
+	assert.Len(t, board[0].Players, 4, "every seat is on the scoreboard")
+
+	for _, playerID := range []string{"a1", "b1", "a2", "b2"} {
+		yourTurn, err := s.IsPlayersTurn(ctx, matchID, playerID)
+		require.NoError(t, err)
+		assert.Equal(t, playerID == "a1", yourTurn)
+	}
+
+	_, err = s.AttackTarget(ctx, matchID, "a1", "a2", 5, 5)
+	require.ErrorIs(t, err, model.ErrInvalidTarget, "teammates do not fire at each other")
+	_, err = s.AttackTarget(ctx, matchID, "a1", "b2", 5, 5)
+	require.NoError(t, err, "either player of the other side names its board")
+}
+
+func TestMemoryService_FreeForAll(t *testing.T) {
+	t.Parallel()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	for _, settings := range []dto.MatchSettings{
+		{FreeForAll: 2},
+		{FreeForAll: 3, TeamSize: 2},
+		{FreeForAll: 3, VsBot: true},
+	} {
+		_, err := s.CreateMatch(ctx, "p1", settings)
+		require.ErrorIs(t, err, controller.ErrInvalidSettings, "%+v", settings)
+	}
+
+	matchID, err := s.CreateMatch(ctx, "p1", dto.MatchSettings{FreeForAll: 3, BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	for _, player := range []string{"p2", "p3"} {
+		_, err := s.JoinMatch(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+	for _, player := range []string{"p1", "p2", "p3"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err := s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+		_, err := s.Ready(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+
+	view, err := s.GetState(ctx, matchID, "p1")
+	require.NoError(t, err)
+	require.True(t, view.YourTurn, "the host fires first")
+	assert.Equal(t, 3, view.Rules.FreeForAll)
+	assert.Equal(t, []string{"p2", "p3"}, view.TargetIDs)
+
+	sub, events := notifier.Subscribe(matchID, dto.EventAttackMade)
+	defer sub.Unsubscribe()
+
+	_, err = s.Attack(ctx, matchID, "p1", 0, 0)
+	require.ErrorIs(t, err, model.ErrTargetRequired)
+	_, err = s.AttackTarget(ctx, matchID, "p1", "p3", 0, 0)
+	require.NoError(t, err)
+
+	// Every other player hears of the shot, the one fired at included
+	var notified []string
+	for range 2 {
+		event := <-events
+		notified = append(notified, event.TargetID)
+		data, ok := event.Data.(dto.AttackEventData)
+		require.True(t, ok)
+		assert.Equal(t, "p3", data.Target)
+		assert.Equal(t, "p2", data.NextTurn)
+	}
+	assert.ElementsMatch(t, []string{"p2", "p3"}, notified)
+
+	record, err := s.MatchRecord(ctx, matchID, "p2")
+	require.NoError(t, err)
+	require.Len(t, record.Moves, 1)
+	assert.Equal(t, "p3", record.Moves[0].Target, "the export tells whose board was fired at")
+}
+
+func TestMemoryService_TeamsAutoPlace(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "a1", dto.MatchSettings{TeamSize: 2, AutoPlace: true})
+	require.NoError(t, err)
+	for _, playerID := range []string{"b1", "a2", "b2"} {
+		_, err := s.JoinMatch(ctx, matchID, playerID)
+		require.NoError(t, err)
+	}
+
+	view, err := s.GetState(ctx, matchID, "b2")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StatePlaying, view.State, "both fleets are placed once every seat is taken")
+}
+
+func TestMemoryService_ActiveMatch(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.ActiveMatch(ctx, "guest")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+
+	for _, playerID := range []string{"host", "guest"} {
+		active, err := s.ActiveMatch(ctx, playerID)
+		require.NoError(t, err)
+		assert.Equal(t, matchID, active)
+	}
+
+	require.NoError(t, s.DeleteMatch(ctx, matchID, "host"))
+	_, err = s.ActiveMatch(ctx, "guest")
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
+}
+
+func TestMemoryService_VsBot(t *testing.T) {
+	t.Parallel()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.CreateMatch(ctx, "host", dto.MatchSettings{VsBot: true, Difficulty: "impossible"})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+
+	settings := dto.MatchSettings{BoardSize: 6, FleetPreset: "small", VsBot: true, Difficulty: dto.DifficultyHard}
+	matchID, err := s.CreateMatch(ctx, "host", settings)
+	require.NoError(t, err)
+
+	view, err := s.GetState(ctx, matchID, "host")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateSetup, view.State, "the opponent joins right away")
+	assert.True(t, strings.HasPrefix(view.Enemy.ID, dto.AIPlayerPrefix))
+	assert.True(t, view.Enemy.Ready, "the opponent places its fleet right away")
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, matches, "solo matches are not open to other players")
+
+	for y, size := range []int{3, 2, 2} {
+		_, err = s.PlaceShip(ctx, matchID, "host", size, 0, y, false)
+		require.NoError(t, err)
+	}
+	view, err = s.Ready(ctx, matchID, "host")
+	require.NoError(t, err)
+	require.Equal(t, dto.StatePlaying, view.State)
+	require.True(t, view.YourTurn)
+
+	sub, events := notifier.Subscribe(matchID)
+	defer sub.Unsubscribe()
+
+	// Sweep the board until someone wins; the opponent answers every shot
+	for y := range settings.BoardSize {
+		for x := range settings.BoardSize {
+			if view.State != dto.StatePlaying {
+				break
+			}
+			view, err = s.Attack(ctx, matchID, "host", x, y)
+			require.NoError(t, err)
+			if view.State == dto.StatePlaying {
+				assert.True(t, view.YourTurn, "the opponent should have replied")
+			}
+		}
+	}
+	assert.Equal(t, dto.StateFinished, view.State)
+
+	var humanShots, botShots int
+	for len(events) > 0 {
+		event := <-events
+		if event.Type == dto.EventGameOver {
+			assert.Empty(t, events, "the game is over after the winning shot")
+			assert.Equal(t, dto.GameOverEventData{Winner: view.Winner}, event.Data)
+			continue
+		}
+		require.Equal(t, dto.EventAttackMade, event.Type)
+		if event.PlayerID == "host" {
+			humanShots++
+		} else {
+			botShots++
+			assert.Equal(t, "host", event.TargetID)
+		}
+	}
+	// The opponent answers every shot but the winning one
+	assert.Greater(t, humanShots, 0)
+	assert.Contains(t, []int{humanShots, humanShots - 1}, botShots)
+}
+
+func TestMemoryService_Seed(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxActiveGames(0))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.CreateMatch(ctx, "host", dto.MatchSettings{Seed: 42})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings, "nothing is random without vs_bot or auto_place")
+
+	// Auto-placed fleets only depend on the seed
+	boards := func(settings dto.MatchSettings) (host, guest dto.BoardView) {
+		matchID, err := s.CreateMatch(ctx, "host", settings)
+		require.NoError(t, err)
+		guestView, err := s.JoinMatch(ctx, matchID, "guest")
+		require.NoError(t, err)
+		hostView, err := s.GetState(ctx, matchID, "host")
+		require.NoError(t, err)
+		assert.Zero(t, hostView.Seed, "the seed is kept secret while the match is played")
+		for _, remaining := range hostView.Me.Fleet {
+			assert.Zero(t, remaining, "the whole fleet is placed")
+		}
+		return hostView.Me.Board, guestView.Me.Board
+	}
+	settings := dto.MatchSettings{AutoPlace: true, Seed: 42}
+	host1, guest1 := boards(settings)
+	host2, guest2 := boards(settings)
+	assert.Equal(t, host1, host2)
+	assert.Equal(t, guest1, guest2)
+	assert.NotEqual(t, host1, guest1, "each side gets its own fleet")
+
+	host3, _ := boards(dto.MatchSettings{AutoPlace: true, Seed: 43})
+	assert.NotEqual(t, host1, host3)
+
+	matches, err := s.ListMatches(ctx, dto.MatchFilter{IncludeLive: true})
+	require.NoError(t, err)
+	for _, m := range matches {
+		assert.Zero(t, m.Settings.Seed, "listings do not tell the seed")
+	}
+
+	// Against the built-in opponent, the same shots replay the same match
+	play := func() ([]string, dto.GameView) {
+		settings := dto.MatchSettings{BoardSize: 6, FleetPreset: "small", VsBot: true, AutoPlace: true, Seed: 7}
+		matchID, err := s.CreateMatch(ctx, "solo", settings)
+		require.NoError(t, err)
+		_, err = s.Ready(ctx, matchID, "solo")
+		require.NoError(t, err)
+
+		var view dto.GameView
+		for shot := 0; view.State != dto.StateFinished; shot++ {
+			view, err = s.Attack(ctx, matchID, "solo", shot%6, shot/6)
+			require.NoError(t, err)
+		}
+
+		record, err := s.MatchRecord(ctx, matchID, "solo")
+		require.NoError(t, err)
+		var moves []string
+		for _, move := range record.Moves {
+			moves = append(moves, move.Coord+" "+move.Result)
+		}
+		return moves, view
+	}
+	moves1, view1 := play()
+	moves2, view2 := play()
+	assert.Equal(t, moves1, moves2)
+	assert.Equal(t, uint64(7), view1.Seed, "the seed is told once the match is over")
+	assert.Equal(t, view1.Me.Board, view2.Me.Board)
+}
+
+func TestMemoryService_EventsSince(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
+	_, _ = s.JoinMatch(ctx, matchID, "p2")
+
+	for y, size := range []int{5, 4, 3, 3, 2} {
+		_, err := s.PlaceShip(ctx, matchID, "p1", size, 0, y, false)
+		require.NoError(t, err)
+		_, err = s.PlaceShip(ctx, matchID, "p2", size, 0, y, false)
+		require.NoError(t, err)
+	}
+
+	view, err := s.GetState(ctx, matchID, "p2")
+	require.NoError(t, err)
+	since := view.Version
+
+	_, err = s.Attack(ctx, matchID, "p1", 9, 9)
+	require.NoError(t, err)
+	_, err = s.Attack(ctx, matchID, "p2", 9, 9)
+	require.NoError(t, err)
+
+	events, err := s.EventsSince(ctx, matchID, "p2", since)
+	require.NoError(t, err)
+	require.Len(t, events, 2, "only the attacks happened after the snapshot")
+	assert.Equal(t, "p1", events[0].PlayerID)
+	assert.Equal(t, "p2", events[1].PlayerID)
+	assert.Less(t, events[0].Version, events[1].Version)
+
+	all, err := s.EventsSince(ctx, matchID, "p2", 0)
+	require.NoError(t, err)
+	for _, event := range all {
+		if event.Type == dto.EventShipPlaced && event.PlayerID == "p1" {
+			assert.Nil(t, event.Data, "opponent ship positions must not be replayed")
+		}
+	}
+
+	_, err = s.EventsSince(ctx, matchID, "ghost", 0)
+	assert.Error(t, err)
+
+	// Long catch-ups are bounded to the most recent events
+	for i := range 30 {
+		_, err = s.Attack(ctx, matchID, "p1", i%10, 5+i/10)
+		require.NoError(t, err)
+		_, err = s.Attack(ctx, matchID, "p2", i%10, 5+i/10)
+		require.NoError(t, err)
+	}
+
+	view, err = s.GetState(ctx, matchID, "p2")
+	require.NoError(t, err)
+
+	all, err = s.EventsSince(ctx, matchID, "p2", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 50)
+	assert.Equal(t, view.Version, all[len(all)-1].Version)
+}
+
+func TestMemoryService_EventsSinceFreeForAll(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "p1", dto.MatchSettings{FreeForAll: 3, BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	for _, player := range []string{"p2", "p3"} {
+		_, err := s.JoinMatch(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+	for _, player := range []string{"p1", "p2", "p3"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err := s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+		_, err := s.Ready(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+	_, err = s.AttackTarget(ctx, matchID, "p1", "p3", 5, 5)
+	require.NoError(t, err)
+	_, err = s.AttackTarget(ctx, matchID, "p2", "p1", 5, 5)
+	require.NoError(t, err)
+
+	// Every event is replayed once, however many players were told of it
+	events, err := s.EventsSince(ctx, matchID, "p3", 0)
+	require.NoError(t, err)
+	type key struct {
+		Type     dto.EventType
+		PlayerID string
+		Version  int
+	}
+	seen := map[key]int{}
+	attacks := 0
+	for _, event := range events {
+		seen[key{event.Type, event.PlayerID, event.Version}]++
+		if event.Type == dto.EventAttackMade {
+			attacks++
+			assert.Empty(t, event.TargetID, "an event told to several players is not addressed to one of them")
+		}
+	}
+	for k, n := range seen {
+		assert.Equal(t, 1, n, "%+v", k)
+	}
+	assert.Equal(t, 2, attacks)
+}
+
+func TestMemoryService_Attack_NotStarted(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{})
+	_, err := s.Attack(ctx, matchID, "p1", 0, 0)
+	assert.Error(t, err) // Game not started
+}
+
+func TestMemoryService_AttackTarget(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	_, _ = s.JoinMatch(ctx, matchID, "p2")
+	for _, player := range []string{"p1", "p2"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err := s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+		_, err := s.Ready(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+
+	view, err := s.GetState(ctx, matchID, "p1")
+	require.NoError(t, err)
+	first, second := "p1", "p2"
+	if !view.YourTurn {
+		first, second = second, first
+	}
+
+	_, err = s.AttackTarget(ctx, matchID, first, first, 0, 0)
+	require.ErrorIs(t, err, model.ErrInvalidTarget)
+	_, err = s.AttackTarget(ctx, matchID, first, "stranger", 0, 0)
+	require.ErrorIs(t, err, model.ErrUnknownPlayer)
+
+	view, err = s.AttackTarget(ctx, matchID, first, second, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellHit, view.Enemy.Board.Grid[0][0])
+
+	// Without a target the shot goes to the sole opponent
+	view, err = s.AttackTarget(ctx, matchID, second, "", 5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellMiss, view.Enemy.Board.Grid[5][5])
+}
+
+func TestMemoryService_ValidateAttack(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	_, _ = s.JoinMatch(ctx, matchID, "p2")
+
+	check, err := s.ValidateAttack(ctx, matchID, "p1", "", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, dto.AttackNotInPlay, check.Code, "nobody fires during setup")
+
+	for _, player := range []string{"p1", "p2"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+		_, err = s.Ready(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+
+	view, err := s.GetState(ctx, matchID, "p1")
+	require.NoError(t, err)
+	first, second := "p1", "p2"
+	if !view.YourTurn {
+		first, second = second, first
+	}
+	_, err = s.Attack(ctx, matchID, first, 5, 5)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		attacker string
+		target   string
+		x, y     int
+		want     dto.AttackCheck
+	}{
+		{"Legal", second, "", 0, 0, dto.AttackCheck{Valid: true}},
+		{"Not your turn", first, "", 0, 0, dto.AttackCheck{Code: dto.AttackNotYourTurn}},
+		{"Out of bounds", second, "", 6, 0, dto.AttackCheck{Code: dto.AttackOutOfBounds}},
+		{"Own board", second, second, 0, 0, dto.AttackCheck{Code: dto.AttackInvalidTarget}},
+	}
+	for _, tt := range tests {
+		check, err := s.ValidateAttack(ctx, matchID, tt.attacker, tt.target, tt.x, tt.y)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want.Valid, check.Valid, tt.name)
+		assert.Equal(t, tt.want.Code, check.Code, tt.name)
+		assert.Equal(t, check.Valid, check.Reason == "", "%s: a reason is given for refusals only", tt.name)
+	}
+
+	_, err = s.Attack(ctx, matchID, second, 5, 5)
+	require.NoError(t, err)
+	check, err = s.ValidateAttack(ctx, matchID, first, "", 5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, dto.AttackAlreadyAttacked, check.Code)
+
+	view, err = s.GetState(ctx, matchID, first)
+	require.NoError(t, err)
+	assert.Equal(t, dto.CellShip, view.Me.Board.Grid[0][0], "validating fires nothing")
+
+	_, err = s.ValidateAttack(ctx, "missing", "p1", "", 0, 0)
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
+}
+
+func TestMemoryService_LegalMoves(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, _ := s.CreateMatch(ctx, "p1", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	_, _ = s.JoinMatch(ctx, matchID, "p2")
+
+	_, err := s.LegalMoves(ctx, matchID, "p1", "")
+	require.ErrorIs(t, err, model.ErrNotInPlay)
+
+	for _, player := range []string{"p1", "p2"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+		_, err = s.Ready(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+
+	view, err := s.GetState(ctx, matchID, "p1")
+	require.NoError(t, err)
+	first, second := "p1", "p2"
+	if !view.YourTurn {
+		first, second = second, first
+	}
+
+	moves, err := s.LegalMoves(ctx, matchID, first, "")
+	require.NoError(t, err)
+	assert.Len(t, moves.Targets, 36)
+
+	_, err = s.LegalMoves(ctx, matchID, second, "")
+	require.ErrorIs(t, err, model.ErrNotYourTurn)
+
+	_, err = s.Attack(ctx, matchID, first, 5, 5)
+	require.NoError(t, err)
+	_, err = s.Attack(ctx, matchID, second, 5, 5)
+	require.NoError(t, err)
+
+	moves, err = s.LegalMoves(ctx, matchID, first, "")
+	require.NoError(t, err)
+	assert.Len(t, moves.Targets, 35)
+	assert.Equal(t, dto.CellMiss, moves.Board.Grid[5][5])
+
+	named, err := s.LegalMoves(ctx, matchID, first, second)
+	require.NoError(t, err)
+	assert.Equal(t, moves, named, "naming the sole opponent changes nothing")
+
+	_, err = s.LegalMoves(ctx, "missing", "p1", "")
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
+}
+
+func TestMemoryService_LegalMovesFreeForAll(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "p1", dto.MatchSettings{FreeForAll: 3, BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	for _, player := range []string{"p2", "p3"} {
+		_, err = s.JoinMatch(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+	for _, player := range []string{"p1", "p2", "p3"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+		_, err = s.Ready(ctx, matchID, player)
+		require.NoError(t, err)
+	}
+
+	_, err = s.LegalMoves(ctx, matchID, "p1", "")
+	require.ErrorIs(t, err, model.ErrTargetRequired, "a free-for-all needs a target")
+	_, err = s.LegalMoves(ctx, matchID, "p1", "p1")
+	require.ErrorIs(t, err, model.ErrInvalidTarget)
+
+	_, err = s.AttackTarget(ctx, matchID, "p1", "p3", 0, 0)
+	require.NoError(t, err)
+	_, err = s.AttackTarget(ctx, matchID, "p2", "p3", 1, 0)
+	require.NoError(t, err)
+	_, err = s.AttackTarget(ctx, matchID, "p3", "p1", 5, 5)
+	require.NoError(t, err)
+
+	// Each board keeps its own record of the player's shots
+	atP3, err := s.LegalMoves(ctx, matchID, "p1", "p3")
+	require.NoError(t, err)
+	assert.Len(t, atP3.Targets, 35)
+	assert.Equal(t, dto.CellHit, atP3.Board.Grid[0][0])
+	assert.Equal(t, dto.CellUnknown, atP3.Board.Grid[0][1], "another player's shot is not the player's own")
+
+	atP2, err := s.LegalMoves(ctx, matchID, "p1", "p2")
+	require.NoError(t, err)
+	assert.Len(t, atP2.Targets, 36)
+}
+
+func TestMemoryService_MatchRecord(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	settings := dto.MatchSettings{BoardSize: 6, FleetPreset: "small", VsBot: true, Difficulty: dto.DifficultyEasy}
+	matchID, err := s.CreateMatch(ctx, "host", settings)
+	require.NoError(t, err)
+
+	record, err := s.MatchRecord(ctx, matchID, "host")
+	require.NoError(t, err)
+	assert.Equal(t, matchID, record.MatchID)
+	assert.Equal(t, dto.StateSetup, record.State)
+	assert.Empty(t, record.Moves)
+
+	for y, size := range []int{3, 2, 2} {
+		_, err = s.PlaceShip(ctx, matchID, "host", size, 0, y, false)
+		require.NoError(t, err)
+	}
+	_, err = s.Ready(ctx, matchID, "host")
+	require.NoError(t, err)
+
+	_, err = s.Attack(ctx, matchID, "host", 5, 5)
+	require.NoError(t, err)
+
+	record, err = s.MatchRecord(ctx, matchID, "host")
+	require.NoError(t, err)
+	require.Len(t, record.Moves, 2, "the opponent answers the shot")
+	assert.Equal(t, dto.MoveRecord{Number: 1, Attacker: "host", Coord: "F6", Result: record.Moves[0].Result}, record.Moves[0])
+	assert.Equal(t, 2, record.Moves[1].Number)
+	assert.NotEqual(t, "host", record.Moves[1].Attacker)
+
+	_, err = s.MatchRecord(ctx, matchID, "stranger")
+	require.ErrorIs(t, err, model.ErrUnknownPlayer)
+
+	_, err = s.MatchRecord(ctx, "missing", "host")
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
+}
+
+func TestMemoryService_MoveLimit(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	_, err := s.CreateMatch(ctx, "host", dto.MatchSettings{MoveLimit: -1})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small", MoveLimit: 2})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	for _, player := range []string{"host", "guest"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+
+	view, err := s.Attack(ctx, matchID, "host", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, view.MovesLeft)
+	view, err = s.Attack(ctx, matchID, "guest", 5, 5)
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateFinished, view.State)
+	assert.Equal(t, "host", view.Winner, "the host kept more ship cells afloat")
+
+	events, err := s.EventsSince(ctx, matchID, "host", 0)
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, dto.EventGameOver, last.Type)
+	assert.Equal(t, "host", last.PlayerID, "the winner need not be the last shooter")
+	assert.Equal(t, "guest", last.TargetID)
+	assert.Equal(t, dto.GameOverEventData{Winner: "host"}, last.Data)
+}
+
+func TestMemoryService_MoveLimitDraw(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small", MoveLimit: 2})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	for _, player := range []string{"host", "guest"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+
+	_, err = s.Attack(ctx, matchID, "host", 0, 0)
+	require.NoError(t, err)
+	view, err := s.Attack(ctx, matchID, "guest", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, dto.StateDraw, view.State)
+	assert.Empty(t, view.Winner)
+
+	events, err := s.EventsSince(ctx, matchID, "host", 0)
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, dto.EventGameOver, last.Type)
+	assert.Equal(t, "guest", last.PlayerID, "the draw is announced by the last shooter")
+	assert.Equal(t, dto.GameOverEventData{}, last.Data)
+}
+
+func TestMemoryService_ExpectedVersion(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	view, err := s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+
+	_, err = s.PlaceShip(controller.WithExpectedVersion(ctx, view.Version+1), matchID, "host", 3, 0, 0, false)
+	require.ErrorIs(t, err, controller.ErrVersionConflict)
+	view, err = s.PlaceShip(controller.WithExpectedVersion(ctx, view.Version), matchID, "host", 3, 0, 0, false)
+	require.NoError(t, err)
+
+	for _, player := range []string{"host", "guest"} {
+		for y, size := range []int{3, 2, 2} {
+			if player == "host" && y == 0 {
+				continue
+			}
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+
+	view, err = s.GetState(ctx, matchID, "host")
+	require.NoError(t, err)
+	atVersion := controller.WithExpectedVersion(ctx, view.Version)
+	_, err = s.Attack(atVersion, matchID, "host", 5, 5)
+	require.NoError(t, err)
+
+	// Retrying the same request must not fire a second shot
+	_, err = s.Attack(atVersion, matchID, "host", 5, 4)
+	require.ErrorIs(t, err, controller.ErrVersionConflict)
+	record, err := s.MatchRecord(ctx, matchID, "host")
+	require.NoError(t, err)
+	assert.Len(t, record.Moves, 1)
+}
+
+func TestMemoryService_ResumeMatch(t *testing.T) {
+	t.Parallel()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier)
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	view, err := s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	token := view.ResumeToken
+	require.NotEmpty(t, token)
+
+	hostView, err := s.GetState(ctx, matchID, "host")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, hostView.ResumeToken, "each seat has its own token")
+
+	_, err = s.PlaceShip(ctx, matchID, "guest", 3, 0, 0, false)
+	require.NoError(t, err)
+
+	_, err = s.ResumeMatch(ctx, matchID, "guest-phone", "wrong")
+	require.ErrorIs(t, err, controller.ErrInvalidResumeToken)
+	_, err = s.ResumeMatch(ctx, "missing", "guest-phone", token)
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+	_, err = s.ResumeMatch(ctx, matchID, "host", token)
+	require.ErrorIs(t, err, model.ErrAlreadySeated, "the opponent cannot take the seat")
+
+	sub, events := notifier.Subscribe(matchID)
+	defer sub.Unsubscribe()
+
+	view, err = s.ResumeMatch(ctx, matchID, "guest-phone", token)
+	require.NoError(t, err)
+	assert.Equal(t, "guest-phone", view.Me.ID)
+	assert.Equal(t, token, view.ResumeToken)
+	assert.Equal(t, "SHIP", string(view.Me.Board.Grid[0][0]), "the placed ship comes along")
+
+	event := <-events
+	assert.Equal(t, dto.EventPlayerResumed, event.Type)
+	assert.Equal(t, "host", event.TargetID)
+	assert.Equal(t, dto.PlayerResumedEventData{Previous: "guest"}, event.Data)
+
+	_, err = s.GetState(ctx, matchID, "guest")
+	require.ErrorIs(t, err, model.ErrUnknownPlayer, "the old identity lost the seat")
+	active, err := s.ActiveMatch(ctx, "guest-phone")
+	require.NoError(t, err)
+	assert.Equal(t, matchID, active)
+	_, err = s.ActiveMatch(ctx, "guest")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound)
+
+	// Resuming again from the same identity changes nothing
+	again, err := s.ResumeMatch(ctx, matchID, "guest-phone", token)
+	require.NoError(t, err)
+	assert.Equal(t, view.Version, again.Version)
+}
+
+func TestMemoryService_Reveal(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	matchID, err := s.CreateMatch(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	_, err = s.JoinMatch(ctx, matchID, "guest")
+	require.NoError(t, err)
+	for _, player := range []string{"host", "guest"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+
+	_, err = s.Reveal(ctx, matchID)
+	require.ErrorIs(t, err, model.ErrNotFinished)
+
+	started, err := s.GetState(ctx, matchID, "guest")
+	require.NoError(t, err)
+	committed := started.PlacementCommitment
+	require.NotEmpty(t, committed, "the fleets are committed to once play starts")
+
+	// The host sinks the guest's fleet while the guest fires at empty water
+	shots := map[string][]model.Coordinate{
+		"host":  {{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+		"guest": {{X: 5, Y: 0}, {X: 5, Y: 1}, {X: 5, Y: 2}, {X: 5, Y: 3}, {X: 5, Y: 4}, {X: 5, Y: 5}, {X: 4, Y: 5}},
+	}
+	for {
+		view, err := s.GetState(ctx, matchID, "host")
+		require.NoError(t, err)
+		if view.State != dto.StatePlaying {
+			break
+		}
+		shot := shots[view.Turn][0]
+		shots[view.Turn] = shots[view.Turn][1:]
+		_, err = s.Attack(ctx, matchID, view.Turn, shot.X, shot.Y)
+		require.NoError(t, err)
+	}
+
+	reveal, err := s.Reveal(ctx, matchID)
+	require.NoError(t, err)
+	assert.Equal(t, matchID, reveal.MatchID)
+	assert.Equal(t, "host", reveal.Winner)
+	require.Len(t, reveal.Players, 2)
+	assert.Equal(t, "host", reveal.Players[0].ID)
+	assert.Equal(t, dto.CellShip, reveal.Players[0].Board.Grid[0][0], "the winner's unhit ships are shown")
+	assert.Equal(t, dto.CellSunk, reveal.Players[1].Board.Grid[0][0])
+	assert.Equal(t, committed, reveal.PlacementCommitment)
+	assert.Equal(t, committed, commitment.Reveal(reveal), "the revealed fleets are the ones play started with")
+
+	_, err = s.Reveal(ctx, "missing")
+	assert.ErrorIs(t, err, controller.ErrMatchNotFound)
+}
+
+func TestMemoryService_SingleActiveGameLimit(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	// Create first game
+	game1, err := s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+	require.NoError(t, err, "should create first game")
+	require.NotEmpty(t, game1)
+
+	// Try to create second game while first is active - should fail
+	_, err = s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+	require.ErrorIs(t, err, controller.ErrTooManyActiveGames, "should not allow creating second game")
+	require.Contains(t, err.Error(), "already in an active game")
+	require.Contains(t, err.Error(), game1)
+
+	// Try to join another game while in first game - should fail with the same error
+	game2, err := s.CreateMatch(ctx, "bob", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	_, err = s.JoinMatch(ctx, game2, "alice")
+	require.ErrorIs(t, err, controller.ErrTooManyActiveGames, "should not allow joining another game")
+	require.Contains(t, err.Error(), "already in an active game")
+
+	// The failed join must not have seated alice
+	_, err = s.JoinMatch(ctx, game2, "carol")
+	require.NoError(t, err)
+}
+
+func TestMemoryService_ConcurrentJoinsRespectLimit(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	const matches = 16
+	ids := make([]string, matches)
+	for n := range matches {
+		id, err := s.CreateMatch(ctx, fmt.Sprintf("host-%d", n), dto.MatchSettings{})
+		require.NoError(t, err)
+		ids[n] = id
+	}
+
+	var joined atomic.Int32
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.JoinMatch(ctx, id, "guest"); err == nil {
+				joined.Add(1)
+			} else {
+				assert.ErrorIs(t, err, controller.ErrTooManyActiveGames)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), joined.Load(), "a guest cannot get into several matches at once")
+}
+
+func TestMemoryService_MaxActiveGames(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("Configured limit", func(t *testing.T) {
+		t.Parallel()
+		s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxActiveGames(2))
+		t.Cleanup(s.Close)
+
+		first, err := s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+		require.NoError(t, err)
+
+		other, err := s.CreateMatch(ctx, "bob", dto.MatchSettings{})
+		require.NoError(t, err)
+		_, err = s.JoinMatch(ctx, other, "alice")
+		require.NoError(t, err, "joined matches count towards the same limit as hosted ones")
+
+		_, err = s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+		require.ErrorIs(t, err, controller.ErrTooManyActiveGames)
+
+		active, err := s.ActiveMatch(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, other, active, "the most recent match is reported")
+
+		require.NoError(t, s.DeleteMatch(ctx, first, "alice"))
+		_, err = s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+		require.NoError(t, err, "a cancelled match frees its slot")
+	})
+
+	t.Run("Finished matches are not active", func(t *testing.T) {
+		t.Parallel()
+		s := service.NewMemoryService(service.NewNotificationService())
+		t.Cleanup(s.Close)
+
+		matchID, err := s.CreateMatch(ctx, "alice", dto.MatchSettings{
+			BoardSize: 5, FleetPreset: "small", VsBot: true, Difficulty: dto.DifficultyEasy,
+		})
+		require.NoError(t, err)
+
+		_, err = s.PlaceShip(ctx, matchID, "alice", 3, 0, 0, false)
+		require.NoError(t, err)
+		_, err = s.PlaceShip(ctx, matchID, "alice", 2, 0, 2, false)
+		require.NoError(t, err)
+		view, err := s.PlaceShip(ctx, matchID, "alice", 2, 0, 4, false)
+		require.NoError(t, err)
+		if view.State == dto.StateSetup {
+			view, err = s.Ready(ctx, matchID, "alice")
+			require.NoError(t, err)
+		}
+
+		// Sweep the board; the built-in opponent answers every shot, so one side wins
+		for i := 0; i < 25 && view.State != dto.StateFinished; i++ {
+			view, err = s.Attack(ctx, matchID, "alice", i%5, i/5)
+			require.NoError(t, err)
+		}
+		require.Equal(t, dto.StateFinished, view.State)
+
+		_, err = s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+		require.NoError(t, err)
+	})
+
+	t.Run("No limit", func(t *testing.T) {
+		t.Parallel()
+		s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxActiveGames(0))
+		t.Cleanup(s.Close)
+
+		for range 3 {
+			_, err := s.CreateMatch(ctx, "alice", dto.MatchSettings{})
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestMemoryService_MaxTotalGames(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxTotalGames(2))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	botMatch, err := s.CreateMatch(ctx, "alice", dto.MatchSettings{
+		BoardSize: 5, FleetPreset: "small", VsBot: true, Difficulty: dto.DifficultyEasy,
+	})
+	require.NoError(t, err)
+	_, err = s.CreateMatch(ctx, "bob", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	_, err = s.CreateMatch(ctx, "carol", dto.MatchSettings{})
+	require.ErrorIs(t, err, controller.ErrServerAtCapacity, "every match held is unfinished")
+
+	playOutVsBot(t, s, botMatch, "alice")
+
+	_, err = s.CreateMatch(ctx, "carol", dto.MatchSettings{})
+	require.NoError(t, err, "the finished match is evicted to make room")
+}
+
+func TestMemoryService_MaxTotalGamesEvictsFinished(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService(), service.WithMaxTotalGames(3))
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	older := finishedBotMatch(t, s, "alice")
+	newer := finishedBotMatch(t, s, "bob")
+	_, err := s.CreateMatch(ctx, "carol", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	_, err = s.CreateMatch(ctx, "dave", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	_, err = s.GetState(ctx, older, "alice")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound, "the least recently updated finished match makes room")
+	_, err = s.GetState(ctx, newer, "bob")
+	require.NoError(t, err)
+}
+
+// finishedBotMatch plays a whole match against the built-in opponent.
+func finishedBotMatch(t *testing.T, s *service.MemoryService, playerID string) string {
+	t.Helper()
+	matchID, err := s.CreateMatch(context.Background(), playerID, dto.MatchSettings{
+		BoardSize: 5, FleetPreset: "small", VsBot: true, Difficulty: dto.DifficultyEasy,
+	})
+	require.NoError(t, err)
+	playOutVsBot(t, s, matchID, playerID)
+	return matchID
+}
+
+// playOutVsBot places the player's small fleet and sweeps the 5x5 board until one side wins.
+func playOutVsBot(t *testing.T, s *service.MemoryService, matchID, playerID string) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := s.PlaceShip(ctx, matchID, playerID, 3, 0, 0, false)
+	require.NoError(t, err)
+	_, err = s.PlaceShip(ctx, matchID, playerID, 2, 0, 2, false)
+	require.NoError(t, err)
+	view, err := s.PlaceShip(ctx, matchID, playerID, 2, 0, 4, false)
+	require.NoError(t, err)
+	if view.State == dto.StateSetup {
+		view, err = s.Ready(ctx, matchID, playerID)
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 25 && view.State != dto.StateFinished; i++ {
+		view, err = s.Attack(ctx, matchID, playerID, i%5, i/5)
+		require.NoError(t, err)
+	}
+	require.Equal(t, dto.StateFinished, view.State)
+}
+
+func TestMemoryService_PracticeBoard(t *testing.T) {
+	t.Parallel()
+	s := service.NewMemoryService(service.NewNotificationService())
+	t.Cleanup(s.Close)
+	ctx := context.Background()
+
+	board, err := s.PracticeBoard(ctx, dto.MatchSettings{})
+	require.NoError(t, err)
+	require.Equal(t, 10, board.Size)
+
+	cells := 0
+	for _, row := range board.Grid {
+		for _, cell := range row {
+			if cell == dto.CellShip {
+				cells++
+			}
+		}
+	}
+	assert.Equal(t, 5+4+3+3+2, cells, "the whole standard fleet is placed")
+
+	again, err := s.PracticeBoard(ctx, dto.MatchSettings{})
+	require.NoError(t, err)
+	assert.Equal(t, board, again, "the layout is deterministic")
+
+	small, err := s.PracticeBoard(ctx, dto.MatchSettings{BoardSize: 8, FleetPreset: "small"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, small.Size)
+
+	_, err = s.PracticeBoard(ctx, dto.MatchSettings{FleetPreset: "armada"})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+}
+
+func TestNotificationService_SubscribeFiltered(t *testing.T) {
+	t.Parallel()
+	n := service.NewNotificationService()
+
+	allSub, all := n.Subscribe("m1")
+	defer allSub.Unsubscribe()
+	attackSub, attacks := n.Subscribe("m1", dto.EventAttackMade, dto.EventGameOver)
+	defer attackSub.Unsubscribe()
+
+	n.Publish(&dto.GameEvent{Type: dto.EventShipPlaced, MatchID: "m1"})
+	n.Publish(&dto.GameEvent{Type: dto.EventAttackMade, MatchID: "m1"})
+
+	assert.Equal(t, dto.EventShipPlaced, (<-all).Type)
+	assert.Equal(t, dto.EventAttackMade, (<-all).Type)
+
+	assert.Equal(t, dto.EventAttackMade, (<-attacks).Type, "other event types are not delivered")
+	select {
+	case event := <-attacks:
+		t.Fatalf("unexpected %s event", event.Type)
+	default:
+	}
+}
+
+func TestNotificationService_SubscribeAllDropsNothing(t *testing.T) {
+	t.Parallel()
+	n := service.NewNotificationService()
+
+	lossySub, lossy := n.Subscribe("*")
+	defer lossySub.Unsubscribe()
+	sub, events := n.SubscribeAll(dto.EventAttackMade)
+
+	// Far more events than a regular subscription buffers, published before anything is read
+	const count = 1000
+	for i := range count {
+		n.Publish(&dto.GameEvent{Type: dto.EventShipPlaced, MatchID: "m1"})
+		n.Publish(&dto.GameEvent{Type: dto.EventAttackMade, MatchID: fmt.Sprintf("m%d", i)})
+	}
+	assert.Len(t, lossy, cap(lossy), "the regular subscription dropped what did not fit")
+
+	for i := range count {
+		event := <-events
+		require.Equal(t, dto.EventAttackMade, event.Type)
+		require.Equal(t, fmt.Sprintf("m%d", i), event.MatchID, "events arrive in order")
+	}
+
+	sub.Unsubscribe()
+	_, open := <-events
+	assert.False(t, open, "the channel is closed once unsubscribed")
 }
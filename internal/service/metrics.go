@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/metrics"
+)
+
+// MetricsLobbyService times match creation on the way to the lobby it wraps.
+// The other lobby calls go straight through.
+type MetricsLobbyService struct {
+	controller.LobbyService
+	latency *metrics.Histogram
+}
+
+// NewMetricsLobbyService records how long next takes to create matches in latency.
+func NewMetricsLobbyService(next controller.LobbyService, latency *metrics.Histogram) *MetricsLobbyService {
+	return &MetricsLobbyService{LobbyService: next, latency: latency}
+}
+
+// CreateMatch is timed as "create_match".
+func (s *MetricsLobbyService) CreateMatch(
+	ctx context.Context,
+	hostID string,
+	settings dto.MatchSettings,
+) (string, error) {
+	defer s.latency.Since("create_match", time.Now())
+	return s.LobbyService.CreateMatch(ctx, hostID, settings)
+}
+
+// MetricsGameService times the busiest game calls on the way to the game service it wraps,
+// which shows when they queue up behind a match's lock. The other game calls go straight through.
+type MetricsGameService struct {
+	controller.GameService
+	latency *metrics.Histogram
+}
+
+// NewMetricsGameService records how long next takes to place ships, attack and read states in latency.
+func NewMetricsGameService(next controller.GameService, latency *metrics.Histogram) *MetricsGameService {
+	return &MetricsGameService{GameService: next, latency: latency}
+}
+
+// PlaceShip is timed as "place_ship".
+func (s *MetricsGameService) PlaceShip(
+	ctx context.Context,
+	matchID, playerID string,
+	shipID int,
+	x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	defer s.latency.Since("place_ship", time.Now())
+	return s.GameService.PlaceShip(ctx, matchID, playerID, shipID, x, y, vertical)
+}
+
+// PlaceShipType is timed as "place_ship" too, being the same operation.
+func (s *MetricsGameService) PlaceShipType(
+	ctx context.Context,
+	matchID, playerID string,
+	shipType string,
+	x, y int,
+	vertical bool,
+) (dto.GameView, error) {
+	defer s.latency.Since("place_ship", time.Now())
+	return s.GameService.PlaceShipType(ctx, matchID, playerID, shipType, x, y, vertical)
+}
+
+// Attack is timed as "attack".
+func (s *MetricsGameService) Attack(
+	ctx context.Context,
+	matchID, playerID string,
+	x, y int,
+) (dto.GameView, error) {
+	defer s.latency.Since("attack", time.Now())
+	return s.GameService.Attack(ctx, matchID, playerID, x, y)
+}
+
+// AttackTarget is timed as "attack" too, being the same operation.
+func (s *MetricsGameService) AttackTarget(
+	ctx context.Context,
+	matchID, playerID, targetID string,
+	x, y int,
+) (dto.GameView, error) {
+	defer s.latency.Since("attack", time.Now())
+	return s.GameService.AttackTarget(ctx, matchID, playerID, targetID, x, y)
+}
+
+// GetState is timed as "get_state".
+func (s *MetricsGameService) GetState(ctx context.Context, matchID, playerID string) (dto.GameView, error) {
+	defer s.latency.Since("get_state", time.Now())
+	return s.GameService.GetState(ctx, matchID, playerID)
+}
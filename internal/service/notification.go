@@ -15,14 +15,44 @@ type NotificationService struct {
 }
 
 type subscriber struct {
-	id string
-	ch chan *dto.GameEvent
+	id     string
+	ch     chan *dto.GameEvent
+	types  map[dto.EventType]bool // Event types delivered, or nil for all of them
+	queue  *eventQueue            // Feeds ch for subscribers that must not miss events, nil otherwise
+	reason *string                // Shared with the subscription, set by Close under the lock
+}
+
+func (s subscriber) wants(t dto.EventType) bool {
+	return s.types == nil || s.types[t]
+}
+
+// send delivers the event without blocking. Without a queue it is dropped when the buffer is full.
+func (s subscriber) send(event *dto.GameEvent) {
+	if s.queue != nil {
+		s.queue.push(event)
+		return
+	}
+	select {
+	case s.ch <- event:
+	default:
+		// Non-blocking send
+	}
+}
+
+// close ends the subscriber's channel.
+func (s subscriber) close() {
+	if s.queue != nil {
+		s.queue.stop() // The queue closes ch once it stops feeding it
+		return
+	}
+	close(s.ch)
 }
 
 type subscription struct {
 	ns      *NotificationService
 	matchID string
 	id      string
+	reason  *string
 }
 
 // NewNotificationService creates a new notification service.
@@ -33,28 +63,76 @@ func NewNotificationService() *NotificationService {
 }
 
 // Subscribe returns a channel of events for the match.
+// Given event types, only events of those types are delivered.
 func (s *NotificationService) Subscribe(
 	matchID string,
+	types ...dto.EventType,
 ) (sub controller.Subscription, out <-chan *dto.GameEvent) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	id := uuid.NewString()
 	ch := make(chan *dto.GameEvent, 100)
+	reason := new(string)
 
 	s.subscribers[matchID] = append(s.subscribers[matchID],
 		subscriber{
-			id: id,
-			ch: ch,
+			id:     id,
+			ch:     ch,
+			types:  eventFilter(types),
+			reason: reason,
 		})
 
 	return &subscription{
 		ns:      s,
 		matchID: matchID,
 		id:      id,
+		reason:  reason,
+	}, ch
+}
+
+// SubscribeAll returns a channel of the events of every match, like Subscribe("*"),
+// except that events the reader has not taken yet queue up without bound instead of being dropped.
+// Events still queued when the subscription ends are discarded.
+func (s *NotificationService) SubscribeAll(
+	types ...dto.EventType,
+) (sub controller.Subscription, out <-chan *dto.GameEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.NewString()
+	ch := make(chan *dto.GameEvent)
+	reason := new(string)
+
+	s.subscribers["*"] = append(s.subscribers["*"],
+		subscriber{
+			id:     id,
+			ch:     ch,
+			types:  eventFilter(types),
+			queue:  newEventQueue(ch),
+			reason: reason,
+		})
+
+	return &subscription{
+		ns:      s,
+		matchID: "*",
+		id:      id,
+		reason:  reason,
 	}, ch
 }
 
+// eventFilter returns the set of the given event types, or nil to deliver every type.
+func eventFilter(types []dto.EventType) map[dto.EventType]bool {
+	if len(types) == 0 {
+		return nil
+	}
+	filter := make(map[dto.EventType]bool, len(types))
+	for _, t := range types {
+		filter[t] = true
+	}
+	return filter
+}
+
 // Publish publishes an event to all subscribers.
 func (s *NotificationService) Publish(event *dto.GameEvent) {
 	s.mu.RLock()
@@ -67,12 +145,32 @@ func (s *NotificationService) Publish(event *dto.GameEvent) {
 	s.publishToSlice(event, s.subscribers["*"])
 }
 
+// SubscriberCount returns the number of subscribers listening to the match.
+// Wildcard subscribers are not counted.
+func (s *NotificationService) SubscriberCount(matchID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.subscribers[matchID])
+}
+
+// Close ends every subscription to the match, closing their channels, and records the reason
+// their Reason reports. Events already sent are still delivered first. Wildcard subscribers are left alone.
+func (s *NotificationService) Close(matchID, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subscribers[matchID] {
+		*sub.reason = reason
+		sub.close()
+	}
+	delete(s.subscribers, matchID)
+}
+
 func (s *NotificationService) publishToSlice(event *dto.GameEvent, subscribers []subscriber) {
 	for _, sub := range subscribers {
-		select {
-		case sub.ch <- event:
-		default:
-			// Non-blocking send
+		if sub.wants(event.Type) {
+			sub.send(event)
 		}
 	}
 }
@@ -86,9 +184,77 @@ func (s *subscription) Unsubscribe() {
 	for i, sub := range subs {
 		if sub.id == s.id {
 			// Close the channel to signal end of stream
-			close(sub.ch)
+			sub.close()
 			s.ns.subscribers[s.matchID] = append(subs[:i], subs[i+1:]...)
 			break
 		}
 	}
 }
+
+// Reason returns why Close ended the subscription, or an empty string if it did not.
+func (s *subscription) Reason() string {
+	s.ns.mu.RLock()
+	defer s.ns.mu.RUnlock()
+
+	return *s.reason
+}
+
+// eventQueue feeds a subscriber's channel from a list that grows as needed,
+// so that publishing neither blocks nor drops events however slow the reader is.
+type eventQueue struct {
+	mu     sync.Mutex
+	events []*dto.GameEvent
+	ready  chan struct{} // Holds a signal while events wait to be forwarded
+	done   chan struct{} // Closed to stop forwarding
+}
+
+func newEventQueue(out chan<- *dto.GameEvent) *eventQueue {
+	q := &eventQueue{
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	go q.forward(out)
+	return q
+}
+
+func (q *eventQueue) push(event *dto.GameEvent) {
+	q.mu.Lock()
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+		// A signal is already pending
+	}
+}
+
+func (q *eventQueue) stop() {
+	close(q.done)
+}
+
+// forward sends the queued events to out in order until stopped, then closes out.
+func (q *eventQueue) forward(out chan<- *dto.GameEvent) {
+	defer close(out)
+
+	for {
+		select {
+		case <-q.ready:
+		case <-q.done:
+			return
+		}
+
+		q.mu.Lock()
+		pending := q.events
+		q.events = nil
+		q.mu.Unlock()
+
+		for _, event := range pending {
+			select {
+			case out <- event:
+			case <-q.done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/google/uuid"
+)
+
+var _ controller.TournamentService = (*MemoryTournamentService)(nil)
+
+// maxTournamentPlayers bounds the sign-ups of a single tournament.
+const maxTournamentPlayers = 64
+
+// MemoryTournamentService is an in-memory implementation of controller.TournamentService.
+// It creates the matches of each round through the lobby and follows game-over events
+// to advance the winners, so the matches themselves are ordinary ones.
+type MemoryTournamentService struct {
+	tournaments map[string]*tournament
+	byMatch     map[string]*tournament // Map[MatchID]tournament the match belongs to
+	mu          sync.Mutex
+	lobby       controller.LobbyService
+	logger      *slog.Logger
+
+	sub     controller.Subscription
+	stopped chan struct{} // Closed once the event loop has returned
+	once    sync.Once
+}
+
+type tournament struct {
+	id       string
+	host     string
+	state    dto.TournamentState
+	settings dto.MatchSettings
+	players  []string
+	rounds   [][]dto.Pairing
+	err      string // Why the tournament failed, if it did
+}
+
+// TournamentOption configures optional MemoryTournamentService behavior.
+type TournamentOption func(*MemoryTournamentService)
+
+// WithTournamentLogger sets the logger the service reports bracket problems to.
+// The default logger is used otherwise.
+func WithTournamentLogger(logger *slog.Logger) TournamentOption {
+	return func(s *MemoryTournamentService) { s.logger = logger }
+}
+
+// NewMemoryTournamentService creates a tournament service playing its matches in the lobby.
+// It listens to every match on the notifier until Close is called, through a subscription
+// that never drops events, as a missed game over would stall the bracket.
+func NewMemoryTournamentService(
+	l controller.LobbyService,
+	n controller.NotificationService,
+	opts ...TournamentOption,
+) *MemoryTournamentService {
+	s := &MemoryTournamentService{
+		tournaments: make(map[string]*tournament),
+		byMatch:     make(map[string]*tournament),
+		lobby:       l,
+		logger:      slog.Default(),
+		stopped:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	sub, events := n.SubscribeAll(
+		dto.EventGameOver,
+		dto.EventGameAbandoned,
+		dto.EventMatchCancelled,
+		dto.EventMatchTerminated,
+		dto.EventPlayerResumed,
+	)
+	s.sub = sub
+	go s.eventLoop(events)
+	return s
+}
+
+// Close stops following match events and waits for the event loop to exit.
+// It is safe to call more than once.
+func (s *MemoryTournamentService) Close() {
+	s.once.Do(s.sub.Unsubscribe)
+	<-s.stopped
+}
+
+func (s *MemoryTournamentService) eventLoop(events <-chan *dto.GameEvent) {
+	defer close(s.stopped)
+
+	for event := range events {
+		switch event.Type {
+		case dto.EventGameOver:
+			if data, ok := event.Data.(dto.GameOverEventData); ok {
+				s.matchEnded(event.MatchID, data.Winner)
+			}
+		case dto.EventGameAbandoned, dto.EventMatchCancelled, dto.EventMatchTerminated:
+			s.matchEnded(event.MatchID, "")
+		case dto.EventPlayerResumed:
+			if data, ok := event.Data.(dto.PlayerResumedEventData); ok {
+				s.playerResumed(event.MatchID, data.Previous, event.PlayerID)
+			}
+		}
+	}
+}
+
+// playerResumed renames a player who took their seat in a tournament match back under a new ID,
+// so that the bracket follows them.
+func (s *MemoryTournamentService) playerResumed(matchID, previous, playerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.byMatch[matchID]
+	if !ok {
+		return
+	}
+
+	if t.host == previous {
+		t.host = playerID
+	}
+	for i, p := range t.players {
+		if p == previous {
+			t.players[i] = playerID
+		}
+	}
+	for _, round := range t.rounds {
+		for i := range round {
+			round[i].PlayerA = replaceID(round[i].PlayerA, previous, playerID)
+			round[i].PlayerB = replaceID(round[i].PlayerB, previous, playerID)
+			round[i].Winner = replaceID(round[i].Winner, previous, playerID)
+		}
+	}
+}
+
+// CreateTournament opens a tournament with the host signed up.
+func (s *MemoryTournamentService) CreateTournament(
+	_ context.Context,
+	hostID string,
+	settings dto.MatchSettings,
+) (dto.TournamentView, error) {
+	switch {
+	case settings.VsBot:
+		return dto.TournamentView{}, fmt.Errorf("%w: tournaments are played between players", controller.ErrInvalidSettings)
+	case settings.TeamSize > 1 || settings.FreeForAll != 0:
+		return dto.TournamentView{}, fmt.Errorf("%w: tournaments are played one on one", controller.ErrInvalidSettings)
+	}
+	settings, _, err := resolveSettings(settings)
+	if err != nil {
+		return dto.TournamentView{}, fmt.Errorf("%w: %w", controller.ErrInvalidSettings, err)
+	}
+
+	t := &tournament{
+		id:       fmt.Sprintf("tournament-%v", uuid.NewString()),
+		host:     hostID,
+		state:    dto.TournamentRegistering,
+		settings: settings,
+		players:  []string{hostID},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tournaments[t.id] = t
+	return t.view(), nil
+}
+
+// JoinTournament signs the player up while the tournament has not started.
+func (s *MemoryTournamentService) JoinTournament(
+	_ context.Context,
+	tournamentID, playerID string,
+) (dto.TournamentView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[tournamentID]
+	if !ok {
+		return dto.TournamentView{}, controller.ErrTournamentNotFound
+	}
+
+	switch {
+	case slices.Contains(t.players, playerID):
+		return t.view(), nil
+	case t.state != dto.TournamentRegistering:
+		return dto.TournamentView{}, controller.ErrTournamentStarted
+	case len(t.players) >= maxTournamentPlayers:
+		return dto.TournamentView{}, controller.ErrTournamentFull
+	}
+
+	t.players = append(t.players, playerID)
+	return t.view(), nil
+}
+
+// StartTournament pairs the players in sign-up order and creates the first round's matches.
+// If a match cannot be created, for instance because a player is busy in another one,
+// the matches already created are cancelled and the tournament stays open.
+func (s *MemoryTournamentService) StartTournament(
+	ctx context.Context,
+	tournamentID, playerID string,
+) (dto.TournamentView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[tournamentID]
+	switch {
+	case !ok:
+		return dto.TournamentView{}, controller.ErrTournamentNotFound
+	case t.host != playerID:
+		return dto.TournamentView{}, controller.ErrNotHost
+	case t.state != dto.TournamentRegistering:
+		return dto.TournamentView{}, controller.ErrTournamentStarted
+	case len(t.players) < 2:
+		return dto.TournamentView{}, controller.ErrNotEnoughPlayers
+	}
+
+	round := pairUp(t.players)
+	if err := s.createMatches(ctx, t.matchSettings(), round); err != nil {
+		return dto.TournamentView{}, err
+	}
+
+	s.addRound(t, round)
+	t.state = dto.TournamentRunning
+	return t.view(), nil
+}
+
+// GetTournament returns the bracket and standings of a tournament.
+func (s *MemoryTournamentService) GetTournament(_ context.Context, tournamentID string) (dto.TournamentView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[tournamentID]
+	if !ok {
+		return dto.TournamentView{}, controller.ErrTournamentNotFound
+	}
+	return t.view(), nil
+}
+
+// createMatches creates a match for every pairing of the round that is not a bye.
+// If one cannot be created, for instance because a player is busy in another match,
+// those already created are cancelled by their owner. It only talks to the lobby, so s.mu need not be held.
+func (s *MemoryTournamentService) createMatches(
+	ctx context.Context,
+	settings dto.MatchSettings,
+	round []dto.Pairing,
+) error {
+	err := s.seatPairings(ctx, settings, round)
+	if err != nil {
+		for i, p := range round {
+			if p.MatchID != "" {
+				_ = s.lobby.DeleteMatch(ctx, p.MatchID, settings.Owner)
+				round[i].MatchID = ""
+			}
+		}
+	}
+	return err
+}
+
+// seatPairings creates the match of each pairing and seats both players, stopping at the first failure.
+func (s *MemoryTournamentService) seatPairings(
+	ctx context.Context,
+	settings dto.MatchSettings,
+	round []dto.Pairing,
+) error {
+	for i := range round {
+		p := &round[i]
+		if p.PlayerB == "" {
+			continue
+		}
+
+		matchID, err := s.lobby.CreateMatch(ctx, p.PlayerA, settings)
+		if err != nil {
+			return fmt.Errorf("creating match for %s: %w", p.PlayerA, err)
+		}
+		p.MatchID = matchID
+
+		if _, err := s.lobby.JoinMatch(ctx, matchID, p.PlayerB); err != nil {
+			return fmt.Errorf("seating %s: %w", p.PlayerB, err)
+		}
+	}
+	return nil
+}
+
+// addRound appends a round whose matches were created, so that their events are followed.
+// It must be called with s.mu held.
+func (s *MemoryTournamentService) addRound(t *tournament, round []dto.Pairing) {
+	for _, p := range round {
+		if p.MatchID != "" {
+			s.byMatch[p.MatchID] = t
+		}
+	}
+	t.rounds = append(t.rounds, round)
+}
+
+// matchEnded records the winner of a tournament match and starts the next round once
+// every match of the current one is over. A match that ended without a winner, because
+// it was drawn, abandoned or terminated, is played again between the same players.
+// If the next round or the replay cannot be set up, the tournament fails.
+func (s *MemoryTournamentService) matchEnded(matchID, winner string) {
+	s.mu.Lock()
+	t, settings, next, replay := s.recordWinner(matchID, winner)
+	s.mu.Unlock()
+	if next == nil {
+		return
+	}
+
+	// Events are handled one at a time, so nothing else touches the bracket's last round meanwhile
+	err := s.createMatches(context.Background(), settings, next)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case err != nil && replay:
+		s.logger.Error("Could not replay tournament match", "tournament_id", t.id,
+			"match_id", matchID, "error", err)
+		t.state = dto.TournamentFailed
+		t.err = fmt.Sprintf("the match between %s and %s could not be replayed: %v",
+			next[0].PlayerA, next[0].PlayerB, err)
+	case err != nil:
+		s.logger.Error("Could not start tournament round", "tournament_id", t.id,
+			"round", len(t.rounds)+1, "error", err)
+		t.state = dto.TournamentFailed
+		t.err = fmt.Sprintf("round %d could not be started: %v", len(t.rounds)+1, err)
+	case replay:
+		s.replacePairing(t, next[0])
+	default:
+		s.addRound(t, next)
+	}
+}
+
+// recordWinner sets the winner of the match's pairing. Once that completes the round,
+// it returns the pairings of the next one, or finishes the tournament after the final.
+// If the match has no winner, it returns the pairing alone, to be replayed.
+// It must be called with s.mu held.
+func (s *MemoryTournamentService) recordWinner(
+	matchID, winner string,
+) (t *tournament, settings dto.MatchSettings, next []dto.Pairing, replay bool) {
+	t, ok := s.byMatch[matchID]
+	if !ok {
+		return nil, settings, nil, false
+	}
+	delete(s.byMatch, matchID)
+
+	round := t.rounds[len(t.rounds)-1]
+	i := slices.IndexFunc(round, func(p dto.Pairing) bool { return p.MatchID == matchID })
+	if i < 0 || round[i].Winner != "" {
+		return nil, settings, nil, false
+	}
+	if winner != round[i].PlayerA && winner != round[i].PlayerB {
+		return t, t.matchSettings(), []dto.Pairing{{PlayerA: round[i].PlayerA, PlayerB: round[i].PlayerB}}, true
+	}
+	round[i].Winner = winner
+
+	if slices.ContainsFunc(round, func(p dto.Pairing) bool { return p.Winner == "" }) {
+		return nil, settings, nil, false
+	}
+
+	winners := make([]string, len(round))
+	for i, p := range round {
+		winners[i] = p.Winner
+	}
+	if len(winners) == 1 {
+		t.state = dto.TournamentFinished
+		return nil, settings, nil, false
+	}
+	return t, t.matchSettings(), pairUp(winners), false
+}
+
+// replacePairing points the pairing of the same players in the last round at its replayed match.
+// It must be called with s.mu held.
+func (s *MemoryTournamentService) replacePairing(t *tournament, replayed dto.Pairing) {
+	round := t.rounds[len(t.rounds)-1]
+	for i, p := range round {
+		if p.PlayerA == replayed.PlayerA && p.PlayerB == replayed.PlayerB {
+			round[i].MatchID = replayed.MatchID
+			s.byMatch[replayed.MatchID] = t
+			return
+		}
+	}
+}
+
+// pairUp pairs the players in order. With an odd count, the last one gets a bye and wins right away.
+func pairUp(players []string) []dto.Pairing {
+	round := make([]dto.Pairing, 0, (len(players)+1)/2)
+	for pair := range slices.Chunk(players, 2) {
+		p := dto.Pairing{PlayerA: pair[0]}
+		if len(pair) == 2 {
+			p.PlayerB = pair[1]
+		} else {
+			p.Winner = pair[0]
+		}
+		round = append(round, p)
+	}
+	return round
+}
+
+// matchSettings returns the settings of the tournament's matches, which only the tournament may cancel.
+func (t *tournament) matchSettings() dto.MatchSettings {
+	settings := t.settings
+	settings.Owner = t.id
+	return settings
+}
+
+func (t *tournament) view() dto.TournamentView {
+	view := dto.TournamentView{
+		ID:        t.id,
+		HostID:    t.host,
+		State:     t.state,
+		Settings:  t.settings,
+		Players:   slices.Clone(t.players),
+		Rounds:    make([][]dto.Pairing, len(t.rounds)),
+		Standings: make([]dto.Standing, len(t.players)),
+		Error:     t.err,
+	}
+
+	index := make(map[string]int, len(t.players))
+	for i, id := range t.players {
+		view.Standings[i] = dto.Standing{PlayerID: id}
+		index[id] = i
+	}
+
+	for r, round := range t.rounds {
+		view.Rounds[r] = slices.Clone(round)
+		for _, p := range round {
+			if p.Winner == "" {
+				continue
+			}
+			view.Standings[index[p.Winner]].Wins++
+			if loser := p.PlayerA; loser != p.Winner {
+				view.Standings[index[loser]].Eliminated = true
+			} else if p.PlayerB != "" {
+				view.Standings[index[p.PlayerB]].Eliminated = true
+			}
+		}
+	}
+
+	if t.state == dto.TournamentFinished {
+		last := t.rounds[len(t.rounds)-1]
+		view.Winner = last[0].Winner
+	}
+
+	// Players who got further come first; ties keep the seeding
+	slices.SortStableFunc(view.Standings, func(a, b dto.Standing) int {
+		if a.Eliminated != b.Eliminated {
+			if a.Eliminated {
+				return 1
+			}
+			return -1
+		}
+		return b.Wins - a.Wins
+	})
+
+	return view
+}
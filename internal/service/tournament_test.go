@@ -0,0 +1,296 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/controller"
+	"github.com/callegarimattia/battleship/internal/dto"
+	"github.com/callegarimattia/battleship/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTournamentTest(t *testing.T) (*service.MemoryService, *service.MemoryTournamentService) {
+	t.Helper()
+	notifier := service.NewNotificationService()
+	s := service.NewMemoryService(notifier, service.WithAutoReady(true))
+	t.Cleanup(s.Close)
+	ts := service.NewMemoryTournamentService(s, notifier)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// playOut places both fleets of a small 6x6 match and sweeps the boards until someone wins.
+func playOut(t *testing.T, s *service.MemoryService, matchID, a, b string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, player := range []string{a, b} {
+		for y, size := range []int{3, 2, 2} {
+			_, err := s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+
+	next := map[string]int{}
+	for {
+		view, err := s.GetState(ctx, matchID, a)
+		require.NoError(t, err)
+		if view.State != dto.StatePlaying {
+			return view.Winner
+		}
+
+		shooter := view.Turn
+		shot := next[shooter]
+		next[shooter]++
+		_, err = s.Attack(ctx, matchID, shooter, shot%6, shot/6)
+		require.NoError(t, err)
+	}
+}
+
+func TestTournament_Registration(t *testing.T) {
+	t.Parallel()
+	_, ts := newTournamentTest(t)
+	ctx := context.Background()
+
+	_, err := ts.CreateTournament(ctx, "host", dto.MatchSettings{VsBot: true})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+	_, err = ts.CreateTournament(ctx, "host", dto.MatchSettings{FleetPreset: "huge"})
+	require.ErrorIs(t, err, controller.ErrInvalidSettings)
+
+	view, err := ts.CreateTournament(ctx, "host", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	assert.Equal(t, dto.TournamentRegistering, view.State)
+	assert.Equal(t, []string{"host"}, view.Players)
+
+	_, err = ts.StartTournament(ctx, view.ID, "host")
+	require.ErrorIs(t, err, controller.ErrNotEnoughPlayers)
+
+	_, err = ts.JoinTournament(ctx, view.ID, "p2")
+	require.NoError(t, err)
+	view, err = ts.JoinTournament(ctx, view.ID, "p2")
+	require.NoError(t, err, "signing up twice is harmless")
+	assert.Equal(t, []string{"host", "p2"}, view.Players)
+
+	_, err = ts.StartTournament(ctx, view.ID, "p2")
+	require.ErrorIs(t, err, controller.ErrNotHost)
+
+	view, err = ts.StartTournament(ctx, view.ID, "host")
+	require.NoError(t, err)
+	assert.Equal(t, dto.TournamentRunning, view.State)
+
+	_, err = ts.JoinTournament(ctx, view.ID, "late")
+	require.ErrorIs(t, err, controller.ErrTournamentStarted)
+	_, err = ts.StartTournament(ctx, view.ID, "host")
+	require.ErrorIs(t, err, controller.ErrTournamentStarted)
+
+	_, err = ts.GetTournament(ctx, "missing")
+	require.ErrorIs(t, err, controller.ErrTournamentNotFound)
+}
+
+func TestTournament_StartRollsBack(t *testing.T) {
+	t.Parallel()
+	s, ts := newTournamentTest(t)
+	ctx := context.Background()
+
+	view, err := ts.CreateTournament(ctx, "a", dto.MatchSettings{})
+	require.NoError(t, err)
+	for _, p := range []string{"b", "c", "d"} {
+		_, err = ts.JoinTournament(ctx, view.ID, p)
+		require.NoError(t, err)
+	}
+
+	// d is busy elsewhere, so the second match cannot be created
+	_, err = s.CreateMatch(ctx, "d", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	_, err = ts.StartTournament(ctx, view.ID, "a")
+	require.ErrorIs(t, err, controller.ErrTooManyActiveGames)
+
+	_, err = s.ActiveMatch(ctx, "a")
+	require.ErrorIs(t, err, controller.ErrMatchNotFound, "the first match was cancelled")
+
+	view, err = ts.GetTournament(ctx, view.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.TournamentRegistering, view.State)
+	assert.Empty(t, view.Rounds)
+}
+
+func TestTournament_Bracket(t *testing.T) {
+	t.Parallel()
+	s, ts := newTournamentTest(t)
+	ctx := context.Background()
+
+	view, err := ts.CreateTournament(ctx, "a", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	id := view.ID
+	for _, p := range []string{"b", "c"} {
+		_, err = ts.JoinTournament(ctx, id, p)
+		require.NoError(t, err)
+	}
+
+	view, err = ts.StartTournament(ctx, id, "a")
+	require.NoError(t, err)
+	require.Len(t, view.Rounds, 1)
+	first := view.Rounds[0]
+	require.Len(t, first, 2)
+	assert.Equal(t, "a", first[0].PlayerA)
+	assert.Equal(t, "b", first[0].PlayerB)
+	assert.NotEmpty(t, first[0].MatchID)
+	assert.Equal(t, dto.Pairing{PlayerA: "c", Winner: "c"}, first[1], "the odd player out gets a bye")
+
+	semiWinner := playOut(t, s, first[0].MatchID, "a", "b")
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && len(view.Rounds) == 2
+	}, time.Second, 10*time.Millisecond, "the final should start once the round is over")
+	assert.Equal(t, semiWinner, view.Rounds[0][0].Winner)
+
+	final := view.Rounds[1]
+	require.Len(t, final, 1)
+	assert.Equal(t, semiWinner, final[0].PlayerA)
+	assert.Equal(t, "c", final[0].PlayerB)
+
+	winner := playOut(t, s, final[0].MatchID, semiWinner, "c")
+	runnerUp := semiWinner
+	if winner == semiWinner {
+		runnerUp = "c"
+	}
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && view.State == dto.TournamentFinished
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, winner, view.Winner)
+
+	require.Len(t, view.Standings, 3)
+	assert.Equal(t, dto.Standing{PlayerID: winner, Wins: 2}, view.Standings[0])
+	assert.Equal(t, dto.Standing{PlayerID: runnerUp, Wins: 1, Eliminated: true}, view.Standings[1])
+	assert.True(t, view.Standings[2].Eliminated)
+	assert.Zero(t, view.Standings[2].Wins)
+}
+
+func TestTournament_CancelledMatch(t *testing.T) {
+	t.Parallel()
+	s, ts := newTournamentTest(t)
+	ctx := context.Background()
+
+	view, err := ts.CreateTournament(ctx, "a", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	id := view.ID
+	_, err = ts.JoinTournament(ctx, id, "b")
+	require.NoError(t, err)
+	view, err = ts.StartTournament(ctx, id, "a")
+	require.NoError(t, err)
+	matchID := view.Rounds[0][0].MatchID
+
+	for _, player := range []string{"a", "b"} {
+		for y, size := range []int{3, 2, 2} {
+			_, err = s.PlaceShip(ctx, matchID, player, size, 0, y, false)
+			require.NoError(t, err)
+		}
+	}
+	_, err = s.Attack(ctx, matchID, "a", 5, 5)
+	require.NoError(t, err)
+
+	// a hosts the match but may not cancel it to get through
+	require.ErrorIs(t, s.DeleteMatch(ctx, matchID, "a"), controller.ErrNotHost)
+	state, err := s.GetState(ctx, matchID, "a")
+	require.NoError(t, err)
+	assert.Equal(t, dto.StatePlaying, state.State)
+
+	// A match ended without a winner is played again
+	require.NoError(t, s.TerminateMatch(ctx, matchID))
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && view.Rounds[0][0].MatchID != matchID
+	}, time.Second, 10*time.Millisecond, "the pairing should get a new match")
+	assert.Equal(t, dto.TournamentRunning, view.State)
+	replay := view.Rounds[0][0]
+	assert.Empty(t, replay.Winner, "nobody advances on a terminated match")
+	assert.Equal(t, "a", replay.PlayerA)
+	assert.Equal(t, "b", replay.PlayerB)
+	for _, p := range view.Standings {
+		assert.False(t, p.Eliminated)
+	}
+
+	winner := playOut(t, s, replay.MatchID, "a", "b")
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && view.State == dto.TournamentFinished
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, winner, view.Winner)
+}
+
+func TestTournament_NextRoundFails(t *testing.T) {
+	t.Parallel()
+	s, ts := newTournamentTest(t)
+	ctx := context.Background()
+
+	view, err := ts.CreateTournament(ctx, "a", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	id := view.ID
+	for _, p := range []string{"b", "c"} {
+		_, err = ts.JoinTournament(ctx, id, p)
+		require.NoError(t, err)
+	}
+	view, err = ts.StartTournament(ctx, id, "a")
+	require.NoError(t, err)
+
+	// c, through to the final on a bye, is busy elsewhere when the semifinal ends
+	busyID, err := s.CreateMatch(ctx, "c", dto.MatchSettings{})
+	require.NoError(t, err)
+
+	playOut(t, s, view.Rounds[0][0].MatchID, "a", "b")
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && view.State == dto.TournamentFailed
+	}, time.Second, 10*time.Millisecond, "the tournament should fail rather than wait forever")
+	assert.Len(t, view.Rounds, 1, "the final that could not be set up is not in the bracket")
+	assert.Contains(t, view.Error, "round 2")
+	assert.Empty(t, view.Winner)
+
+	active, err := s.ActiveMatch(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, busyID, active, "c is left in the other match")
+}
+
+func TestTournament_ResumedPlayer(t *testing.T) {
+	t.Parallel()
+	s, ts := newTournamentTest(t)
+	ctx := context.Background()
+
+	view, err := ts.CreateTournament(ctx, "a", dto.MatchSettings{BoardSize: 6, FleetPreset: "small"})
+	require.NoError(t, err)
+	id := view.ID
+	_, err = ts.JoinTournament(ctx, id, "b")
+	require.NoError(t, err)
+	view, err = ts.StartTournament(ctx, id, "a")
+	require.NoError(t, err)
+	matchID := view.Rounds[0][0].MatchID
+
+	state, err := s.GetState(ctx, matchID, "b")
+	require.NoError(t, err)
+	_, err = s.ResumeMatch(ctx, matchID, "b2", state.ResumeToken)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && view.Rounds[0][0].PlayerB == "b2"
+	}, time.Second, 10*time.Millisecond, "the bracket should follow the player")
+
+	winner := playOut(t, s, matchID, "a", "b2")
+
+	require.Eventually(t, func() bool {
+		view, err = ts.GetTournament(ctx, id)
+		return err == nil && view.State == dto.TournamentFinished
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, winner, view.Winner)
+	assert.ElementsMatch(t, []string{"a", "b2"}, []string{view.Standings[0].PlayerID, view.Standings[1].PlayerID})
+}
@@ -2,11 +2,12 @@
 package tui
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/callegarimattia/battleship/internal/client"
 	"github.com/callegarimattia/battleship/internal/dto"
 	"github.com/callegarimattia/battleship/internal/env"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -20,7 +21,27 @@ const (
 	StateGame
 )
 
-const BoardSize = 10
+// DefaultBoardSize is the side length of the standard board, used until a match's rules tell otherwise.
+const DefaultBoardSize = 10
+
+// standardFleet is the sizes of the standard Battleship fleet, used until a match's rules tell otherwise.
+var standardFleet = []int{5, 4, 3, 3, 2}
+
+// Selection is a target or placement waiting for confirmation.
+type Selection struct {
+	X, Y     int
+	Vertical bool
+}
+
+// Option configures the TUI model.
+type Option func(*Model)
+
+// WithConfirmFire requires a second Enter on the same cell before firing or placing a ship.
+func WithConfirmFire(enabled bool) Option {
+	return func(m *Model) {
+		m.ConfirmFire = enabled
+	}
+}
 
 // Model is the main TUI model.
 type Model struct {
@@ -28,18 +49,29 @@ type Model struct {
 	Client *client.Client
 
 	// Login
-	LoginInput textinput.Model
+	LoginInput  textinput.Model
+	SessionPath string // Where the login is remembered, empty to disable
 
 	// Lobby
-	Matches []dto.MatchSummary
-	Cursor  int
+	Matches     []dto.MatchSummary
+	Cursor      int
+	JoinInput   textinput.Model
+	JoiningByID bool // The match ID prompt is open
 
 	// Game
 	GameID   string
 	GameView *dto.GameView
+	Reveal   *dto.RevealView // Both fleets once the game is decided, to show the ships the player never found
+	Copied   bool            // The match ID was sent to the clipboard
+	// Sub streams the match's events. It is closed when the player leaves the match or quits,
+	// so that no connection outlives its game.
+	Sub *client.Subscription
+	// Connection is how the match stream stands, so the player knows when updates stop coming
+	Connection client.SubscriptionStatus
 
 	// Game Interaction
 	CursorX, CursorY int
+	BoardSize        int // Side length of the match's boards, from its rules
 
 	// Setup Phase
 	SetupPhase      bool
@@ -47,6 +79,18 @@ type Model struct {
 	CurrentShipIdx  int
 	ShipOrientation bool // false = horizontal, true = vertical
 
+	// Confirm-Fire Mode
+	ConfirmFire bool
+	Selected    *Selection // Pending action, nil when nothing is selected
+
+	// Waiting Indicator
+	Spinner  spinner.Model
+	Spinning bool // The spinner is ticking
+
+	// Game-Over Animation
+	Animating bool
+	AnimFrame int
+
 	// Error Handling
 	Err error
 
@@ -54,10 +98,11 @@ type Model struct {
 	Width, Height int
 }
 
-func New() *Model {
+// New creates the TUI model, configured from the environment and the given options.
+func New(opts ...Option) (*Model, error) {
 	cfg, err := env.LoadClientConfig()
 	if err != nil {
-		log.Fatalf("Failed to load client config: %v", err)
+		return nil, fmt.Errorf("failed to load client config: %w", err)
 	}
 
 	ti := textinput.New()
@@ -66,14 +111,42 @@ func New() *Model {
 	ti.CharLimit = 20
 	ti.Width = 30
 
-	return &Model{
+	ji := textinput.New()
+	ji.Placeholder = "Match ID"
+	ji.CharLimit = 64
+	ji.Width = 40
+
+	m := &Model{
 		State:        StateLogin,
 		Client:       client.New(cfg.BaseURL),
 		LoginInput:   ti,
-		ShipsToPlace: []int{5, 4, 3, 3, 2}, // Standard Battleship fleet
+		JoinInput:    ji,
+		BoardSize:    DefaultBoardSize,
+		ShipsToPlace: standardFleet,
+		// Unstyled so it takes the color of the instructions around it
+		Spinner: spinner.New(spinner.WithSpinner(spinner.Dot)),
+	}
+	if path, err := client.SessionPath(); err == nil {
+		m.SessionPath = path
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
 }
 
 func (m *Model) Init() tea.Cmd {
-	return textinput.Blink
+	if m.SessionPath == "" {
+		return textinput.Blink
+	}
+
+	// Resume the previous login if its token is still accepted
+	session, err := client.LoadSession(m.SessionPath)
+	if err != nil {
+		return textinput.Blink
+	}
+	m.Client.Token = session.Token
+	m.LoginInput.SetValue(session.Username)
+
+	return tea.Batch(textinput.Blink, resumeSessionCmd(m.Client, m.SessionPath))
 }
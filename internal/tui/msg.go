@@ -3,6 +3,7 @@ package tui
 import (
 	"time"
 
+	"github.com/callegarimattia/battleship/internal/client"
 	"github.com/callegarimattia/battleship/internal/dto"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -10,17 +11,47 @@ import (
 // Messages
 type (
 	PerformLoginMsg struct{}
+	SessionLostMsg  struct{}
 	GotMatchesMsg   []dto.MatchSummary
-	MatchJoinedMsg  struct{ ID string }
+	MatchCancelMsg  struct{}
+	CopiedMsg       struct{}
 	GotGameMsg      *dto.GameView
+	GotRevealMsg    *dto.RevealView
 	ShipPlacedMsg   struct{ Game *dto.GameView }
 	TickMsg         time.Time
-	GameUpdateMsg   struct {
-		Event   *dto.WSEvent
-		Channel <-chan *dto.WSEvent
+	AnimationMsg    time.Time
+	MatchJoinedMsg  struct {
+		ID     string
+		Hosted bool // The player created the match
+	}
+	// GotRulesMsg carries the rules of a match just joined, nil when they could not be fetched.
+	GotRulesMsg struct {
+		Match MatchJoinedMsg
+		Rules *dto.Rules
+	}
+	// SubscribedMsg hands over the stream of a match once it is connected.
+	SubscribedMsg struct {
+		MatchID string
+		Sub     *client.Subscription
+	}
+	GameUpdateMsg struct {
+		Event *dto.WSEvent
+		Sub   *client.Subscription
+	}
+	// ConnectionMsg tells the match stream connected, dropped or ended.
+	ConnectionMsg struct {
+		Status client.SubscriptionStatus
+		Sub    *client.Subscription
 	}
 )
 
+// AnimationCmd returns a command that advances the game-over animation by one frame.
+func AnimationCmd() tea.Cmd {
+	return tea.Tick(animationFrameDelay, func(t time.Time) tea.Msg {
+		return AnimationMsg(t)
+	})
+}
+
 // TickCmd returns a command that triggers a tick.
 func TickCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
@@ -9,6 +9,7 @@ var (
 	ColorSetup  = lipgloss.Color("#00BFFF") // Deep Sky Blue
 	ColorMyTurn = lipgloss.Color("#00FA9A") // Medium Spring Green
 	ColorOpTurn = lipgloss.Color("#FF4500") // Orange Red
+	ColorIdle   = lipgloss.Color("#808080") // Gray
 
 	// General Styles
 	StyleTitle = lipgloss.NewStyle().
@@ -29,9 +30,16 @@ var (
 	StyleCellSunk    = lipgloss.NewStyle().Foreground(lipgloss.Color("208")) // Orange
 	StyleCellUnknown = lipgloss.NewStyle().Foreground(lipgloss.Color("237")) // Gray
 	StyleCellGhost   = lipgloss.NewStyle().Foreground(lipgloss.Color("57"))  // Purple/Ghost
-	StyleCursor      = lipgloss.NewStyle().
+	// Enemy ships shown after the game, dimmer than the player's own
+	StyleCellRevealed = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Faint(true)
+	StyleCursor       = lipgloss.NewStyle().
 				Background(lipgloss.Color("252")).
 				Foreground(lipgloss.Color("0"))
+	StyleRain     = lipgloss.NewStyle().Foreground(lipgloss.Color("67")) // Steel Blue
+	StyleSelected = lipgloss.NewStyle().
+			Background(lipgloss.Color("#FFD700")). // Gold
+			Foreground(lipgloss.Color("0")).
+			Bold(true)
 
 	StyleErrorBox = lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
@@ -1,24 +1,49 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	osc52 "github.com/aymanbagabas/go-osc52/v2"
 	"github.com/callegarimattia/battleship/internal/client"
 	"github.com/callegarimattia/battleship/internal/dto"
-	"github.com/callegarimattia/battleship/internal/tui/rules"
+	"github.com/callegarimattia/battleship/internal/rules"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const (
+	// animationFrames is the length of the game-over animation before it dismisses itself.
+	animationFrames     = 40
+	animationFrameDelay = 80 * time.Millisecond
+)
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	// --- Global Keys (Always generic) ---
 	if key, ok := msg.(tea.KeyMsg); ok {
 		if key.String() == "ctrl+c" {
+			m.closeSubscription()
 			return m, tea.Quit
 		}
 	}
 
+	// A stream only belongs to the match being played; one that connected too late is closed
+	if msg, ok := msg.(SubscribedMsg); ok {
+		if m.State != StateGame || msg.MatchID != m.GameID {
+			msg.Sub.Close()
+			return m, nil
+		}
+		m.closeSubscription()
+		m.Sub = msg.Sub
+		return m, func() tea.Msg { return listenForUpdates(msg.Sub) }
+	}
+
 	// --- Error Handling ---
 	// Block other updates while error is shown
 	if m.Err != nil {
@@ -60,29 +85,56 @@ func (m *Model) updateLogin(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
 		username := m.LoginInput.Value()
 		return m, func() tea.Msg {
-			_, err := m.Client.Login(username)
+			res, err := m.Client.Login(username)
 			if err != nil {
 				return err
 			}
+			if m.SessionPath != "" {
+				// Best effort: failing to remember the login only means logging in again next time
+				_ = client.SaveSession(m.SessionPath, client.Session{Username: res.User.Username, Token: res.Token})
+			}
 			return PerformLoginMsg{}
 		}
 	}
 
-	if _, ok := msg.(PerformLoginMsg); ok {
+	switch msg.(type) {
+	case PerformLoginMsg:
 		m.State = StateLobby
 		return m, fetchMatchesCmd(m.Client)
+	case SessionLostMsg:
+		m.Client.Token = ""
 	}
 	return m, cmd
 }
 
+// resumeSessionCmd checks a stored token with the server. An expired or unknown token is forgotten;
+// any other failure keeps it for the next run. Either way the player lands on the login screen.
+func resumeSessionCmd(c *client.Client, sessionPath string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := c.Me()
+		switch {
+		case err == nil:
+			return PerformLoginMsg{}
+		case errors.Is(err, client.ErrUnauthorized):
+			_ = client.ClearSession(sessionPath)
+		}
+		return SessionLostMsg{}
+	}
+}
+
 func (m *Model) updateLobby(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case GotMatchesMsg:
 		m.Matches = msg
 	case tea.KeyMsg:
+		if m.JoiningByID {
+			return m.handleJoinByIDKeys(msg)
+		}
 		return m.handleLobbyKeys(msg)
 	case MatchJoinedMsg:
 		return m.handleMatchJoined(msg)
+	case GotRulesMsg:
+		return m.enterGame(msg.Match, msg.Rules)
 	}
 	return m, nil
 }
@@ -101,12 +153,16 @@ func (m *Model) handleLobbyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, fetchMatchesCmd(m.Client)
 	case "c":
 		return m, func() tea.Msg {
-			id, err := m.Client.CreateMatch()
+			id, err := m.Client.CreateMatch(dto.MatchSettings{})
 			if err != nil {
 				return err
 			}
-			return MatchJoinedMsg{ID: id}
+			return MatchJoinedMsg{ID: id, Hosted: true}
 		}
+	case "i":
+		m.JoiningByID = true
+		m.JoinInput.SetValue("")
+		return m, m.JoinInput.Focus()
 	case "enter":
 		if len(m.Matches) > 0 {
 			selectedID := m.Matches[m.Cursor].ID
@@ -122,16 +178,94 @@ func (m *Model) handleLobbyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleJoinByIDKeys drives the prompt for joining a match that is not listed, e.g. a friend's.
+func (m *Model) handleJoinByIDKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.JoiningByID = false
+		m.JoinInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		matchID := strings.TrimSpace(m.JoinInput.Value())
+		if matchID == "" {
+			return m, nil
+		}
+		m.JoiningByID = false
+		m.JoinInput.Blur()
+		return m, func() tea.Msg {
+			if _, err := m.Client.JoinMatch(matchID); err != nil {
+				return fmt.Errorf("could not join match %s: %w", matchID, err)
+			}
+			return MatchJoinedMsg{ID: matchID}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.JoinInput, cmd = m.JoinInput.Update(msg)
+	return m, cmd
+}
+
+// handleMatchJoined fetches the rules of the match, so that the board and fleet are set up
+// for it before entering the game. The standard game is assumed when they cannot be fetched.
 func (m *Model) handleMatchJoined(msg MatchJoinedMsg) (tea.Model, tea.Cmd) {
+	return m, func() tea.Msg {
+		rules, err := m.Client.GetRules(msg.ID)
+		if err != nil {
+			return GotRulesMsg{Match: msg}
+		}
+		return GotRulesMsg{Match: msg, Rules: rules}
+	}
+}
+
+// applyRules sizes the board and lists the ships to place from the match's rules,
+// the host's fleet or the guest's, which differ with a handicap.
+func (m *Model) applyRules(matchRules *dto.Rules, hosted bool) {
+	m.BoardSize = DefaultBoardSize
+	m.ShipsToPlace = standardFleet
+	if matchRules == nil {
+		return
+	}
+
+	if matchRules.BoardSize > 0 {
+		m.BoardSize = matchRules.BoardSize
+	}
+	fleet := matchRules.GuestFleet
+	if hosted {
+		fleet = matchRules.HostFleet
+	}
+	if len(fleet) > 0 {
+		m.ShipsToPlace = nil
+		for _, ship := range fleet {
+			for range ship.Count {
+				m.ShipsToPlace = append(m.ShipsToPlace, ship.Size)
+			}
+		}
+	}
+}
+
+func (m *Model) enterGame(msg MatchJoinedMsg, rules *dto.Rules) (tea.Model, tea.Cmd) {
+	m.applyRules(rules, msg.Hosted)
 	m.GameID = msg.ID
+	m.GameView = nil
+	m.Reveal = nil
+	m.Copied = false
+	m.Connection = client.SubscriptionStatus{}
 	m.State = StateGame
 	// Initialize game state params
 	m.CursorX = 0
 	m.CursorY = 0
 	m.CurrentShipIdx = 0
 	m.SetupPhase = true
+
+	// Hosts need to share the ID, so put it on the clipboard right away
+	var copyID tea.Cmd
+	if msg.Hosted {
+		copyID = copyToClipboardCmd(m.GameID)
+	}
+
 	// Kick off WS listener and initial fetch
 	return m, tea.Batch(
+		copyID,
 		func() tea.Msg { // Initial fetch
 			g, err := m.Client.GetGameState(m.GameID)
 			if err != nil {
@@ -145,21 +279,53 @@ func (m *Model) handleMatchJoined(msg MatchJoinedMsg) (tea.Model, tea.Cmd) {
 
 func subToWSCmd(c *client.Client, matchID string) tea.Cmd {
 	return func() tea.Msg {
-		ch, err := c.SubscribeToMatch(matchID)
+		sub, err := c.Subscribe(context.Background(), matchID)
 		if err != nil {
 			return err
 		}
-		return listenForUpdates(ch)
+		return SubscribedMsg{MatchID: matchID, Sub: sub}
 	}
 }
 
-// listenForUpdates waits for a signal from the WS channel
-func listenForUpdates(ch <-chan *dto.WSEvent) tea.Msg {
-	evt, ok := <-ch
-	if !ok {
+// closeSubscription closes the stream of the match being left, if there is one.
+func (m *Model) closeSubscription() {
+	if m.Sub != nil {
+		m.Sub.Close()
+		m.Sub = nil
+	}
+}
+
+// leaveGame closes the match's stream and returns to the lobby.
+func (m *Model) leaveGame() (tea.Model, tea.Cmd) {
+	m.closeSubscription()
+	m.State = StateLobby
+	m.GameID = ""
+	m.GameView = nil
+	m.Reveal = nil
+	m.Spinning = false
+	m.Animating = false
+	return m, fetchMatchesCmd(m.Client)
+}
+
+// listenForUpdates waits for the next event or connection status of the subscription.
+// Once the subscription has ended and both are drained, it returns nil and listening stops.
+func listenForUpdates(sub *client.Subscription) tea.Msg {
+	select {
+	case evt, ok := <-sub.Events:
+		if ok {
+			return GameUpdateMsg{Event: evt, Sub: sub}
+		}
+		// The stream ended: its last status tells why
+		if status, ok := <-sub.Status; ok {
+			return ConnectionMsg{Status: status, Sub: sub}
+		}
 		return nil
+	case status, ok := <-sub.Status:
+		if !ok {
+			return nil
+		}
+		return ConnectionMsg{Status: status, Sub: sub}
 	}
-	return GameUpdateMsg{Event: evt, Channel: ch}
 }
 
 func (m *Model) updateGame(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -168,10 +334,38 @@ func (m *Model) updateGame(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleGotGame(msg)
 	case tea.KeyMsg:
 		return m.handleGameKeys(msg)
+	case AnimationMsg:
+		if !m.Animating {
+			return m, nil // Skipped
+		}
+		m.AnimFrame++
+		if m.AnimFrame >= animationFrames {
+			m.Animating = false
+			return m, nil
+		}
+		return m, AnimationCmd()
+	case spinner.TickMsg:
+		if !m.Spinning {
+			return m, nil // Let the tick chain die out
+		}
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	case CopiedMsg:
+		m.Copied = true
+		return m, nil
+	case GotRevealMsg:
+		m.Reveal = msg
+		return m, nil
+	case MatchCancelMsg:
+		return m.leaveGame()
 	case ShipPlacedMsg:
 		m.CurrentShipIdx++
 		return m.handleGotGame(GotGameMsg(msg.Game))
 	case GameUpdateMsg:
+		if msg.Sub != m.Sub {
+			return m, nil // Left over from a stream that was closed
+		}
 		// Handle Event
 		var cmd tea.Cmd
 		if msg.Event.Type == "game_update" && msg.Event.Payload != nil {
@@ -187,9 +381,17 @@ func (m *Model) updateGame(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(
 			cmd,
 			func() tea.Msg {
-				return listenForUpdates(msg.Channel)
+				return listenForUpdates(msg.Sub)
 			},
 		)
+	case ConnectionMsg:
+		if msg.Sub != m.Sub {
+			return m, nil
+		}
+		m.Connection = msg.Status
+		return m, func() tea.Msg {
+			return listenForUpdates(msg.Sub)
+		}
 	}
 	return m, nil
 }
@@ -198,24 +400,120 @@ func (m *Model) handleGotGame(msg GotGameMsg) (tea.Model, tea.Cmd) {
 	if msg == nil {
 		return m, nil
 	}
+	wasSetup := m.SetupPhase
+	justFinished := m.GameView != nil && m.GameView.State != dto.StateFinished &&
+		msg.State == dto.StateFinished
+	// Fetch the fleets once the game is decided, also when joining a match that already is
+	decided := msg.State == dto.StateFinished || msg.State == dto.StateDraw
+	fetchReveal := decided && (m.GameView == nil || m.GameView.State != msg.State)
 	m.GameView = msg
 	switch m.GameView.State {
-	case dto.StatePlaying, dto.StateFinished:
+	case dto.StatePlaying, dto.StateFinished, dto.StateDraw, dto.StateAbandoned:
 		m.SetupPhase = false
 	default:
 		m.SetupPhase = true
 	}
-	return m, nil
+
+	// A selection only makes sense while the player can still act on it
+	if m.SetupPhase != wasSetup || (!m.SetupPhase && !m.GameView.YourTurn) {
+		m.Selected = nil
+	}
+
+	var animation tea.Cmd
+	if justFinished {
+		m.Animating = true
+		m.AnimFrame = 0
+		animation = AnimationCmd()
+	}
+
+	var reveal tea.Cmd
+	if fetchReveal {
+		reveal = fetchRevealCmd(m.Client, m.GameID)
+	}
+
+	return m, tea.Batch(m.syncSpinner(), animation, reveal)
+}
+
+// fetchRevealCmd fetches both fleets of a decided match. It is only a bonus on the final boards,
+// so a failure is not worth an error box.
+func fetchRevealCmd(c *client.Client, matchID string) tea.Cmd {
+	return func() tea.Msg {
+		reveal, err := c.Reveal(matchID)
+		if err != nil {
+			return nil
+		}
+		return GotRevealMsg(reveal)
+	}
+}
+
+// awaitingOpponent reports whether the player hosts a match nobody has joined yet.
+func (m *Model) awaitingOpponent() bool {
+	return m.GameView != nil && (m.GameView.State == "" || m.GameView.State == dto.StateWaiting)
+}
+
+// isDecided reports whether the game is over, so that there is nothing left to play.
+func (m *Model) isDecided() bool {
+	if m.GameView == nil {
+		return false
+	}
+	switch m.GameView.State {
+	case dto.StateFinished, dto.StateDraw, dto.StateAbandoned:
+		return true
+	}
+	return false
+}
+
+// awaitingReady reports whether the whole fleet is placed but the player has not confirmed it yet.
+// Servers running with auto-ready mark the player ready on the last placement, skipping this step.
+func (m *Model) awaitingReady() bool {
+	return m.GameView != nil && m.GameView.State == dto.StateSetup &&
+		m.CurrentShipIdx >= len(m.ShipsToPlace) && !m.GameView.Me.Ready
+}
+
+// isWaiting reports whether the player is waiting on the opponent, either for their turn
+// or for them to finish placing ships.
+func (m *Model) isWaiting() bool {
+	if m.GameView == nil {
+		return false
+	}
+	switch m.GameView.State {
+	case dto.StatePlaying:
+		return !m.GameView.YourTurn
+	case dto.StateFinished, dto.StateDraw, dto.StateAbandoned:
+		return false
+	default:
+		return m.GameView.State != dto.StateSetup ||
+			(m.CurrentShipIdx >= len(m.ShipsToPlace) && m.GameView.Me.Ready)
+	}
+}
+
+// syncSpinner starts the waiting spinner when the player starts waiting and stops it otherwise.
+func (m *Model) syncSpinner() tea.Cmd {
+	waiting := m.isWaiting()
+	if waiting == m.Spinning {
+		return nil
+	}
+	m.Spinning = waiting
+	if waiting {
+		return m.Spinner.Tick
+	}
+	return nil
 }
 
 func (m *Model) handleGameKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Any key skips the game-over animation
+	if m.Animating {
+		m.Animating = false
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "up", "k":
 		if m.CursorY > 0 {
 			m.CursorY--
 		}
 	case "down", "j":
-		if m.CursorY < BoardSize-1 {
+		if m.CursorY < m.BoardSize-1 {
 			m.CursorY++
 		}
 	case "left", "h":
@@ -223,13 +521,26 @@ func (m *Model) handleGameKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.CursorX--
 		}
 	case "right", "l":
-		if m.CursorX < BoardSize-1 {
+		if m.CursorX < m.BoardSize-1 {
 			m.CursorX++
 		}
 	case "r":
 		if m.SetupPhase {
 			m.ShipOrientation = !m.ShipOrientation
+			m.Selected = nil
 		}
+	case "y":
+		if m.awaitingOpponent() {
+			return m, copyToClipboardCmd(m.GameID)
+		}
+	case "esc":
+		if m.awaitingOpponent() {
+			return m, cancelMatchCmd(m.Client, m.GameID)
+		}
+		if m.isDecided() {
+			return m.leaveGame()
+		}
+		m.Selected = nil
 	case "enter", "space":
 		return m.handleAction()
 	}
@@ -243,13 +554,22 @@ func (m *Model) handleAction() (tea.Model, tea.Cmd) {
 
 	if m.SetupPhase {
 		return m.handleSetupAction()
-	} else if m.GameView.State == dto.StatePlaying && m.GameView.Turn == m.GameView.Me.ID {
+	} else if m.GameView.YourTurn {
 		return m.handlePlayAction()
 	}
 	return m, nil
 }
 
 func (m *Model) handleSetupAction() (tea.Model, tea.Cmd) {
+	if m.awaitingReady() {
+		return m, func() tea.Msg {
+			g, err := m.Client.Ready(m.GameID)
+			if err != nil {
+				return err
+			}
+			return GotGameMsg(g)
+		}
+	}
 	if m.CurrentShipIdx >= len(m.ShipsToPlace) {
 		return m, nil
 	}
@@ -263,12 +583,16 @@ func (m *Model) handleSetupAction() (tea.Model, tea.Cmd) {
 	}
 
 	// Validation: Check Rules
-	if err := rules.CanPlaceShip(m.GameView.Me.Board, size, cx, cy, vert); err != nil {
+	if err := rules.CanPlaceShip(rules.View(m.GameView.Me.Board), size, cx, cy, vert); err != nil {
 		return m, func() tea.Msg {
 			return err
 		}
 	}
 
+	if !m.confirm(Selection{X: cx, Y: cy, Vertical: vert}) {
+		return m, nil
+	}
+
 	return m, func() tea.Msg {
 		g, err := m.Client.PlaceShip(m.GameID, size, cx, cy, vert)
 		if err != nil {
@@ -282,12 +606,16 @@ func (m *Model) handlePlayAction() (tea.Model, tea.Cmd) {
 	cx, cy := m.CursorX, m.CursorY
 
 	// Validation: Check if cell can be attacked
-	if err := rules.CanAttack(m.GameView.Enemy.Board, cx, cy); err != nil {
+	if err := rules.CanAttack(rules.View(m.GameView.Enemy.Board), cx, cy); err != nil {
 		return m, func() tea.Msg {
 			return err
 		}
 	}
 
+	if !m.confirm(Selection{X: cx, Y: cy}) {
+		return m, nil
+	}
+
 	return m, func() tea.Msg {
 		g, err := m.Client.Attack(m.GameID, cx, cy)
 		if err != nil {
@@ -297,9 +625,50 @@ func (m *Model) handlePlayAction() (tea.Model, tea.Cmd) {
 	}
 }
 
+// confirm reports whether the action on sel should go ahead.
+// In confirm-fire mode the first press only selects the cell and a second press on it confirms.
+func (m *Model) confirm(sel Selection) bool {
+	if !m.ConfirmFire {
+		return true
+	}
+	if m.Selected != nil && *m.Selected == sel {
+		m.Selected = nil
+		return true
+	}
+	m.Selected = &sel
+	return false
+}
+
+// copyToClipboardCmd copies text with an OSC 52 escape sequence, which the terminal forwards
+// to the system clipboard. It works over SSH, but some terminals ignore it.
+func copyToClipboardCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		switch {
+		case os.Getenv("TMUX") != "":
+			seq = seq.Tmux()
+		case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+			seq = seq.Screen()
+		}
+		if _, err := seq.WriteTo(os.Stderr); err != nil {
+			return err
+		}
+		return CopiedMsg{}
+	}
+}
+
+func cancelMatchCmd(c *client.Client, matchID string) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.CancelMatch(matchID); err != nil {
+			return err
+		}
+		return MatchCancelMsg{}
+	}
+}
+
 func fetchMatchesCmd(c *client.Client) tea.Cmd {
 	return func() tea.Msg {
-		matches, err := c.ListMatches()
+		matches, err := c.ListMatches(dto.MatchFilter{})
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/callegarimattia/battleship/internal/client"
+	"github.com/callegarimattia/battleship/internal/dto"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRules(t *testing.T) {
+	t.Parallel()
+
+	handicap := &dto.Rules{
+		BoardSize:  8,
+		HostFleet:  []dto.ShipRule{{Size: 4, Count: 1}, {Size: 2, Count: 2}},
+		GuestFleet: []dto.ShipRule{{Size: 3, Count: 1}},
+	}
+
+	tests := []struct {
+		name      string
+		rules     *dto.Rules
+		hosted    bool
+		wantSize  int
+		wantShips []int
+	}{
+		{name: "Host", rules: handicap, hosted: true, wantSize: 8, wantShips: []int{4, 2, 2}},
+		{name: "Guest", rules: handicap, wantSize: 8, wantShips: []int{3}},
+		{name: "No Rules", wantSize: DefaultBoardSize, wantShips: standardFleet},
+		{
+			name:      "Unset Fields",
+			rules:     &dto.Rules{},
+			hosted:    true,
+			wantSize:  DefaultBoardSize,
+			wantShips: standardFleet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Left over from a previous match, so that the defaults have to be restored
+			m := &Model{BoardSize: 12, ShipsToPlace: []int{1}}
+			m.applyRules(tt.rules, tt.hosted)
+
+			assert.Equal(t, tt.wantSize, m.BoardSize)
+			assert.Equal(t, tt.wantShips, m.ShipsToPlace)
+		})
+	}
+}
+
+func TestUpdate_ClosesSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	// The server reports the path of every match stream that the TUI closed
+	closed := make(chan string, 3)
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ws.Close()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				break
+			}
+		}
+		closed <- r.URL.Path
+	}))
+	defer ts.Close()
+
+	assertClosed := func(sub *client.Subscription, path string) {
+		t.Helper()
+		_, ok := <-sub.Events
+		assert.False(t, ok, "the subscription to %s should be closed", path)
+		select {
+		case got := <-closed:
+			assert.Equal(t, path, got)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the connection to %s was left open", path)
+		}
+	}
+
+	m := &Model{Client: client.New(ts.URL)}
+	play := func(matchID string) *client.Subscription {
+		t.Helper()
+		m.State, m.GameID = StateGame, matchID
+		msg, ok := subToWSCmd(m.Client, matchID)().(SubscribedMsg)
+		require.True(t, ok)
+		m.Update(msg)
+		require.Same(t, msg.Sub, m.Sub)
+		return msg.Sub
+	}
+
+	first := play("m1")
+	m.Update(MatchCancelMsg{})
+	assert.Equal(t, StateLobby, m.State)
+	assert.Nil(t, m.Sub)
+	assertClosed(first, "/matches/m1/ws")
+
+	// A stream that connects once the player has moved on is not kept either
+	late, ok := subToWSCmd(m.Client, "m1")().(SubscribedMsg)
+	require.True(t, ok)
+	m.Update(late)
+	assert.Nil(t, m.Sub)
+	assertClosed(late.Sub, "/matches/m1/ws")
+
+	second := play("m2")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.QuitMsg{}, cmd())
+	assert.Nil(t, m.Sub)
+	assertClosed(second, "/matches/m2/ws")
+}
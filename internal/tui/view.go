@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/callegarimattia/battleship/internal/client"
 	"github.com/callegarimattia/battleship/internal/dto"
-	"github.com/callegarimattia/battleship/internal/tui/rules"
+	"github.com/callegarimattia/battleship/internal/rules"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -85,11 +86,19 @@ func (m *Model) viewLobby() string {
 			s.WriteString(line + "\n")
 		}
 	}
-	s.WriteString("\n[C] Create New Match | [Enter] Join Selected | [R] Refresh")
+	if m.JoiningByID {
+		s.WriteString("\nJoin by ID: " + m.JoinInput.View() + "\n[Enter] Join | [Esc] Cancel")
+		return s.String()
+	}
+	s.WriteString("\n[C] Create New Match | [Enter] Join Selected | [I] Join by ID | [R] Refresh")
 	return s.String()
 }
 
 func (m *Model) viewGame() string {
+	if m.awaitingOpponent() {
+		return m.viewAwaitingOpponent()
+	}
+
 	// 1. Determine Base Color based on State
 	var baseColor lipgloss.Color
 	stateLabel := ""
@@ -103,16 +112,25 @@ func (m *Model) viewGame() string {
 			baseColor = ColorLose
 			stateLabel = "DEFEAT"
 		}
+	case m.GameView.State == dto.StateDraw:
+		baseColor = ColorIdle
+		stateLabel = "DRAW"
+	case m.GameView.State == dto.StateAbandoned:
+		baseColor = ColorIdle
+		stateLabel = "ABANDONED"
 	case m.SetupPhase || m.GameView.State == dto.StateSetup:
 		baseColor = ColorSetup
 		stateLabel = "SETUP PHASE"
-	case m.GameView.Turn == m.GameView.Me.ID:
+	case m.GameView.YourTurn:
 		baseColor = ColorMyTurn
 		stateLabel = "YOUR TURN"
 	default:
 		baseColor = ColorOpTurn
 		stateLabel = "OPPONENT'S TURN"
 	}
+	if m.GameView.MovesLeft > 0 && m.GameView.State == dto.StatePlaying {
+		stateLabel += fmt.Sprintf(" · %d MOVES LEFT", m.GameView.MovesLeft)
+	}
 
 	// 2. Styles
 	styleBorder := StyleBoardBorder.BorderForeground(baseColor)
@@ -120,31 +138,87 @@ func (m *Model) viewGame() string {
 
 	// 3. Render Content
 	instructions := styleLabel.Render(m.getInstructions())
+	if m.GameView.NearbyShip && m.GameView.State == dto.StatePlaying {
+		instructions += "\n" + lipgloss.NewStyle().Foreground(ColorIdle).Italic(true).
+			Render("Getting warmer: your last miss is right next to a ship")
+	}
+	if notice := m.connectionNotice(); notice != "" {
+		instructions += "\n" + lipgloss.NewStyle().Foreground(ColorLose).Render(notice)
+	}
 
 	// Boards
 	showMyCursor := m.SetupPhase && m.CurrentShipIdx < len(m.ShipsToPlace)
-	showEnemyCursor := !m.SetupPhase && m.GameView.State == dto.StatePlaying &&
-		m.GameView.Turn == m.GameView.Me.ID
+	showEnemyCursor := !m.SetupPhase && m.GameView.YourTurn
 
 	myBoard := m.renderBoard(m.GameView.Me.Board, showMyCursor, true, &styleBorder)
 	enemyBoard := m.renderBoard(m.GameView.Enemy.Board, showEnemyCursor, false, &styleBorder)
 
+	myLabel, enemyLabel := "YOUR FLEET", "ENEMY WATERS"
+	if m.GameView.State == dto.StatePlaying || m.GameView.State == dto.StateFinished {
+		myLabel += fmt.Sprintf(" · %d AFLOAT", m.GameView.Me.ShipsAfloat)
+		enemyLabel += fmt.Sprintf(" · %d AFLOAT", m.GameView.Enemy.ShipsAfloat)
+	}
+
 	leftPanel := lipgloss.JoinVertical(
 		lipgloss.Left,
 		styleLabel.Render(stateLabel),
-		styleLabel.Render("YOUR FLEET"),
+		styleLabel.Render(myLabel),
 		myBoard,
 	)
 
 	boards := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		lipgloss.NewStyle().MarginRight(4).Render(leftPanel),
-		lipgloss.JoinVertical(lipgloss.Left, "", styleLabel.Render("ENEMY WATERS"), enemyBoard),
+		lipgloss.JoinVertical(lipgloss.Left, "", styleLabel.Render(enemyLabel), enemyBoard),
 	)
 
 	return fmt.Sprintf("%s\n\n%s", boards, instructions)
 }
 
+// connectionNotice warns that live updates stopped, or is empty while they flow.
+// A stream the server closed because the game is over needs no warning.
+func (m *Model) connectionNotice() string {
+	switch m.Connection.State {
+	case client.SubscriptionReconnecting:
+		return fmt.Sprintf("Connection lost, reconnecting (attempt %d)...", m.Connection.Attempt)
+	case client.SubscriptionClosed:
+		switch reason := m.Connection.Close.Reason; reason {
+		case dto.StreamClosedGameOver:
+			return ""
+		case "":
+			return "Live updates stopped: the server cannot be reached"
+		default:
+			return "Live updates stopped: " + strings.ReplaceAll(reason, "_", " ")
+		}
+	}
+	return ""
+}
+
+// viewAwaitingOpponent shows a hosted match nobody has joined yet, with the ID to share.
+func (m *Model) viewAwaitingOpponent() string {
+	styleLabel := lipgloss.NewStyle().Foreground(ColorSetup).Bold(true)
+	matchID := StyleBoardBorder.BorderForeground(ColorSetup).Bold(true).Render(m.GameID)
+
+	copied := ""
+	if m.Copied {
+		copied = lipgloss.NewStyle().Foreground(ColorMyTurn).Render("✓ Copied to clipboard!")
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		StyleTitle.Render("MATCH CREATED"),
+		"",
+		styleLabel.Render(m.Spinner.View()+" Waiting for an opponent to join"),
+		"",
+		"Match ID:",
+		matchID,
+		copied,
+		"Share this ID with a friend so they can join.",
+		"",
+		styleLabel.Render("[Y] Copy ID | [Esc] Cancel match and return to lobby"),
+	)
+}
+
 func (m *Model) getInstructions() string {
 	switch {
 	case m.GameView.State == dto.StateFinished:
@@ -152,7 +226,21 @@ func (m *Model) getInstructions() string {
 		if m.GameView.Winner == m.GameView.Me.ID {
 			res = "WIN"
 		}
-		return fmt.Sprintf("GAME OVER - YOU %s! Winner: %s", res, m.GameView.Winner)
+		if m.Animating {
+			return fmt.Sprintf("GAME OVER - YOU %s! Winner: %s | [Any Key] Skip", res, m.GameView.Winner)
+		}
+		if m.Reveal != nil {
+			return fmt.Sprintf("GAME OVER - YOU %s! Winner: %s | Dim S: enemy ships you never found | [Esc] Lobby",
+				res, m.GameView.Winner)
+		}
+		return fmt.Sprintf("GAME OVER - YOU %s! Winner: %s | [Esc] Lobby", res, m.GameView.Winner)
+	case m.GameView.State == dto.StateDraw:
+		if m.Reveal != nil {
+			return "GAME OVER - DRAW! Neither fleet was sunk | Dim S: enemy ships you never found | [Esc] Lobby"
+		}
+		return "GAME OVER - DRAW! Neither fleet was sunk | [Esc] Lobby"
+	case m.GameView.State == dto.StateAbandoned:
+		return "GAME ABANDONED - both players left the match | [Esc] Lobby"
 	case m.SetupPhase:
 		if m.CurrentShipIdx < len(m.ShipsToPlace) {
 			size := m.ShipsToPlace[m.CurrentShipIdx]
@@ -161,9 +249,16 @@ func (m *Model) getInstructions() string {
 				orient = "VERT"
 			}
 
-			action := "Waiting for game..."
+			action := m.Spinner.View() + " Waiting for game..."
 			if m.GameView.State == dto.StateSetup {
-				action = "[Enter] Place"
+				switch {
+				case m.Selected != nil:
+					action = fmt.Sprintf("[Enter] Confirm at %s | [Esc] Cancel", cellLabel(m.Selected.X, m.Selected.Y))
+				case m.ConfirmFire:
+					action = "[Enter] Select"
+				default:
+					action = "[Enter] Place"
+				}
 			}
 
 			return fmt.Sprintf(
@@ -173,11 +268,23 @@ func (m *Model) getInstructions() string {
 				action,
 			)
 		}
-		return "SETUP: Waiting for opponent..."
-	case m.GameView.Turn == m.GameView.Me.ID:
+		if m.awaitingReady() {
+			return "SETUP: Review your fleet | [Enter] Ready"
+		}
+		return fmt.Sprintf("SETUP: %s Waiting for opponent...", m.Spinner.View())
+	case m.GameView.YourTurn:
+		if m.Selected != nil {
+			return fmt.Sprintf(
+				"YOUR TURN: Target %s selected | [Arrows] Move | [Enter] Fire | [Esc] Cancel",
+				cellLabel(m.Selected.X, m.Selected.Y),
+			)
+		}
+		if m.ConfirmFire {
+			return "YOUR TURN: Select target on enemy board | [Arrows] Move | [Enter] Select"
+		}
 		return "YOUR TURN: Select target on enemy board | [Arrows] Move | [Enter] Fire"
 	default:
-		return "OPPONENT'S TURN: Please wait..."
+		return fmt.Sprintf("OPPONENT'S TURN: %s Please wait...", m.Spinner.View())
 	}
 }
 
@@ -236,6 +343,12 @@ func (m *Model) renderCell(
 		style = StyleCellUnknown
 	}
 
+	// Enemy ships the player never found, once the game is over
+	if !isMe && cell == dto.CellUnknown && m.revealedShip(x, y) {
+		symbol = "S"
+		style = StyleCellRevealed
+	}
+
 	// Render basic cell
 	rendered := style.Render(symbol)
 
@@ -244,14 +357,103 @@ func (m *Model) renderCell(
 		rendered = ghost
 	}
 
+	// Game-over animation overlay
+	if glyph, ok := m.getAnimationGlyph(x, y, board.Size, isMe); ok {
+		return glyph
+	}
+
 	// Cursor overlay
 	if showCursor && x == m.CursorX && y == m.CursorY {
 		rendered = StyleCursor.Render(symbol)
 	}
 
+	// Pending selection overlay (confirm-fire mode)
+	if showCursor && m.isSelected(x, y, isMe) {
+		rendered = StyleSelected.Render(symbol)
+	}
+
 	return rendered
 }
 
+// revealedShip reports whether the enemy had a ship on the cell, as revealed after the game.
+func (m *Model) revealedShip(x, y int) bool {
+	if m.Reveal == nil {
+		return false
+	}
+	for _, p := range m.Reveal.Players {
+		if p.ID == m.GameView.Enemy.ID && y < p.Board.Size && x < p.Board.Size {
+			return p.Board.Grid[y][x] == dto.CellShip
+		}
+	}
+	return false
+}
+
+// isSelected reports whether the cell is covered by the selection waiting for confirmation:
+// the targeted cell on the enemy board, or the cells of the ship about to be placed on ours.
+func (m *Model) isSelected(x, y int, isMe bool) bool {
+	sel := m.Selected
+	if sel == nil || isMe != m.SetupPhase {
+		return false
+	}
+	if !isMe {
+		return x == sel.X && y == sel.Y
+	}
+	if m.CurrentShipIdx >= len(m.ShipsToPlace) {
+		return false
+	}
+
+	size := m.ShipsToPlace[m.CurrentShipIdx]
+	if sel.Vertical {
+		return x == sel.X && y >= sel.Y && y < sel.Y+size
+	}
+	return y == sel.Y && x >= sel.X && x < sel.X+size
+}
+
+var (
+	confettiGlyphs = []string{"*", "+", "•", "o", "✦"}
+	confettiColors = []lipgloss.Color{ColorWin, ColorMyTurn, ColorSetup, "212", "208"}
+)
+
+// getAnimationGlyph returns the particle covering the cell in the current game-over frame, if any.
+// Victory rains confetti over both boards; defeat drips a slow, steel-blue rain.
+func (m *Model) getAnimationGlyph(x, y, size int, isMe bool) (string, bool) {
+	if !m.Animating {
+		return "", false
+	}
+
+	// Number the columns across both boards so they don't fall in sync
+	col := x
+	if !isMe {
+		col += size
+	}
+
+	won := m.GameView.Winner == m.GameView.Me.ID
+	frame := m.AnimFrame
+	if !won {
+		frame /= 2 // Rain falls at half speed
+	}
+
+	period := size + size/2 // Leave gaps between the drops of a column
+	head := (frame + col*7) % period
+
+	switch {
+	case won && y == head:
+		glyph := confettiGlyphs[(col+m.AnimFrame)%len(confettiGlyphs)]
+		color := confettiColors[(col+y)%len(confettiColors)]
+		return lipgloss.NewStyle().Foreground(color).Bold(true).Render(glyph), true
+	case !won && y == head:
+		return StyleRain.Render("|"), true
+	case !won && y == head-1:
+		return StyleRain.Faint(true).Render("'"), true
+	}
+	return "", false
+}
+
+// cellLabel formats a cell the way the boards are labelled, e.g. "B4".
+func cellLabel(x, y int) string {
+	return fmt.Sprintf("%c%d", 'A'+y, x)
+}
+
 func (m *Model) getGhostSymbol(
 	x, y int,
 	board dto.BoardView,
@@ -277,7 +479,7 @@ func (m *Model) getGhostSymbol(
 
 	if isGhost {
 		err := rules.CanPlaceShip(
-			board,
+			rules.View(board),
 			size,
 			m.CursorX,
 			m.CursorY,